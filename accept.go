@@ -0,0 +1,150 @@
+package echo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaRange is single media range of `Accept` header field, i.e. `text/*;level=1;q=0.8`.
+type MediaRange struct {
+	// Type is the media type, i.e. "text". Value "*" matches any type.
+	Type string
+	// Subtype is the media subtype, i.e. "html". Value "*" matches any subtype.
+	Subtype string
+	// Params contains media range parameters excluding quality value "q".
+	Params map[string]string
+	// Quality is the quality value (weight) of media range between 0 and 1. Defaults to 1.
+	Quality float64
+}
+
+// String returns media range without quality value, i.e. "text/html;level=1".
+func (m MediaRange) String() string {
+	s := m.Type + "/" + m.Subtype
+	if len(m.Params) == 0 {
+		return s
+	}
+	keys := make([]string, 0, len(m.Params))
+	for k := range m.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s += ";" + k + "=" + m.Params[k]
+	}
+	return s
+}
+
+// Matches checks if media type (i.e. "text/html" or "text/html;level=1") is matched by media range. All parameters
+// of media range must be present in media type.
+func (m MediaRange) Matches(mediaType string) bool {
+	t, ok := parseMediaRange(ParseHeaderValues(mediaType))
+	if !ok {
+		return false
+	}
+	if m.Type != "*" && m.Type != t.Type {
+		return false
+	}
+	if m.Subtype != "*" && m.Subtype != t.Subtype {
+		return false
+	}
+	for k, v := range m.Params {
+		if t.Params[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// specificity returns how specific media range is. More specific ranges have precedence over less specific ones.
+func (m MediaRange) specificity() int {
+	switch {
+	case m.Type == "*":
+		return 0
+	case m.Subtype == "*":
+		return 1
+	}
+	return 2 + len(m.Params)
+}
+
+// ParseAccept parses `Accept` header field lines into media ranges sorted by quality value and specificity in
+// descending order. Ranges with same precedence keep their order. Invalid media ranges are skipped.
+func ParseAccept(lines ...string) []MediaRange {
+	var result []MediaRange
+	for _, hv := range ParseHeaderValues(lines...) {
+		if m, ok := parseMediaRange([]HeaderValue{hv}); ok {
+			result = append(result, m)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Quality != result[j].Quality {
+			return result[i].Quality > result[j].Quality
+		}
+		return result[i].specificity() > result[j].specificity()
+	})
+	return result
+}
+
+func parseMediaRange(values []HeaderValue) (MediaRange, bool) {
+	if len(values) != 1 {
+		return MediaRange{}, false
+	}
+	hv := values[0]
+	i := strings.IndexByte(hv.Value, '/')
+	if i <= 0 || i == len(hv.Value)-1 {
+		return MediaRange{}, false
+	}
+	m := MediaRange{
+		Type:    strings.ToLower(hv.Value[:i]),
+		Subtype: strings.ToLower(hv.Value[i+1:]),
+		Quality: 1,
+	}
+	if m.Type == "*" && m.Subtype != "*" {
+		return MediaRange{}, false
+	}
+	for k, v := range hv.Params {
+		if k == "q" {
+			q, err := strconv.ParseFloat(v, 64)
+			if err != nil || q < 0 || q > 1 {
+				return MediaRange{}, false
+			}
+			m.Quality = q
+			continue
+		}
+		if m.Params == nil {
+			m.Params = map[string]string{}
+		}
+		m.Params[k] = v
+	}
+	return m, true
+}
+
+// NegotiateMediaType returns offered media type that is most preferred by request `Accept` header. Quality of
+// offer is determined by the most specific matching media range. Offers with same quality are preferred in the
+// given order. When request has no `Accept` header first offer is returned. Empty string is returned when no offer
+// is acceptable.
+//
+// Example: `switch echo.NegotiateMediaType(c, echo.MIMEApplicationJSON, echo.MIMEApplicationXML) {...}`
+func NegotiateMediaType(c Context, offers ...string) string {
+	lines := c.Request().Header[HeaderAccept]
+	if len(lines) == 0 {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+	ranges := ParseAccept(lines...)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, m := range ranges {
+			if s := m.specificity(); s > specificity && m.Matches(offer) {
+				q, specificity = m.Quality, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
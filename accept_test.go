@@ -0,0 +1,130 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAccept(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   string
+		expect []MediaRange
+	}{
+		{
+			name: "ok, sorted by quality and specificity",
+			when: "text/*;q=0.3, text/html;q=0.7, text/html;level=1, text/html;level=2;q=0.4, */*;q=0.5",
+			expect: []MediaRange{
+				{Type: "text", Subtype: "html", Params: map[string]string{"level": "1"}, Quality: 1},
+				{Type: "text", Subtype: "html", Quality: 0.7},
+				{Type: "*", Subtype: "*", Quality: 0.5},
+				{Type: "text", Subtype: "html", Params: map[string]string{"level": "2"}, Quality: 0.4},
+				{Type: "text", Subtype: "*", Quality: 0.3},
+			},
+		},
+		{
+			name: "ok, same precedence keeps order",
+			when: "application/xml, application/JSON",
+			expect: []MediaRange{
+				{Type: "application", Subtype: "xml", Quality: 1},
+				{Type: "application", Subtype: "json", Quality: 1},
+			},
+		},
+		{
+			name: "ok, invalid ranges are skipped",
+			when: "text, /html, */html, text/html;q=2, text/plain;q=abc, application/json",
+			expect: []MediaRange{
+				{Type: "application", Subtype: "json", Quality: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, ParseAccept(tc.when))
+		})
+	}
+}
+
+func TestMediaRange_Matches(t *testing.T) {
+	var testCases = []struct {
+		whenRange string
+		whenType  string
+		expect    bool
+	}{
+		{whenRange: "*/*", whenType: "application/json", expect: true},
+		{whenRange: "text/*", whenType: "text/html", expect: true},
+		{whenRange: "text/*", whenType: "application/json", expect: false},
+		{whenRange: "text/html", whenType: "TEXT/HTML", expect: true},
+		{whenRange: "text/html;level=1", whenType: "text/html", expect: false},
+		{whenRange: "text/html;level=1", whenType: "text/html;level=1", expect: true},
+		{whenRange: "text/html", whenType: "invalid", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.whenRange+" "+tc.whenType, func(t *testing.T) {
+			m := ParseAccept(tc.whenRange)[0]
+			assert.Equal(t, tc.expect, m.Matches(tc.whenType))
+		})
+	}
+}
+
+func TestMediaRange_String(t *testing.T) {
+	m := MediaRange{Type: "text", Subtype: "html", Params: map[string]string{"level": "1", "charset": "utf-8"}, Quality: 0.5}
+	assert.Equal(t, "text/html;charset=utf-8;level=1", m.String())
+}
+
+func TestNegotiateMediaType(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenAccept string
+		whenOffers []string
+		expect     string
+	}{
+		{
+			name:       "ok, no accept header",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationJSON,
+		},
+		{
+			name:       "ok, highest quality",
+			whenAccept: "application/json;q=0.5, application/xml",
+			whenOffers: []string{MIMEApplicationJSON, MIMEApplicationXML},
+			expect:     MIMEApplicationXML,
+		},
+		{
+			name:       "ok, most specific range decides quality",
+			whenAccept: "text/*, text/plain;q=0.1",
+			whenOffers: []string{MIMETextPlain, MIMETextHTML},
+			expect:     MIMETextHTML,
+		},
+		{
+			name:       "ok, same quality prefers offer order",
+			whenAccept: "*/*",
+			whenOffers: []string{MIMEApplicationXML, MIMEApplicationJSON},
+			expect:     MIMEApplicationXML,
+		},
+		{
+			name:       "nok, nothing acceptable",
+			whenAccept: "image/png, application/json;q=0",
+			whenOffers: []string{MIMEApplicationJSON},
+			expect:     "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.whenAccept != "" {
+				req.Header.Set(HeaderAccept, tc.whenAccept)
+			}
+			c := e.NewContext(req, nil)
+
+			assert.Equal(t, tc.expect, NegotiateMediaType(c, tc.whenOffers...))
+		})
+	}
+}
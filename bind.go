@@ -2,13 +2,16 @@ package echo
 
 import (
 	"encoding"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type (
@@ -17,8 +20,46 @@ type (
 		Bind(i interface{}, c Context) error
 	}
 
+	// Normalizer is the interface implemented by bind destinations that normalize their values after binding i.e. trim
+	// strings or lowercase emails. `DefaultBinder#Bind` and Context bind methods call Normalize after populating
+	// destination. Returned error is sent as `400 Bad Request` unless it is an *HTTPError.
+	Normalizer interface {
+		Normalize() error
+	}
+
+	// SourceBinder is the interface implemented by binders that are able to bind each request data source separately.
+	// See `Context#BindPath`, `Context#BindQuery`, `Context#BindHeaders` and `Context#BindBody`.
+	SourceBinder interface {
+		BindPathParams(c Context, i interface{}) error
+		BindQueryParams(c Context, i interface{}) error
+		BindHeaders(c Context, i interface{}) error
+		BindBody(c Context, i interface{}) error
+	}
+
+	// FormBinder is the interface implemented by binders that are able to bind form data regardless of request
+	// method. See `Context#BindForm`.
+	FormBinder interface {
+		BindForm(c Context, i interface{}) error
+	}
+
+	// StrictBinder is the interface implemented by binders that support strict binding. See `Context#BindStrict`.
+	StrictBinder interface {
+		BindStrict(i interface{}, c Context) error
+	}
+
 	// DefaultBinder is the default implementation of the Binder interface.
-	DefaultBinder struct{}
+	DefaultBinder struct {
+		// DisallowUnknownFields enables strict binding. JSON request body fields and query parameters that do not
+		// have matching field in destination struct result in `400 Bad Request` error naming the unexpected field.
+		// Useful for API contract enforcement.
+		DisallowUnknownFields bool
+
+		converters map[reflect.Type]BindValueConverter
+	}
+
+	// BindValueConverter converts path/query/form/header value into value of type it was registered for.
+	// See `DefaultBinder#RegisterConverter`.
+	BindValueConverter func(value string) (interface{}, error)
 
 	// BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 	// Types that don't implement this, but do implement encoding.TextUnmarshaler
@@ -29,6 +70,75 @@ type (
 	}
 )
 
+// BindSource identifies part of the request that `Context#BindFrom` binds data from.
+type BindSource uint8
+
+const (
+	// BindSourcePath binds path parameters (`param` tag).
+	BindSourcePath BindSource = iota + 1
+	// BindSourceQuery binds query parameters (`query` tag) regardless of request method.
+	BindSourceQuery
+	// BindSourceHeaders binds request headers (`header` tag).
+	BindSourceHeaders
+	// BindSourceBody binds request body based on Content-Type header.
+	BindSourceBody
+)
+
+const jsonUnknownFieldPrefix = "json: unknown field "
+
+// FormTimeLayouts are layouts used to parse form values into `time.Time` fields without `layout` tag. They cover
+// RFC 3339 and values sent by HTML `datetime-local`, `date` and `time` inputs.
+var FormTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02", "15:04:05", "15:04"}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var bindUnmarshalerType = reflect.TypeOf((*BindUnmarshaler)(nil)).Elem()
+
+// RegisterConverter registers converter for type of given value `v`. Converters are used when binding path, query,
+// form and header values to fields of that type (or pointer or slice of that type) and have priority over
+// BindUnmarshaler and encoding.TextUnmarshaler implementations. Useful for third-party types that you can not modify.
+// Example: `b.RegisterConverter(decimal.Decimal{}, func(v string) (interface{}, error) { return decimal.NewFromString(v) })`
+// NB: register converters before server is started. Registering is not safe for concurrent use.
+func (b *DefaultBinder) RegisterConverter(v interface{}, fn BindValueConverter) {
+	if b.converters == nil {
+		b.converters = map[reflect.Type]BindValueConverter{}
+	}
+	b.converters[reflect.TypeOf(v)] = fn
+}
+
+// convertValue converts value with registered converter when there is one for field type (or field pointer element type)
+func (b *DefaultBinder) convertValue(value string, field reflect.Value) (bool, error) {
+	if len(b.converters) == 0 {
+		return false, nil
+	}
+	typ := field.Type()
+	fn, ok := b.converters[typ]
+	isPtr := false
+	if !ok && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		fn, ok = b.converters[typ]
+		isPtr = true
+	}
+	if !ok {
+		return false, nil
+	}
+	v, err := fn(value)
+	if err != nil {
+		return true, err
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !rv.Type().AssignableTo(typ) {
+		return true, fmt.Errorf("converter for %v returned value of type %T", typ, v)
+	}
+	if isPtr {
+		ptr := reflect.New(typ)
+		ptr.Elem().Set(rv)
+		rv = ptr
+	}
+	field.Set(rv)
+	return true, nil
+}
+
 // BindPathParams binds path params to bindable object
 func (b *DefaultBinder) BindPathParams(c Context, i interface{}) error {
 	names := c.ParamNames()
@@ -45,6 +155,11 @@ func (b *DefaultBinder) BindPathParams(c Context, i interface{}) error {
 
 // BindQueryParams binds query params to bindable object
 func (b *DefaultBinder) BindQueryParams(c Context, i interface{}) error {
+	if b.DisallowUnknownFields {
+		if err := checkUnknownParams(i, c.QueryParams(), "query"); err != nil {
+			return err
+		}
+	}
 	if err := b.bindData(i, c.QueryParams(), "query"); err != nil {
 		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 	}
@@ -64,6 +179,8 @@ func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 
 	ctype := req.Header.Get(HeaderContentType)
 	switch {
+	case strings.HasPrefix(ctype, MIMEApplicationJSON) && b.DisallowUnknownFields:
+		return deserializeJSONStrict(c, i)
 	case strings.HasPrefix(ctype, MIMEApplicationJSON):
 		if err = c.Echo().JSONSerializer.Deserialize(c, i); err != nil {
 			switch err.(type) {
@@ -96,6 +213,19 @@ func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 	return nil
 }
 
+// BindForm binds form data (URL query and `application/x-www-form-urlencoded` or `multipart/form-data` body) to
+// bindable object regardless of request method.
+func (b *DefaultBinder) BindForm(c Context, i interface{}) error {
+	params, err := c.FormParams()
+	if err != nil {
+		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err = b.bindData(i, params, "form"); err != nil {
+		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
 // BindHeaders binds HTTP headers to a bindable object
 func (b *DefaultBinder) BindHeaders(c Context, i interface{}) error {
 	if err := b.bindData(i, c.Request().Header, "header"); err != nil {
@@ -120,7 +250,102 @@ func (b *DefaultBinder) Bind(i interface{}, c Context) (err error) {
 			return err
 		}
 	}
-	return b.BindBody(c, i)
+	if err = b.BindBody(c, i); err != nil {
+		return err
+	}
+	return normalize(i)
+}
+
+// normalize calls Normalize on bind destination if it implements Normalizer interface.
+func normalize(i interface{}) error {
+	n, ok := i.(Normalizer)
+	if !ok {
+		return nil
+	}
+	if err := n.Normalize(); err != nil {
+		if _, ok := err.(*HTTPError); ok {
+			return err
+		}
+		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+// BindStrict binds request like `Bind` does but with DisallowUnknownFields enabled.
+func (b *DefaultBinder) BindStrict(i interface{}, c Context) error {
+	strict := *b
+	strict.DisallowUnknownFields = true
+	return strict.Bind(i, c)
+}
+
+// deserializeJSONStrict decodes JSON request body into destination and errors on fields that destination does not have.
+// NB: strict decoding is done with `encoding/json` and does not use Echo#JSONSerializer.
+func deserializeJSONStrict(c Context, i interface{}) error {
+	dec := json.NewDecoder(c.Request().Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(i)
+	if err == nil {
+		return nil
+	}
+	// encoding/json does not have typed error for unknown fields, error message is in form `json: unknown field "name"`
+	if msg := err.Error(); strings.HasPrefix(msg, jsonUnknownFieldPrefix) {
+		field, uErr := strconv.Unquote(msg[len(jsonUnknownFieldPrefix):])
+		if uErr != nil {
+			field = msg[len(jsonUnknownFieldPrefix):]
+		}
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown field %q", field)).SetInternal(err)
+	}
+	err = jsonDecodeError(err)
+	if _, ok := err.(*HTTPError); ok {
+		return err
+	}
+	return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
+// checkUnknownParams returns an error for first data key that does not have matching tagged field in destination struct.
+// Keys are compared case-insensitively same way as bindData does.
+func checkUnknownParams(destination interface{}, data map[string][]string, tag string) error {
+	if destination == nil || len(data) == 0 {
+		return nil
+	}
+	typ := reflect.TypeOf(destination)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	known := map[string]struct{}{}
+	collectFieldNames(typ, tag, known)
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // for deterministic error message when there are multiple unknown keys
+	for _, k := range keys {
+		if _, ok := known[strings.ToLower(k)]; !ok {
+			return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown %s parameter %q", tag, k))
+		}
+	}
+	return nil
+}
+
+// collectFieldNames collects (lowercased) tag values of all struct fields that bindData could bind to
+func collectFieldNames(typ reflect.Type, tag string, names map[string]struct{}) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldType := field.Type
+		if field.Anonymous && fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if name := field.Tag.Get(tag); name != "" {
+			names[strings.ToLower(name)] = struct{}{}
+			continue
+		}
+		if fieldType.Kind() == reflect.Struct && !reflect.PtrTo(fieldType).Implements(bindUnmarshalerType) {
+			collectFieldNames(fieldType, tag, names)
+		}
+	}
 }
 
 // bindData will bind data ONLY fields in destination struct that have EXPLICIT tag
@@ -193,10 +418,31 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			}
 		}
 
+		if !exists && tag == "form" {
+			// repeated fields named like `tags[]`
+			inputValue, exists = data[inputFieldName+"[]"]
+		}
+
 		if !exists {
 			continue
 		}
 
+		if ok, err := b.convertValue(inputValue[0], structField); ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag == "form" {
+			if ok, err := bindFormField(typeField, structField, inputValue); ok {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
 		// Call this first, in case we're dealing with an alias to an array type
 		if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
 			if err != nil {
@@ -210,6 +456,12 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			sliceOf := structField.Type().Elem().Kind()
 			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
 			for j := 0; j < numElems; j++ {
+				if ok, err := b.convertValue(inputValue[j], slice.Index(j)); ok {
+					if err != nil {
+						return err
+					}
+					continue
+				}
 				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
 					return err
 				}
@@ -223,6 +475,69 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 	return nil
 }
 
+// bindFormField binds form values to `time.Time` and bool fields (or pointers or slices of them). Time values are
+// parsed with layouts from `layout` tag (separated by `|`) or `FormTimeLayouts`. Bool fields accept checkbox values
+// ("on", "yes", "checked" and "off", "no") and use the last value so checkbox can follow hidden input with the same
+// name. Returns false when field is of other type.
+func bindFormField(typeField reflect.StructField, field reflect.Value, values []string) (bool, error) {
+	typ := field.Type()
+	if typ.Kind() == reflect.Slice && isFormScalar(typ.Elem()) {
+		slice := reflect.MakeSlice(typ, len(values), len(values))
+		for j, v := range values {
+			if err := setFormScalar(typeField, slice.Index(j), v); err != nil {
+				return true, err
+			}
+		}
+		field.Set(slice)
+		return true, nil
+	}
+	if !isFormScalar(typ) {
+		return false, nil
+	}
+	return true, setFormScalar(typeField, field, values[len(values)-1])
+}
+
+func isFormScalar(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ == timeType || typ.Kind() == reflect.Bool
+}
+
+func setFormScalar(typeField reflect.StructField, field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	if field.Type() != timeType {
+		switch strings.ToLower(value) {
+		case "on", "yes", "checked":
+			value = "true"
+		case "off", "no":
+			value = "false"
+		}
+		return setBoolField(value, field)
+	}
+
+	if value == "" {
+		field.Set(reflect.ValueOf(time.Time{}))
+		return nil
+	}
+	layouts := FormTimeLayouts
+	if l := typeField.Tag.Get("layout"); l != "" {
+		layouts = strings.Split(l, "|")
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			field.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("can not parse %q as time with layouts %q", value, strings.Join(layouts, "|"))
+}
+
 func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
 	// But also call it here, in case we're dealing with an array of BindUnmarshalers
 	if ok, err := unmarshalField(valueKind, val, structField); ok {
@@ -1043,3 +1043,363 @@ func TestDefaultBinder_BindBody(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultBinder_DisallowUnknownFields(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenURL         string
+		givenMethod      string
+		givenContentType string
+		givenContent     string
+		expect           *user
+		expectError      string
+	}{
+		{
+			name:             "ok, JSON body with known fields",
+			givenURL:         "/",
+			givenMethod:      http.MethodPost,
+			givenContentType: MIMEApplicationJSON,
+			givenContent:     userJSON,
+			expect:           &user{ID: 1, Name: "Jon Snow"},
+		},
+		{
+			name:             "nok, JSON body with unknown field",
+			givenURL:         "/",
+			givenMethod:      http.MethodPost,
+			givenContentType: MIMEApplicationJSON,
+			givenContent:     `{"id":1,"nickname":"Jon"}`,
+			expect:           &user{ID: 1},
+			expectError:      `code=400, message=unknown field "nickname", internal=json: unknown field "nickname"`,
+		},
+		{
+			name:             "nok, JSON body with invalid type",
+			givenURL:         "/",
+			givenMethod:      http.MethodPost,
+			givenContentType: MIMEApplicationJSON,
+			givenContent:     userJSONInvalidType,
+			expect:           &user{Name: "Jon Snow"},
+			expectError:      "code=400, message=Unmarshal type error: expected=int, got=string, field=id, offset=9, internal=json: cannot unmarshal string into Go struct field user.id of type int",
+		},
+		{
+			name:        "ok, query params with known fields",
+			givenURL:    "/?id=1&NAME=Jon+Snow",
+			givenMethod: http.MethodGet,
+			expect:      &user{ID: 1, Name: "Jon Snow"},
+		},
+		{
+			name:        "nok, query params with unknown field",
+			givenURL:    "/?id=1&sort=asc&limit=10",
+			givenMethod: http.MethodGet,
+			expect:      &user{},
+			expectError: `code=400, message=unknown query parameter "limit"`,
+		},
+		{
+			name:        "ok, unknown query params are not checked when query is not bound",
+			givenURL:    "/?sort=asc",
+			givenMethod: http.MethodPost,
+			expect:      &user{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Binder = &DefaultBinder{DisallowUnknownFields: true}
+
+			var body io.Reader
+			if tc.givenContent != "" {
+				body = strings.NewReader(tc.givenContent)
+			}
+			req := httptest.NewRequest(tc.givenMethod, tc.givenURL, body)
+			if tc.givenContentType != "" {
+				req.Header.Set(HeaderContentType, tc.givenContentType)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			u := new(user)
+			err := c.Bind(u)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expect, u)
+		})
+	}
+}
+
+func TestDefaultBinder_BindStrict(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":1,"nickname":"Jon"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	b := &DefaultBinder{}
+	err := b.BindStrict(new(user), c)
+
+	assert.EqualError(t, err, `code=400, message=unknown field "nickname", internal=json: unknown field "nickname"`)
+	assert.False(t, b.DisallowUnknownFields)
+}
+
+type normalizedUser struct {
+	Email string `json:"email" query:"email"`
+}
+
+func (u *normalizedUser) Normalize() error {
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	if u.Email == "" {
+		return errors.New("email is required")
+	}
+	if !strings.Contains(u.Email, "@") {
+		return NewHTTPError(http.StatusUnprocessableEntity, "invalid email")
+	}
+	return nil
+}
+
+func TestDefaultBinder_Normalizer(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenURL    string
+		givenBody   string
+		expect      *normalizedUser
+		expectError string
+	}{
+		{
+			name:      "ok, body is normalized",
+			givenURL:  "/",
+			givenBody: `{"email":"  Jon@Example.COM "}`,
+			expect:    &normalizedUser{Email: "jon@example.com"},
+		},
+		{
+			name:        "nok, normalizer error is converted to bad request",
+			givenURL:    "/",
+			givenBody:   `{"email":"  "}`,
+			expect:      &normalizedUser{},
+			expectError: "code=400, message=email is required, internal=email is required",
+		},
+		{
+			name:        "nok, normalizer HTTPError is returned as is",
+			givenURL:    "/",
+			givenBody:   `{"email":"jon"}`,
+			expect:      &normalizedUser{Email: "jon"},
+			expectError: "code=422, message=invalid email",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, tc.givenURL, strings.NewReader(tc.givenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			u := new(normalizedUser)
+			err := c.Bind(u)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expect, u)
+		})
+	}
+}
+
+func TestContext_BindQueryCallsNormalizer(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/?email=JON@example.com", nil), httptest.NewRecorder())
+
+	u := new(normalizedUser)
+	err := c.BindQuery(u)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jon@example.com", u.Email)
+}
+
+type customID struct {
+	value string
+}
+
+func TestDefaultBinder_RegisterConverter(t *testing.T) {
+	type target struct {
+		ID    customID    `query:"id"`
+		PtrID *customID   `query:"ptr"`
+		IDs   []customID  `query:"ids"`
+		Level int         `query:"level"`
+		Nil   *customID   `query:"nil"`
+		Any   interface{} `query:"any"`
+	}
+	b := &DefaultBinder{}
+	b.RegisterConverter(customID{}, func(value string) (interface{}, error) {
+		if !strings.HasPrefix(value, "id-") {
+			return nil, errors.New("invalid id")
+		}
+		return customID{value: strings.TrimPrefix(value, "id-")}, nil
+	})
+	b.RegisterConverter(0, func(value string) (interface{}, error) {
+		switch value {
+		case "low":
+			return 1, nil
+		case "high":
+			return 10, nil
+		}
+		return strconv.Atoi(value)
+	})
+
+	var testCases = []struct {
+		name        string
+		givenURL    string
+		expect      target
+		expectError string
+	}{
+		{
+			name:     "ok",
+			givenURL: "/?id=id-1&ptr=id-2&ids=id-3&ids=id-4&level=high",
+			expect: target{
+				ID:    customID{value: "1"},
+				PtrID: &customID{value: "2"},
+				IDs:   []customID{{value: "3"}, {value: "4"}},
+				Level: 10,
+			},
+		},
+		{
+			name:        "nok, converter error",
+			givenURL:    "/?id=1",
+			expectError: "code=400, message=invalid id, internal=invalid id",
+		},
+		{
+			name:        "nok, converter error for slice element",
+			givenURL:    "/?ids=id-1&ids=x",
+			expectError: "code=400, message=invalid id, internal=invalid id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, tc.givenURL, nil), httptest.NewRecorder())
+
+			dest := target{}
+			err := b.BindQueryParams(c, &dest)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestDefaultBinder_RegisterConverterInvalidReturnType(t *testing.T) {
+	b := &DefaultBinder{}
+	b.RegisterConverter(customID{}, func(value string) (interface{}, error) {
+		return value, nil
+	})
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=1", nil), httptest.NewRecorder())
+
+	dest := struct {
+		ID customID `query:"id"`
+	}{}
+	err := b.BindQueryParams(c, &dest)
+
+	assert.EqualError(t, err, "code=400, message=converter for echo.customID returned value of type string, internal=converter for echo.customID returned value of type string")
+}
+
+func TestContext_BindForm(t *testing.T) {
+	type form struct {
+		Name      *string    `form:"name"`
+		Nickname  *string    `form:"nickname"`
+		Agree     bool       `form:"agree"`
+		Subscribe *bool      `form:"subscribe"`
+		Remember  bool       `form:"remember"`
+		Tags      []string   `form:"tags"`
+		Flags     []bool     `form:"flags"`
+		Birthday  time.Time  `form:"birthday"`
+		Meeting   *time.Time `form:"meeting" layout:"02.01.2006 15:04|2006-01-02T15:04"`
+		Deadline  *time.Time `form:"deadline"`
+	}
+
+	var testCases = []struct {
+		name        string
+		whenMethod  string
+		whenBody    string
+		expect      func(t *testing.T, f form)
+		expectError string
+	}{
+		{
+			name:       "ok, checkbox values, repeated fields and time layouts",
+			whenMethod: http.MethodPost,
+			whenBody:   "name=jon&nickname=&agree=on&subscribe=yes&remember=off&remember=on&tags[]=a&tags[]=b&flags=on&flags=no&birthday=1990-05-17&meeting=24.12.2021+18:30&deadline=",
+			expect: func(t *testing.T, f form) {
+				assert.Equal(t, "jon", *f.Name)
+				assert.Equal(t, "", *f.Nickname)
+				assert.True(t, f.Agree)
+				assert.True(t, *f.Subscribe)
+				assert.True(t, f.Remember)
+				assert.Equal(t, []string{"a", "b"}, f.Tags)
+				assert.Equal(t, []bool{true, false}, f.Flags)
+				assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), f.Birthday)
+				assert.Equal(t, time.Date(2021, 12, 24, 18, 30, 0, 0, time.UTC), *f.Meeting)
+				assert.True(t, f.Deadline.IsZero())
+			},
+		},
+		{
+			name:       "ok, missing fields leave pointers nil",
+			whenMethod: http.MethodPut,
+			whenBody:   "meeting=2021-12-24T18:30",
+			expect: func(t *testing.T, f form) {
+				assert.Nil(t, f.Name)
+				assert.Nil(t, f.Subscribe)
+				assert.Nil(t, f.Deadline)
+				assert.False(t, f.Agree)
+				assert.Equal(t, time.Date(2021, 12, 24, 18, 30, 0, 0, time.UTC), *f.Meeting)
+			},
+		},
+		{
+			name:        "nok, time not matching layouts",
+			whenMethod:  http.MethodPost,
+			whenBody:    "meeting=2021-12-24",
+			expectError: `code=400, message=can not parse "2021-12-24" as time with layouts "02.01.2006 15:04|2006-01-02T15:04"`,
+		},
+		{
+			name:        "nok, invalid bool",
+			whenMethod:  http.MethodPost,
+			whenBody:    "agree=maybe",
+			expectError: `code=400, message=strconv.ParseBool: parsing "maybe": invalid syntax`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(tc.whenMethod, "/", strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationForm)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			f := form{}
+			err := c.BindForm(&f)
+			if tc.expectError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			tc.expect(t, f)
+		})
+	}
+}
+
+func TestContext_BindForm_queryOnGET(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?agree=on&day=2021-01-02", nil), httptest.NewRecorder())
+
+	f := struct {
+		Agree bool      `form:"agree"`
+		Day   time.Time `form:"day"`
+	}{}
+	assert.NoError(t, c.BindForm(&f))
+	assert.True(t, f.Agree)
+	assert.Equal(t, 2021, f.Day.Year())
+}
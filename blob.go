@@ -0,0 +1,88 @@
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+type (
+	// BlobServer opens blobs (i.e. objects of object storage like S3) served with `BlobHandler`.
+	BlobServer interface {
+		// Open opens blob by name (slash separated path without leading slash). Error wrapping `os.ErrNotExist`
+		// means blob does not exist.
+		Open(ctx stdContext.Context, name string) (*Blob, error)
+	}
+
+	// BlobServerFunc is an adapter to use function as `BlobServer`.
+	BlobServerFunc func(ctx stdContext.Context, name string) (*Blob, error)
+
+	// Blob is content opened with `BlobServer`.
+	Blob struct {
+		// Content is read at offsets of requested ranges so only requested parts are transferred from storage.
+		// Content is closed after response is sent when it implements `io.Closer`.
+		Content io.ReaderAt
+		// Size is the size of content in bytes.
+		Size int64
+		// ETag is the entity tag of content (i.e. `"5d41402abc4b2a76"`) used for conditional requests.
+		// Optional.
+		ETag string
+		// ModTime is the modification time of content used for conditional requests.
+		// Optional.
+		ModTime time.Time
+		// ContentType is the media type of content. When empty media type is detected from name extension or
+		// content.
+		// Optional.
+		ContentType string
+	}
+)
+
+// Open calls f(ctx, name).
+func (f BlobServerFunc) Open(ctx stdContext.Context, name string) (*Blob, error) {
+	return f(ctx, name)
+}
+
+// BlobHandler returns handler serving blobs of server with conditional requests (`If-None-Match`,
+// `If-Modified-Since` etc.) and range requests like files served by `Echo#Static`. Blob name is the value of
+// wildcard path parameter.
+//
+// Example:
+//
+//	e.GET("/assets/*", echo.BlobHandler(s3Server))
+func BlobHandler(server BlobServer) HandlerFunc {
+	return func(c Context) error {
+		p, err := url.PathUnescape(c.Param("*"))
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(path.Clean("/"+p), "/") // "/"+ for security
+		if name == "" {
+			return ErrNotFound
+		}
+
+		blob, err := server.Open(c.Request().Context(), name)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if closer, ok := blob.Content.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		h := c.Response().Header()
+		if blob.ETag != "" {
+			h.Set(HeaderETag, normalizeETag(blob.ETag))
+		}
+		if blob.ContentType != "" {
+			h.Set(HeaderContentType, blob.ContentType)
+		}
+		return c.ServeContentAt(path.Base(name), blob.ModTime, blob.Size, blob.Content)
+	}
+}
@@ -0,0 +1,146 @@
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type closingReader struct {
+	*strings.Reader
+	closed bool
+}
+
+func (r *closingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestBlobHandler(t *testing.T) {
+	modified := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		name          string
+		whenURL       string
+		whenHeaders   map[string]string
+		expectStatus  int
+		expectBody    string
+		expectName    string
+		expectHeaders map[string]string
+	}{
+		{
+			name:         "ok",
+			whenURL:      "/assets/css/site.css",
+			expectStatus: http.StatusOK,
+			expectBody:   "body{color:red}",
+			expectName:   "css/site.css",
+			expectHeaders: map[string]string{
+				HeaderETag:         `"abc"`,
+				HeaderContentType:  "text/css; charset=utf-8",
+				HeaderLastModified: "Tue, 01 Jun 2021 12:00:00 GMT",
+			},
+		},
+		{
+			name:         "ok, custom content type",
+			whenURL:      "/assets/data",
+			expectStatus: http.StatusOK,
+			expectBody:   "body{color:red}",
+			expectName:   "data",
+			expectHeaders: map[string]string{
+				HeaderContentType: "application/x-custom",
+			},
+		},
+		{
+			name:         "ok, range",
+			whenURL:      "/assets/css/site.css",
+			whenHeaders:  map[string]string{"Range": "bytes=0-3"},
+			expectStatus: http.StatusPartialContent,
+			expectBody:   "body",
+			expectName:   "css/site.css",
+		},
+		{
+			name:         "ok, if-none-match",
+			whenURL:      "/assets/css/site.css",
+			whenHeaders:  map[string]string{HeaderIfNoneMatch: `"abc"`},
+			expectStatus: http.StatusNotModified,
+			expectName:   "css/site.css",
+		},
+		{
+			name:         "nok, if-match",
+			whenURL:      "/assets/css/site.css",
+			whenHeaders:  map[string]string{HeaderIfMatch: `"xyz"`},
+			expectStatus: http.StatusPreconditionFailed,
+			expectName:   "css/site.css",
+		},
+		{
+			name:         "ok, path traversal is cleaned",
+			whenURL:      "/assets/%2e%2e/%2e%2e/secret.txt",
+			expectStatus: http.StatusOK,
+			expectBody:   "body{color:red}",
+			expectName:   "secret.txt",
+		},
+		{
+			name:         "nok, not found",
+			whenURL:      "/assets/missing.css",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "{\"message\":\"Not Found\"}\n",
+			expectName:   "missing.css",
+		},
+		{
+			name:         "nok, storage error",
+			whenURL:      "/assets/broken.css",
+			expectStatus: http.StatusInternalServerError,
+			expectBody:   "{\"message\":\"Internal Server Error\"}\n",
+			expectName:   "broken.css",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var content *closingReader
+			openedName := ""
+			server := BlobServerFunc(func(ctx stdContext.Context, name string) (*Blob, error) {
+				openedName = name
+				switch name {
+				case "missing.css":
+					return nil, fmt.Errorf("open %v: %w", name, os.ErrNotExist)
+				case "broken.css":
+					return nil, errors.New("storage is down")
+				}
+				content = &closingReader{Reader: strings.NewReader("body{color:red}")}
+				blob := &Blob{Content: content, Size: content.Size(), ETag: "abc", ModTime: modified}
+				if name == "data" {
+					blob.ContentType = "application/x-custom"
+				}
+				return blob, nil
+			})
+
+			e := New()
+			e.GET("/assets/*", BlobHandler(server))
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectName, openedName)
+			for k, v := range tc.expectHeaders {
+				assert.Equal(t, v, rec.Header().Get(k), k)
+			}
+			if content != nil {
+				assert.True(t, content.closed)
+			}
+		})
+	}
+}
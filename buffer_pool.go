@@ -0,0 +1,65 @@
+package echo
+
+import (
+	"bytes"
+	"sync"
+)
+
+// BufferPool is pool of reusable byte buffers shared by Echo and middlewares (i.e. for rendering templates, dumping
+// and decompressing bodies) to reduce allocations per request. Implementations must be safe for concurrent use.
+type BufferPool interface {
+	// Get returns buffer from pool or new buffer when pool is empty.
+	Get() *bytes.Buffer
+	// Put returns buffer to pool.
+	Put(b *bytes.Buffer)
+}
+
+// DefaultBufferPoolMaxSize is maximum capacity of buffers retained by buffer pool created with `New`.
+const DefaultBufferPoolMaxSize = 64 * 1024
+
+type syncBufferPool struct {
+	pool    sync.Pool
+	maxSize int
+}
+
+// NewBufferPool returns BufferPool backed by `sync.Pool`. Buffers with capacity over maxSize are not returned to the
+// pool so single large payload does not keep large amount of memory allocated. Zero maxSize means no limit.
+func NewBufferPool(maxSize int) BufferPool {
+	return &syncBufferPool{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+		maxSize: maxSize,
+	}
+}
+
+func (p *syncBufferPool) Get() *bytes.Buffer {
+	return p.pool.Get().(*bytes.Buffer)
+}
+
+func (p *syncBufferPool) Put(b *bytes.Buffer) {
+	if p.maxSize > 0 && b.Cap() > p.maxSize {
+		return
+	}
+	p.pool.Put(b)
+}
+
+// AcquireBuffer returns empty buffer from `Echo#BufferPool`. Buffer must be returned with `Echo#ReleaseBuffer`
+// when it (and slices of its content) is no longer used.
+func (e *Echo) AcquireBuffer() *bytes.Buffer {
+	if e.BufferPool == nil {
+		return new(bytes.Buffer)
+	}
+	b := e.BufferPool.Get()
+	b.Reset()
+	return b
+}
+
+// ReleaseBuffer returns buffer acquired with `Echo#AcquireBuffer` back to `Echo#BufferPool`.
+func (e *Echo) ReleaseBuffer(b *bytes.Buffer) {
+	if e.BufferPool != nil {
+		e.BufferPool.Put(b)
+	}
+}
@@ -0,0 +1,67 @@
+package echo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingBufferPool struct {
+	gets int
+	puts int
+}
+
+func (p *countingBufferPool) Get() *bytes.Buffer {
+	p.gets++
+	return bytes.NewBufferString("stale")
+}
+
+func (p *countingBufferPool) Put(b *bytes.Buffer) {
+	p.puts++
+}
+
+func TestNewBufferPool(t *testing.T) {
+	pool := NewBufferPool(1024)
+
+	b := pool.Get()
+	assert.NotNil(t, b)
+	pool.Put(b)
+}
+
+func TestNewBufferPool_doesNotRetainLargeBuffers(t *testing.T) {
+	pool := NewBufferPool(1024).(*syncBufferPool)
+	pool.pool.New = nil // empty pool returns nil
+
+	pool.Put(bytes.NewBuffer(make([]byte, 0, 4096)))
+
+	assert.Nil(t, pool.pool.Get())
+}
+
+func TestEcho_AcquireBuffer(t *testing.T) {
+	e := New()
+	pool := &countingBufferPool{}
+	e.BufferPool = pool
+
+	b := e.AcquireBuffer()
+	assert.Equal(t, 0, b.Len())
+	e.ReleaseBuffer(b)
+
+	assert.Equal(t, 1, pool.gets)
+	assert.Equal(t, 1, pool.puts)
+}
+
+func TestEcho_AcquireBufferWithoutPool(t *testing.T) {
+	e := New()
+	e.BufferPool = nil
+
+	b := e.AcquireBuffer()
+	assert.NotNil(t, b)
+	e.ReleaseBuffer(b)
+}
+
+func TestEcho_ClonesBufferPool(t *testing.T) {
+	e := New()
+	assert.NotNil(t, e.BufferPool)
+	assert.Equal(t, e.BufferPool, e.Clone().BufferPool)
+}
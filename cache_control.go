@@ -0,0 +1,144 @@
+package echo
+
+import (
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl describes caching policy of response. It is sent as `Cache-Control`, `Expires` and `Vary` headers.
+type CacheControl struct {
+	// Public allows shared caches to store response even when it would normally be non-cacheable.
+	Public bool
+	// Private allows only private (browser) caches to store response.
+	Private bool
+	// NoCache requires caches to revalidate response before every reuse.
+	NoCache bool
+	// NoStore forbids caches to store response.
+	NoStore bool
+	// NoTransform forbids intermediaries to transform response body.
+	NoTransform bool
+	// MustRevalidate forbids caches to reuse stale response without revalidation.
+	MustRevalidate bool
+	// ProxyRevalidate is `MustRevalidate` for shared caches.
+	ProxyRevalidate bool
+	// Immutable tells that response will not change while it is fresh.
+	Immutable bool
+	// MaxAge is how long response is fresh. Zero value does not send `max-age`, negative value sends `max-age=0`
+	// (same as `http.Cookie#MaxAge`).
+	MaxAge time.Duration
+	// SMaxAge is `MaxAge` for shared caches. Zero value does not send `s-maxage`.
+	SMaxAge time.Duration
+	// StaleWhileRevalidate is how long stale response can be reused while it is revalidated in background.
+	StaleWhileRevalidate time.Duration
+	// StaleIfError is how long stale response can be reused when revalidation fails.
+	StaleIfError time.Duration
+	// Vary are request headers response depends on. They are added to `Vary` header.
+	Vary []string
+}
+
+// RouteMetaCacheControl is route metadata key for caching policy (`CacheControl`) of route. Policy is applied to
+// successful (status code below 400) responses of route unless handler sets `Cache-Control` header itself. Policy is
+// resolved once when instance is frozen (see `Echo#Freeze`), routes without policy have no per request cost.
+// Example: `e.RouteMeta(e.GET("/logo.png", logo))[echo.RouteMetaCacheControl] = echo.CacheControl{Public: true, MaxAge: 24 * time.Hour}`
+const RouteMetaCacheControl = "echo.cache_control"
+
+// NoCacheControl is caching policy that forbids caching response and reusing it without revalidation.
+var NoCacheControl = CacheControl{NoCache: true, NoStore: true, MustRevalidate: true, MaxAge: -1}
+
+// String returns value of `Cache-Control` header for caching policy.
+func (cc CacheControl) String() string {
+	var d []string
+	flag := func(set bool, name string) {
+		if set {
+			d = append(d, name)
+		}
+	}
+	seconds := func(v time.Duration, name string) {
+		if v > 0 {
+			d = append(d, name+"="+strconv.FormatInt(int64(v/time.Second), 10))
+		}
+	}
+	flag(cc.Public, "public")
+	flag(cc.Private, "private")
+	flag(cc.NoCache, "no-cache")
+	flag(cc.NoStore, "no-store")
+	flag(cc.NoTransform, "no-transform")
+	if cc.MaxAge < 0 {
+		d = append(d, "max-age=0")
+	}
+	seconds(cc.MaxAge, "max-age")
+	seconds(cc.SMaxAge, "s-maxage")
+	flag(cc.MustRevalidate, "must-revalidate")
+	flag(cc.ProxyRevalidate, "proxy-revalidate")
+	flag(cc.Immutable, "immutable")
+	seconds(cc.StaleWhileRevalidate, "stale-while-revalidate")
+	seconds(cc.StaleIfError, "stale-if-error")
+	return strings.Join(d, ", ")
+}
+
+// WriteHeaders sets `Cache-Control` and `Expires` headers of caching policy to header and adds `Vary` headers not
+// already present. `Expires` is for HTTP/1.0 caches and is computed from `MaxAge` relative to `now`.
+func (cc CacheControl) WriteHeaders(h http.Header, now time.Time) {
+	if v := cc.String(); v != "" {
+		h.Set(HeaderCacheControl, v)
+	}
+	switch {
+	case cc.NoStore || cc.NoCache || cc.MaxAge < 0:
+		h.Set(HeaderExpires, "0")
+	case cc.MaxAge > 0:
+		h.Set(HeaderExpires, now.Add(cc.MaxAge).UTC().Format(http.TimeFormat))
+	}
+	for _, v := range cc.Vary {
+		if !headerHasToken(h, HeaderVary, v) {
+			h.Add(HeaderVary, v)
+		}
+	}
+}
+
+// headerHasToken checks if comma separated values of header contain token (case-insensitive).
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h[textproto.CanonicalMIMEHeaderKey(name)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *context) CacheControl(cc CacheControl) {
+	cc.WriteHeaders(c.response.Header(), time.Now())
+}
+
+func (c *context) NoCache() {
+	c.CacheControl(NoCacheControl)
+}
+
+// routeCacheControl returns caching policy from metadata `RouteMetaCacheControl` of route.
+func (e *Echo) routeCacheControl(r *Route) (CacheControl, bool) {
+	cc, ok := e.routeMeta[r][RouteMetaCacheControl].(CacheControl)
+	return cc, ok
+}
+
+// withRouteCacheControl wraps route handler to apply caching policy to response. Used when policy is resolved once
+// (see `Echo#Freeze`).
+func withRouteCacheControl(h HandlerFunc, cc CacheControl) HandlerFunc {
+	return func(c Context) error {
+		applyCacheControl(c.Response(), cc)
+		return h(c)
+	}
+}
+
+// applyCacheControl applies caching policy of route to response before it is written.
+func applyCacheControl(res *Response, cc CacheControl) {
+	res.Before(func() {
+		if res.Status >= http.StatusBadRequest || res.Header().Get(HeaderCacheControl) != "" {
+			return
+		}
+		cc.WriteHeaders(res.Header(), time.Now())
+	})
+}
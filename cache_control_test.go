@@ -0,0 +1,182 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl_String(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   CacheControl
+		expect string
+	}{
+		{
+			name:   "ok, empty",
+			expect: "",
+		},
+		{
+			name:   "ok, public immutable",
+			when:   CacheControl{Public: true, MaxAge: 365 * 24 * time.Hour, Immutable: true},
+			expect: "public, max-age=31536000, immutable",
+		},
+		{
+			name: "ok, shared cache with stale directives",
+			when: CacheControl{
+				Public:               true,
+				MaxAge:               time.Minute,
+				SMaxAge:              10 * time.Minute,
+				StaleWhileRevalidate: 30 * time.Second,
+				StaleIfError:         time.Hour,
+				ProxyRevalidate:      true,
+				NoTransform:          true,
+			},
+			expect: "public, no-transform, max-age=60, s-maxage=600, proxy-revalidate, stale-while-revalidate=30, stale-if-error=3600",
+		},
+		{
+			name:   "ok, no cache",
+			when:   NoCacheControl,
+			expect: "no-cache, no-store, max-age=0, must-revalidate",
+		},
+		{
+			name:   "ok, private",
+			when:   CacheControl{Private: true, NoCache: true},
+			expect: "private, no-cache",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, tc.when.String())
+		})
+	}
+}
+
+func TestCacheControl_WriteHeaders(t *testing.T) {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	h := http.Header{}
+	h.Set(HeaderVary, "Accept-Encoding, Origin")
+	CacheControl{Private: true, MaxAge: time.Hour, Vary: []string{"accept-encoding", HeaderCookie}}.WriteHeaders(h, now)
+	assert.Equal(t, "private, max-age=3600", h.Get(HeaderCacheControl))
+	assert.Equal(t, "Mon, 01 Mar 2021 11:00:00 GMT", h.Get(HeaderExpires))
+	assert.Equal(t, []string{"Accept-Encoding, Origin", HeaderCookie}, h[HeaderVary])
+
+	h = http.Header{}
+	NoCacheControl.WriteHeaders(h, now)
+	assert.Equal(t, "0", h.Get(HeaderExpires))
+}
+
+func TestContext_CacheControl(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	c.CacheControl(CacheControl{Public: true, MaxAge: time.Minute})
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(HeaderCacheControl))
+	assert.NotEmpty(t, rec.Header().Get(HeaderExpires))
+
+	c.NoCache()
+	assert.Equal(t, "no-cache, no-store, max-age=0, must-revalidate", rec.Header().Get(HeaderCacheControl))
+	assert.Equal(t, "0", rec.Header().Get(HeaderExpires))
+}
+
+func TestEcho_RouteMetaCacheControl(t *testing.T) {
+	newEcho := func() *Echo {
+		e := New()
+		e.RouteMeta(e.GET("/static", func(c Context) error {
+			return c.String(http.StatusOK, "static")
+		}))[RouteMetaCacheControl] = CacheControl{Public: true, MaxAge: time.Hour, Vary: []string{HeaderAcceptEncoding}}
+		e.RouteMeta(e.GET("/own", func(c Context) error {
+			c.NoCache()
+			return c.String(http.StatusOK, "own")
+		}))[RouteMetaCacheControl] = CacheControl{Public: true, MaxAge: time.Hour}
+		e.RouteMeta(e.GET("/error", func(c Context) error {
+			return ErrNotFound
+		}))[RouteMetaCacheControl] = CacheControl{Public: true, MaxAge: time.Hour}
+		e.GET("/plain", func(c Context) error {
+			return c.String(http.StatusOK, "plain")
+		})
+		return e
+	}
+
+	var testCases = []struct {
+		name         string
+		whenFrozen   bool
+		whenDebug    bool
+		whenPath     string
+		expectCache  string
+		expectVary   string
+		expectExpire bool
+	}{
+		{
+			name:         "ok, route policy is applied",
+			whenPath:     "/static",
+			expectCache:  "public, max-age=3600",
+			expectVary:   HeaderAcceptEncoding,
+			expectExpire: true,
+		},
+		{
+			name:         "ok, route policy is applied by frozen instance",
+			whenFrozen:   true,
+			whenPath:     "/static",
+			expectCache:  "public, max-age=3600",
+			expectVary:   HeaderAcceptEncoding,
+			expectExpire: true,
+		},
+		{
+			name:         "ok, handler headers take precedence",
+			whenPath:     "/own",
+			expectCache:  "no-cache, no-store, max-age=0, must-revalidate",
+			expectExpire: true,
+		},
+		{
+			name:     "ok, error responses are not cached by policy",
+			whenPath: "/error",
+		},
+		{
+			name:     "ok, route without policy",
+			whenPath: "/plain",
+		},
+		{
+			name:         "ok, route policy is applied in debug mode",
+			whenDebug:    true,
+			whenPath:     "/static",
+			expectCache:  "public, max-age=3600",
+			expectVary:   HeaderAcceptEncoding,
+			expectExpire: true,
+		},
+		{
+			name:        "ok, route without policy is not cached in debug mode",
+			whenDebug:   true,
+			whenPath:    "/plain",
+			expectCache: "no-store",
+		},
+		{
+			name:        "ok, error response is not cached in debug mode",
+			whenDebug:   true,
+			whenPath:    "/error",
+			expectCache: "no-store",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newEcho()
+			e.Debug = tc.whenDebug
+			if tc.whenFrozen {
+				assert.NoError(t, e.Freeze())
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenPath, nil))
+
+			assert.Equal(t, tc.expectCache, rec.Header().Get(HeaderCacheControl))
+			assert.Equal(t, tc.expectVary, rec.Header().Get(HeaderVary))
+			assert.Equal(t, tc.expectExpire, rec.Header().Get(HeaderExpires) != "")
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package echo
+
+import (
+	"hash/fnv"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/gommon/random"
+)
+
+type (
+	// CanaryRouteConfig defines the config for canary route registered with `Echo#Canary`.
+	CanaryRouteConfig struct {
+		// Percent is initial share (0-100) of requests served by canary handler. Share can be changed at runtime
+		// with `CanaryRoute#SetPercent`.
+		Percent float64
+
+		// CookieName is the name of cookie used for sticky assignment. When request has no such cookie a random
+		// value is assigned and cookie is set, so subsequent requests of the same client stay on the same variant.
+		// Optional. Default value "echo_canary".
+		CookieName string
+
+		// Header is the name of request header (i.e. "X-User-ID") used for sticky assignment. When set it takes
+		// precedence over cookie and requests without the header are assigned by random value.
+		// Optional.
+		Header string
+	}
+
+	// CanaryRoute is route with requests split between stable and canary handler.
+	CanaryRoute struct {
+		// Route is the registered route.
+		Route *Route
+
+		config      CanaryRouteConfig
+		basisPoints uint32
+		stable      canaryCounters
+		canary      canaryCounters
+	}
+
+	// CanaryStats contains metrics of one canary route variant.
+	CanaryStats struct {
+		// Requests is the number of requests served.
+		Requests uint64
+		// Errors is the number of requests where handler returned an error or responded with 5xx status.
+		Errors uint64
+		// Duration is total time spent in handler.
+		Duration time.Duration
+	}
+
+	canaryCounters struct {
+		requests uint64
+		errors   uint64
+		duration int64
+	}
+)
+
+// Canary route variants. See `CanaryVariant`.
+const (
+	CanaryVariantStable = "stable"
+	CanaryVariantCanary = "canary"
+)
+
+// CanaryBuckets is the number of buckets requests are distributed into by `CanaryBucket` (percentage resolution is
+// 0.01%).
+const CanaryBuckets = 10000
+
+const canaryVariantKey = "echo.canary_variant"
+
+// Canary registers a route for method and path with requests split between stable and canary handler. Requests
+// are assigned to variant by sticky cookie or header (see `CanaryRouteConfig`) and metrics are collected for each
+// variant (see `CanaryRoute#Stats`). Variant serving current request is available with `CanaryVariant`.
+//
+// Example:
+//
+//	cr := e.Canary(http.MethodGet, "/search", searchV1, searchV2, echo.CanaryRouteConfig{Percent: 5})
+//	...
+//	cr.SetPercent(50)
+func (e *Echo) Canary(method, path string, stable, canary HandlerFunc, config CanaryRouteConfig, middleware ...MiddlewareFunc) *CanaryRoute {
+	return e.canary(method, path, stable, canary, config, e.Add, middleware...)
+}
+
+func (common) canary(method, path string, stable, canary HandlerFunc, config CanaryRouteConfig, add func(string, string, HandlerFunc, ...MiddlewareFunc) *Route, middleware ...MiddlewareFunc) *CanaryRoute {
+	if stable == nil || canary == nil {
+		panic("echo: canary route requires stable and canary handler")
+	}
+	if config.CookieName == "" {
+		config.CookieName = "echo_canary"
+	}
+	cr := &CanaryRoute{config: config}
+	cr.SetPercent(config.Percent)
+	cr.Route = add(method, path, func(c Context) error {
+		if cr.isCanary(c) {
+			return cr.canary.serve(c, CanaryVariantCanary, canary)
+		}
+		return cr.stable.serve(c, CanaryVariantStable, stable)
+	}, middleware...)
+	return cr
+}
+
+// SetPercent sets share (0-100) of requests served by canary handler. Values out of range are clamped.
+func (cr *CanaryRoute) SetPercent(percent float64) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	atomic.StoreUint32(&cr.basisPoints, uint32(percent*CanaryBuckets/100))
+}
+
+// Percent returns share (0-100) of requests served by canary handler.
+func (cr *CanaryRoute) Percent() float64 {
+	return float64(atomic.LoadUint32(&cr.basisPoints)) * 100 / CanaryBuckets
+}
+
+// Stats returns metrics of stable and canary variant.
+func (cr *CanaryRoute) Stats() (stable CanaryStats, canary CanaryStats) {
+	return cr.stable.stats(), cr.canary.stats()
+}
+
+func (cr *CanaryRoute) isCanary(c Context) bool {
+	return CanaryBucket(c, cr.config.Header, cr.config.CookieName) < atomic.LoadUint32(&cr.basisPoints)
+}
+
+// CanaryBucket assigns request to one of `CanaryBuckets` buckets by value of request header (when header is not empty)
+// or cookie. Request without the value is assigned by random value and, when cookie is used, cookie with the value is
+// set so subsequent requests of the same client are assigned to the same bucket. Request is served by canary when its
+// bucket is lower than canary share in basis points. Used by `Echo#Canary` and `middleware.Canary`.
+func CanaryBucket(c Context, header, cookieName string) uint32 {
+	key := ""
+	if header != "" {
+		key = c.Request().Header.Get(header)
+	} else if cookie, err := c.Cookie(cookieName); err == nil {
+		key = cookie.Value
+	}
+	if key == "" {
+		key = random.String(32)
+		if header == "" {
+			c.SetCookie(&http.Cookie{
+				Name:     cookieName,
+				Value:    key,
+				Path:     "/",
+				HttpOnly: true,
+			})
+		}
+	}
+	return HashBucket(key, CanaryBuckets)
+}
+
+// HashBucket deterministically assigns key to one of n buckets.
+func HashBucket(key string, n uint32) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32() % n
+}
+
+func (cc *canaryCounters) serve(c Context, variant string, h HandlerFunc) error {
+	c.Set(canaryVariantKey, variant)
+	start := time.Now()
+	err := h(c)
+	atomic.AddInt64(&cc.duration, int64(time.Since(start)))
+	atomic.AddUint64(&cc.requests, 1)
+	if err != nil || c.Response().Status >= http.StatusInternalServerError {
+		atomic.AddUint64(&cc.errors, 1)
+	}
+	return err
+}
+
+func (cc *canaryCounters) stats() CanaryStats {
+	return CanaryStats{
+		Requests: atomic.LoadUint64(&cc.requests),
+		Errors:   atomic.LoadUint64(&cc.errors),
+		Duration: time.Duration(atomic.LoadInt64(&cc.duration)),
+	}
+}
+
+// CanaryVariant returns variant (`CanaryVariantStable` or `CanaryVariantCanary`) serving the request or empty string
+// when request is not served by route registered with `Echo#Canary`.
+func CanaryVariant(c Context) string {
+	v, _ := c.Get(canaryVariantKey).(string)
+	return v
+}
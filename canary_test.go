@@ -0,0 +1,108 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_Canary(t *testing.T) {
+	e := New()
+	cr := e.Canary(http.MethodGet, "/search", func(c Context) error {
+		return c.String(http.StatusOK, CanaryVariant(c))
+	}, func(c Context) error {
+		return errors.New("canary failed")
+	}, CanaryRouteConfig{Percent: 50, Header: "X-User-ID"})
+
+	assert.Equal(t, http.MethodGet, cr.Route.Method)
+	assert.Equal(t, "/search", cr.Route.Path)
+	assert.Equal(t, 50.0, cr.Percent())
+
+	serve := func(userID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 1000; i++ {
+		id := strconv.Itoa(i)
+		assert.Equal(t, serve(id), serve(id), "assignment must be sticky")
+	}
+
+	stable, canary := cr.Stats()
+	assert.Equal(t, uint64(2000), stable.Requests+canary.Requests)
+	assert.InDelta(t, 1000, canary.Requests, 200)
+	assert.Equal(t, canary.Requests, canary.Errors)
+	assert.Equal(t, uint64(0), stable.Errors)
+
+	cr.SetPercent(0)
+	assert.Equal(t, http.StatusOK, serve("1"))
+	cr.SetPercent(100)
+	assert.Equal(t, http.StatusInternalServerError, serve("1"))
+}
+
+func TestGroup_Canary_Cookie(t *testing.T) {
+	e := New()
+	g := e.Group("/api")
+	cr := g.Canary(http.MethodGet, "/search", func(c Context) error {
+		return c.String(http.StatusOK, CanaryVariant(c))
+	}, func(c Context) error {
+		return c.String(http.StatusOK, CanaryVariant(c))
+	}, CanaryRouteConfig{Percent: 100})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, CanaryVariantCanary, rec.Body.String())
+	assert.Contains(t, rec.Header().Get(HeaderSetCookie), "echo_canary=")
+
+	cr.SetPercent(0)
+	req = httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	req.Header.Set(HeaderCookie, "echo_canary=abc")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, CanaryVariantStable, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(HeaderSetCookie))
+}
+
+func TestEcho_Canary_Panics(t *testing.T) {
+	e := New()
+	assert.Panics(t, func() {
+		e.Canary(http.MethodGet, "/", nil, handlerFunc, CanaryRouteConfig{})
+	})
+}
+
+func TestCanaryBucket(t *testing.T) {
+	e := New()
+
+	// request without cookie gets random value stored in cookie
+	rec := httptest.NewRecorder()
+	bucket := CanaryBucket(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec), "", "canary")
+	assert.True(t, bucket < CanaryBuckets)
+	cookies := rec.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, "canary", cookies[0].Name)
+		assert.Equal(t, HashBucket(cookies[0].Value, CanaryBuckets), bucket)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+		rec = httptest.NewRecorder()
+		assert.Equal(t, bucket, CanaryBucket(e.NewContext(req, rec), "", "canary"))
+		assert.Empty(t, rec.Header().Get(HeaderSetCookie))
+	}
+
+	// header takes precedence and no cookie is set
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User-ID", "user-1")
+	rec = httptest.NewRecorder()
+	assert.Equal(t, HashBucket("user-1", CanaryBuckets), CanaryBucket(e.NewContext(req, rec), "X-User-ID", "canary"))
+	assert.Empty(t, rec.Header().Get(HeaderSetCookie))
+}
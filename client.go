@@ -0,0 +1,89 @@
+package echo
+
+import (
+	stdContext "context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Trace context headers (https://www.w3.org/TR/trace-context/)
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderTracestate  = "Tracestate"
+)
+
+// clientPropagatedHeaders are request headers copied from incoming request to outbound requests.
+var clientPropagatedHeaders = []string{HeaderTraceparent, HeaderTracestate}
+
+// clientTransport propagates request id, deadline and trace headers of the incoming request to outbound requests.
+// Values are captured when client is created so client can be used after context is released back to the pool.
+type clientTransport struct {
+	base      http.RoundTripper
+	header    http.Header
+	deadline  time.Time
+	requestID string
+}
+
+func (c *context) HTTPClient() *http.Client {
+	base := c.echo.HTTPClientTransport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &clientTransport{base: base, header: http.Header{}}
+	if c.request != nil {
+		for _, h := range clientPropagatedHeaders {
+			if v := c.request.Header.Get(h); v != "" {
+				t.header.Set(h, v)
+			}
+		}
+		t.requestID = c.request.Header.Get(HeaderXRequestID)
+		t.deadline, _ = c.request.Context().Deadline()
+	}
+	if t.requestID == "" && c.response != nil {
+		t.requestID = c.response.Header().Get(HeaderXRequestID)
+	}
+	return &http.Client{Transport: t}
+}
+
+// RoundTrip implements `http.RoundTripper`.
+func (t *clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	cancel := stdContext.CancelFunc(nil)
+	if _, ok := ctx.Deadline(); !ok && !t.deadline.IsZero() {
+		ctx, cancel = stdContext.WithDeadline(ctx, t.deadline)
+	}
+
+	r := req.Clone(ctx) // RoundTripper must not modify original request
+	for h, v := range t.header {
+		if r.Header.Get(h) == "" {
+			r.Header[h] = v
+		}
+	}
+	if t.requestID != "" && r.Header.Get(HeaderXRequestID) == "" {
+		r.Header.Set(HeaderXRequestID, t.requestID)
+	}
+
+	res, err := t.base.RoundTrip(r)
+	if cancel == nil {
+		return res, err
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelOnCloseBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// cancelOnCloseBody cancels request context when response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel stdContext.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
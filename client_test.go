@@ -0,0 +1,109 @@
+package echo
+
+import (
+	stdContext "context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_HTTPClient(t *testing.T) {
+	var received http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer upstream.Close()
+
+	var testCases = []struct {
+		name           string
+		whenHeaders    map[string]string
+		whenOutHeaders map[string]string
+		expectHeaders  map[string]string
+	}{
+		{
+			name: "ok, propagates request id and trace headers",
+			whenHeaders: map[string]string{
+				HeaderXRequestID:  "req-1",
+				HeaderTraceparent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				HeaderTracestate:  "congo=t61rcWkgMzE",
+			},
+			expectHeaders: map[string]string{
+				HeaderXRequestID:  "req-1",
+				HeaderTraceparent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				HeaderTracestate:  "congo=t61rcWkgMzE",
+			},
+		},
+		{
+			name:           "ok, outbound request headers are not overwritten",
+			whenHeaders:    map[string]string{HeaderXRequestID: "req-1"},
+			whenOutHeaders: map[string]string{HeaderXRequestID: "req-2"},
+			expectHeaders:  map[string]string{HeaderXRequestID: "req-2", HeaderTraceparent: ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			out, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+			for k, v := range tc.whenOutHeaders {
+				out.Header.Set(k, v)
+			}
+			res, err := c.HTTPClient().Do(out)
+			assert.NoError(t, err)
+			res.Body.Close()
+
+			for k, v := range tc.expectHeaders {
+				assert.Equal(t, v, received.Get(k))
+			}
+		})
+	}
+}
+
+func TestContext_HTTPClient_usesEchoTransport(t *testing.T) {
+	e := New()
+	e.HTTPClientTransport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "from-response", r.Header.Get(HeaderXRequestID))
+		return nil, stdContext.Canceled
+	})
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.Response().Header().Set(HeaderXRequestID, "from-response")
+
+	_, err := c.HTTPClient().Get("http://example.com")
+	assert.Error(t, err)
+}
+
+func TestContext_HTTPClient_propagatesDeadline(t *testing.T) {
+	e := New()
+	var outDeadline time.Time
+	e.HTTPClientTransport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		outDeadline, _ = r.Context().Deadline()
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("ok"))}, nil
+	})
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	done := c.WithTimeout(time.Minute)
+	defer done()
+	deadline, _ := c.Deadline()
+
+	res, err := c.HTTPClient().Get("http://example.com")
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, deadline, outDeadline)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,90 @@
+package echo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Client hint headers
+const (
+	HeaderAcceptCH                  = "Accept-CH"
+	HeaderDPR                       = "DPR"
+	HeaderSecCHDPR                  = "Sec-CH-DPR"
+	HeaderWidth                     = "Width"
+	HeaderSecCHWidth                = "Sec-CH-Width"
+	HeaderViewportWidth             = "Viewport-Width"
+	HeaderSecCHViewportWidth        = "Sec-CH-Viewport-Width"
+	HeaderDeviceMemory              = "Device-Memory"
+	HeaderSecCHDeviceMemory         = "Sec-CH-Device-Memory"
+	HeaderSaveData                  = "Save-Data"
+	HeaderSecCHUAMobile             = "Sec-CH-UA-Mobile"
+	HeaderSecCHPrefersColorScheme   = "Sec-CH-Prefers-Color-Scheme"
+	HeaderSecCHPrefersReducedMotion = "Sec-CH-Prefers-Reduced-Motion"
+)
+
+// ClientHints contains client hints sent by user agent. Zero values mean that hint was not sent or was invalid.
+type ClientHints struct {
+	// DPR is device pixel ratio (`Sec-CH-DPR` or legacy `DPR` header).
+	DPR float64
+	// Width is wanted resource width in physical pixels (`Sec-CH-Width` or legacy `Width` header).
+	Width int
+	// ViewportWidth is layout viewport width in CSS pixels (`Sec-CH-Viewport-Width` or legacy `Viewport-Width`).
+	ViewportWidth int
+	// DeviceMemory is approximate amount of device memory in GiB (`Sec-CH-Device-Memory` or `Device-Memory`).
+	DeviceMemory float64
+	// SaveData is true when user agent prefers reduced data usage (`Save-Data: on`).
+	SaveData bool
+	// Mobile is true when user agent prefers mobile experience (`Sec-CH-UA-Mobile: ?1`).
+	Mobile bool
+	// PrefersColorScheme is preferred color scheme, i.e. "light" or "dark" (`Sec-CH-Prefers-Color-Scheme`).
+	PrefersColorScheme string
+	// PrefersReducedMotion is true when user prefers reduced motion (`Sec-CH-Prefers-Reduced-Motion: reduce`).
+	PrefersReducedMotion bool
+}
+
+func (c *context) ClientHints() ClientHints {
+	h := c.request.Header
+	first := func(names ...string) string {
+		for _, n := range names {
+			if v := strings.TrimSpace(h.Get(n)); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	float := func(names ...string) float64 {
+		f, err := strconv.ParseFloat(first(names...), 64)
+		if err != nil || f < 0 {
+			return 0
+		}
+		return f
+	}
+	integer := func(names ...string) int {
+		i, err := strconv.Atoi(first(names...))
+		if err != nil || i < 0 {
+			return 0
+		}
+		return i
+	}
+	return ClientHints{
+		DPR:                  float(HeaderSecCHDPR, HeaderDPR),
+		Width:                integer(HeaderSecCHWidth, HeaderWidth),
+		ViewportWidth:        integer(HeaderSecCHViewportWidth, HeaderViewportWidth),
+		DeviceMemory:         float(HeaderSecCHDeviceMemory, HeaderDeviceMemory),
+		SaveData:             strings.EqualFold(first(HeaderSaveData), "on"),
+		Mobile:               first(HeaderSecCHUAMobile) == "?1",
+		PrefersColorScheme:   strings.ToLower(strings.Trim(first(HeaderSecCHPrefersColorScheme), `"`)),
+		PrefersReducedMotion: strings.EqualFold(strings.Trim(first(HeaderSecCHPrefersReducedMotion), `"`), "reduce"),
+	}
+}
+
+func (c *context) AcceptClientHints(hints ...string) {
+	if len(hints) == 0 {
+		return
+	}
+	h := c.response.Header()
+	h.Add(HeaderAcceptCH, strings.Join(hints, ", "))
+	for _, hint := range hints {
+		h.Add(HeaderVary, hint)
+	}
+}
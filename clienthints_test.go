@@ -0,0 +1,73 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_ClientHints(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenHeaders map[string]string
+		expect      ClientHints
+	}{
+		{
+			name: "ok, standard hints",
+			whenHeaders: map[string]string{
+				HeaderSecCHDPR:                  "2.5",
+				HeaderSecCHWidth:                "640",
+				HeaderSecCHViewportWidth:        "1280",
+				HeaderSecCHDeviceMemory:         "4",
+				HeaderSaveData:                  "on",
+				HeaderSecCHUAMobile:             "?1",
+				HeaderSecCHPrefersColorScheme:   `"dark"`,
+				HeaderSecCHPrefersReducedMotion: "reduce",
+			},
+			expect: ClientHints{
+				DPR:                  2.5,
+				Width:                640,
+				ViewportWidth:        1280,
+				DeviceMemory:         4,
+				SaveData:             true,
+				Mobile:               true,
+				PrefersColorScheme:   "dark",
+				PrefersReducedMotion: true,
+			},
+		},
+		{
+			name:        "ok, legacy hints",
+			whenHeaders: map[string]string{HeaderDPR: "2", HeaderWidth: "320", HeaderViewportWidth: "800"},
+			expect:      ClientHints{DPR: 2, Width: 320, ViewportWidth: 800},
+		},
+		{
+			name:        "ok, invalid values are ignored",
+			whenHeaders: map[string]string{HeaderSecCHDPR: "abc", HeaderSecCHWidth: "-1", HeaderSecCHUAMobile: "?0"},
+			expect:      ClientHints{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := New().NewContext(req, httptest.NewRecorder())
+
+			assert.Equal(t, tc.expect, c.ClientHints())
+		})
+	}
+}
+
+func TestContext_AcceptClientHints(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	c.AcceptClientHints(HeaderSecCHDPR, HeaderSecCHWidth)
+
+	assert.Equal(t, "Sec-CH-DPR, Sec-CH-Width", rec.Header().Get(HeaderAcceptCH))
+	assert.Equal(t, []string{HeaderSecCHDPR, HeaderSecCHWidth}, rec.Header()[HeaderVary])
+}
@@ -1,7 +1,7 @@
 package echo
 
 import (
-	"bytes"
+	stdContext "context"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -11,8 +11,10 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
+	"time"
 )
 
 type (
@@ -28,9 +30,53 @@ type (
 		// SetResponse sets `*Response`.
 		SetResponse(r *Response)
 
+		// DumpRequest returns dump of request for debugging. Sensitive headers (Authorization, Cookie etc.) are
+		// redacted and at most `maxBodySize` bytes of body are included. Body is read but restored so handlers can
+		// still read it whole.
+		DumpRequest(maxBodySize int64) (*RequestDump, error)
+
+		// WithTimeout replaces request context with child context that is canceled after timeout `d`. Returned
+		// `done` function cancels the child context and restores the original request. It must be called when bounded
+		// work is finished, usually with `defer`.
+		// Example:
+		//	done := c.WithTimeout(2 * time.Second)
+		//	defer done()
+		//	result, err := client.Fetch(c.Request().Context())
+		WithTimeout(d time.Duration) (done func())
+
+		// Deadline returns the deadline of request context. See `context.Context#Deadline`.
+		Deadline() (deadline time.Time, ok bool)
+
+		// Err returns the error of request context, i.e. `context.DeadlineExceeded` after timeout passed or
+		// `context.Canceled` after client disconnected. See `context.Context#Err`.
+		Err() error
+
+		// RequestBodyBytesRead returns the number of bytes read so far from the request body. Reads are counted
+		// only for requests served by `Echo#ServeHTTP`.
+		RequestBodyBytesRead() int64
+
+		// RequestBodyReadDuration returns total time spent reading the request body. Reads are measured only for
+		// requests served by `Echo#ServeHTTP`.
+		RequestBodyReadDuration() time.Duration
+
 		// Response returns `*Response`.
 		Response() *Response
 
+		// ClientHints returns client hints (i.e. DPR, Width, prefers-color-scheme) sent with request.
+		ClientHints() ClientHints
+
+		// AcceptClientHints asks user agent to send given client hints (i.e. `echo.HeaderSecCHDPR`) with subsequent
+		// requests by adding `Accept-CH` header to response. Hints are also added to `Vary` header as response
+		// content depends on them.
+		AcceptClientHints(hints ...string)
+
+		// CacheControl sets `Cache-Control`, `Expires` and `Vary` response headers of caching policy.
+		// Example: `c.CacheControl(echo.CacheControl{Private: true, MaxAge: 5 * time.Minute, Vary: []string{echo.HeaderAcceptEncoding}})`
+		CacheControl(cc CacheControl)
+
+		// NoCache sets response headers forbidding caching of response (`NoCacheControl`).
+		NoCache()
+
 		// IsTLS returns true if HTTP connection is TLS otherwise false.
 		IsTLS() bool
 
@@ -51,7 +97,8 @@ type (
 		// SetPath sets the registered path for the handler.
 		SetPath(p string)
 
-		// Param returns path parameter by name.
+		// Param returns path parameter by name. Value of unnamed wildcard (`/files/*`) is returned for name "*" and
+		// value of named wildcard (`/files/*filepath`) for its name.
 		Param(name string) string
 
 		// ParamNames returns path parameter names.
@@ -72,9 +119,29 @@ type (
 		// QueryParams returns the query parameters as `url.Values`.
 		QueryParams() url.Values
 
+		// QueryParamsWithPrefix returns query parameters named `prefix[key]` as `url.Values` keyed by `key`. i.e.
+		// for `?filter[name]=jon&filter[age]=20` and prefix "filter" result is `{"name": ["jon"], "age": ["20"]}`.
+		QueryParamsWithPrefix(prefix string) url.Values
+
 		// QueryString returns the URL query string.
 		QueryString() string
 
+		// MatrixParams returns matrix parameters (`;key=value`) of request path segment with given name. i.e. for
+		// `/cars;color=red;year=2020/models` and segment "cars" result is `{"color": ["red"], "year": ["2020"]}`.
+		// Parameters are parsed from escaped request path on every call and do not affect routing. Returns nil
+		// when path has no such segment.
+		MatrixParams(segment string) url.Values
+
+		// Pagination parses limit and offset or cursor query parameters of list request. Limit is capped to
+		// `PaginationConfig.MaxLimit` and invalid values are returned as 400 error. Zero value config fields
+		// default to `DefaultPaginationConfig`.
+		Pagination(config PaginationConfig) (Pagination, error)
+
+		// SetPaginationHeaders sets `X-Total-Count` header and `Link` header with next, prev, first and last
+		// pages of list response. Negative total means total is unknown. For cursor based pagination links
+		// are created from `Pagination.NextCursor` and `Pagination.PrevCursor`.
+		SetPaginationHeaders(p Pagination, total int64)
+
 		// FormValue returns the form field value for the provided name.
 		FormValue(name string) string
 
@@ -87,6 +154,14 @@ type (
 		// MultipartForm returns the multipart form.
 		MultipartForm() (*multipart.Form, error)
 
+		// MultipartReader returns reader for streaming multipart request body. Use it instead of
+		// `MultipartForm` to process large uploads without buffering them to memory or disk.
+		MultipartReader() (*multipart.Reader, error)
+
+		// Parts returns iterator over parts of streaming multipart request body. Reading part content larger
+		// than maxPartSize returns `ErrMultipartPartTooLarge`. Zero maxPartSize means no limit.
+		Parts(maxPartSize int64) *Parts
+
 		// Cookie returns the named cookie provided in the request.
 		Cookie(name string) (*http.Cookie, error)
 
@@ -96,20 +171,79 @@ type (
 		// Cookies returns the HTTP cookies sent with the request.
 		Cookies() []*http.Cookie
 
+		// SetSecureCookie adds a `Set-Cookie` header with value encrypted and authenticated with
+		// `Echo#SecureCookieKeys`. Cookie is HttpOnly, SameSite=Lax, has path "/" and is Secure for HTTPS requests
+		// (including requests forwarded by TLS terminating proxy, see `Context#Scheme`). Cookie has `Max-Age` of
+		// `Echo#SecureCookieMaxAge`.
+		SetSecureCookie(name, value string) error
+
+		// SecureCookie returns decrypted value of cookie set with `SetSecureCookie`. Returns `http.ErrNoCookie` when
+		// cookie is not sent, `ErrSecureCookieInvalid` when cookie value was tampered with and `ErrSecureCookieExpired`
+		// when cookie value is older than `Echo#SecureCookieMaxAge`.
+		SecureCookie(name string) (string, error)
+
 		// Get retrieves data from the context.
 		Get(key string) interface{}
 
 		// Set saves data in the context.
 		Set(key string, val interface{})
 
+		// Experiment returns variant request is assigned to in experiment `name` or empty string when request does
+		// not participate in experiment. Assignments are made by experiment middleware, see `middleware.Experiment`.
+		Experiment(name string) string
+
+		// SetExperiment assigns request to `variant` of experiment `name`.
+		SetExperiment(name, variant string)
+
+		// User returns the authenticated principal of the request or nil when request is not authenticated.
+		// Principal is set by authentication middleware, see `middleware.OIDC`.
+		User() *Principal
+
+		// SetUser sets the authenticated principal of the request.
+		SetUser(p *Principal)
+
 		// Bind binds the request body into provided type `i`. The default binder
 		// does it based on Content-Type header.
 		Bind(i interface{}) error
 
+		// BindPath binds only path parameters into provided type `i`.
+		BindPath(i interface{}) error
+
+		// BindQuery binds only query parameters into provided type `i`. Unlike `Bind` it does so regardless of
+		// request method.
+		BindQuery(i interface{}) error
+
+		// BindHeaders binds only request headers into provided type `i`.
+		BindHeaders(i interface{}) error
+
+		// BindBody binds only request body into provided type `i` based on Content-Type header.
+		BindBody(i interface{}) error
+
+		// BindForm binds only form data (URL query and form body) into provided type `i` regardless of request
+		// method. `time.Time` fields are parsed with `layout` tag or HTML input layouts, bool fields accept checkbox
+		// values ("on", "yes") and pointer fields stay nil for missing fields. See `DefaultBinder#BindForm`.
+		BindForm(i interface{}) error
+
+		// BindFrom binds only given request parts into provided type `i`. Sources are bound in given order and each
+		// source COULD override values binded by previous sources i.e. `c.BindFrom(&u, BindSourceBody, BindSourcePath)`
+		// makes sure that path parameters have priority over body fields.
+		BindFrom(i interface{}, sources ...BindSource) error
+
+		// BindStrict binds the request like `Bind` does but rejects JSON body fields and query parameters that
+		// do not exist in provided type `i` with `400 Bad Request` error. Binder must implement `StrictBinder`.
+		BindStrict(i interface{}) error
+
 		// Validate validates provided `i`. It is usually called after `Context#Bind()`.
-		// Validator must be registered using `Echo#Validator`.
+		// Validator must be registered using `Echo#Validator`. Scenarios from matched route metadata
+		// `RouteMetaValidationScenarios` are passed to validators implementing `ScenarioValidator`. Validators
+		// implementing `ContextValidator` are given the current context.
 		Validate(i interface{}) error
 
+		// ValidateScenario validates provided `i` like `Validate` does with given scenarios (i.e. "create", "update")
+		// passed to validator implementing `ScenarioValidator`. When no scenarios are given, scenarios from matched
+		// route metadata `RouteMetaValidationScenarios` are used.
+		ValidateScenario(i interface{}, scenarios ...string) error
+
 		// Render renders a template with data and sends a text/html response with status
 		// code. Renderer must be registered using `Echo.Renderer`.
 		Render(code int, name string, data interface{}) error
@@ -120,6 +254,34 @@ type (
 		// HTMLBlob sends an HTTP blob response with status code.
 		HTMLBlob(code int, b []byte) error
 
+		// HTMLSafe sends an HTML response composed of fragments with status code. `SafeHTML` fragments are written
+		// as is, `UntrustedHTML` fragments are sanitized with `Echo#HTMLPolicy` and all other values are escaped.
+		// Example: `c.HTMLSafe(http.StatusOK, echo.SafeHTML("<h1>"), title, echo.SafeHTML("</h1>"), echo.UntrustedHTML(comment))`
+		HTMLSafe(code int, fragments ...interface{}) error
+
+		// IsHTMX returns true if request was made by htmx (`HX-Request: true` header).
+		IsHTMX() bool
+
+		// HTMXRequest returns headers sent by htmx with request.
+		HTMXRequest() HTMXRequest
+
+		// HTMXTrigger adds client side event triggered by htmx when response is received (`HX-Trigger` header).
+		// Events with non-nil `detail` are sent as JSON object with detail as event value.
+		HTMXTrigger(event string, detail interface{}) error
+
+		// HTMXRedirect makes htmx do a client side redirect to url (`HX-Redirect` header). Requests not made by htmx
+		// are redirected with 303 status code.
+		HTMXRedirect(url string) error
+
+		// HTMXPushURL makes htmx push url into browser history (`HX-Push-Url` header).
+		HTMXPushURL(url string)
+
+		// RenderHTMX renders `partial` template for htmx requests and `page` template for all other requests,
+		// including boosted and history restore requests that swap the whole page. `Vary: HX-Request` is added to
+		// response so caches do not mix up both variants.
+		// Example: `c.RenderHTMX(http.StatusOK, "contacts.html", "contacts_rows", contacts)`
+		RenderHTMX(code int, page, partial string, data interface{}) error
+
 		// String sends a string response with status code.
 		String(code int, s string) error
 
@@ -155,9 +317,20 @@ type (
 		// Stream sends a streaming response with status code and content type.
 		Stream(code int, contentType string, r io.Reader) error
 
-		// File sends a response with the content of the file.
+		// File sends a response with the content of the file. When `Echo#FileOffload` is configured, file
+		// is sent by reverse proxy instead.
 		File(file string) error
 
+		// ServeContent sends content with support for range requests (`Range`, `If-Range`) and conditional
+		// requests (`If-Modified-Since` etc.) like `File` does for files. Content type is detected from name
+		// extension or content when `Content-Type` header is not set. Zero modtime omits `Last-Modified` header.
+		// See `http.ServeContent`.
+		ServeContent(name string, modtime time.Time, content io.ReadSeeker) error
+
+		// ServeContentAt implements `ServeContent` for content of known size that is not seekable but supports
+		// reading at offset (i.e. object in object storage) so ranges are read without buffering whole content.
+		ServeContentAt(name string, modtime time.Time, size int64, content io.ReaderAt) error
+
 		// Attachment sends a response as attachment, prompting client to save the
 		// file.
 		Attachment(file string, name string) error
@@ -168,12 +341,22 @@ type (
 		// NoContent sends a response with no body and a status code.
 		NoContent(code int) error
 
+		// EvaluatePreconditions evaluates conditional request headers (`If-Match`, `If-Unmodified-Since`,
+		// `If-None-Match` and `If-Modified-Since`) against current entity tag (i.e. `"v1"` or `W/"v1"`, unquoted
+		// tag is quoted) and modification time of resource according to RFC 9110 section 13.2.2. When ok is false
+		// request must not be processed and status (304 Not Modified or 412 Precondition Failed) should be sent.
+		// Empty etag and zero lastModified mean resource does not exist or value is unknown.
+		EvaluatePreconditions(etag string, lastModified time.Time) (status int, ok bool)
+
 		// Redirect redirects the request to a provided URL with status code.
 		Redirect(code int, url string) error
 
 		// Error invokes the registered HTTP error handler. Generally used by middleware.
 		Error(err error)
 
+		// Route returns the route matched by router or nil when request did not match any registered route.
+		Route() *Route
+
 		// Handler returns the matched handler by router.
 		Handler() HandlerFunc
 
@@ -189,6 +372,23 @@ type (
 		// Echo returns the `Echo` instance.
 		Echo() *Echo
 
+		// Go runs fn in a new goroutine. Context given to fn keeps values of request context (i.e. trace context),
+		// request id (see `RequestIDFromContext`) and logger (see `LoggerFromContext`), is not canceled when request
+		// finishes and is canceled when server shutdown deadline passes before background work has finished.
+		// Panics in fn are recovered and logged. `Echo#Shutdown` waits for started goroutines to finish.
+		Go(fn func(ctx stdContext.Context))
+
+		// HTTPClient returns `*http.Client` for outbound (service to service) requests. Client propagates request id
+		// (`X-Request-ID`), trace context headers (`Traceparent`, `Tracestate`) and deadline of the current request to
+		// outbound requests unless outbound request sets them itself. Client uses `Echo#HTTPClientTransport`.
+		HTTPClient() *http.Client
+
+		// Clone returns detached copy of the context that is safe to use in goroutines after handler has returned
+		// and context has been released back to the pool. Clone has its own copy of store, path parameters and
+		// request (without body). Request context of the clone keeps values of the original but is never canceled.
+		// Responses written to the clone are discarded.
+		Clone() Context
+
 		// Reset resets the context after request completes. It must be called along
 		// with `Echo#AcquireContext()` and `Echo#ReleaseContext()`.
 		// See `Echo#ServeHTTP()`
@@ -207,9 +407,61 @@ type (
 		echo     *Echo
 		logger   Logger
 		lock     sync.RWMutex
+		body     *countingBody
+		version  uint64 // incremented when context is released back to the pool
+	}
+
+	// countingBody wraps request body and counts bytes read and time spent reading.
+	countingBody struct {
+		io.ReadCloser
+		n int64
+		d time.Duration
 	}
 )
 
+// RouteMetaValidationScenarios is route metadata key for default validation scenarios (`[]string`) that
+// `Context#Validate` and `Context#ValidateScenario` (called without scenarios) use.
+const RouteMetaValidationScenarios = "echo.validation_scenarios"
+
+// RouteMetaRequiredHeaders is route metadata key for request headers (`[]string`) that route requires. Required
+// headers are enforced by `middleware.RequiredHeaders`.
+// Example: `e.RouteMeta(e.POST("/payments", pay))[echo.RouteMetaRequiredHeaders] = []string{"Idempotency-Key"}`
+const RouteMetaRequiredHeaders = "echo.required_headers"
+
+// RouteMetaRequiredScopes is route metadata key for scopes (`[]string`) principal must be granted to access the
+// route. Scopes are enforced by `middleware.RequireScopes`.
+// Example: `e.RouteMeta(e.DELETE("/orders/:id", cancel))[echo.RouteMetaRequiredScopes] = []string{"orders:write"}`
+const RouteMetaRequiredScopes = "echo.required_scopes"
+
+// RouteMetaRequiredPermission is route metadata key for permission (`string`) principal must have to access the
+// route. Permission is enforced by `middleware.RequirePermission`.
+// Example: `e.RouteMeta(e.GET("/reports", reports))[echo.RouteMetaRequiredPermission] = "reports.view"`
+const RouteMetaRequiredPermission = "echo.required_permission"
+
+// RouteMetaPriorityClass is route metadata key for name of priority class (`string`) route requests are scheduled
+// in by `middleware.PriorityScheduler`.
+// Example: `e.RouteMeta(e.GET("/health", health))[echo.RouteMetaPriorityClass] = "critical"`
+const RouteMetaPriorityClass = "echo.priority_class"
+
+// experimentsKey is the context store key for experiment assignments (`map[string]string`).
+const experimentsKey = "echo.experiments"
+
+// userKey is the context store key for authenticated principal (`*Principal`).
+const userKey = "echo.user"
+
+// htmxTriggersKey is the context store key for events set with `Context#HTMXTrigger` (`*htmxTriggers`).
+const htmxTriggersKey = "echo.htmx_triggers"
+
+// Route metadata keys (`bool` values) annotating that route handler uses `Context#Render` or `Context#Validate`.
+// `Echo#VerifyRoutes` reports annotated routes when corresponding component is not configured so misconfiguration
+// is detected at startup instead of on first request. Routes with `RouteMetaValidationScenarios` are considered
+// to use Validator.
+// Example: `e.RouteMeta(e.GET("/", index))[echo.RouteMetaUsesRenderer] = true`
+const (
+	RouteMetaUsesRenderer  = "echo.uses_renderer"
+	RouteMetaUsesValidator = "echo.uses_validator"
+)
+
 const (
 	defaultMemory = 32 << 20 // 32 MB
 	indexPage     = "index.html"
@@ -227,6 +479,57 @@ func (c *context) Request() *http.Request {
 	return c.request
 }
 
+func (c *context) WithTimeout(d time.Duration) (done func()) {
+	original := c.request
+	ctx, cancel := stdContext.WithTimeout(original.Context(), d)
+	c.request = original.WithContext(ctx)
+	return func() {
+		cancel()
+		c.request = original
+	}
+}
+
+func (c *context) Deadline() (deadline time.Time, ok bool) {
+	return c.request.Context().Deadline()
+}
+
+func (c *context) Err() error {
+	return c.request.Context().Err()
+}
+
+func (c *context) RequestBodyBytesRead() int64 {
+	if c.body == nil {
+		return 0
+	}
+	return c.body.n
+}
+
+func (c *context) RequestBodyReadDuration() time.Duration {
+	if c.body == nil {
+		return 0
+	}
+	return c.body.d
+}
+
+// countBody installs counting wrapper to the request body. Wrapper is allocated per request as request can outlive
+// the pooled context (i.e. when used by goroutines started by handler).
+func (c *context) countBody() {
+	r := c.request
+	if r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+	c.body = &countingBody{ReadCloser: r.Body}
+	r.Body = c.body
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := b.ReadCloser.Read(p)
+	b.d += time.Since(start)
+	b.n += int64(n)
+	return n, err
+}
+
 func (c *context) SetRequest(r *http.Request) {
 	c.request = r
 }
@@ -358,10 +661,44 @@ func (c *context) QueryParams() url.Values {
 	return c.query
 }
 
+func (c *context) QueryParamsWithPrefix(prefix string) url.Values {
+	return QueryParamsWithPrefix(c.QueryParams(), prefix)
+}
+
 func (c *context) QueryString() string {
 	return c.request.URL.RawQuery
 }
 
+func (c *context) MatrixParams(segment string) url.Values {
+	for _, s := range strings.Split(c.request.URL.EscapedPath(), "/") {
+		parts := strings.Split(s, ";")
+		if name, err := url.PathUnescape(parts[0]); err != nil || name != segment {
+			continue
+		}
+		params := url.Values{}
+		for _, p := range parts[1:] {
+			if p == "" {
+				continue
+			}
+			key, value := p, ""
+			if i := strings.IndexByte(p, '='); i >= 0 {
+				key, value = p[:i], p[i+1:]
+			}
+			key, err := url.PathUnescape(key)
+			if err != nil {
+				continue
+			}
+			value, err = url.PathUnescape(value)
+			if err != nil {
+				continue
+			}
+			params.Add(key, value)
+		}
+		return params
+	}
+	return nil
+}
+
 func (c *context) FormValue(name string) string {
 	return c.request.FormValue(name)
 }
@@ -405,6 +742,31 @@ func (c *context) Cookies() []*http.Cookie {
 	return c.request.Cookies()
 }
 
+func (c *context) SetSecureCookie(name, value string) error {
+	v, err := c.echo.EncryptCookieValue(name, value)
+	if err != nil {
+		return err
+	}
+	c.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    v,
+		Path:     "/",
+		MaxAge:   int(c.echo.SecureCookieMaxAge / time.Second),
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+func (c *context) SecureCookie(name string) (string, error) {
+	cookie, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return c.echo.DecryptCookieValue(name, cookie.Value)
+}
+
 func (c *context) Get(key string) interface{} {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -421,22 +783,140 @@ func (c *context) Set(key string, val interface{}) {
 	c.store[key] = val
 }
 
+func (c *context) Experiment(name string) string {
+	experiments, _ := c.Get(experimentsKey).(map[string]string)
+	return experiments[name]
+}
+
+func (c *context) SetExperiment(name, variant string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.store == nil {
+		c.store = make(Map)
+	}
+	// copy on write so clones of the context do not share assignments
+	old, _ := c.store[experimentsKey].(map[string]string)
+	experiments := make(map[string]string, len(old)+1)
+	for k, v := range old {
+		experiments[k] = v
+	}
+	experiments[name] = variant
+	c.store[experimentsKey] = experiments
+}
+
+func (c *context) User() *Principal {
+	p, _ := c.Get(userKey).(*Principal)
+	return p
+}
+
+func (c *context) SetUser(p *Principal) {
+	c.Set(userKey, p)
+}
+
 func (c *context) Bind(i interface{}) error {
 	return c.echo.Binder.Bind(i, c)
 }
 
+// sourceBinder returns Echo#Binder when it supports binding by source and DefaultBinder otherwise
+func (c *context) sourceBinder() SourceBinder {
+	if sb, ok := c.echo.Binder.(SourceBinder); ok {
+		return sb
+	}
+	return &DefaultBinder{}
+}
+
+func (c *context) BindPath(i interface{}) error {
+	return c.BindFrom(i, BindSourcePath)
+}
+
+func (c *context) BindQuery(i interface{}) error {
+	return c.BindFrom(i, BindSourceQuery)
+}
+
+func (c *context) BindHeaders(i interface{}) error {
+	return c.BindFrom(i, BindSourceHeaders)
+}
+
+func (c *context) BindBody(i interface{}) error {
+	return c.BindFrom(i, BindSourceBody)
+}
+
+func (c *context) BindForm(i interface{}) error {
+	fb, ok := c.echo.Binder.(FormBinder)
+	if !ok {
+		fb = &DefaultBinder{}
+	}
+	if err := fb.BindForm(c, i); err != nil {
+		return err
+	}
+	return normalize(i)
+}
+
+func (c *context) BindFrom(i interface{}, sources ...BindSource) error {
+	b := c.sourceBinder()
+	for _, source := range sources {
+		var err error
+		switch source {
+		case BindSourcePath:
+			err = b.BindPathParams(c, i)
+		case BindSourceQuery:
+			err = b.BindQueryParams(c, i)
+		case BindSourceHeaders:
+			err = b.BindHeaders(c, i)
+		case BindSourceBody:
+			err = b.BindBody(c, i)
+		default:
+			err = fmt.Errorf("echo: unknown bind source: %d", source)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return normalize(i)
+}
+
+func (c *context) BindStrict(i interface{}) error {
+	sb, ok := c.echo.Binder.(StrictBinder)
+	if !ok {
+		return ErrStrictBindingNotSupported
+	}
+	return sb.BindStrict(i, c)
+}
+
 func (c *context) Validate(i interface{}) error {
+	return c.ValidateScenario(i)
+}
+
+func (c *context) ValidateScenario(i interface{}, scenarios ...string) error {
 	if c.echo.Validator == nil {
 		return ErrValidatorNotRegistered
 	}
-	return c.echo.Validator.Validate(i)
+	if len(scenarios) == 0 {
+		scenarios, _ = c.echo.RouteMeta(c.Route())[RouteMetaValidationScenarios].([]string)
+	}
+	if len(scenarios) == 0 {
+		if cv, ok := c.echo.Validator.(ContextValidator); ok {
+			return cv.ValidateContext(c, i)
+		}
+		return c.echo.Validator.Validate(i)
+	}
+	sv, ok := c.echo.Validator.(ScenarioValidator)
+	if !ok {
+		return ErrValidatorScenariosNotSupported
+	}
+	return sv.ValidateScenarios(i, scenarios...)
 }
 
 func (c *context) Render(code int, name string, data interface{}) (err error) {
 	if c.echo.Renderer == nil {
 		return ErrRendererNotRegistered
 	}
-	buf := new(bytes.Buffer)
+	buf := c.echo.AcquireBuffer()
+	defer c.echo.ReleaseBuffer(buf)
 	if err = c.echo.Renderer.Render(buf, name, data, c); err != nil {
 		return
 	}
@@ -563,6 +1043,9 @@ func (c *context) Stream(code int, contentType string, r io.Reader) (err error)
 }
 
 func (c *context) File(file string) (err error) {
+	if c.offloadFile(file) {
+		return nil
+	}
 	f, err := os.Open(file)
 	if err != nil {
 		return NotFoundHandler(c)
@@ -585,6 +1068,15 @@ func (c *context) File(file string) (err error) {
 	return
 }
 
+func (c *context) ServeContent(name string, modtime time.Time, content io.ReadSeeker) error {
+	http.ServeContent(c.Response(), c.Request(), name, modtime, content)
+	return nil
+}
+
+func (c *context) ServeContentAt(name string, modtime time.Time, size int64, content io.ReaderAt) error {
+	return c.ServeContent(name, modtime, io.NewSectionReader(content, 0, size))
+}
+
 func (c *context) Attachment(file, name string) error {
 	return c.contentDisposition(file, name, "attachment")
 }
@@ -620,6 +1112,133 @@ func (c *context) Echo() *Echo {
 	return c.echo
 }
 
+func (c *context) Clone() Context {
+	c.lock.RLock()
+	store := make(Map, len(c.store))
+	for k, v := range c.store {
+		store[k] = v
+	}
+	c.lock.RUnlock()
+
+	var req *http.Request
+	if c.request != nil {
+		req = c.request.Clone(detachedContext{parent: c.request.Context()})
+		req.Body = http.NoBody
+	}
+	return &context{
+		request:  req,
+		response: NewResponse(&discardResponseWriter{header: http.Header{}}, c.echo),
+		path:     c.path,
+		pnames:   append([]string(nil), c.pnames...),
+		pvalues:  append([]string(nil), c.pvalues...),
+		handler:  c.handler,
+		store:    store,
+		echo:     c.echo,
+		logger:   c.logger,
+	}
+}
+
+func (c *context) Go(fn func(ctx stdContext.Context)) {
+	values := stdContext.Background()
+	requestID := ""
+	if c.request != nil {
+		values = c.request.Context()
+		requestID = c.request.Header.Get(HeaderXRequestID)
+	}
+	if requestID == "" {
+		requestID = c.response.Header().Get(HeaderXRequestID)
+	}
+	logger := c.Logger()
+	ctx := stdContext.WithValue(backgroundContext{Context: c.echo.backgroundCtx, values: values}, requestIDContextKey, requestID)
+	ctx = stdContext.WithValue(ctx, loggerContextKey, logger)
+
+	c.echo.background.Add(1)
+	go func() {
+		defer c.echo.background.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				stack := make([]byte, 4<<10)
+				length := runtime.Stack(stack, false)
+				logger.Errorf("[PANIC RECOVER] background goroutine (request id %q): %v %s", requestID, r, stack[:length])
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+type backgroundContextKey int
+
+const (
+	requestIDContextKey backgroundContextKey = iota
+	loggerContextKey
+)
+
+// RequestIDFromContext returns request id of the request that started background work with `Context#Go`.
+func RequestIDFromContext(ctx stdContext.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// LoggerFromContext returns logger of the request that started background work with `Context#Go`. Returns nil
+// when ctx was not created by `Context#Go`.
+func LoggerFromContext(ctx stdContext.Context) Logger {
+	l, _ := ctx.Value(loggerContextKey).(Logger)
+	return l
+}
+
+// backgroundContext is canceled by embedded context and looks up values from request context.
+type backgroundContext struct {
+	stdContext.Context
+	values stdContext.Context
+}
+
+func (b backgroundContext) Value(key interface{}) interface{} {
+	return b.values.Value(key)
+}
+
+// detachedContext keeps values of parent context but is never canceled and has no deadline.
+type detachedContext struct {
+	parent stdContext.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// discardResponseWriter is response writer of cloned context that discards everything written to it.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
+
+func (c *context) Route() *Route {
+	if c.request == nil {
+		return nil
+	}
+	return c.echo.findRouter(c.request.Host).routes[c.request.Method+c.path]
+}
+
 func (c *context) Handler() HandlerFunc {
 	return c.handler
 }
@@ -649,6 +1268,7 @@ func (c *context) Reset(r *http.Request, w http.ResponseWriter) {
 	c.path = ""
 	c.pnames = nil
 	c.logger = nil
+	c.body = nil
 	// NOTE: Don't reset because it has to have length c.echo.maxParam at all times
 	for i := 0; i < *c.echo.maxParam; i++ {
 		c.pvalues[i] = ""
@@ -0,0 +1,522 @@
+package echo
+
+import (
+	stdContext "context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// guardedContext wraps pooled context when `Echo#GuardContextPool` is enabled. Every method checks that context
+// has not been released back to the pool since guard was created and panics otherwise.
+type guardedContext struct {
+	context *context
+	version uint64
+}
+
+var _ Context = (*guardedContext)(nil)
+
+func (g *guardedContext) check() {
+	if g.version != atomic.LoadUint64(&g.context.version) {
+		panic("echo: context used after request was finished and context was released back to the pool. " +
+			"Use Context#Clone to pass context to goroutines that outlive the handler")
+	}
+}
+
+func (g *guardedContext) Request() *http.Request {
+	g.check()
+	return g.context.Request()
+}
+
+func (g *guardedContext) SetRequest(r *http.Request) {
+	g.check()
+	g.context.SetRequest(r)
+}
+
+func (g *guardedContext) SetResponse(r *Response) {
+	g.check()
+	g.context.SetResponse(r)
+}
+
+func (g *guardedContext) DumpRequest(maxBodySize int64) (*RequestDump, error) {
+	g.check()
+	return g.context.DumpRequest(maxBodySize)
+}
+
+func (g *guardedContext) WithTimeout(d time.Duration) (done func()) {
+	g.check()
+	return g.context.WithTimeout(d)
+}
+
+func (g *guardedContext) Deadline() (deadline time.Time, ok bool) {
+	g.check()
+	return g.context.Deadline()
+}
+
+func (g *guardedContext) Err() error {
+	g.check()
+	return g.context.Err()
+}
+
+func (g *guardedContext) RequestBodyBytesRead() int64 {
+	g.check()
+	return g.context.RequestBodyBytesRead()
+}
+
+func (g *guardedContext) RequestBodyReadDuration() time.Duration {
+	g.check()
+	return g.context.RequestBodyReadDuration()
+}
+
+func (g *guardedContext) Response() *Response {
+	g.check()
+	return g.context.Response()
+}
+
+func (g *guardedContext) CacheControl(cc CacheControl) {
+	g.check()
+	g.context.CacheControl(cc)
+}
+
+func (g *guardedContext) NoCache() {
+	g.check()
+	g.context.NoCache()
+}
+
+func (g *guardedContext) IsTLS() bool {
+	g.check()
+	return g.context.IsTLS()
+}
+
+func (g *guardedContext) IsWebSocket() bool {
+	g.check()
+	return g.context.IsWebSocket()
+}
+
+func (g *guardedContext) Scheme() string {
+	g.check()
+	return g.context.Scheme()
+}
+
+func (g *guardedContext) RealIP() string {
+	g.check()
+	return g.context.RealIP()
+}
+
+func (g *guardedContext) Path() string {
+	g.check()
+	return g.context.Path()
+}
+
+func (g *guardedContext) SetPath(p string) {
+	g.check()
+	g.context.SetPath(p)
+}
+
+func (g *guardedContext) Param(name string) string {
+	g.check()
+	return g.context.Param(name)
+}
+
+func (g *guardedContext) ParamNames() []string {
+	g.check()
+	return g.context.ParamNames()
+}
+
+func (g *guardedContext) SetParamNames(names ...string) {
+	g.check()
+	g.context.SetParamNames(names...)
+}
+
+func (g *guardedContext) ParamValues() []string {
+	g.check()
+	return g.context.ParamValues()
+}
+
+func (g *guardedContext) SetParamValues(values ...string) {
+	g.check()
+	g.context.SetParamValues(values...)
+}
+
+func (g *guardedContext) QueryParam(name string) string {
+	g.check()
+	return g.context.QueryParam(name)
+}
+
+func (g *guardedContext) QueryParams() url.Values {
+	g.check()
+	return g.context.QueryParams()
+}
+
+func (g *guardedContext) QueryParamsWithPrefix(prefix string) url.Values {
+	g.check()
+	return g.context.QueryParamsWithPrefix(prefix)
+}
+
+func (g *guardedContext) QueryString() string {
+	g.check()
+	return g.context.QueryString()
+}
+
+func (g *guardedContext) MatrixParams(segment string) url.Values {
+	g.check()
+	return g.context.MatrixParams(segment)
+}
+
+func (g *guardedContext) Pagination(config PaginationConfig) (Pagination, error) {
+	g.check()
+	return g.context.Pagination(config)
+}
+
+func (g *guardedContext) SetPaginationHeaders(p Pagination, total int64) {
+	g.check()
+	g.context.SetPaginationHeaders(p, total)
+}
+
+func (g *guardedContext) FormValue(name string) string {
+	g.check()
+	return g.context.FormValue(name)
+}
+
+func (g *guardedContext) FormParams() (url.Values, error) {
+	g.check()
+	return g.context.FormParams()
+}
+
+func (g *guardedContext) FormFile(name string) (*multipart.FileHeader, error) {
+	g.check()
+	return g.context.FormFile(name)
+}
+
+func (g *guardedContext) MultipartForm() (*multipart.Form, error) {
+	g.check()
+	return g.context.MultipartForm()
+}
+
+func (g *guardedContext) MultipartReader() (*multipart.Reader, error) {
+	g.check()
+	return g.context.MultipartReader()
+}
+
+func (g *guardedContext) Parts(maxPartSize int64) *Parts {
+	g.check()
+	return g.context.Parts(maxPartSize)
+}
+
+func (g *guardedContext) Cookie(name string) (*http.Cookie, error) {
+	g.check()
+	return g.context.Cookie(name)
+}
+
+func (g *guardedContext) SetCookie(cookie *http.Cookie) {
+	g.check()
+	g.context.SetCookie(cookie)
+}
+
+func (g *guardedContext) Cookies() []*http.Cookie {
+	g.check()
+	return g.context.Cookies()
+}
+
+func (g *guardedContext) SetSecureCookie(name, value string) error {
+	g.check()
+	return g.context.SetSecureCookie(name, value)
+}
+
+func (g *guardedContext) SecureCookie(name string) (string, error) {
+	g.check()
+	return g.context.SecureCookie(name)
+}
+
+func (g *guardedContext) Get(key string) interface{} {
+	g.check()
+	return g.context.Get(key)
+}
+
+func (g *guardedContext) Set(key string, val interface{}) {
+	g.check()
+	g.context.Set(key, val)
+}
+
+func (g *guardedContext) Bind(i interface{}) error {
+	g.check()
+	return g.context.Bind(i)
+}
+
+func (g *guardedContext) BindPath(i interface{}) error {
+	g.check()
+	return g.context.BindPath(i)
+}
+
+func (g *guardedContext) BindQuery(i interface{}) error {
+	g.check()
+	return g.context.BindQuery(i)
+}
+
+func (g *guardedContext) BindHeaders(i interface{}) error {
+	g.check()
+	return g.context.BindHeaders(i)
+}
+
+func (g *guardedContext) BindBody(i interface{}) error {
+	g.check()
+	return g.context.BindBody(i)
+}
+
+func (g *guardedContext) BindForm(i interface{}) error {
+	g.check()
+	return g.context.BindForm(i)
+}
+
+func (g *guardedContext) BindFrom(i interface{}, sources ...BindSource) error {
+	g.check()
+	return g.context.BindFrom(i, sources...)
+}
+
+func (g *guardedContext) BindStrict(i interface{}) error {
+	g.check()
+	return g.context.BindStrict(i)
+}
+
+func (g *guardedContext) Validate(i interface{}) error {
+	g.check()
+	return g.context.Validate(i)
+}
+
+func (g *guardedContext) ValidateScenario(i interface{}, scenarios ...string) error {
+	g.check()
+	return g.context.ValidateScenario(i, scenarios...)
+}
+
+func (g *guardedContext) Render(code int, name string, data interface{}) error {
+	g.check()
+	return g.context.Render(code, name, data)
+}
+
+func (g *guardedContext) HTML(code int, html string) error {
+	g.check()
+	return g.context.HTML(code, html)
+}
+
+func (g *guardedContext) HTMLBlob(code int, b []byte) error {
+	g.check()
+	return g.context.HTMLBlob(code, b)
+}
+
+func (g *guardedContext) HTMLSafe(code int, fragments ...interface{}) error {
+	g.check()
+	return g.context.HTMLSafe(code, fragments...)
+}
+
+func (g *guardedContext) IsHTMX() bool {
+	g.check()
+	return g.context.IsHTMX()
+}
+
+func (g *guardedContext) HTMXRequest() HTMXRequest {
+	g.check()
+	return g.context.HTMXRequest()
+}
+
+func (g *guardedContext) HTMXTrigger(event string, detail interface{}) error {
+	g.check()
+	return g.context.HTMXTrigger(event, detail)
+}
+
+func (g *guardedContext) HTMXRedirect(url string) error {
+	g.check()
+	return g.context.HTMXRedirect(url)
+}
+
+func (g *guardedContext) HTMXPushURL(url string) {
+	g.check()
+	g.context.HTMXPushURL(url)
+}
+
+func (g *guardedContext) RenderHTMX(code int, page, partial string, data interface{}) error {
+	g.check()
+	return g.context.RenderHTMX(code, page, partial, data)
+}
+
+func (g *guardedContext) String(code int, s string) error {
+	g.check()
+	return g.context.String(code, s)
+}
+
+func (g *guardedContext) JSON(code int, i interface{}) error {
+	g.check()
+	return g.context.JSON(code, i)
+}
+
+func (g *guardedContext) JSONPretty(code int, i interface{}, indent string) error {
+	g.check()
+	return g.context.JSONPretty(code, i, indent)
+}
+
+func (g *guardedContext) JSONBlob(code int, b []byte) error {
+	g.check()
+	return g.context.JSONBlob(code, b)
+}
+
+func (g *guardedContext) JSONP(code int, callback string, i interface{}) error {
+	g.check()
+	return g.context.JSONP(code, callback, i)
+}
+
+func (g *guardedContext) JSONPBlob(code int, callback string, b []byte) error {
+	g.check()
+	return g.context.JSONPBlob(code, callback, b)
+}
+
+func (g *guardedContext) XML(code int, i interface{}) error {
+	g.check()
+	return g.context.XML(code, i)
+}
+
+func (g *guardedContext) XMLPretty(code int, i interface{}, indent string) error {
+	g.check()
+	return g.context.XMLPretty(code, i, indent)
+}
+
+func (g *guardedContext) XMLBlob(code int, b []byte) error {
+	g.check()
+	return g.context.XMLBlob(code, b)
+}
+
+func (g *guardedContext) Blob(code int, contentType string, b []byte) error {
+	g.check()
+	return g.context.Blob(code, contentType, b)
+}
+
+func (g *guardedContext) Stream(code int, contentType string, r io.Reader) error {
+	g.check()
+	return g.context.Stream(code, contentType, r)
+}
+
+func (g *guardedContext) File(file string) error {
+	g.check()
+	return g.context.File(file)
+}
+
+func (g *guardedContext) ServeContent(name string, modtime time.Time, content io.ReadSeeker) error {
+	g.check()
+	return g.context.ServeContent(name, modtime, content)
+}
+
+func (g *guardedContext) ServeContentAt(name string, modtime time.Time, size int64, content io.ReaderAt) error {
+	g.check()
+	return g.context.ServeContentAt(name, modtime, size, content)
+}
+
+func (g *guardedContext) Attachment(file string, name string) error {
+	g.check()
+	return g.context.Attachment(file, name)
+}
+
+func (g *guardedContext) Inline(file string, name string) error {
+	g.check()
+	return g.context.Inline(file, name)
+}
+
+func (g *guardedContext) NoContent(code int) error {
+	g.check()
+	return g.context.NoContent(code)
+}
+
+func (g *guardedContext) EvaluatePreconditions(etag string, lastModified time.Time) (int, bool) {
+	g.check()
+	return g.context.EvaluatePreconditions(etag, lastModified)
+}
+
+func (g *guardedContext) Redirect(code int, url string) error {
+	g.check()
+	return g.context.Redirect(code, url)
+}
+
+func (g *guardedContext) Error(err error) {
+	g.check()
+	g.context.Error(err)
+}
+
+func (g *guardedContext) ClientHints() ClientHints {
+	g.check()
+	return g.context.ClientHints()
+}
+
+func (g *guardedContext) AcceptClientHints(hints ...string) {
+	g.check()
+	g.context.AcceptClientHints(hints...)
+}
+
+func (g *guardedContext) Experiment(name string) string {
+	g.check()
+	return g.context.Experiment(name)
+}
+
+func (g *guardedContext) SetExperiment(name, variant string) {
+	g.check()
+	g.context.SetExperiment(name, variant)
+}
+
+func (g *guardedContext) User() *Principal {
+	g.check()
+	return g.context.User()
+}
+
+func (g *guardedContext) SetUser(p *Principal) {
+	g.check()
+	g.context.SetUser(p)
+}
+
+func (g *guardedContext) Route() *Route {
+	g.check()
+	return g.context.Route()
+}
+
+func (g *guardedContext) Handler() HandlerFunc {
+	g.check()
+	return g.context.Handler()
+}
+
+func (g *guardedContext) SetHandler(h HandlerFunc) {
+	g.check()
+	g.context.SetHandler(h)
+}
+
+func (g *guardedContext) Logger() Logger {
+	g.check()
+	return g.context.Logger()
+}
+
+func (g *guardedContext) SetLogger(l Logger) {
+	g.check()
+	g.context.SetLogger(l)
+}
+
+func (g *guardedContext) Echo() *Echo {
+	g.check()
+	return g.context.Echo()
+}
+
+func (g *guardedContext) Go(fn func(ctx stdContext.Context)) {
+	g.check()
+	g.context.Go(fn)
+}
+
+func (g *guardedContext) HTTPClient() *http.Client {
+	g.check()
+	return g.context.HTTPClient()
+}
+
+func (g *guardedContext) Clone() Context {
+	g.check()
+	return g.context.Clone()
+}
+
+func (g *guardedContext) Reset(r *http.Request, w http.ResponseWriter) {
+	g.check()
+	g.context.Reset(r, w)
+}
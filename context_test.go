@@ -2,12 +2,14 @@ package echo
 
 import (
 	"bytes"
+	stdContext "context"
 	"crypto/tls"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"mime/multipart"
 	"net/http"
@@ -723,6 +725,17 @@ func TestContext_Validate(t *testing.T) {
 	testify.NoError(t, c.Validate(struct{}{}))
 }
 
+// validatingContext is custom context overriding `Validate` the way existing applications do.
+type validatingContext struct {
+	Context
+}
+
+func (c *validatingContext) Validate(i interface{}) error {
+	return c.Context.Validate(i)
+}
+
+var _ Context = (*validatingContext)(nil)
+
 func TestContext_QueryString(t *testing.T) {
 	e := New()
 
@@ -734,6 +747,55 @@ func TestContext_QueryString(t *testing.T) {
 	testify.Equal(t, queryString, c.QueryString())
 }
 
+func TestContext_MatrixParams(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenURL     string
+		whenSegment string
+		expect      url.Values
+	}{
+		{
+			name:        "ok",
+			whenURL:     "/cars;color=red;year=2020/models;color=blue",
+			whenSegment: "cars",
+			expect:      url.Values{"color": {"red"}, "year": {"2020"}},
+		},
+		{
+			name:        "ok, repeated and valueless keys",
+			whenURL:     "/cars/models;color=red;color=blue;electric",
+			whenSegment: "models",
+			expect:      url.Values{"color": {"red", "blue"}, "electric": {""}},
+		},
+		{
+			name:        "ok, escaped values",
+			whenURL:     "/my%20cars;owner=j%C3%B5n%3Bdoe",
+			whenSegment: "my cars",
+			expect:      url.Values{"owner": {"jõn;doe"}},
+		},
+		{
+			name:        "ok, segment without params",
+			whenURL:     "/cars/models",
+			whenSegment: "cars",
+			expect:      url.Values{},
+		},
+		{
+			name:        "nok, unknown segment",
+			whenURL:     "/cars;color=red",
+			whenSegment: "boats",
+			expect:      nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(GET, tc.whenURL, nil)
+			c := e.NewContext(req, nil)
+
+			testify.Equal(t, tc.expect, c.MatrixParams(tc.whenSegment))
+		})
+	}
+}
+
 func TestContext_Request(t *testing.T) {
 	var c Context = new(context)
 
@@ -928,3 +990,532 @@ func TestContext_RealIP(t *testing.T) {
 		testify.Equal(t, tt.s, tt.c.RealIP())
 	}
 }
+
+func TestContext_BindStrict(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":1,"name":"Jon Snow","age":42}`))
+	req.Header.Add(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, nil)
+
+	err := c.BindStrict(new(user))
+	testify.EqualError(t, err, `code=400, message=unknown field "age", internal=json: unknown field "age"`)
+}
+
+type customBinder struct{}
+
+func (customBinder) Bind(i interface{}, c Context) error { return nil }
+
+func TestContext_BindStrictNotSupported(t *testing.T) {
+	e := New()
+	e.Binder = customBinder{}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	err := c.BindStrict(new(user))
+	testify.Equal(t, ErrStrictBindingNotSupported, err)
+}
+
+func TestContext_BindBySource(t *testing.T) {
+	type filter struct {
+		Sort  string `query:"sort"`
+		Token string `header:"X-Token"`
+		ID    int    `param:"id"`
+	}
+
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/users/2?sort=desc&id=3", strings.NewReader(userJSON))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set("X-Token", "secret")
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+
+	f := new(filter)
+	testify.NoError(t, c.BindQuery(f))
+	testify.Equal(t, &filter{Sort: "desc"}, f)
+
+	testify.NoError(t, c.BindHeaders(f))
+	testify.Equal(t, &filter{Sort: "desc", Token: "secret"}, f)
+
+	testify.NoError(t, c.BindPath(f))
+	testify.Equal(t, &filter{Sort: "desc", Token: "secret", ID: 2}, f)
+
+	u := new(user)
+	testify.NoError(t, c.BindBody(u))
+	testify.Equal(t, &user{ID: 1, Name: "Jon Snow"}, u)
+}
+
+func TestContext_BindBySourceWithCustomBinder(t *testing.T) {
+	e := New()
+	e.Binder = customBinder{}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=1", nil), nil)
+
+	u := new(user)
+	testify.NoError(t, c.BindQuery(u))
+	testify.Equal(t, &user{ID: 1}, u)
+}
+
+func TestContext_BindFrom(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenSources []BindSource
+		expect      *user
+		expectError string
+	}{
+		{
+			name:        "ok, body then path, path has priority",
+			whenSources: []BindSource{BindSourceBody, BindSourcePath},
+			expect:      &user{ID: 2, Name: "Jon Snow"},
+		},
+		{
+			name:        "ok, path then body, body has priority",
+			whenSources: []BindSource{BindSourcePath, BindSourceBody},
+			expect:      &user{ID: 1, Name: "Jon Snow"},
+		},
+		{
+			name:        "ok, query for POST request",
+			whenSources: []BindSource{BindSourceQuery},
+			expect:      &user{ID: 3},
+		},
+		{
+			name:        "ok, headers only",
+			whenSources: []BindSource{BindSourceHeaders},
+			expect:      &user{Name: "Arya"},
+		},
+		{
+			name:        "ok, no sources",
+			whenSources: nil,
+			expect:      &user{},
+		},
+		{
+			name:        "nok, unknown source",
+			whenSources: []BindSource{BindSourceHeaders, BindSource(99)},
+			expect:      &user{Name: "Arya"},
+			expectError: "echo: unknown bind source: 99",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/users/2?id=3", strings.NewReader(userJSON))
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			req.Header.Set("name", "Arya")
+			c := e.NewContext(req, httptest.NewRecorder())
+			c.SetParamNames("id")
+			c.SetParamValues("2")
+
+			u := new(user)
+			err := c.BindFrom(u, tc.whenSources...)
+			if tc.expectError != "" {
+				testify.EqualError(t, err, tc.expectError)
+			} else {
+				testify.NoError(t, err)
+			}
+			testify.Equal(t, tc.expect, u)
+		})
+	}
+}
+
+type scenarioValidator struct {
+	scenarios []string
+}
+
+func (v *scenarioValidator) Validate(i interface{}) error {
+	v.scenarios = nil
+	return nil
+}
+
+func (v *scenarioValidator) ValidateScenarios(i interface{}, scenarios ...string) error {
+	v.scenarios = scenarios
+	return nil
+}
+
+func TestContext_ValidateScenarios(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		givenMeta       []string
+		whenScenarios   []string
+		expectScenarios []string
+	}{
+		{
+			name:            "ok, no scenarios",
+			expectScenarios: nil,
+		},
+		{
+			name:            "ok, scenarios from arguments",
+			whenScenarios:   []string{"create"},
+			expectScenarios: []string{"create"},
+		},
+		{
+			name:            "ok, scenarios from route metadata",
+			givenMeta:       []string{"update"},
+			expectScenarios: []string{"update"},
+		},
+		{
+			name:            "ok, arguments have priority over route metadata",
+			givenMeta:       []string{"update"},
+			whenScenarios:   []string{"create", "admin"},
+			expectScenarios: []string{"create", "admin"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			v := &scenarioValidator{}
+			e.Validator = v
+			r := e.PATCH("/users/:id", func(c Context) error {
+				return c.ValidateScenario(struct{}{}, tc.whenScenarios...)
+			})
+			if tc.givenMeta != nil {
+				e.RouteMeta(r)[RouteMetaValidationScenarios] = tc.givenMeta
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/1", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			testify.Equal(t, http.StatusOK, rec.Code)
+			testify.Equal(t, tc.expectScenarios, v.scenarios)
+		})
+	}
+}
+
+func TestContext_ValidateScenariosNotSupported(t *testing.T) {
+	e := New()
+	e.Validator = &validator{}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	testify.Equal(t, ErrValidatorScenariosNotSupported, c.ValidateScenario(struct{}{}, "create"))
+}
+
+func TestContext_Experiment(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	testify.Equal(t, "", c.Experiment("checkout"))
+
+	c.SetExperiment("checkout", "b")
+	clone := c.Clone()
+	clone.SetExperiment("checkout", "a")
+
+	testify.Equal(t, "b", c.Experiment("checkout"))
+	testify.Equal(t, "a", clone.Experiment("checkout"))
+
+	c.Reset(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	testify.Equal(t, "", c.Experiment("checkout"))
+}
+
+func TestContext_User(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	testify.Nil(t, c.User())
+	testify.False(t, c.User().HasScope("read"))
+
+	c.SetUser(&Principal{Subject: "42", Scopes: []string{"read"}})
+	testify.Equal(t, "42", c.User().Subject)
+	testify.True(t, c.User().HasScope("read"))
+	testify.False(t, c.User().HasScope("write"))
+
+	c.Reset(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	testify.Nil(t, c.User())
+}
+
+func TestContext_Route(t *testing.T) {
+	e := New()
+	var matched *Route
+	h := func(c Context) error {
+		matched = c.Route()
+		return nil
+	}
+	r := e.GET("/users/:id", h)
+	hr := e.Host("api.example.com").GET("/users/:id", h)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	testify.Same(t, r, matched)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Host = "api.example.com"
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	testify.Same(t, hr, matched)
+
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	testify.Nil(t, c.Route())
+}
+
+type contextValidator struct {
+	validator
+	called bool
+}
+
+func (v *contextValidator) ValidateContext(c Context, i interface{}) error {
+	v.called = c != nil
+	return nil
+}
+
+func TestContext_ValidateWithContextValidator(t *testing.T) {
+	e := New()
+	v := &contextValidator{}
+	e.Validator = v
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+
+	testify.NoError(t, c.Validate(struct{}{}))
+	testify.True(t, v.called)
+}
+
+func TestContext_RequestBodyBytesRead(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		givenBody  string
+		whenRead   int
+		expectRead int64
+	}{
+		{
+			name:       "ok, whole body read",
+			givenBody:  "hello world",
+			whenRead:   -1,
+			expectRead: 11,
+		},
+		{
+			name:       "ok, partially read",
+			givenBody:  "hello world",
+			whenRead:   5,
+			expectRead: 5,
+		},
+		{
+			name:       "ok, no body",
+			givenBody:  "",
+			whenRead:   -1,
+			expectRead: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			var read int64
+			e.POST("/", func(c Context) error {
+				if tc.whenRead == -1 {
+					_, err := ioutil.ReadAll(c.Request().Body)
+					testify.NoError(t, err)
+				} else {
+					_, err := io.ReadFull(c.Request().Body, make([]byte, tc.whenRead))
+					testify.NoError(t, err)
+				}
+				read = c.RequestBodyBytesRead()
+				if read > 0 {
+					testify.True(t, c.RequestBodyReadDuration() > 0)
+				}
+				return nil
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+			if tc.givenBody == "" {
+				req.Body = http.NoBody
+			}
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			testify.Equal(t, tc.expectRead, read)
+		})
+	}
+}
+
+func TestContext_RequestBodyBytesRead_notSharedBetweenRequests(t *testing.T) {
+	e := New()
+	var first *http.Request
+	e.POST("/", func(c Context) error {
+		if first == nil {
+			first = c.Request()
+		}
+		_, err := ioutil.ReadAll(c.Request().Body)
+		return err
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("first")))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("second")))
+
+	testify.Equal(t, int64(5), first.Body.(*countingBody).n)
+}
+
+func TestContext_Clone(t *testing.T) {
+	e := New()
+	type ctxKey struct{}
+	reqCtx, cancel := stdContext.WithCancel(stdContext.WithValue(stdContext.Background(), ctxKey{}, "trace"))
+	req := httptest.NewRequest(http.MethodPost, "/users/1?q=go", strings.NewReader("body")).WithContext(reqCtx)
+	req.Header.Set("X-Request-ID", "123")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/users/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", "jon")
+
+	clone := c.Clone()
+
+	// original context is reused from pool
+	cancel()
+	c.Reset(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	c.Set("user", "other")
+
+	testify.Equal(t, "/users/:id", clone.Path())
+	testify.Equal(t, "1", clone.Param("id"))
+	testify.Equal(t, "go", clone.QueryParam("q"))
+	testify.Equal(t, "jon", clone.Get("user"))
+	testify.Equal(t, "123", clone.Request().Header.Get("X-Request-ID"))
+	testify.Equal(t, http.NoBody, clone.Request().Body)
+	testify.Equal(t, "trace", clone.Request().Context().Value(ctxKey{}))
+	testify.NoError(t, clone.Request().Context().Err())
+
+	testify.NoError(t, clone.String(http.StatusOK, "discarded"))
+	testify.Equal(t, "", rec.Body.String())
+}
+
+func TestContext_WithTimeout(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	_, ok := c.Deadline()
+	testify.False(t, ok)
+
+	done := c.WithTimeout(10 * time.Millisecond)
+	deadline, ok := c.Deadline()
+	testify.True(t, ok)
+	testify.True(t, deadline.After(time.Now().Add(-time.Second)))
+	testify.NoError(t, c.Err())
+
+	<-c.Request().Context().Done()
+	testify.Equal(t, stdContext.DeadlineExceeded, c.Err())
+
+	ctx := c.Request().Context()
+	done()
+	testify.Same(t, req, c.Request())
+	testify.NoError(t, c.Err())
+	testify.Error(t, ctx.Err())
+}
+
+func TestContext_Go(t *testing.T) {
+	e := New()
+	type ctxKey struct{}
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+
+	result := make(chan string, 1)
+	e.GET("/", func(c Context) error {
+		c.Go(func(ctx stdContext.Context) {
+			<-time.After(10 * time.Millisecond)
+			result <- fmt.Sprintf("%v %v %v %v", ctx.Value(ctxKey{}), RequestIDFromContext(ctx), LoggerFromContext(ctx) != nil, ctx.Err())
+		})
+		c.Go(func(ctx stdContext.Context) {
+			panic("boom")
+		})
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderXRequestID, "abc")
+	reqCtx, cancel := stdContext.WithCancel(stdContext.WithValue(req.Context(), ctxKey{}, "trace"))
+	e.ServeHTTP(httptest.NewRecorder(), req.WithContext(reqCtx))
+	cancel()
+
+	testify.NoError(t, e.Shutdown(stdContext.Background()))
+	testify.Equal(t, "trace abc true <nil>", <-result)
+	testify.Contains(t, buf.String(), `[PANIC RECOVER] background goroutine (request id \"abc\"): boom`)
+}
+
+func TestContext_GoShutdownTimeout(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	canceled := make(chan error, 1)
+	c.Go(func(ctx stdContext.Context) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+	})
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 10*time.Millisecond)
+	defer cancel()
+	testify.Equal(t, stdContext.DeadlineExceeded, e.Shutdown(ctx))
+	testify.Equal(t, stdContext.Canceled, <-canceled)
+}
+
+func TestContext_ServeContent(t *testing.T) {
+	modified := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		name          string
+		whenHeaders   map[string]string
+		whenAt        bool
+		expectStatus  int
+		expectBody    string
+		expectHeaders map[string]string
+	}{
+		{
+			name:         "ok, whole content",
+			expectStatus: http.StatusOK,
+			expectBody:   "0123456789",
+			expectHeaders: map[string]string{
+				HeaderContentType:  "text/plain; charset=utf-8",
+				HeaderLastModified: "Tue, 01 Jun 2021 12:00:00 GMT",
+				"Accept-Ranges":    "bytes",
+			},
+		},
+		{
+			name:         "ok, range",
+			whenHeaders:  map[string]string{"Range": "bytes=2-4"},
+			expectStatus: http.StatusPartialContent,
+			expectBody:   "234",
+			expectHeaders: map[string]string{
+				"Content-Range":     "bytes 2-4/10",
+				HeaderContentLength: "3",
+			},
+		},
+		{
+			name:         "ok, range of reader at",
+			whenHeaders:  map[string]string{"Range": "bytes=-3"},
+			whenAt:       true,
+			expectStatus: http.StatusPartialContent,
+			expectBody:   "789",
+			expectHeaders: map[string]string{
+				"Content-Range": "bytes 7-9/10",
+			},
+		},
+		{
+			name:         "nok, range not satisfiable",
+			whenHeaders:  map[string]string{"Range": "bytes=20-"},
+			whenAt:       true,
+			expectStatus: http.StatusRequestedRangeNotSatisfiable,
+			expectBody:   "invalid range: failed to overlap\n",
+		},
+		{
+			name:         "ok, not modified",
+			whenHeaders:  map[string]string{HeaderIfModifiedSince: "Tue, 01 Jun 2021 12:00:00 GMT"},
+			expectStatus: http.StatusNotModified,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			content := strings.NewReader("0123456789")
+			var err error
+			if tc.whenAt {
+				err = c.ServeContentAt("digits.txt", modified, content.Size(), content)
+			} else {
+				err = c.ServeContent("digits.txt", modified, content)
+			}
+
+			testify.NoError(t, err)
+			testify.Equal(t, tc.expectStatus, rec.Code)
+			testify.Equal(t, tc.expectBody, rec.Body.String())
+			for k, v := range tc.expectHeaders {
+				testify.Equal(t, v, rec.Header().Get(k), k)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package echo
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// StackTracer is implemented by errors carrying stack trace of their origin (i.e. panics recovered by
+// `middleware.Recover`). Stack trace is shown on error page in debug mode.
+type StackTracer interface {
+	StackTrace() []byte
+}
+
+type debugErrorPageData struct {
+	Code    int
+	Status  string
+	Message string
+	Method  string
+	URI     string
+	Route   string
+	Errors  []string
+	Stack   string
+}
+
+var debugErrorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Code}} {{.Status}}</title>
+<style>
+body{font-family:sans-serif;margin:2em;color:#222}
+h1{color:#c00}
+pre{background:#f5f5f5;padding:1em;overflow:auto}
+th{text-align:left;padding-right:1em}
+</style>
+</head>
+<body>
+<h1>{{.Code}} {{.Status}}</h1>
+<p>{{.Message}}</p>
+<table>
+<tr><th>Request</th><td>{{.Method}} {{.URI}}</td></tr>
+{{if .Route}}<tr><th>Route</th><td>{{.Route}}</td></tr>{{end}}
+</table>
+{{if .Errors}}<h2>Error</h2>
+<pre>{{range $i, $e := .Errors}}{{if $i}}
+caused by: {{end}}{{$e}}{{end}}</pre>{{end}}
+{{if .Stack}}<h2>Stack trace</h2>
+<pre>{{.Stack}}</pre>{{end}}
+</body>
+</html>
+`))
+
+// writeDebugErrorPage sends HTML error page with error chain and stack trace of error. Used by
+// `Echo#DefaultHTTPErrorHandler` in debug mode for clients preferring HTML.
+func writeDebugErrorPage(c Context, code int, message interface{}, err error) error {
+	data := debugErrorPageData{
+		Code:    code,
+		Status:  http.StatusText(code),
+		Message: fmt.Sprint(message),
+		Method:  c.Request().Method,
+		URI:     c.Request().RequestURI,
+		Route:   c.Path(),
+	}
+	if m, ok := message.(Map); ok {
+		data.Message = fmt.Sprint(m["message"])
+	}
+	data.Errors = errorChain(err)
+	data.Stack = string(errorStack(err))
+
+	buf := c.Echo().AcquireBuffer()
+	defer c.Echo().ReleaseBuffer(buf)
+	if err := debugErrorPageTemplate.Execute(buf, data); err != nil {
+		return err
+	}
+	return c.HTMLBlob(code, buf.Bytes())
+}
@@ -0,0 +1,40 @@
+package echo
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecation describes deprecation of API version or route.
+type Deprecation struct {
+	// Since is the time of deprecation. Zero value sends `Deprecation: true`.
+	Since time.Time
+	// Sunset is the time after which version or route is expected to be removed. Zero value does not send
+	// `Sunset` header.
+	Sunset time.Time
+	// Link is URL of documentation describing deprecation and migration. Sent as `Link` header with
+	// relation type "deprecation".
+	// Optional.
+	Link string
+}
+
+// RouteMetaDeprecation is route metadata key for deprecation (`Deprecation`) of route. Deprecated routes are
+// handled by `middleware.Deprecation`.
+// Example: `e.RouteMeta(e.GET("/users", listUsers))[echo.RouteMetaDeprecation] = echo.Deprecation{Sunset: sunset}`
+const RouteMetaDeprecation = "echo.deprecation"
+
+// WriteHeaders sets `Deprecation`, `Sunset` and `Link` headers of deprecation to header.
+func (d Deprecation) WriteHeaders(h http.Header) {
+	if d.Since.IsZero() {
+		h.Set(HeaderDeprecation, "true")
+	} else {
+		h.Set(HeaderDeprecation, "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		h.Set(HeaderSunset, d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		h.Add(HeaderLink, "<"+d.Link+`>; rel="deprecation"`)
+	}
+}
@@ -0,0 +1,87 @@
+package echo
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// dumpRedacted replaces values of redacted headers in `RequestDump`.
+const dumpRedacted = "[REDACTED]"
+
+// RequestDump is a sanitized dump of request and response for debugging. Sensitive headers are redacted and bodies
+// are truncated.
+type RequestDump struct {
+	Time                  time.Time     `json:"time"`
+	RequestID             string        `json:"request_id,omitempty"`
+	RemoteIP              string        `json:"remote_ip"`
+	Method                string        `json:"method"`
+	URI                   string        `json:"uri"`
+	Proto                 string        `json:"proto"`
+	Host                  string        `json:"host"`
+	RequestHeader         http.Header   `json:"request_header"`
+	RequestBody           string        `json:"request_body,omitempty"`
+	RequestBodyTruncated  bool          `json:"request_body_truncated,omitempty"`
+	Status                int           `json:"status,omitempty"`
+	ResponseHeader        http.Header   `json:"response_header,omitempty"`
+	ResponseBody          string        `json:"response_body,omitempty"`
+	ResponseBodyTruncated bool          `json:"response_body_truncated,omitempty"`
+	Latency               time.Duration `json:"latency,omitempty"`
+	Error                 string        `json:"error,omitempty"`
+}
+
+// Redact replaces values of given request and response headers with "[REDACTED]".
+func (d *RequestDump) Redact(headers ...string) {
+	for _, h := range headers {
+		redactHeader(d.RequestHeader, h)
+		redactHeader(d.ResponseHeader, h)
+	}
+}
+
+func redactHeader(h http.Header, name string) {
+	name = http.CanonicalHeaderKey(name)
+	if _, ok := h[name]; ok {
+		h[name] = []string{dumpRedacted}
+	}
+}
+
+func (c *context) DumpRequest(maxBodySize int64) (*RequestDump, error) {
+	req := c.request
+	d := &RequestDump{
+		Time:          time.Now(),
+		RequestID:     req.Header.Get(HeaderXRequestID),
+		RemoteIP:      c.RealIP(),
+		Method:        req.Method,
+		URI:           req.RequestURI,
+		Proto:         req.Proto,
+		Host:          req.Host,
+		RequestHeader: req.Header.Clone(),
+	}
+	if d.RequestID == "" {
+		d.RequestID = c.response.Header().Get(HeaderXRequestID)
+	}
+	d.Redact(traceSensitiveHeaders...)
+
+	if req.Body == nil || req.Body == http.NoBody || maxBodySize <= 0 {
+		return d, nil
+	}
+	head, err := ioutil.ReadAll(io.LimitReader(req.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	// restore body so handler reads it whole
+	req.Body = &dumpBodyReader{Reader: io.MultiReader(bytes.NewReader(head), req.Body), Closer: req.Body}
+	if int64(len(head)) > maxBodySize {
+		head = head[:maxBodySize]
+		d.RequestBodyTruncated = true
+	}
+	d.RequestBody = string(head)
+	return d, nil
+}
+
+type dumpBodyReader struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,76 @@
+package echo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_DumpRequest(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		whenBody        string
+		whenMaxBodySize int64
+		expectBody      string
+		expectTruncated bool
+	}{
+		{
+			name:            "ok, whole body",
+			whenBody:        `{"name":"Jon"}`,
+			whenMaxBodySize: 100,
+			expectBody:      `{"name":"Jon"}`,
+		},
+		{
+			name:            "ok, truncated body",
+			whenBody:        `{"name":"Jon"}`,
+			whenMaxBodySize: 5,
+			expectBody:      `{"nam`,
+			expectTruncated: true,
+		},
+		{
+			name:            "ok, body size equal to limit",
+			whenBody:        `12345`,
+			whenMaxBodySize: 5,
+			expectBody:      `12345`,
+		},
+		{
+			name:            "ok, body not dumped",
+			whenBody:        `{"name":"Jon"}`,
+			whenMaxBodySize: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/users?a=1", strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderAuthorization, "Bearer secret")
+			req.Header.Set(HeaderCookie, "session=secret")
+			req.Header.Set(HeaderXRequestID, "rid-1")
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			d, err := c.DumpRequest(tc.whenMaxBodySize)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.MethodPost, d.Method)
+			assert.Equal(t, "/users?a=1", d.URI)
+			assert.Equal(t, "rid-1", d.RequestID)
+			assert.Equal(t, "192.0.2.1", d.RemoteIP)
+			assert.Equal(t, "[REDACTED]", d.RequestHeader.Get(HeaderAuthorization))
+			assert.Equal(t, "[REDACTED]", d.RequestHeader.Get(HeaderCookie))
+			assert.Equal(t, MIMEApplicationJSON, d.RequestHeader.Get(HeaderContentType))
+			assert.Equal(t, "Bearer secret", req.Header.Get(HeaderAuthorization))
+			assert.Equal(t, tc.expectBody, d.RequestBody)
+			assert.Equal(t, tc.expectTruncated, d.RequestBodyTruncated)
+
+			body, err := ioutil.ReadAll(c.Request().Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.whenBody, string(body))
+		})
+	}
+}
@@ -52,7 +52,10 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/gommon/color"
@@ -74,9 +77,23 @@ type (
 		colorer          *color.Color
 		premiddleware    []MiddlewareFunc
 		middleware       []MiddlewareFunc
+		middlewareNames  []string
+		skipsMiddleware  bool
 		maxParam         *int
 		router           *Router
 		routers          map[string]*Router
+		routeMeta        map[*Route]Map
+		routeNames       map[string]*Route
+		lastRoute        *Route
+		registrations    []routeRegistration
+		versionedRoutes  map[string]*versionedRoute
+		errorPages       map[int]HandlerFunc
+		traceDisabled    bool
+		routeErrors      []*RouteError
+		background       sync.WaitGroup
+		backgroundCtx    stdContext.Context
+		backgroundCancel stdContext.CancelFunc
+		frozen           bool
 		notFoundHandler  HandlerFunc
 		pool             sync.Pool
 		Server           *http.Server
@@ -85,6 +102,9 @@ type (
 		TLSListener      net.Listener
 		AutoTLSManager   autocert.Manager
 		DisableHTTP2     bool
+		// Debug enables development mode: JSON is indented, errors include internal error, browsers get error
+		// page with stack trace, `TemplateRenderer` reloads templates on every render and responses without
+		// `Cache-Control` header set by handler, middleware or route cache policy are not cached.
 		Debug            bool
 		HideBanner       bool
 		HidePort         bool
@@ -96,6 +116,45 @@ type (
 		Logger           Logger
 		IPExtractor      IPExtractor
 		ListenerNetwork  string
+		RouterConfig     RouterConfig
+		// BufferPool provides reusable buffers for Echo and middlewares. See `Echo#AcquireBuffer`.
+		// Optional. Defaults to pool created with `NewBufferPool(DefaultBufferPoolMaxSize)`.
+		BufferPool BufferPool
+		// FileOffload delegates sending files with `Context#File`, `Context#Attachment` and `Context#Inline` to
+		// reverse proxy using `X-Accel-Redirect` or `X-Sendfile` header.
+		// Optional. By default files are sent by Echo.
+		FileOffload *FileOffloadConfig
+		// HTTPClientTransport is transport used by clients created with `Context#HTTPClient`.
+		// Optional. Defaults to `http.DefaultTransport`.
+		HTTPClientTransport http.RoundTripper
+		// ErrorPages renders HTML error pages for clients preferring HTML in `Echo#DefaultHTTPErrorHandler`.
+		// Optional. By default errors are sent as JSON.
+		ErrorPages *ErrorPages
+		// Versioning defines how API version of request is resolved for routes registered with `Echo#Version`.
+		Versioning VersioningConfig
+		// GuardContextPool enables detection of contexts used after request is finished and context is released
+		// back to the pool (i.e. in goroutines started by handler). Such usage panics with descriptive message
+		// instead of causing data races. Guarding adds overhead to every context method so it is meant for development
+		// and testing.
+		GuardContextPool bool
+		// SecureCookieKeys are AES keys (16, 24 or 32 bytes long) used by `Context#SetSecureCookie` and
+		// `Context#SecureCookie` to encrypt and authenticate cookie values. The first key encrypts new cookies and all
+		// keys decrypt, so keys are rotated by adding new key to the front and removing the old key later.
+		SecureCookieKeys [][]byte
+		// SecureCookieMaxAge is how long secure cookie values are accepted after they were encrypted. It is also sent
+		// as `Max-Age` of cookies set with `Context#SetSecureCookie`.
+		// Optional. Zero value does not limit age of secure cookie values.
+		SecureCookieMaxAge time.Duration
+		// HTMLPolicy is the allow-list sanitizer for `UntrustedHTML` fragments sent with `Context#HTMLSafe`.
+		// Optional. Defaults to `DefaultHTMLPolicy`.
+		HTMLPolicy *HTMLPolicy
+		// LogServerErrors makes `Echo#DefaultHTTPErrorHandler` log errors responded with 5xx status code with error
+		// chain and request id so they can be traced. In debug mode stack trace of error (see `HTTPError#StackTrace`)
+		// or of where it was handled is logged too.
+		LogServerErrors bool
+		// ErrorReporter reports server errors and recovered panics to crash reporting service.
+		// Optional.
+		ErrorReporter ErrorReporter
 	}
 
 	// Route contains a handler and information for matching against requests.
@@ -105,11 +164,22 @@ type (
 		Name   string `json:"name"`
 	}
 
+	// routeRegistration holds arguments of route registration so routes can be registered again to cloned instance.
+	routeRegistration struct {
+		host       string
+		handler    HandlerFunc
+		middleware []MiddlewareFunc
+		// groupMiddleware is the number of leading middlewares in middleware that were added at group level.
+		groupMiddleware int
+		route           *Route
+	}
+
 	// HTTPError represents an error that occurred while handling a request.
 	HTTPError struct {
 		Code     int         `json:"-"`
 		Message  interface{} `json:"message"`
 		Internal error       `json:"-"` // Stores the error returned by an external dependency
+		stack    []byte
 	}
 
 	// MiddlewareFunc defines a function to process middleware.
@@ -126,6 +196,18 @@ type (
 		Validate(i interface{}) error
 	}
 
+	// ScenarioValidator is the interface implemented by validators that support validation scenarios (groups) i.e.
+	// different rules for "create" and "update" endpoints. See `Context#ValidateScenario`.
+	ScenarioValidator interface {
+		ValidateScenarios(i interface{}, scenarios ...string) error
+	}
+
+	// ContextValidator is the interface implemented by validators that need request context i.e. to translate
+	// validation messages to request locale. `Context#Validate` prefers it over `Validator#Validate`.
+	ContextValidator interface {
+		ValidateContext(c Context, i interface{}) error
+	}
+
 	// JSONSerializer is the interface that encodes and decodes JSON to and from interfaces.
 	JSONSerializer interface {
 		Serialize(c Context, i interface{}, indent string) error
@@ -163,6 +245,8 @@ const (
 const (
 	MIMEApplicationJSON                  = "application/json"
 	MIMEApplicationJSONCharsetUTF8       = MIMEApplicationJSON + "; " + charsetUTF8
+	MIMEApplicationHALJSON               = "application/hal+json"
+	MIMEApplicationProblemJSON           = "application/problem+json"
 	MIMEApplicationJavaScript            = "application/javascript"
 	MIMEApplicationJavaScriptCharsetUTF8 = MIMEApplicationJavaScript + "; " + charsetUTF8
 	MIMEApplicationXML                   = "application/xml"
@@ -186,6 +270,18 @@ const (
 	PROPFIND = "PROPFIND"
 	// REPORT Method can be used to get information about a resource, see rfc 3253
 	REPORT = "REPORT"
+	// PROPPATCH Method sets and removes properties of resource, see rfc 4918
+	PROPPATCH = "PROPPATCH"
+	// MKCOL Method creates new collection resource, see rfc 4918
+	MKCOL = "MKCOL"
+	// COPY Method creates duplicate of resource, see rfc 4918
+	COPY = "COPY"
+	// MOVE Method moves resource to destination, see rfc 4918
+	MOVE = "MOVE"
+	// LOCK Method locks resource, see rfc 4918
+	LOCK = "LOCK"
+	// UNLOCK Method removes lock of resource, see rfc 4918
+	UNLOCK = "UNLOCK"
 )
 
 // Headers
@@ -194,19 +290,29 @@ const (
 	HeaderAcceptEncoding      = "Accept-Encoding"
 	HeaderAllow               = "Allow"
 	HeaderAuthorization       = "Authorization"
+	HeaderCacheControl        = "Cache-Control"
 	HeaderContentDisposition  = "Content-Disposition"
 	HeaderContentEncoding     = "Content-Encoding"
 	HeaderContentLength       = "Content-Length"
 	HeaderContentType         = "Content-Type"
 	HeaderCookie              = "Cookie"
 	HeaderSetCookie           = "Set-Cookie"
+	HeaderExpires             = "Expires"
 	HeaderIfModifiedSince     = "If-Modified-Since"
+	HeaderIfUnmodifiedSince   = "If-Unmodified-Since"
+	HeaderIfMatch             = "If-Match"
+	HeaderIfNoneMatch         = "If-None-Match"
+	HeaderETag                = "ETag"
 	HeaderLastModified        = "Last-Modified"
 	HeaderLocation            = "Location"
+	HeaderLink                = "Link"
 	HeaderUpgrade             = "Upgrade"
 	HeaderVary                = "Vary"
 	HeaderWWWAuthenticate     = "WWW-Authenticate"
 	HeaderXForwardedFor       = "X-Forwarded-For"
+	HeaderXForwardedHost      = "X-Forwarded-Host"
+	HeaderXForwardedMethod    = "X-Forwarded-Method"
+	HeaderXForwardedURI       = "X-Forwarded-Uri"
 	HeaderXForwardedProto     = "X-Forwarded-Proto"
 	HeaderXForwardedProtocol  = "X-Forwarded-Protocol"
 	HeaderXForwardedSsl       = "X-Forwarded-Ssl"
@@ -215,7 +321,16 @@ const (
 	HeaderXRealIP             = "X-Real-IP"
 	HeaderXRequestID          = "X-Request-ID"
 	HeaderXRequestedWith      = "X-Requested-With"
+	HeaderXAccelRedirect      = "X-Accel-Redirect"
+	HeaderXSendfile           = "X-Sendfile"
+	HeaderXAPIVersion         = "X-API-Version"
+	HeaderXTotalCount         = "X-Total-Count"
+	HeaderRequestTimeout      = "Request-Timeout"
+	HeaderXRequestTimeout     = "X-Request-Timeout"
+	HeaderDeprecation         = "Deprecation"
+	HeaderSunset              = "Sunset"
 	HeaderServer              = "Server"
+	HeaderRetryAfter          = "Retry-After"
 	HeaderOrigin              = "Origin"
 
 	// Access control
@@ -269,29 +384,37 @@ var (
 		http.MethodPut,
 		http.MethodTrace,
 		REPORT,
+		PROPPATCH,
+		MKCOL,
+		COPY,
+		MOVE,
+		LOCK,
+		UNLOCK,
 	}
 )
 
 // Errors
 var (
-	ErrUnsupportedMediaType        = NewHTTPError(http.StatusUnsupportedMediaType)
-	ErrNotFound                    = NewHTTPError(http.StatusNotFound)
-	ErrUnauthorized                = NewHTTPError(http.StatusUnauthorized)
-	ErrForbidden                   = NewHTTPError(http.StatusForbidden)
-	ErrMethodNotAllowed            = NewHTTPError(http.StatusMethodNotAllowed)
-	ErrStatusRequestEntityTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge)
-	ErrTooManyRequests             = NewHTTPError(http.StatusTooManyRequests)
-	ErrBadRequest                  = NewHTTPError(http.StatusBadRequest)
-	ErrBadGateway                  = NewHTTPError(http.StatusBadGateway)
-	ErrInternalServerError         = NewHTTPError(http.StatusInternalServerError)
-	ErrRequestTimeout              = NewHTTPError(http.StatusRequestTimeout)
-	ErrServiceUnavailable          = NewHTTPError(http.StatusServiceUnavailable)
-	ErrValidatorNotRegistered      = errors.New("validator not registered")
-	ErrRendererNotRegistered       = errors.New("renderer not registered")
-	ErrInvalidRedirectCode         = errors.New("invalid redirect status code")
-	ErrCookieNotFound              = errors.New("cookie not found")
-	ErrInvalidCertOrKeyType        = errors.New("invalid cert or key type, must be string or []byte")
-	ErrInvalidListenerNetwork      = errors.New("invalid listener network")
+	ErrUnsupportedMediaType           = NewHTTPError(http.StatusUnsupportedMediaType)
+	ErrNotFound                       = NewHTTPError(http.StatusNotFound)
+	ErrUnauthorized                   = NewHTTPError(http.StatusUnauthorized)
+	ErrForbidden                      = NewHTTPError(http.StatusForbidden)
+	ErrMethodNotAllowed               = NewHTTPError(http.StatusMethodNotAllowed)
+	ErrStatusRequestEntityTooLarge    = NewHTTPError(http.StatusRequestEntityTooLarge)
+	ErrTooManyRequests                = NewHTTPError(http.StatusTooManyRequests)
+	ErrBadRequest                     = NewHTTPError(http.StatusBadRequest)
+	ErrBadGateway                     = NewHTTPError(http.StatusBadGateway)
+	ErrInternalServerError            = NewHTTPError(http.StatusInternalServerError)
+	ErrRequestTimeout                 = NewHTTPError(http.StatusRequestTimeout)
+	ErrServiceUnavailable             = NewHTTPError(http.StatusServiceUnavailable)
+	ErrValidatorNotRegistered         = errors.New("validator not registered")
+	ErrRendererNotRegistered          = errors.New("renderer not registered")
+	ErrStrictBindingNotSupported      = errors.New("binder does not support strict binding")
+	ErrValidatorScenariosNotSupported = errors.New("validator does not support validation scenarios")
+	ErrInvalidRedirectCode            = errors.New("invalid redirect status code")
+	ErrCookieNotFound                 = errors.New("cookie not found")
+	ErrInvalidCertOrKeyType           = errors.New("invalid cert or key type, must be string or []byte")
+	ErrInvalidListenerNetwork         = errors.New("invalid listener network")
 )
 
 // Error handlers
@@ -323,6 +446,7 @@ func New() (e *Echo) {
 	e.HTTPErrorHandler = e.DefaultHTTPErrorHandler
 	e.Binder = &DefaultBinder{}
 	e.JSONSerializer = &DefaultJSONSerializer{}
+	e.BufferPool = NewBufferPool(DefaultBufferPoolMaxSize)
 	e.Logger.SetLevel(log.ERROR)
 	e.StdLogger = stdLog.New(e.Logger.Output(), e.Logger.Prefix()+": ", 0)
 	e.pool.New = func() interface{} {
@@ -330,6 +454,11 @@ func New() (e *Echo) {
 	}
 	e.router = NewRouter(e)
 	e.routers = map[string]*Router{}
+	e.routeMeta = map[*Route]Map{}
+	e.routeNames = map[string]*Route{}
+	e.versionedRoutes = map[string]*versionedRoute{}
+	e.errorPages = map[int]HandlerFunc{}
+	e.backgroundCtx, e.backgroundCancel = stdContext.WithCancel(stdContext.Background())
 	return
 }
 
@@ -356,7 +485,10 @@ func (e *Echo) Routers() map[string]*Router {
 }
 
 // DefaultHTTPErrorHandler is the default HTTP error handler. It sends a JSON response
-// with status code.
+// with status code. Errors with status code registered with `Echo#ErrorPage` are sent by registered handler.
+// Clients preferring HTML get error page of `Echo#ErrorPages` when configured and in debug mode error page with
+// error chain and stack trace. Server errors are logged when `Echo#LogServerErrors` is set and reported to
+// `Echo#ErrorReporter`.
 func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	he, ok := err.(*HTTPError)
 	if ok {
@@ -383,10 +515,28 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 		}
 	}
 
+	traced := err
+	if e.Debug && errorStack(err) == nil {
+		traced = &stackTraceError{error: err, stack: callerStack(3)} // skip runtime.Callers, callerStack and this method
+	}
+	if code >= http.StatusInternalServerError {
+		if e.LogServerErrors {
+			e.logServerError(c, code, traced)
+		}
+		e.ReportError(c, traced)
+	}
+
 	// Send response
 	if !c.Response().Committed {
+		if e.serveErrorPage(c, code, err) {
+			return
+		}
 		if c.Request().Method == http.MethodHead { // Issue #608
 			err = c.NoContent(he.Code)
+		} else if e.Debug && prefersHTML(c) {
+			err = writeDebugErrorPage(c, code, message, traced)
+		} else if ok, perr := e.ErrorPages.render(c, code, message); ok {
+			err = perr
 		} else {
 			err = c.JSON(code, message)
 		}
@@ -398,12 +548,17 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 
 // Pre adds middleware to the chain which is run before router.
 func (e *Echo) Pre(middleware ...MiddlewareFunc) {
+	e.checkNotFrozen()
 	e.premiddleware = append(e.premiddleware, middleware...)
 }
 
 // Use adds middleware to the chain which is run after router.
 func (e *Echo) Use(middleware ...MiddlewareFunc) {
+	e.checkNotFrozen()
 	e.middleware = append(e.middleware, middleware...)
+	for _, m := range middleware {
+		e.middlewareNames = append(e.middlewareNames, middlewareName(m))
+	}
 }
 
 // CONNECT registers a new CONNECT route for a path with matching handler in the
@@ -489,6 +644,46 @@ func (e *Echo) Static(prefix, root string) *Route {
 	return e.static(prefix, root, e.GET)
 }
 
+// Mount registers handler for all requests with path prefix. Prefix is stripped from the request path before
+// handler is called, so handler sees paths relative to the prefix. Handler can be another `*Echo` instance which
+// enables composing application from independently built routers. Prefix can contain path parameters.
+//
+// Example: `e.Mount("/admin", adminEcho)` serves request `/admin/users` with `adminEcho` route `/users`.
+func (e *Echo) Mount(prefix string, h http.Handler, middleware ...MiddlewareFunc) []*Route {
+	return e.mount(prefix, h, e.Any, middleware...)
+}
+
+func (common) mount(prefix string, h http.Handler, anyFn func(string, HandlerFunc, ...MiddlewareFunc) []*Route, m ...MiddlewareFunc) []*Route {
+	handler := func(c Context) error {
+		h.ServeHTTP(c.Response(), stripPathPrefix(c.Request(), "/"+c.Param("*")))
+		return nil
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	routes := anyFn(prefix+"/*", handler, m...)
+	if prefix != "" {
+		routes = append(routes, anyFn(prefix, handler, m...)...)
+	}
+	return routes
+}
+
+// stripPathPrefix returns shallow copy of request with URL path replaced by rest of the path after mount prefix.
+func stripPathPrefix(r *http.Request, rest string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = rest
+	r2.URL.RawPath = ""
+	if r.URL.RawPath != "" {
+		// rest comes from raw path when request path has escaped characters
+		if p, err := url.PathUnescape(rest); err == nil {
+			r2.URL.Path = p
+			r2.URL.RawPath = rest
+		}
+	}
+	return r2
+}
+
 func (common) static(prefix, root string, get func(string, HandlerFunc, ...MiddlewareFunc) *Route) *Route {
 	h := func(c Context) error {
 		p, err := url.PathUnescape(c.Param("*"))
@@ -536,30 +731,119 @@ func (e *Echo) File(path, file string, m ...MiddlewareFunc) *Route {
 	return e.file(path, file, e.GET, m...)
 }
 
-func (e *Echo) add(host, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
-	name := handlerName(handler)
-	router := e.findRouter(host)
-	router.Add(method, path, func(c Context) error {
-		h := applyMiddleware(handler, middleware...)
-		return h(c)
-	})
+func (e *Echo) add(host string, groupMiddleware int, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	if paths := expandOptionalParams(path); paths != nil {
+		return e.addOptional(host, groupMiddleware, method, path, paths, handler, middleware...)
+	}
 	r := &Route{
 		Method: method,
 		Path:   path,
-		Name:   name,
+		Name:   e.routeName(method, path, handler),
+	}
+	if e.RouterConfig.CollectRouteErrors {
+		if err := e.checkRoute(method, path, handler); err != nil {
+			e.routeErrors = append(e.routeErrors, err.(*RouteError))
+			return r // not registered, returned so chained calls (i.e. `.Name = "x"`) do not panic
+		}
+	}
+	if err := e.checkRouteName(method, path, r.Name); err != nil {
+		switch {
+		case e.RouterConfig.DuplicateRouteNames == DuplicateRouteNamesWarn:
+			e.Logger.Warn(err)
+		case e.RouterConfig.CollectRouteErrors:
+			e.routeErrors = append(e.routeErrors, err.(*RouteError))
+			return r
+		default:
+			panic(err)
+		}
 	}
-	e.router.routes[method+path] = r
+	e.addRoute(host, groupMiddleware, r, handler, middleware...)
+	e.routeMeta[r] = Map{}
 	return r
 }
 
+// RouteMetaOptionalPath is route metadata key for path with optional params (`/archive/:year?/:month?`) that route
+// was expanded from. It is set for all routes registered for such path.
+const RouteMetaOptionalPath = "echo.optional_path"
+
+// addOptional registers routes for paths expanded from route with optional params. Paths are checked before any of
+// them is registered.
+func (e *Echo) addOptional(host string, groupMiddleware int, method, path string, paths []string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	for _, p := range paths {
+		if err := e.checkRoute(method, p, handler); err != nil {
+			if !e.RouterConfig.CollectRouteErrors {
+				panic(err)
+			}
+			e.routeErrors = append(e.routeErrors, err.(*RouteError))
+			return &Route{Method: method, Path: paths[0], Name: e.routeName(method, paths[0], handler)}
+		}
+	}
+	r := e.add(host, groupMiddleware, method, paths[0], handler, middleware...)
+	meta := e.RouteMeta(r)
+	meta[RouteMetaOptionalPath] = path
+	for _, p := range paths[1:] {
+		if pr := e.add(host, groupMiddleware, method, p, handler, middleware...); e.routeMeta[pr] != nil {
+			e.routeMeta[pr] = meta
+		}
+	}
+	return r
+}
+
+func (e *Echo) routeName(method, path string, handler HandlerFunc) string {
+	if e.RouterConfig.RouteNamer != nil {
+		return e.RouterConfig.RouteNamer(method, path)
+	}
+	return handlerName(handler)
+}
+
+func (e *Echo) addRoute(host string, groupMiddleware int, r *Route, handler HandlerFunc, middleware ...MiddlewareFunc) {
+	router := e.findRouter(host)
+	router.Add(r.Method, r.Path, func(c Context) error {
+		if cc, ok := e.routeCacheControl(r); ok {
+			applyCacheControl(c.Response(), cc)
+		}
+		h := applyMiddleware(handler, middleware...)
+		return h(c)
+	})
+	router.routes[r.Method+normalizePath(r.Path)] = r
+	e.indexRouteName(r)
+	e.registrations = append(e.registrations, routeRegistration{
+		host:            host,
+		handler:         handler,
+		middleware:      middleware,
+		groupMiddleware: groupMiddleware,
+		route:           r,
+	})
+}
+
+// RouteMeta returns metadata of route registered with this Echo instance. Metadata is free form data attached to route
+// (i.e. validation scenarios, required scopes) that middlewares and handlers can read with `Context#Route`.
+// Metadata should be modified only before server is started.
+// Example: `e.RouteMeta(e.POST("/users", createUser))[echo.RouteMetaValidationScenarios] = []string{"create"}`
+func (e *Echo) RouteMeta(r *Route) Map {
+	if m, ok := e.routeMeta[r]; ok {
+		return m
+	}
+	return Map{} // not registered route, modifications are not stored
+}
+
 // Add registers a new route for an HTTP method and path with matching handler
 // in the router with optional route-level middleware.
+//
+// Path can contain params (`/users/:id`) and a wildcard matching rest of the path (`/files/*`). Wildcard can be named
+// (`/files/*filepath`) and named wildcard can be followed by static path segments (`/files/*filepath/edit`) to
+// match paths ending with them.
+//
+// Trailing params can be optional (`/archive/:year?/:month?`). Handler is then registered for every path matched by
+// route (`/archive/:year/:month`, `/archive/:year` and `/archive`) either for all or none of them. Returned route is
+// the longest one and routes share metadata (see `Echo#RouteMeta`).
 func (e *Echo) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
-	return e.add("", method, path, handler, middleware...)
+	return e.add("", 0, method, path, handler, middleware...)
 }
 
 // Host creates a new router group for the provided host and optional host-level middleware.
 func (e *Echo) Host(name string, m ...MiddlewareFunc) (g *Group) {
+	e.checkNotFrozen()
 	e.routers[name] = NewRouter(e)
 	g = &Group{host: name, echo: e}
 	g.Use(m...)
@@ -589,7 +873,7 @@ func (e *Echo) Reverse(name string, params ...interface{}) string {
 	uri := new(bytes.Buffer)
 	ln := len(params)
 	n := 0
-	for _, r := range e.router.routes {
+	for _, r := range e.Routes() {
 		if r.Name == name {
 			for i, l := 0, len(r.Path); i < l; i++ {
 				if (r.Path[i] == ':' || r.Path[i] == '*') && n < ln {
@@ -608,15 +892,287 @@ func (e *Echo) Reverse(name string, params ...interface{}) string {
 	return uri.String()
 }
 
-// Routes returns the registered routes.
+// RouteByName returns route with given name or nil when there is no such route. When multiple routes have the
+// same name the one with lowest path and method is returned. Names assigned after registration (`Route.Name`) are
+// indexed by `Echo#VerifyRoutes` (called by server start methods), until then they are looked up from all routes.
+func (e *Echo) RouteByName(name string) *Route {
+	if r := e.routeNames[name]; r != nil && r.Name == name {
+		return r
+	}
+	var found *Route
+	for _, r := range e.Routes() {
+		if r.Name == name && (found == nil || routeLess(r, found)) {
+			found = r
+		}
+	}
+	return found
+}
+
+// Freeze validates configuration of Echo instance and makes it immutable. Adding routes, middlewares or hosts after
+// freeze panics. This catches modifications of instance that is already serving requests which is not safe.
+// Freeze returns error when required components (Binder, JSONSerializer, HTTPErrorHandler, Logger) are not set or
+// routes are not valid (see `Echo#VerifyRoutes`). Route level middleware chains are built once during freeze
+// instead of on every request. Calling Freeze on frozen instance does nothing.
+func (e *Echo) Freeze() error {
+	if e.frozen {
+		return nil
+	}
+	if err := e.verifyConfig(); err != nil {
+		return err
+	}
+	if err := e.VerifyRoutes(); err != nil {
+		return err
+	}
+	for _, reg := range e.registrations {
+		h := applyMiddleware(reg.handler, reg.middleware...)
+		if cc, ok := e.routeCacheControl(reg.route); ok {
+			h = withRouteCacheControl(h, cc)
+		}
+		e.findRouter(reg.host).Add(reg.route.Method, reg.route.Path, h)
+	}
+	e.frozen = true
+	return nil
+}
+
+func (e *Echo) verifyConfig() error {
+	var problems []string
+	if e.Binder == nil {
+		problems = append(problems, "Binder is not set")
+	}
+	if e.JSONSerializer == nil {
+		problems = append(problems, "JSONSerializer is not set")
+	}
+	if e.HTTPErrorHandler == nil {
+		problems = append(problems, "HTTPErrorHandler is not set")
+	}
+	if e.Logger == nil {
+		problems = append(problems, "Logger is not set")
+	}
+	if len(problems) > 0 {
+		return errors.New("echo: invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (e *Echo) checkNotFrozen() {
+	if e.frozen {
+		panic("echo: can not modify Echo instance after it has been frozen")
+	}
+}
+
+// Clone creates copy of Echo instance with same configuration, middlewares and routes. Route metadata is copied and
+// routes and middlewares can be added to the clone without affecting the original instance (i.e. to experiment
+// with alternative route table). Handlers, middlewares and components (Binder, Renderer etc.) are shared with the
+// original. Clone has its own servers, listeners, AutoTLSManager and is not frozen.
+func (e *Echo) Clone() *Echo {
+	c := New()
+	c.DisableHTTP2 = e.DisableHTTP2
+	c.Debug = e.Debug
+	c.HideBanner = e.HideBanner
+	c.HidePort = e.HidePort
+	c.Binder = e.Binder
+	c.JSONSerializer = e.JSONSerializer
+	c.Validator = e.Validator
+	c.Renderer = e.Renderer
+	c.Logger = e.Logger
+	c.StdLogger = e.StdLogger
+	c.IPExtractor = e.IPExtractor
+	c.ListenerNetwork = e.ListenerNetwork
+	c.RouterConfig = e.RouterConfig
+	c.HTTPClientTransport = e.HTTPClientTransport
+	c.FileOffload = e.FileOffload
+	c.BufferPool = e.BufferPool
+	c.GuardContextPool = e.GuardContextPool
+	c.SecureCookieKeys = e.SecureCookieKeys
+	c.SecureCookieMaxAge = e.SecureCookieMaxAge
+	c.LogServerErrors = e.LogServerErrors
+	c.ErrorReporter = e.ErrorReporter
+	c.HTMLPolicy = e.HTMLPolicy
+	c.Versioning = e.Versioning
+	c.ErrorPages = e.ErrorPages
+	c.traceDisabled = e.traceDisabled
+	for code, h := range e.errorPages {
+		c.errorPages[code] = h
+	}
+	if reflect.ValueOf(e.HTTPErrorHandler).Pointer() != reflect.ValueOf(e.DefaultHTTPErrorHandler).Pointer() {
+		c.HTTPErrorHandler = e.HTTPErrorHandler // default handler is bound to original instance so it is not copied
+	}
+	c.premiddleware = append([]MiddlewareFunc(nil), e.premiddleware...)
+	c.middleware = append([]MiddlewareFunc(nil), e.middleware...)
+	c.middlewareNames = append([]string(nil), e.middlewareNames...)
+	c.skipsMiddleware = e.skipsMiddleware
+	for host := range e.routers {
+		c.routers[host] = NewRouter(c)
+	}
+	routes := make(map[*Route]*Route, len(e.registrations))
+	for _, reg := range e.registrations {
+		r := *reg.route
+		c.addRoute(reg.host, reg.groupMiddleware, &r, reg.handler, reg.middleware...)
+		meta := Map{}
+		for k, v := range e.routeMeta[reg.route] {
+			meta[k] = v
+		}
+		c.routeMeta[&r] = meta
+		routes[reg.route] = &r
+	}
+	for key, vr := range e.versionedRoutes {
+		handlers := make(map[string]HandlerFunc, len(vr.handlers))
+		for v, h := range vr.handlers {
+			handlers[v] = h
+		}
+		c.versionedRoutes[key] = &versionedRoute{route: routes[vr.route], handlers: handlers, latest: vr.latest}
+	}
+	return c
+}
+
+// VerifyRoutes checks registered routes against `Echo#RouterConfig` rules and returns error describing all violations.
+// Routes annotated with `RouteMetaUsesRenderer` or `RouteMetaUsesValidator` are checked to have corresponding
+// component configured. Registration errors collected with `RouterConfig.CollectRouteErrors` are reported first.
+// Routes skipping middlewares (see `Echo#SkipMiddleware`) are checked to skip only registered global middlewares.
+func (e *Echo) VerifyRoutes() error {
+	if err := e.verifyRouteErrors(); err != nil {
+		return err
+	}
+	e.reindexRouteNames()
+	if e.RouterConfig.UniqueRouteNames {
+		if err := verifyRouteNames(e.Routes()); err != nil {
+			return err
+		}
+	}
+	if e.RouterConfig.DuplicateRouteNames == DuplicateRouteNamesReject {
+		if err := verifyDuplicateRouteNames(e.sortedRoutes()); err != nil {
+			return err
+		}
+	}
+	if err := e.verifyRouteComponents(); err != nil {
+		return err
+	}
+	if problems := e.verifySkippedMiddleware(); len(problems) > 0 {
+		return errors.New("echo: routes skip unknown middlewares: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// sortedRoutes returns registered routes sorted by path and method.
+func (e *Echo) sortedRoutes() []*Route {
+	routes := e.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		return routeLess(routes[i], routes[j])
+	})
+	return routes
+}
+
+// routeLess orders routes by path and method.
+func routeLess(a, b *Route) bool {
+	if a.Path == b.Path {
+		return a.Method < b.Method
+	}
+	return a.Path < b.Path
+}
+
+// indexRouteName adds route to route name index. When multiple routes have the same name index keeps the one with
+// lowest path and method.
+func (e *Echo) indexRouteName(r *Route) {
+	if cur, ok := e.routeNames[r.Name]; !ok || cur.Name != r.Name || routeLess(r, cur) {
+		e.routeNames[r.Name] = r
+	}
+	e.lastRoute = r
+}
+
+// reindexRouteNames rebuilds route name index from current names of registered routes.
+func (e *Echo) reindexRouteNames() {
+	e.routeNames = make(map[string]*Route, len(e.routeNames))
+	for _, r := range e.Routes() {
+		e.indexRouteName(r)
+	}
+}
+
+func (e *Echo) verifyRouteComponents() error {
+	var problems []string
+	for _, r := range e.sortedRoutes() {
+		meta := e.RouteMeta(r)
+		if uses, _ := meta[RouteMetaUsesRenderer].(bool); uses && e.Renderer == nil {
+			problems = append(problems, fmt.Sprintf("route %s %s uses Renderer but Echo#Renderer is not set", r.Method, r.Path))
+		}
+		uses, _ := meta[RouteMetaUsesValidator].(bool)
+		if _, ok := meta[RouteMetaValidationScenarios]; (uses || ok) && e.Validator == nil {
+			problems = append(problems, fmt.Sprintf("route %s %s uses Validator but Echo#Validator is not set", r.Method, r.Path))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("echo: routes use missing components: " + strings.Join(problems, "; "))
+}
+
+// Routes returns the registered routes (including routes registered for hosts).
 func (e *Echo) Routes() []*Route {
 	routes := make([]*Route, 0, len(e.router.routes))
 	for _, v := range e.router.routes {
 		routes = append(routes, v)
 	}
+	for _, router := range e.routers {
+		for _, v := range router.routes {
+			routes = append(routes, v)
+		}
+	}
 	return routes
 }
 
+// RouteDescription describes registered route along with its route level (including group level) middlewares.
+type RouteDescription struct {
+	Host       string   `json:"host"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name"`
+	Middleware []string `json:"middleware"`
+}
+
+// DescribeRoutes returns descriptions of registered routes in stable order (sorted by host, path and method) so they
+// can be compared to golden file to detect accidental route changes. Middleware names are function names
+// (i.e. "github.com/labstack/echo/v4/middleware.KeyAuthWithConfig.func1").
+func (e *Echo) DescribeRoutes() []RouteDescription {
+	latest := map[*Route]routeRegistration{}
+	for _, reg := range e.registrations {
+		latest[reg.route] = reg
+	}
+	result := make([]RouteDescription, 0, len(latest))
+	for host, router := range e.allRouters() {
+		for _, r := range router.routes {
+			reg, ok := latest[r]
+			if !ok {
+				continue
+			}
+			d := RouteDescription{Host: host, Method: r.Method, Path: r.Path, Name: r.Name, Middleware: []string{}}
+			for _, m := range reg.middleware {
+				d.Middleware = append(d.Middleware, funcName(m))
+			}
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return result
+}
+
+// allRouters returns routers of Echo instance by host. Default router has empty host.
+func (e *Echo) allRouters() map[string]*Router {
+	routers := make(map[string]*Router, len(e.routers)+1)
+	for host, r := range e.routers {
+		routers[host] = r
+	}
+	routers[""] = e.router
+	return routers
+}
+
 // AcquireContext returns an empty `Context` instance from the pool.
 // You must return the context by calling `ReleaseContext()`.
 func (e *Echo) AcquireContext() Context {
@@ -626,6 +1182,9 @@ func (e *Echo) AcquireContext() Context {
 // ReleaseContext returns the `Context` instance back to the pool.
 // You must call it after `AcquireContext()`.
 func (e *Echo) ReleaseContext(c Context) {
+	if ctx, ok := c.(*context); ok {
+		atomic.AddUint64(&ctx.version, 1)
+	}
 	e.pool.Put(c)
 }
 
@@ -634,28 +1193,47 @@ func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Acquire context
 	c := e.pool.Get().(*context)
 	c.Reset(r, w)
+	c.countBody()
+	if e.Debug {
+		// errors created while serving request have stack trace of where they were created
+		atomic.AddInt32(&debugRequests, 1)
+		defer atomic.AddInt32(&debugRequests, -1)
+	}
+	var ctx Context = c
+	if e.GuardContextPool {
+		ctx = &guardedContext{context: c, version: atomic.LoadUint64(&c.version)}
+	}
 	h := NotFoundHandler
+	var start time.Time
+	if e.RouterConfig.CollectRouteStats {
+		start = time.Now()
+	}
 
 	if e.premiddleware == nil {
-		e.findRouter(r.Host).Find(r.Method, GetPath(r), c)
-		h = c.Handler()
-		h = applyMiddleware(h, e.middleware...)
+		e.findRouter(r.Host).Find(r.Method, e.routingPath(r), c)
+		h = e.routeHandler(c)
+		h = e.applyGlobalMiddleware(h, c)
 	} else {
-		h = func(c Context) error {
-			e.findRouter(r.Host).Find(r.Method, GetPath(r), c)
-			h := c.Handler()
-			h = applyMiddleware(h, e.middleware...)
-			return h(c)
+		h = func(ctx Context) error {
+			e.findRouter(r.Host).Find(r.Method, e.routingPath(r), c)
+			h := e.routeHandler(c)
+			h = e.applyGlobalMiddleware(h, c)
+			return h(ctx)
 		}
 		h = applyMiddleware(h, e.premiddleware...)
 	}
 
 	// Execute chain
-	if err := h(c); err != nil {
-		e.HTTPErrorHandler(err, c)
+	err := h(ctx)
+	if err != nil {
+		e.HTTPErrorHandler(err, ctx)
+	}
+	if e.RouterConfig.CollectRouteStats {
+		e.recordRouteStats(c, start, err)
 	}
 
 	// Release context
+	atomic.AddUint64(&c.version, 1)
 	e.pool.Put(c)
 }
 
@@ -764,6 +1342,9 @@ func (e *Echo) configureServer(s *http.Server) (err error) {
 	if e.Debug {
 		e.Logger.SetLevel(log.DEBUG)
 	}
+	if err := e.VerifyRoutes(); err != nil {
+		return err
+	}
 
 	if !e.HideBanner {
 		e.colorer.Printf(banner, e.colorer.Red("v"+Version), e.colorer.Blue(website))
@@ -826,6 +1407,10 @@ func (e *Echo) StartH2CServer(address string, h2s *http2.Server) (err error) {
 	if e.Debug {
 		e.Logger.SetLevel(log.DEBUG)
 	}
+	if err := e.VerifyRoutes(); err != nil {
+		e.startupMutex.Unlock()
+		return err
+	}
 
 	if !e.HideBanner {
 		e.colorer.Printf(banner, e.colorer.Red("v"+Version), e.colorer.Blue(website))
@@ -850,6 +1435,7 @@ func (e *Echo) StartH2CServer(address string, h2s *http2.Server) (err error) {
 func (e *Echo) Close() error {
 	e.startupMutex.Lock()
 	defer e.startupMutex.Unlock()
+	e.backgroundCancel()
 	if err := e.TLSServer.Close(); err != nil {
 		return err
 	}
@@ -857,19 +1443,34 @@ func (e *Echo) Close() error {
 }
 
 // Shutdown stops the server gracefully.
-// It internally calls `http.Server#Shutdown()`.
+// It internally calls `http.Server#Shutdown()` and waits for goroutines started with `Context#Go` to finish. When
+// ctx is done before they finish, their contexts are canceled and ctx error is returned.
 func (e *Echo) Shutdown(ctx stdContext.Context) error {
 	e.startupMutex.Lock()
 	defer e.startupMutex.Unlock()
 	if err := e.TLSServer.Shutdown(ctx); err != nil {
 		return err
 	}
-	return e.Server.Shutdown(ctx)
+	if err := e.Server.Shutdown(ctx); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		e.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		e.backgroundCancel()
+		return ctx.Err()
+	}
 }
 
 // NewHTTPError creates a new HTTPError instance.
 func NewHTTPError(code int, message ...interface{}) *HTTPError {
-	he := &HTTPError{Code: code, Message: http.StatusText(code)}
+	he := &HTTPError{Code: code, Message: http.StatusText(code), stack: captureErrorStack()}
 	if len(message) > 0 {
 		he.Message = message[0]
 	}
@@ -895,9 +1496,11 @@ func (he *HTTPError) Unwrap() error {
 	return he.Internal
 }
 
-// WrapHandler wraps `http.Handler` into `echo.HandlerFunc`.
+// WrapHandler wraps `http.Handler` into `echo.HandlerFunc`. With Go 1.22+ path parameters are available to handler
+// with `http.Request#PathValue`.
 func WrapHandler(h http.Handler) HandlerFunc {
 	return func(c Context) error {
+		setPathValues(c.Request(), c.ParamNames(), c.ParamValues())
 		h.ServeHTTP(c.Response(), c.Request())
 		return nil
 	}
@@ -917,10 +1520,75 @@ func WrapMiddleware(m func(http.Handler) http.Handler) MiddlewareFunc {
 	}
 }
 
+// WrapMiddlewareCaptureErrors wraps `func(http.Handler) http.Handler` into `echo.MiddlewareFunc`. Unlike
+// `WrapMiddleware`, when wrapped middleware responds with error status (>= 400) itself instead of calling next
+// handler (i.e. 401 from third-party auth middleware), response is not written to the client. Instead `*HTTPError`
+// with that status is returned so it reaches `Echo#HTTPErrorHandler` and logging middlewares. Headers set by wrapped
+// middleware (i.e. `WWW-Authenticate`) are kept, written body is available as `HTTPError.Internal`.
+func WrapMiddlewareCaptureErrors(m func(http.Handler) http.Handler) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) (err error) {
+			cw := &errorCaptureWriter{ResponseWriter: c.Response()}
+			m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				cw.nextCalled = true
+				c.SetRequest(r)
+				c.SetResponse(NewResponse(w, c.Echo()))
+				err = next(c)
+			})).ServeHTTP(cw, c.Request())
+			if cw.status == 0 {
+				return
+			}
+			header := c.Response().Header()
+			header.Del(HeaderContentType)
+			header.Del(HeaderContentLength)
+			return &HTTPError{
+				Code:     cw.status,
+				Message:  http.StatusText(cw.status),
+				Internal: fmt.Errorf("wrapped middleware responded with status %d: %s", cw.status, cw.body.String()),
+			}
+		}
+	}
+}
+
+// errorCaptureWriter holds back error response written before next handler is called.
+type errorCaptureWriter struct {
+	http.ResponseWriter
+	nextCalled bool
+	status     int
+	body       bytes.Buffer
+}
+
+func (w *errorCaptureWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	if !w.nextCalled && code >= http.StatusBadRequest {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *errorCaptureWriter) Write(b []byte) (int, error) {
+	if w.status != 0 {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *errorCaptureWriter) Flush() {
+	if w.status != 0 {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // GetPath returns RawPath, if it's empty returns Path from URL
 // Difference between RawPath and Path is:
-//  * Path is where request path is stored. Value is stored in decoded form: /%47%6f%2f becomes /Go/.
-//  * RawPath is an optional field which only gets set if the default encoding is different from Path.
+//   - Path is where request path is stored. Value is stored in decoded form: /%47%6f%2f becomes /Go/.
+//   - RawPath is an optional field which only gets set if the default encoding is different from Path.
 func GetPath(r *http.Request) string {
 	path := r.URL.RawPath
 	if path == "" {
@@ -929,6 +1597,18 @@ func GetPath(r *http.Request) string {
 	return path
 }
 
+// routingPath returns path router matches routes against. See `RouterConfig.RawPathParams`. CONNECT requests with
+// authority-form target (`CONNECT example.com:443`) have no path and are matched against "/".
+func (e *Echo) routingPath(r *http.Request) string {
+	if r.Method == http.MethodConnect && r.URL.Path == "" {
+		return "/"
+	}
+	if e.RouterConfig.RawPathParams {
+		return r.URL.EscapedPath()
+	}
+	return GetPath(r)
+}
+
 func (e *Echo) findRouter(host string) *Router {
 	if len(e.routers) > 0 {
 		if r, ok := e.routers[host]; ok {
@@ -939,11 +1619,15 @@ func (e *Echo) findRouter(host string) *Router {
 }
 
 func handlerName(h HandlerFunc) string {
-	t := reflect.ValueOf(h).Type()
-	if t.Kind() == reflect.Func {
-		return runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	return funcName(h)
+}
+
+func funcName(f interface{}) string {
+	v := reflect.ValueOf(f)
+	if v.Kind() == reflect.Func {
+		return runtime.FuncForPC(v.Pointer()).Name()
 	}
-	return t.String()
+	return v.Type().String()
 }
 
 // // PathUnescape is wraps `url.PathUnescape`
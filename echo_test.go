@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/labstack/gommon/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/http2"
@@ -545,6 +546,55 @@ func TestEchoServeHTTPPathEncoding(t *testing.T) {
 	}
 }
 
+func TestEchoServeHTTPRawPathParams(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenRaw    bool
+		whenURL     string
+		expectParam string
+	}{
+		{
+			name:        "default, space is decoded",
+			whenURL:     "/files/a%20b",
+			expectParam: "a b",
+		},
+		{
+			name:        "default, encoded slash is not decoded",
+			whenURL:     "/files/a%2Fb%20c",
+			expectParam: "a%2Fb%20c",
+		},
+		{
+			name:        "raw, space is not decoded",
+			givenRaw:    true,
+			whenURL:     "/files/a%20b",
+			expectParam: "a%20b",
+		},
+		{
+			name:        "raw, encoded slash is not decoded",
+			givenRaw:    true,
+			whenURL:     "/files/a%2Fb",
+			expectParam: "a%2Fb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.RouterConfig.RawPathParams = tc.givenRaw
+			e.GET("/files/:name", func(c Context) error {
+				return c.String(http.StatusOK, c.Param("name"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectParam, rec.Body.String())
+		})
+	}
+}
+
 func TestEchoHost(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1151,6 +1201,52 @@ func TestDefaultHTTPErrorHandler(t *testing.T) {
 	assert.Equal(t, "{\"code\":33,\"error\":\"stackinfo\",\"message\":\"Something bad happened\"}\n", b)
 }
 
+type stackError struct {
+	error
+}
+
+func (e stackError) StackTrace() []byte {
+	return []byte("goroutine 1 [running]:\nmain.handler()")
+}
+
+func TestDefaultHTTPErrorHandler_debugErrorPage(t *testing.T) {
+	e := New()
+	e.Debug = true
+	e.GET("/users/:id", func(c Context) error {
+		return fmt.Errorf("loading user: %w", stackError{errors.New("db <down>")})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(HeaderAccept, "text/html,application/xhtml+xml,*/*;q=0.8")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, MIMETextHTMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "no-store", rec.Header().Get(HeaderCacheControl))
+	body := rec.Body.String()
+	assert.Contains(t, body, "<h1>500 Internal Server Error</h1>")
+	assert.Contains(t, body, "<tr><th>Request</th><td>GET /users/1</td></tr>")
+	assert.Contains(t, body, "<tr><th>Route</th><td>/users/:id</td></tr>")
+	assert.Contains(t, body, "<pre>loading user: db &lt;down&gt;\ncaused by: db &lt;down&gt;</pre>")
+	assert.Contains(t, body, "<pre>goroutine 1 [running]:\nmain.handler()</pre>")
+
+	// clients preferring JSON get JSON
+	req = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+
+	// without debug mode
+	e.Debug = false
+	req = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set(HeaderAccept, "text/html")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Empty(t, rec.Header().Get(HeaderCacheControl))
+}
+
 func TestEchoClose(t *testing.T) {
 	e := New()
 	errCh := make(chan error)
@@ -1430,14 +1526,23 @@ func benchmarkEchoRoutes(b *testing.B, routes []*Route) {
 	}
 
 	// Find routes
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
+	serve := func() {
 		for _, route := range routes {
 			req.Method = route.Method
 			u.Path = route.Path
 			e.ServeHTTP(w, req)
 		}
 	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		serve()
+	}
+	b.StopTimer()
+
+	// routing must not allocate when routes do not use features needing it (i.e. `RouteMetaCacheControl`)
+	if allocs := testing.AllocsPerRun(10, serve); allocs != 0 {
+		b.Errorf("expected no allocations, got %v per run", allocs)
+	}
 }
 
 func BenchmarkEchoStaticRoutes(b *testing.B) {
@@ -1459,3 +1564,625 @@ func BenchmarkEchoGitHubAPIMisses(b *testing.B) {
 func BenchmarkEchoParseAPI(b *testing.B) {
 	benchmarkEchoRoutes(b, parseAPI)
 }
+
+func TestEcho_RouteNamer(t *testing.T) {
+	e := New()
+	e.RouterConfig.RouteNamer = DefaultRouteNamer
+
+	r := e.GET("/users/:id", handlerFunc)
+	assert.Equal(t, "GET /users/:id", r.Name)
+
+	g := e.Group("/api")
+	r = g.POST("/users", handlerFunc)
+	assert.Equal(t, "POST /api/users", r.Name)
+
+	r = e.PUT("", handlerFunc)
+	assert.Equal(t, "PUT /", r.Name)
+}
+
+func TestEcho_VerifyRoutes(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenConfig   RouterConfig
+		whenRoutes    func(e *Echo)
+		expectedError string
+	}{
+		{
+			name:        "ok, duplicate names are allowed by default",
+			givenConfig: RouterConfig{},
+			whenRoutes: func(e *Echo) {
+				e.GET("/a", handlerFunc).Name = "same"
+				e.GET("/b", handlerFunc).Name = "same"
+			},
+		},
+		{
+			name:        "ok, generated names are unique",
+			givenConfig: RouterConfig{UniqueRouteNames: true, RouteNamer: DefaultRouteNamer},
+			whenRoutes: func(e *Echo) {
+				e.Any("/a", handlerFunc)
+				e.Group("/group", func(next HandlerFunc) HandlerFunc { return next })
+			},
+		},
+		{
+			name:        "nok, duplicate names",
+			givenConfig: RouterConfig{UniqueRouteNames: true},
+			whenRoutes: func(e *Echo) {
+				e.GET("/a", handlerFunc).Name = "same"
+				e.POST("/a", handlerFunc).Name = "same"
+				e.GET("/b", handlerFunc).Name = "same"
+				e.GET("/c", handlerFunc).Name = "other"
+			},
+			expectedError: `echo: invalid route names: route name "same" is used by multiple routes: GET /a, POST /a, GET /b`,
+		},
+		{
+			name:        "nok, empty name",
+			givenConfig: RouterConfig{UniqueRouteNames: true, RouteNamer: DefaultRouteNamer},
+			whenRoutes: func(e *Echo) {
+				e.GET("/a", handlerFunc).Name = ""
+				e.GET("/b", handlerFunc)
+			},
+			expectedError: `echo: invalid route names: route GET /a has no name`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.RouterConfig = tc.givenConfig
+			tc.whenRoutes(e)
+
+			err := e.VerifyRoutes()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEcho_VerifyRoutes_hostRoutes(t *testing.T) {
+	e := New()
+	e.RouterConfig.UniqueRouteNames = true
+	e.GET("/a", handlerFunc).Name = "a"
+	e.Host("api.example.com").GET("/a", handlerFunc).Name = "a"
+
+	assert.EqualError(t, e.VerifyRoutes(), `echo: invalid route names: route name "a" is used by multiple routes: GET /a, GET /a`)
+	assert.Len(t, e.Routes(), 2)
+}
+
+func TestEcho_DuplicateRouteNames(t *testing.T) {
+	// names of routes are set after registration so only generated names collide
+	namer := func(method, path string) string {
+		return strings.Trim(path, "/")
+	}
+	var testCases = []struct {
+		name         string
+		givenPolicy  DuplicateRouteNames
+		givenCollect bool
+		expectPanic  bool
+		expectLog    string
+		expectRoutes int
+		expectErrors int
+	}{
+		{
+			name:         "ok, allowed by default",
+			givenPolicy:  DuplicateRouteNamesAllow,
+			expectRoutes: 3,
+		},
+		{
+			name:         "ok, warn",
+			givenPolicy:  DuplicateRouteNamesWarn,
+			expectLog:    `echo: can not add route GET /users/: route name is already used: \"users\" by GET /users`,
+			expectRoutes: 3,
+		},
+		{
+			name:        "nok, reject panics",
+			givenPolicy: DuplicateRouteNamesReject,
+			expectPanic: true,
+		},
+		{
+			name:         "nok, reject collects error",
+			givenPolicy:  DuplicateRouteNamesReject,
+			givenCollect: true,
+			expectRoutes: 2,
+			expectErrors: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			buf := new(bytes.Buffer)
+			e.Logger.SetOutput(buf)
+			e.Logger.SetLevel(log.WARN)
+			e.RouterConfig.RouteNamer = namer
+			e.RouterConfig.DuplicateRouteNames = tc.givenPolicy
+			e.RouterConfig.CollectRouteErrors = tc.givenCollect
+
+			register := func() {
+				e.GET("/users", handlerFunc)
+				e.POST("/users", handlerFunc) // same route path with other method is not a duplicate
+				e.GET("/users/", handlerFunc)
+			}
+			if tc.expectPanic {
+				assert.Panics(t, register)
+				return
+			}
+			register()
+
+			assert.Len(t, e.Routes(), tc.expectRoutes)
+			assert.Len(t, e.RouteErrors(), tc.expectErrors)
+			if tc.expectLog != "" {
+				assert.Contains(t, buf.String(), tc.expectLog)
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestEcho_TryAdd_duplicateRouteName(t *testing.T) {
+	e := New()
+	e.RouterConfig.RouteNamer = DefaultRouteNamer
+	e.RouterConfig.DuplicateRouteNames = DuplicateRouteNamesReject
+	e.GET("/a", handlerFunc).Name = "GET /b"
+
+	r, err := e.TryAdd(http.MethodGet, "/b", handlerFunc)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrRouteDuplicateName))
+	assert.Len(t, e.Routes(), 1)
+}
+
+func TestEcho_DuplicateRouteNames_handlerName(t *testing.T) {
+	e := New()
+	e.RouterConfig.DuplicateRouteNames = DuplicateRouteNamesReject
+	e.GET("/users", handlerFunc)
+
+	r, err := e.TryAdd(http.MethodGet, "/users/:id", handlerFunc)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrRouteDuplicateName))
+}
+
+func TestEcho_VerifyRoutes_duplicateAssignedRouteName(t *testing.T) {
+	e := New()
+	e.RouterConfig.RouteNamer = DefaultRouteNamer
+	e.RouterConfig.DuplicateRouteNames = DuplicateRouteNamesReject
+	e.GET("/a", handlerFunc).Name = "a"
+	e.POST("/a", handlerFunc).Name = "a"
+	e.GET("/b", handlerFunc)
+	e.GET("/c", handlerFunc)
+	e.RouteByName("GET /b").Name = "a" // renamed after next route was registered
+
+	assert.EqualError(t, e.VerifyRoutes(), `echo: invalid route names: route name "a" is used by routes with different paths: GET /a, POST /a, GET /b`)
+	assert.Equal(t, "/a", e.RouteByName("a").Path)
+}
+
+func TestEcho_RouteByName(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc).Name = "user"
+	e.POST("/users", handlerFunc).Name = "users.create"
+	e.GET("/users", handlerFunc).Name = "users.create"
+
+	assert.Equal(t, &Route{Method: http.MethodGet, Path: "/users/:id", Name: "user"}, e.RouteByName("user"))
+	assert.Equal(t, &Route{Method: http.MethodGet, Path: "/users", Name: "users.create"}, e.RouteByName("users.create"))
+	assert.Nil(t, e.RouteByName("unknown"))
+}
+
+func TestEcho_StartFailsWithInvalidRouteNames(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.RouterConfig.UniqueRouteNames = true
+	e.GET("/a", handlerFunc).Name = ""
+
+	err := e.Start(":0")
+	assert.EqualError(t, err, `echo: invalid route names: route GET /a has no name`)
+	assert.Nil(t, e.ListenerAddr())
+}
+
+func TestEcho_RouteMeta(t *testing.T) {
+	e := New()
+	r := e.GET("/users", handlerFunc)
+
+	e.RouteMeta(r)["scopes"] = []string{"users:read"}
+	assert.Equal(t, Map{"scopes": []string{"users:read"}}, e.RouteMeta(r))
+
+	notRegistered := &Route{Method: http.MethodGet, Path: "/", Name: "x"}
+	e.RouteMeta(notRegistered)["key"] = "value"
+	assert.Equal(t, Map{}, e.RouteMeta(notRegistered))
+	assert.Equal(t, Map{}, e.RouteMeta(nil))
+}
+
+func TestEchoWrapMiddlewareCaptureErrors(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenMiddleware  func(h http.Handler) http.Handler
+		expectErr        string
+		expectBody       string
+		expectAuthHeader string
+	}{
+		{
+			name: "ok, next is called",
+			givenMiddleware: func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					h.ServeHTTP(w, r)
+				})
+			},
+			expectBody: "OK",
+		},
+		{
+			name: "nok, middleware responds with error status",
+			givenMiddleware: func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(HeaderWWWAuthenticate, "Bearer")
+					http.Error(w, "invalid token", http.StatusUnauthorized)
+				})
+			},
+			expectErr:        "code=401, message=Unauthorized, internal=wrapped middleware responded with status 401: invalid token\n",
+			expectAuthHeader: "Bearer",
+		},
+		{
+			name: "ok, non-error response is written",
+			givenMiddleware: func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusAccepted)
+					w.Write([]byte("accepted"))
+				})
+			},
+			expectBody: "accepted",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := WrapMiddlewareCaptureErrors(tc.givenMiddleware)(func(c Context) error {
+				return c.String(http.StatusOK, "OK")
+			})
+			err := h(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				assert.False(t, c.Response().Committed)
+				assert.Equal(t, "", rec.Header().Get(HeaderContentType))
+				assert.Equal(t, tc.expectAuthHeader, rec.Header().Get(HeaderWWWAuthenticate))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestEcho_Mount(t *testing.T) {
+	admin := New()
+	admin.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "index:"+c.Request().URL.Path)
+	})
+	admin.GET("/users/:id", func(c Context) error {
+		return c.String(http.StatusOK, c.Path()+":"+c.Param("id")+":"+c.Request().URL.Path)
+	})
+
+	e := New()
+	e.Mount("/admin/", admin)
+	e.Group("/api").Mount("/:tenant", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("api:" + r.URL.Path + ":" + r.URL.RawPath))
+	}))
+
+	var testCases = []struct {
+		name       string
+		whenURL    string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "ok, mounted echo route",
+			whenURL:    "/admin/users/1",
+			expectCode: http.StatusOK,
+			expectBody: "/users/:id:1:/users/1",
+		},
+		{
+			name:       "ok, prefix only",
+			whenURL:    "/admin",
+			expectCode: http.StatusOK,
+			expectBody: "index:/",
+		},
+		{
+			name:       "ok, prefix with trailing slash",
+			whenURL:    "/admin/",
+			expectCode: http.StatusOK,
+			expectBody: "index:/",
+		},
+		{
+			name:       "nok, not found in mounted echo",
+			whenURL:    "/admin/unknown",
+			expectCode: http.StatusNotFound,
+			expectBody: "{\"message\":\"Not Found\"}\n",
+		},
+		{
+			name:       "ok, group mount with path param and escaped path",
+			whenURL:    "/api/acme/files/a%2Fb",
+			expectCode: http.StatusOK,
+			expectBody: "api:/files/a/b:/files/a%2Fb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEcho_Freeze(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		whenFn func(e *Echo)
+	}{
+		{name: "add route", whenFn: func(e *Echo) { e.GET("/new", handlerFunc) }},
+		{name: "add route to group", whenFn: func(e *Echo) { e.Group("/g").GET("/new", handlerFunc) }},
+		{name: "add route to router", whenFn: func(e *Echo) { e.Router().Add(http.MethodGet, "/new", handlerFunc) }},
+		{name: "use middleware", whenFn: func(e *Echo) { e.Use(func(next HandlerFunc) HandlerFunc { return next }) }},
+		{name: "pre middleware", whenFn: func(e *Echo) { e.Pre(func(next HandlerFunc) HandlerFunc { return next }) }},
+		{name: "add host", whenFn: func(e *Echo) { e.Host("example.com") }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.GET("/", handlerFunc)
+			assert.NoError(t, e.Freeze())
+
+			assert.PanicsWithValue(t, "echo: can not modify Echo instance after it has been frozen", func() {
+				tc.whenFn(e)
+			})
+		})
+	}
+}
+
+func TestEcho_Clone(t *testing.T) {
+	e := New()
+	e.Debug = true
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Mw", "1")
+			return next(c)
+		}
+	})
+	r := e.GET("/users/:id", func(c Context) error {
+		return c.String(http.StatusOK, "user:"+c.Param("id"))
+	})
+	r.Name = "user"
+	e.RouteMeta(r)["key"] = "value"
+	e.Host("api.example.com").GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "api")
+	})
+	assert.NoError(t, e.Freeze())
+
+	clone := e.Clone()
+	clone.GET("/new", func(c Context) error {
+		return c.String(http.StatusOK, "new")
+	})
+
+	assert.True(t, clone.Debug)
+	assert.Equal(t, "/users/1", clone.Reverse("user", 1))
+	for _, cr := range clone.Routes() {
+		if cr.Name == "user" {
+			assert.False(t, r == cr)
+			assert.Equal(t, Map{"key": "value"}, clone.RouteMeta(cr))
+		}
+	}
+	assert.Len(t, e.Routes(), 2)
+	assert.Len(t, clone.Routes(), 3)
+
+	var testCases = []struct {
+		whenHost   string
+		whenURL    string
+		expectCode int
+		expectBody string
+	}{
+		{whenURL: "/users/1", expectCode: http.StatusOK, expectBody: "user:1"},
+		{whenURL: "/new", expectCode: http.StatusOK, expectBody: "new"},
+		{whenHost: "api.example.com", whenURL: "/", expectCode: http.StatusOK, expectBody: "api"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenURL, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			if tc.whenHost != "" {
+				req.Host = tc.whenHost
+			}
+			rec := httptest.NewRecorder()
+			clone.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, "1", rec.Header().Get("X-Mw"))
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/new", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEcho_GuardContextPool(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenGuard  bool
+		expectPanic bool
+	}{
+		{name: "panics when guard is enabled", givenGuard: true, expectPanic: true},
+		{name: "does not panic when guard is disabled", givenGuard: false, expectPanic: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.GuardContextPool = tc.givenGuard
+			var leaked Context
+			e.GET("/", func(c Context) error {
+				leaked = c
+				c.Set("key", "value")
+				return c.String(http.StatusOK, "OK")
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, "OK", rec.Body.String())
+
+			if tc.expectPanic {
+				assert.PanicsWithValue(t, "echo: context used after request was finished and context was released "+
+					"back to the pool. Use Context#Clone to pass context to goroutines that outlive the handler", func() {
+					leaked.Get("key")
+				})
+			} else {
+				assert.NotPanics(t, func() {
+					leaked.Get("key")
+				})
+			}
+		})
+	}
+}
+
+func TestEcho_FreezeValidation(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenEcho   func() *Echo
+		expectError string
+	}{
+		{
+			name: "ok",
+			givenEcho: func() *Echo {
+				return New()
+			},
+		},
+		{
+			name: "nok, missing components",
+			givenEcho: func() *Echo {
+				e := New()
+				e.Binder = nil
+				e.JSONSerializer = nil
+				return e
+			},
+			expectError: "echo: invalid configuration: Binder is not set; JSONSerializer is not set",
+		},
+		{
+			name: "nok, invalid routes",
+			givenEcho: func() *Echo {
+				e := New()
+				e.RouterConfig.UniqueRouteNames = true
+				e.GET("/a", handlerFunc).Name = ""
+				return e
+			},
+			expectError: "echo: invalid route names: route GET /a has no name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := tc.givenEcho()
+
+			err := e.Freeze()
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.NotPanics(t, func() { e.GET("/b", handlerFunc) })
+			} else {
+				assert.NoError(t, err)
+				assert.Panics(t, func() { e.GET("/b", handlerFunc) })
+			}
+		})
+	}
+}
+
+func TestEcho_FreezePrecomputesRouteMiddleware(t *testing.T) {
+	e := New()
+	built := 0
+	e.GET("/", handlerFunc, func(next HandlerFunc) HandlerFunc {
+		built++
+		return next
+	})
+	assert.NoError(t, e.Freeze())
+	built = 0
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, 0, built)
+}
+
+func TestEcho_VerifyRoutesComponents(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenRenderer Renderer
+		givenMeta     Map
+		expectError   string
+	}{
+		{
+			name:      "ok, no annotations",
+			givenMeta: Map{},
+		},
+		{
+			name:          "ok, renderer is set",
+			givenRenderer: &Template{},
+			givenMeta:     Map{RouteMetaUsesRenderer: true},
+		},
+		{
+			name:        "nok, renderer missing",
+			givenMeta:   Map{RouteMetaUsesRenderer: true},
+			expectError: "echo: routes use missing components: route GET /page uses Renderer but Echo#Renderer is not set",
+		},
+		{
+			name:          "nok, validator missing for validation scenarios",
+			givenRenderer: &Template{},
+			givenMeta:     Map{RouteMetaUsesRenderer: true, RouteMetaValidationScenarios: []string{"create"}},
+			expectError:   "echo: routes use missing components: route GET /page uses Validator but Echo#Validator is not set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Renderer = tc.givenRenderer
+			meta := e.RouteMeta(e.GET("/page", handlerFunc))
+			for k, v := range tc.givenMeta {
+				meta[k] = v
+			}
+
+			err := e.VerifyRoutes()
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				assert.EqualError(t, e.Freeze(), tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func routeTestMiddleware(next HandlerFunc) HandlerFunc {
+	return next
+}
+
+func TestEcho_DescribeRoutes(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc, routeTestMiddleware).Name = "user"
+	e.POST("/users", handlerFunc).Name = "createUser"
+	e.POST("/users", handlerFunc).Name = "createUser2" // overrides previous registration
+	e.Host("api.example.com").GET("/v1/health", handlerFunc, routeTestMiddleware).Name = "health"
+
+	assert.Equal(t, []RouteDescription{
+		{Host: "", Method: http.MethodPost, Path: "/users", Name: "createUser2", Middleware: []string{}},
+		{Host: "", Method: http.MethodGet, Path: "/users/:id", Name: "user", Middleware: []string{"github.com/labstack/echo/v4.routeTestMiddleware"}},
+		{Host: "api.example.com", Method: http.MethodGet, Path: "/v1/health", Name: "health", Middleware: []string{"github.com/labstack/echo/v4.routeTestMiddleware"}},
+	}, e.DescribeRoutes())
+}
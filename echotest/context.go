@@ -0,0 +1,122 @@
+package echotest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ContextConfig describes context created by `NewContext`.
+type ContextConfig struct {
+	// Request is the request of context.
+	// Optional. Default is request created from Method and Route with path params filled in (i.e. `GET /users/1`
+	// for route `/users/:id` and param `id=1`) or `GET /` when Route is not set.
+	Request *http.Request
+
+	// Method is HTTP method of created request. Ignored when Request is set.
+	// Optional. Default value "GET".
+	Method string
+
+	// Route is path of the route context is matched to, i.e. "/users/:id". When route with the same method and path
+	// is registered to Echo instance `Context#Route` returns it (along with its metadata).
+	// Optional.
+	Route string
+
+	// PathParams are path parameters of context in order they appear in route path.
+	// Optional.
+	PathParams []PathParam
+
+	// QueryParams are added to query string of request.
+	// Optional.
+	QueryParams url.Values
+
+	// Header is added to request headers.
+	// Optional.
+	Header http.Header
+}
+
+// PathParam is name and value of path parameter.
+type PathParam struct {
+	Name  string
+	Value string
+}
+
+// NewContext creates context for testing handlers and middlewares directly (without routing), primed with path
+// params, matched route and query params. Response is written to returned recorder.
+//
+// Example:
+//
+//	c, rec := echotest.NewContext(e, echotest.ContextConfig{
+//		Route:      "/users/:id",
+//		PathParams: []echotest.PathParam{{Name: "id", Value: "1"}},
+//	})
+//	err := getUser(c)
+//	assert.Equal(t, http.StatusOK, rec.Code)
+func NewContext(e *echo.Echo, config ContextConfig) (echo.Context, *httptest.ResponseRecorder) {
+	req := config.Request
+	if req == nil {
+		method := config.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		target := "/"
+		if config.Route != "" {
+			target = routeTarget(config.Route, config.PathParams)
+		}
+		req = httptest.NewRequest(method, target, nil)
+	}
+	if len(config.QueryParams) > 0 {
+		q := req.URL.Query()
+		for k, values := range config.QueryParams {
+			for _, v := range values {
+				q.Add(k, v)
+			}
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+	for k, values := range config.Header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if config.Route != "" {
+		c.SetPath(config.Route)
+	}
+	if len(config.PathParams) > 0 {
+		names := make([]string, len(config.PathParams))
+		values := make([]string, len(config.PathParams))
+		for i, p := range config.PathParams {
+			names[i] = p.Name
+			values[i] = p.Value
+		}
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+	}
+	return c, rec
+}
+
+// routeTarget replaces params and wildcards in route path with path param values.
+func routeTarget(route string, params []PathParam) string {
+	values := make(map[string]string, len(params))
+	for _, p := range params {
+		values[p.Name] = p.Value
+	}
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if s == "" || (s[0] != ':' && s[0] != '*') {
+			continue
+		}
+		name := s[1:]
+		if s == "*" {
+			name = "*"
+		}
+		segments[i] = values[name]
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,62 @@
+package echotest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContext(t *testing.T) {
+	e := echo.New()
+	route := e.PUT("/users/:id/files/*", func(c echo.Context) error { return nil })
+	e.RouteMeta(route)["scope"] = "files"
+
+	c, rec := NewContext(e, ContextConfig{
+		Method: http.MethodPut,
+		Route:  "/users/:id/files/*",
+		PathParams: []PathParam{
+			{Name: "id", Value: "1"},
+			{Name: "*", Value: "a/b.txt"},
+		},
+		QueryParams: url.Values{"lang": {"en"}},
+		Header:      http.Header{"X-Test": {"yes"}},
+	})
+
+	assert.Equal(t, "/users/1/files/a/b.txt", c.Request().URL.Path)
+	assert.Equal(t, "1", c.Param("id"))
+	assert.Equal(t, "a/b.txt", c.Param("*"))
+	assert.Equal(t, "en", c.QueryParam("lang"))
+	assert.Equal(t, "yes", c.Request().Header.Get("X-Test"))
+	assert.Equal(t, "/users/:id/files/*", c.Path())
+	assert.Equal(t, route, c.Route())
+	assert.Equal(t, "files", c.Echo().RouteMeta(c.Route())["scope"])
+
+	assert.NoError(t, c.String(http.StatusAccepted, "ok"))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+}
+
+func TestNewContext_defaults(t *testing.T) {
+	c, _ := NewContext(echo.New(), ContextConfig{})
+
+	assert.Equal(t, http.MethodGet, c.Request().Method)
+	assert.Equal(t, "/", c.Request().URL.Path)
+	assert.Nil(t, c.Route())
+	assert.Empty(t, c.ParamNames())
+}
+
+func TestNewContext_withRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "/items?page=2", nil)
+	c, _ := NewContext(echo.New(), ContextConfig{
+		Request:     req,
+		Route:       "/items",
+		QueryParams: url.Values{"size": {"10"}},
+	})
+
+	assert.Equal(t, http.MethodPost, c.Request().Method)
+	assert.Equal(t, "2", c.QueryParam("page"))
+	assert.Equal(t, "10", c.QueryParam("size"))
+}
@@ -0,0 +1,292 @@
+/*
+Package echotest provides fluent client for testing Echo handlers without repeating `httptest` boilerplate.
+
+Requests are served in-process by `Echo#ServeHTTP` so whole middleware chain and router are exercised. Handlers and
+middlewares can also be called directly with context created by `NewContext`.
+
+Example:
+
+	func TestGetUser(t *testing.T) {
+		e := echo.New()
+		e.GET("/users/:id", getUser)
+
+		var user User
+		echotest.New(e).
+			GET("/users/1").
+			WithHeader(echo.HeaderAuthorization, "Bearer token").
+			Do().
+			AssertStatus(t, http.StatusOK).
+			AssertHeader(t, echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8).
+			DecodeJSON(t, &user)
+	}
+*/
+package echotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Client creates requests served by Echo instance. Headers and cookies set on client are added to every request.
+type Client struct {
+	echo    *echo.Echo
+	header  http.Header
+	cookies []*http.Cookie
+}
+
+// Request is request being built by `Client`. Request is sent with `Request#Do`.
+type Request struct {
+	client *Client
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	body   io.Reader
+	err    error
+}
+
+// Result is response of request served by Echo instance.
+type Result struct {
+	// Recorder is the recorder response was written to.
+	Recorder *httptest.ResponseRecorder
+	// Code is the response status code.
+	Code int
+	// Header is the response header map.
+	Header http.Header
+	// Body is the response body.
+	Body []byte
+}
+
+// New creates client sending requests to Echo instance.
+func New(e *echo.Echo) *Client {
+	return &Client{echo: e, header: http.Header{}}
+}
+
+// WithHeader sets header that is added to every request created by client.
+func (c *Client) WithHeader(name, value string) *Client {
+	c.header.Set(name, value)
+	return c
+}
+
+// WithCookie adds cookie that is sent with every request created by client.
+func (c *Client) WithCookie(cookie *http.Cookie) *Client {
+	c.cookies = append(c.cookies, cookie)
+	return c
+}
+
+// NewRequest creates request with given method and path. Path can contain query string.
+func (c *Client) NewRequest(method, path string) *Request {
+	r := &Request{
+		client: c,
+		method: method,
+		path:   path,
+		header: c.header.Clone(),
+		query:  url.Values{},
+	}
+	for _, cookie := range c.cookies {
+		r.WithCookie(cookie)
+	}
+	return r
+}
+
+// GET creates GET request for path.
+func (c *Client) GET(path string) *Request {
+	return c.NewRequest(http.MethodGet, path)
+}
+
+// HEAD creates HEAD request for path.
+func (c *Client) HEAD(path string) *Request {
+	return c.NewRequest(http.MethodHead, path)
+}
+
+// POST creates POST request for path.
+func (c *Client) POST(path string) *Request {
+	return c.NewRequest(http.MethodPost, path)
+}
+
+// PUT creates PUT request for path.
+func (c *Client) PUT(path string) *Request {
+	return c.NewRequest(http.MethodPut, path)
+}
+
+// PATCH creates PATCH request for path.
+func (c *Client) PATCH(path string) *Request {
+	return c.NewRequest(http.MethodPatch, path)
+}
+
+// DELETE creates DELETE request for path.
+func (c *Client) DELETE(path string) *Request {
+	return c.NewRequest(http.MethodDelete, path)
+}
+
+// OPTIONS creates OPTIONS request for path.
+func (c *Client) OPTIONS(path string) *Request {
+	return c.NewRequest(http.MethodOptions, path)
+}
+
+// WithHeader sets request header.
+func (r *Request) WithHeader(name, value string) *Request {
+	r.header.Set(name, value)
+	return r
+}
+
+// WithQuery adds query parameter to request.
+func (r *Request) WithQuery(name, value string) *Request {
+	r.query.Add(name, value)
+	return r
+}
+
+// WithCookie adds cookie to request.
+func (r *Request) WithCookie(cookie *http.Cookie) *Request {
+	if c := r.header.Get(echo.HeaderCookie); c != "" {
+		r.header.Set(echo.HeaderCookie, c+"; "+cookie.String())
+	} else {
+		r.header.Set(echo.HeaderCookie, cookie.String())
+	}
+	return r
+}
+
+// WithBody sets request body with given content type.
+func (r *Request) WithBody(contentType string, body io.Reader) *Request {
+	r.header.Set(echo.HeaderContentType, contentType)
+	r.body = body
+	return r
+}
+
+// WithJSON sets request body to value encoded as JSON.
+func (r *Request) WithJSON(v interface{}) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	return r.WithBody(echo.MIMEApplicationJSON, bytes.NewReader(b))
+}
+
+// WithForm sets request body to URL encoded form.
+func (r *Request) WithForm(values url.Values) *Request {
+	return r.WithBody(echo.MIMEApplicationForm, strings.NewReader(values.Encode()))
+}
+
+// Do sends request to Echo instance and returns result. Do panics when request can not be created
+// (i.e. value given to `WithJSON` can not be encoded) as it is a mistake in test.
+func (r *Request) Do() *Result {
+	if r.err != nil {
+		panic("echotest: can not create request: " + r.err.Error())
+	}
+	path := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + r.query.Encode()
+	}
+	req := httptest.NewRequest(r.method, path, r.body)
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	r.client.echo.ServeHTTP(rec, req)
+
+	return &Result{
+		Recorder: rec,
+		Code:     rec.Code,
+		Header:   rec.Header(),
+		Body:     rec.Body.Bytes(),
+	}
+}
+
+// String returns response body as string.
+func (r *Result) String() string {
+	return string(r.Body)
+}
+
+// Cookies returns cookies set by response.
+func (r *Result) Cookies() []*http.Cookie {
+	return r.Recorder.Result().Cookies()
+}
+
+// Cookie returns cookie with given name set by response or nil.
+func (r *Result) Cookie(name string) *http.Cookie {
+	for _, c := range r.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// BindJSON decodes JSON response body into v.
+func (r *Result) BindJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// DecodeJSON decodes JSON response body into v and fails test when body can not be decoded.
+func (r *Result) DecodeJSON(t testing.TB, v interface{}) *Result {
+	t.Helper()
+	if err := r.BindJSON(v); err != nil {
+		t.Errorf("echotest: can not decode response body %q as JSON: %v", r.Body, err)
+	}
+	return r
+}
+
+// AssertStatus fails test when response status code is not expected one.
+func (r *Result) AssertStatus(t testing.TB, code int) *Result {
+	t.Helper()
+	if r.Code != code {
+		t.Errorf("echotest: expected status code %d, got %d with body %q", code, r.Code, r.Body)
+	}
+	return r
+}
+
+// AssertHeader fails test when response header value is not expected one.
+func (r *Result) AssertHeader(t testing.TB, name, value string) *Result {
+	t.Helper()
+	if v := r.Header.Get(name); v != value {
+		t.Errorf("echotest: expected header %s value %q, got %q", name, value, v)
+	}
+	return r
+}
+
+// AssertBody fails test when response body is not expected one.
+func (r *Result) AssertBody(t testing.TB, body string) *Result {
+	t.Helper()
+	if string(r.Body) != body {
+		t.Errorf("echotest: expected body %q, got %q", body, r.Body)
+	}
+	return r
+}
+
+// AssertJSON fails test when JSON response body is not equal to expected value encoded as JSON. Documents are
+// compared semantically so formatting and order of object keys does not matter.
+func (r *Result) AssertJSON(t testing.TB, expected interface{}) *Result {
+	t.Helper()
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Errorf("echotest: can not encode expected value as JSON: %v", err)
+		return r
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Errorf("echotest: can not decode expected value as JSON: %v", err)
+		return r
+	}
+	if err := json.Unmarshal(r.Body, &got); err != nil {
+		t.Errorf("echotest: can not decode response body %q as JSON: %v", r.Body, err)
+		return r
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("echotest: expected JSON body %s, got %s", b, bytes.TrimSpace(r.Body))
+	}
+	return r
+}
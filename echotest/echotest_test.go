@@ -0,0 +1,108 @@
+package echotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int    `json:"id" form:"id" query:"id"`
+	Name string `json:"name" form:"name"`
+}
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newTestEcho() *echo.Echo {
+	e := echo.New()
+	e.GET("/users/:id", func(c echo.Context) error {
+		c.SetCookie(&http.Cookie{Name: "session", Value: "abc"})
+		return c.JSON(http.StatusOK, user{ID: 1, Name: c.QueryParam("name") + c.Request().Header.Get("X-Suffix")})
+	})
+	e.POST("/users", func(c echo.Context) error {
+		u := new(user)
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+		cookie, err := c.Cookie("token")
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusCreated, fmt.Sprintf("%d:%s:%s", u.ID, u.Name, cookie.Value))
+	})
+	return e
+}
+
+func TestClient_GET(t *testing.T) {
+	var u user
+	res := New(newTestEcho()).
+		WithHeader("X-Suffix", "!").
+		GET("/users/1").
+		WithQuery("name", "Jon").
+		Do().
+		AssertStatus(t, http.StatusOK).
+		AssertHeader(t, echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8).
+		AssertJSON(t, map[string]interface{}{"name": "Jon!", "id": 1}).
+		DecodeJSON(t, &u)
+
+	assert.Equal(t, user{ID: 1, Name: "Jon!"}, u)
+	assert.Equal(t, "abc", res.Cookie("session").Value)
+	assert.Nil(t, res.Cookie("missing"))
+}
+
+func TestClient_POST(t *testing.T) {
+	c := New(newTestEcho()).WithCookie(&http.Cookie{Name: "token", Value: "t1"})
+
+	c.POST("/users").
+		WithJSON(user{ID: 2, Name: "Jane"}).
+		Do().
+		AssertStatus(t, http.StatusCreated).
+		AssertBody(t, "2:Jane:t1")
+
+	c.POST("/users").
+		WithForm(url.Values{"id": {"3"}, "name": {"Joe"}}).
+		Do().
+		AssertStatus(t, http.StatusCreated).
+		AssertBody(t, "3:Joe:t1")
+}
+
+func TestResult_assertionsFail(t *testing.T) {
+	rt := &recordingT{TB: t}
+
+	var u user
+	New(newTestEcho()).
+		GET("/not-found").
+		Do().
+		AssertStatus(rt, http.StatusOK).
+		AssertHeader(rt, "X-Missing", "value").
+		AssertBody(rt, "body").
+		AssertJSON(rt, map[string]string{"message": "Bad Request"}).
+		DecodeJSON(rt, &u)
+
+	assert.Equal(t, []string{
+		`echotest: expected status code 200, got 404 with body "{\"message\":\"Not Found\"}\n"`,
+		`echotest: expected header X-Missing value "value", got ""`,
+		`echotest: expected body "body", got "{\"message\":\"Not Found\"}\n"`,
+		`echotest: expected JSON body {"message":"Bad Request"}, got {"message":"Not Found"}`,
+	}, rt.errors)
+}
+
+func TestRequest_DoPanicsOnInvalidJSON(t *testing.T) {
+	assert.Panics(t, func() {
+		New(newTestEcho()).POST("/users").WithJSON(make(chan int)).Do()
+	})
+}
@@ -0,0 +1,111 @@
+//go:build go1.18
+// +build go1.18
+
+package echotest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BindSeed is seed corpus entry for `FuzzBind`.
+type BindSeed struct {
+	// Param is value of path parameter "id".
+	Param string
+	// Query is raw query string of request.
+	Query string
+	// ContentType is Content-Type header of request.
+	ContentType string
+	// Body is request body.
+	Body []byte
+}
+
+// DefaultBindSeeds is seed corpus added by `FuzzBind` covering formats default binder supports.
+var DefaultBindSeeds = []BindSeed{
+	{Param: "1", Query: "id=1&name=jon", ContentType: echo.MIMEApplicationJSON, Body: []byte(`{"id":1,"name":"jon"}`)},
+	{Query: "tags=a&tags=b", ContentType: echo.MIMEApplicationForm, Body: []byte("id=1&name=jon&tags=a")},
+	{ContentType: echo.MIMEApplicationXML, Body: []byte("<user><id>1</id><name>jon</name></user>")},
+	{ContentType: echo.MIMEMultipartForm + "; boundary=x", Body: []byte("--x\r\nContent-Disposition: form-data; name=\"id\"\r\n\r\n1\r\n--x--\r\n")},
+	{Param: "%zz", Query: "id=%zz&=&&", ContentType: echo.MIMEApplicationJSON, Body: []byte(`{"id":"1"`)},
+	{Query: "id=99999999999999999999", ContentType: echo.MIMEApplicationJSON, Body: []byte(`[]`)},
+}
+
+// RouterSeeds returns seed corpus for `FuzzRouterLookup` consisting of paths of routes registered to Echo instance
+// and paths exercising edge cases of router (empty segments, escaped characters, wildcards).
+func RouterSeeds(e *echo.Echo) []string {
+	seeds := []string{"", "/", "//", "/%2F", "/a%20b", "/*", "/:id", "/a/../b", strings.Repeat("/a", 64)}
+	for _, r := range e.Routes() {
+		seeds = append(seeds, r.Path, r.Path+"/", strings.NewReplacer(":", "x", "*", "x/y").Replace(r.Path))
+	}
+	return seeds
+}
+
+// FuzzBind fuzzes binding of requests (path param "id", query string, content type and body) into values created by
+// newTarget using binder of Echo instance. Binding must not panic, errors are expected. Seeds are added to
+// `DefaultBindSeeds`.
+//
+// Example (in _test.go file):
+//
+//	func FuzzCreateUserBind(f *testing.F) {
+//		echotest.FuzzBind(f, newServer(), func() interface{} { return new(CreateUserRequest) })
+//	}
+func FuzzBind(f *testing.F, e *echo.Echo, newTarget func() interface{}, seeds ...BindSeed) {
+	for _, s := range append(append([]BindSeed(nil), DefaultBindSeeds...), seeds...) {
+		f.Add(s.Param, s.Query, s.ContentType, s.Body)
+	}
+	f.Fuzz(func(t *testing.T, param, query, contentType string, body []byte) {
+		req := &http.Request{
+			Method:        http.MethodPost,
+			URL:           &url.URL{Path: "/users/" + param, RawQuery: query},
+			Header:        http.Header{},
+			Body:          http.NoBody,
+			ContentLength: int64(len(body)),
+		}
+		if len(body) > 0 {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		req.Header.Set(echo.HeaderContentType, contentType)
+
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetPath("/users/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(param)
+
+		_ = c.Bind(newTarget())
+	})
+}
+
+// FuzzRouterLookup fuzzes route lookup of Echo instance default router with method and path. Lookup must not panic,
+// must always result in handler and path parameter values must be parts of the path. Seeds are added to
+// `RouterSeeds`.
+//
+// Example (in _test.go file):
+//
+//	func FuzzRoutes(f *testing.F) {
+//		echotest.FuzzRouterLookup(f, newServer())
+//	}
+func FuzzRouterLookup(f *testing.F, e *echo.Echo, seeds ...string) {
+	for _, path := range append(RouterSeeds(e), seeds...) {
+		f.Add(http.MethodGet, path)
+	}
+	f.Fuzz(func(t *testing.T, method, path string) {
+		c := e.NewContext(nil, nil)
+		e.Router().Find(method, path, c)
+
+		if c.Handler() == nil {
+			t.Fatalf("no handler for %s %q", method, path)
+		}
+		for _, name := range c.ParamNames() {
+			if v := c.Param(name); !strings.Contains(path, v) {
+				t.Fatalf("param %q value %q is not part of path %q", name, v, path)
+			}
+		}
+	})
+}
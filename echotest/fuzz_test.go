@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package echotest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type fuzzUser struct {
+	ID    int      `param:"id" query:"id" form:"id" json:"id" xml:"id"`
+	Name  string   `query:"name" form:"name" json:"name" xml:"name"`
+	Tags  []string `query:"tags" form:"tags" json:"tags" xml:"tags"`
+	Score *float64 `query:"score" form:"score" json:"score" xml:"score"`
+}
+
+func FuzzBindDefaultBinder(f *testing.F) {
+	FuzzBind(f, echo.New(), func() interface{} { return new(fuzzUser) })
+}
+
+func FuzzRouterLookupRoutes(f *testing.F) {
+	e := echo.New()
+	h := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/", h)
+	e.GET("/users/:id", h)
+	e.POST("/users/:id/files/*", h)
+	e.GET("/static/*filepath/meta", h)
+	e.GET("/:a/:b/:c", h)
+
+	FuzzRouterLookup(f, e, "/users/1/files/a/b")
+}
@@ -0,0 +1,186 @@
+package echotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SnapshotUpdateEnv is environment variable that makes snapshot functions overwrite existing snapshot files
+// instead of comparing results to them, i.e. `ECHOTEST_UPDATE_SNAPSHOTS=1 go test ./...`.
+const SnapshotUpdateEnv = "ECHOTEST_UPDATE_SNAPSHOTS"
+
+// snapshotRedacted replaces redacted values in snapshots.
+const snapshotRedacted = "[REDACTED]"
+
+// SnapshotConfig defines how results are serialized to snapshot files.
+type SnapshotConfig struct {
+	// Dir is directory where snapshot files are stored. Snapshot file name is derived from test name.
+	// Optional. Default value "testdata/snapshots".
+	Dir string
+
+	// Headers are response headers included in snapshot.
+	// Optional. Default value ["Content-Type"].
+	Headers []string
+
+	// RedactJSONFields are names of JSON object fields which values are replaced with "[REDACTED]" at any depth
+	// of JSON body, i.e. generated ids and timestamps.
+	// Optional.
+	RedactJSONFields []string
+
+	// Redact are patterns which matches are replaced with "[REDACTED]" in serialized snapshot (headers and body).
+	// Optional.
+	Redact []*regexp.Regexp
+}
+
+// DefaultSnapshotConfig is the default config for `Snapshot`.
+var DefaultSnapshotConfig = SnapshotConfig{
+	Dir:     filepath.Join("testdata", "snapshots"),
+	Headers: []string{echo.HeaderContentType},
+}
+
+// Snapshot compares result status, Content-Type header and body to snapshot file created from test name. JSON
+// bodies are normalized (indented, object keys sorted) so formatting differences do not break snapshots. Missing
+// snapshot file is created. Files are overwritten when `ECHOTEST_UPDATE_SNAPSHOTS` environment variable is set.
+//
+// Snapshot file format is:
+//
+//	HTTP 200
+//	Content-Type: application/json; charset=UTF-8
+//
+//	{
+//	  "id": 1
+//	}
+func Snapshot(t testing.TB, r *Result) *Result {
+	t.Helper()
+	return SnapshotWithConfig(t, r, DefaultSnapshotConfig)
+}
+
+// SnapshotWithConfig compares result to snapshot file serialized according to config.
+// See: `Snapshot()`.
+func SnapshotWithConfig(t testing.TB, r *Result, config SnapshotConfig) *Result {
+	t.Helper()
+	if config.Dir == "" {
+		config.Dir = DefaultSnapshotConfig.Dir
+	}
+	if config.Headers == nil {
+		config.Headers = DefaultSnapshotConfig.Headers
+	}
+
+	matchSnapshot(t, config.Dir, serializeSnapshot(r, config))
+	return r
+}
+
+// SnapshotRoutes compares route table of Echo instance (see `Echo#DescribeRoutes`) serialized as JSON to snapshot
+// file in "testdata/snapshots" directory created from test name. Missing snapshot file is created. Files are
+// overwritten when `ECHOTEST_UPDATE_SNAPSHOTS` environment variable is set.
+func SnapshotRoutes(t testing.TB, e *echo.Echo) {
+	t.Helper()
+	b, err := json.MarshalIndent(e.DescribeRoutes(), "", "  ")
+	if err != nil {
+		t.Errorf("echotest: can not serialize routes: %v", err)
+		return
+	}
+	matchSnapshot(t, DefaultSnapshotConfig.Dir, string(b)+"\n")
+}
+
+func matchSnapshot(t testing.TB, dir string, actual string) {
+	t.Helper()
+	file := filepath.Join(dir, snapshotFileName(t.Name()))
+
+	expected, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) || os.Getenv(SnapshotUpdateEnv) != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Errorf("echotest: can not create snapshot directory: %v", err)
+			return
+		}
+		if err := ioutil.WriteFile(file, []byte(actual), 0644); err != nil {
+			t.Errorf("echotest: can not write snapshot: %v", err)
+			return
+		}
+		t.Logf("echotest: snapshot %s written", file)
+		return
+	}
+	if err != nil {
+		t.Errorf("echotest: can not read snapshot: %v", err)
+		return
+	}
+	if string(expected) != actual {
+		t.Errorf("echotest: result does not match snapshot %s (set %s=1 to update)\nexpected:\n%s\nactual:\n%s",
+			file, SnapshotUpdateEnv, expected, actual)
+	}
+}
+
+func serializeSnapshot(r *Result, config SnapshotConfig) string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "HTTP %d\n", r.Code)
+	for _, h := range config.Headers {
+		for _, v := range r.Header[textproto.CanonicalMIMEHeaderKey(h)] {
+			fmt.Fprintf(buf, "%s: %s\n", http.CanonicalHeaderKey(h), v)
+		}
+	}
+	buf.WriteString("\n")
+	buf.Write(normalizeSnapshotBody(r.Body, config.RedactJSONFields))
+
+	s := buf.String()
+	for _, re := range config.Redact {
+		s = re.ReplaceAllString(s, snapshotRedacted)
+	}
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	return s
+}
+
+func normalizeSnapshotBody(body []byte, redactFields []string) []byte {
+	var v interface{}
+	if len(bytes.TrimSpace(body)) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	if len(redactFields) > 0 {
+		fields := make(map[string]bool, len(redactFields))
+		for _, f := range redactFields {
+			fields[f] = true
+		}
+		v = redactJSON(v, fields)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+func redactJSON(v interface{}, fields map[string]bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, value := range t {
+			if fields[k] {
+				t[k] = snapshotRedacted
+			} else {
+				t[k] = redactJSON(value, fields)
+			}
+		}
+	case []interface{}:
+		for i, value := range t {
+			t[i] = redactJSON(value, fields)
+		}
+	}
+	return v
+}
+
+var snapshotNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+func snapshotFileName(testName string) string {
+	return snapshotNameReplacer.ReplaceAllString(testName, "_") + ".snap"
+}
@@ -0,0 +1,99 @@
+package echotest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSnapshotEcho() *echo.Echo {
+	e := echo.New()
+	e.GET("/orders/:id", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderXRequestID, time.Now().String())
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"id":      c.Param("id"),
+			"created": time.Now().Format(time.RFC3339Nano),
+			"items":   []map[string]interface{}{{"sku": "A-1", "token": "secret-1"}},
+		})
+	})
+	return e
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		res := New(newSnapshotEcho()).GET("/orders/42").Do()
+		SnapshotWithConfig(t, res, SnapshotConfig{
+			RedactJSONFields: []string{"created", "token"},
+		})
+	})
+	t.Run("not found", func(t *testing.T) {
+		Snapshot(t, New(echo.New()).GET("/missing").Do())
+	})
+}
+
+func TestSnapshotWithConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "echotest-snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := SnapshotConfig{
+		Dir:              dir,
+		Headers:          []string{echo.HeaderContentType, echo.HeaderXRequestID},
+		RedactJSONFields: []string{"created"},
+		Redact:           []*regexp.Regexp{regexp.MustCompile(`(?m)^X-Request-Id: .*$`), regexp.MustCompile(`secret-\d+`)},
+	}
+	e := newSnapshotEcho()
+
+	// missing snapshot is created
+	rt := &recordingT{TB: t}
+	SnapshotWithConfig(rt, New(e).GET("/orders/1").Do(), config)
+	assert.Empty(t, rt.errors)
+
+	snapshot, err := ioutil.ReadFile(filepath.Join(dir, "TestSnapshotWithConfig.snap"))
+	assert.NoError(t, err)
+	assert.Equal(t, `HTTP 200
+Content-Type: application/json; charset=UTF-8
+[REDACTED]
+
+{
+  "created": "[REDACTED]",
+  "id": "1",
+  "items": [
+    {
+      "sku": "A-1",
+      "token": "[REDACTED]"
+    }
+  ]
+}
+`, string(snapshot))
+
+	// same result matches snapshot
+	SnapshotWithConfig(rt, New(e).GET("/orders/1").Do(), config)
+	assert.Empty(t, rt.errors)
+
+	// different result does not match
+	SnapshotWithConfig(rt, New(e).GET("/orders/2").Do(), config)
+	if assert.Len(t, rt.errors, 1) {
+		assert.Contains(t, rt.errors[0], "result does not match snapshot")
+	}
+}
+
+func TestSnapshotFileName(t *testing.T) {
+	assert.Equal(t, "TestA_case_1_ok_.snap", snapshotFileName("TestA/case 1 (ok)"))
+}
+
+func TestSnapshotRoutes(t *testing.T) {
+	e := echo.New()
+	e.GET("/orders/:id", func(c echo.Context) error { return nil }).Name = "order"
+	e.POST("/orders", func(c echo.Context) error { return nil }, middleware.BodyLimit("1M")).Name = "createOrder"
+
+	SnapshotRoutes(t, e)
+}
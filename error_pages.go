@@ -0,0 +1,128 @@
+package echo
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+)
+
+type (
+	// ErrorPages renders HTML error pages for clients preferring HTML (i.e. browsers) in
+	// `Echo#DefaultHTTPErrorHandler`. Template for status code is looked up by name in order: "404.html",
+	// "4xx.html" and "error.html". Errors without matching template are sent as JSON.
+	ErrorPages struct {
+		templates *template.Template
+	}
+
+	// ErrorPageData is the data error page templates are executed with.
+	ErrorPageData struct {
+		// Code is the HTTP status code.
+		Code int
+		// Status is the status text of code (i.e. "Not Found").
+		Status string
+		// Message is the message of `HTTPError`.
+		Message string
+		// Path is the path of request URL.
+		Path string
+		// RequestID is the ID of request from `X-Request-ID` header of request or response.
+		RequestID string
+	}
+)
+
+// handledErrorKey is the context store key of error handled by handler registered with `Echo#ErrorPage`.
+const handledErrorKey = "echo.handled_error"
+
+// ErrorPage registers handler for errors with status code (i.e. 404 for unknown routes) sent by
+// `Echo#DefaultHTTPErrorHandler`. Handler gets the original error with `HandledError`. When handler returns error
+// it is logged and error is sent as if no handler was registered.
+//
+// Example:
+//
+//	e.ErrorPage(http.StatusNotFound, func(c echo.Context) error {
+//		return c.Render(http.StatusNotFound, "404.html", echo.HandledError(c))
+//	})
+func (e *Echo) ErrorPage(code int, h HandlerFunc) {
+	e.checkNotFrozen()
+	e.errorPages[code] = h
+}
+
+// HandledError returns error handled by handler registered with `Echo#ErrorPage` or nil when called outside of
+// such handler.
+func HandledError(c Context) error {
+	err, _ := c.Get(handledErrorKey).(error)
+	return err
+}
+
+// serveErrorPage sends error with handler registered for status code. Returns false when there is no handler or
+// handler failed without sending response.
+func (e *Echo) serveErrorPage(c Context, code int, err error) bool {
+	h, ok := e.errorPages[code]
+	if !ok {
+		return false
+	}
+	c.Set(handledErrorKey, err)
+	if herr := h(c); herr != nil {
+		e.Logger.Error(herr)
+		return c.Response().Committed
+	}
+	return true
+}
+
+// NewErrorPages creates ErrorPages rendering templates named by status code (see `ErrorPages`).
+//
+// Example:
+//
+//	e.ErrorPages = echo.NewErrorPages(template.Must(template.ParseGlob("views/errors/*.html")))
+func NewErrorPages(templates *template.Template) *ErrorPages {
+	return &ErrorPages{templates: templates}
+}
+
+// lookup returns template of status code or nil when there is no template for it.
+func (p *ErrorPages) lookup(code int) *template.Template {
+	s := strconv.Itoa(code)
+	for _, name := range []string{s + ".html", s[:1] + "xx.html", "error.html"} {
+		if t := p.templates.Lookup(name); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// render sends error page of status code to client preferring HTML. Returns false when error page is not sent
+// because pages are not configured, client does not prefer HTML or there is no template for status code.
+func (p *ErrorPages) render(c Context, code int, message interface{}) (bool, error) {
+	if p == nil || !prefersHTML(c) {
+		return false, nil
+	}
+	t := p.lookup(code)
+	if t == nil {
+		return false, nil
+	}
+	data := ErrorPageData{
+		Code:      code,
+		Status:    http.StatusText(code),
+		Path:      c.Request().URL.Path,
+		RequestID: c.Request().Header.Get(HeaderXRequestID),
+	}
+	if data.RequestID == "" {
+		data.RequestID = c.Response().Header().Get(HeaderXRequestID)
+	}
+	switch m := message.(type) {
+	case string:
+		data.Message = m
+	case Map:
+		data.Message, _ = m["message"].(string)
+	}
+
+	buf := c.Echo().AcquireBuffer()
+	defer c.Echo().ReleaseBuffer(buf)
+	if err := t.Execute(buf, data); err != nil {
+		return true, err
+	}
+	return true, c.HTMLBlob(code, buf.Bytes())
+}
+
+// prefersHTML checks if client prefers HTML over JSON (i.e. browser navigation).
+func prefersHTML(c Context) bool {
+	return NegotiateMediaType(c, MIMEApplicationJSON, MIMETextHTML) == MIMETextHTML
+}
@@ -0,0 +1,35 @@
+//go:build go1.16
+// +build go1.16
+
+package echo
+
+import (
+	"html/template"
+	"io/fs"
+)
+
+// NewErrorPagesFS creates ErrorPages with templates parsed from files of fsys matching patterns (see
+// `template.ParseFS`). Templates are named by base name of their files (i.e. "404.html").
+//
+// Example:
+//
+//	//go:embed errors/*.html
+//	var errorPages embed.FS
+//	...
+//	e.ErrorPages = echo.MustErrorPagesFS(errorPages, "errors/*.html")
+func NewErrorPagesFS(fsys fs.FS, patterns ...string) (*ErrorPages, error) {
+	t, err := template.ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return NewErrorPages(t), nil
+}
+
+// MustErrorPagesFS is like `NewErrorPagesFS` but panics when templates can not be parsed.
+func MustErrorPagesFS(fsys fs.FS, patterns ...string) *ErrorPages {
+	p, err := NewErrorPagesFS(fsys, patterns...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,36 @@
+//go:build go1.16
+// +build go1.16
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorPagesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"errors/404.html": {Data: []byte(`not found: {{.Path}}`)},
+		"errors/500.html": {Data: []byte(`oops`)},
+	}
+	e := New()
+	e.ErrorPages = MustErrorPagesFS(fsys, "errors/*.html")
+
+	req := httptest.NewRequest(http.MethodGet, "/nope", nil)
+	req.Header.Set(HeaderAccept, "text/html")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "not found: /nope", rec.Body.String())
+
+	_, err := NewErrorPagesFS(fsys, "missing/*.html")
+	assert.EqualError(t, err, "template: pattern matches no files: `missing/*.html`")
+	assert.Panics(t, func() {
+		MustErrorPagesFS(fsys, "missing/*.html")
+	})
+}
@@ -0,0 +1,181 @@
+package echo
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorPages(t *testing.T) {
+	templates := template.Must(template.New("404.html").Parse(`<h1>{{.Status}}</h1><p>{{.Path}} {{.RequestID}}</p>`))
+	template.Must(templates.New("5xx.html").Parse(`<h1>{{.Code}} {{.Message}}</h1>`))
+
+	var testCases = []struct {
+		name       string
+		whenURL    string
+		whenAccept string
+		expectCode int
+		expectType string
+		expectBody string
+	}{
+		{
+			name:       "ok, page of status code",
+			whenURL:    "/missing",
+			whenAccept: "text/html,*/*;q=0.8",
+			expectCode: http.StatusNotFound,
+			expectType: MIMETextHTMLCharsetUTF8,
+			expectBody: "<h1>Not Found</h1><p>/missing abc</p>",
+		},
+		{
+			name:       "ok, page of status class",
+			whenURL:    "/unavailable",
+			whenAccept: "text/html",
+			expectCode: http.StatusServiceUnavailable,
+			expectType: MIMETextHTMLCharsetUTF8,
+			expectBody: "<h1>503 down for &lt;maintenance&gt;</h1>",
+		},
+		{
+			name:       "ok, status without page is sent as JSON",
+			whenURL:    "/forbidden",
+			whenAccept: "text/html",
+			expectCode: http.StatusForbidden,
+			expectType: MIMEApplicationJSONCharsetUTF8,
+			expectBody: "{\"message\":\"Forbidden\"}\n",
+		},
+		{
+			name:       "ok, client preferring JSON",
+			whenURL:    "/missing",
+			whenAccept: "application/json, text/html;q=0.9",
+			expectCode: http.StatusNotFound,
+			expectType: MIMEApplicationJSONCharsetUTF8,
+			expectBody: "{\"message\":\"Not Found\"}\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.ErrorPages = NewErrorPages(templates)
+			e.GET("/unavailable", func(c Context) error {
+				return NewHTTPError(http.StatusServiceUnavailable, "down for <maintenance>")
+			})
+			e.GET("/forbidden", func(c Context) error {
+				return ErrForbidden
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			req.Header.Set(HeaderAccept, tc.whenAccept)
+			req.Header.Set(HeaderXRequestID, "abc")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectType, rec.Header().Get(HeaderContentType))
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestErrorPages_fallback(t *testing.T) {
+	templates := template.Must(template.New("error.html").Parse(`{{.Code}}`))
+	template.Must(templates.New("4xx.html").Parse(`client {{.Code}}`))
+	p := NewErrorPages(templates)
+
+	assert.Equal(t, "4xx.html", p.lookup(http.StatusNotFound).Name())
+	assert.Equal(t, "error.html", p.lookup(http.StatusInternalServerError).Name())
+}
+
+func TestEcho_ErrorPage(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenURL    string
+		whenMethod string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "ok, not found handler",
+			whenURL:    "/missing",
+			whenMethod: http.MethodGet,
+			expectCode: http.StatusNotFound,
+			expectBody: "page not found: code=404, message=Not Found",
+		},
+		{
+			name:       "ok, method not allowed handler",
+			whenURL:    "/users",
+			whenMethod: http.MethodDelete,
+			expectCode: http.StatusMethodNotAllowed,
+			expectBody: "try GET",
+		},
+		{
+			name:       "ok, internal error handler gets original error",
+			whenURL:    "/users",
+			whenMethod: http.MethodGet,
+			expectCode: http.StatusInternalServerError,
+			expectBody: "sorry: db is down",
+		},
+		{
+			name:       "ok, failing handler falls back to default response",
+			whenURL:    "/teapot",
+			whenMethod: http.MethodGet,
+			expectCode: http.StatusTeapot,
+			expectBody: "{\"message\":\"I'm a teapot\"}\n",
+		},
+		{
+			name:       "ok, status without handler",
+			whenURL:    "/forbidden",
+			whenMethod: http.MethodGet,
+			expectCode: http.StatusForbidden,
+			expectBody: "{\"message\":\"Forbidden\"}\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.ErrorPage(http.StatusNotFound, func(c Context) error {
+				return c.String(http.StatusNotFound, "page not found: "+HandledError(c).Error())
+			})
+			e.ErrorPage(http.StatusMethodNotAllowed, func(c Context) error {
+				return c.String(http.StatusMethodNotAllowed, "try GET")
+			})
+			e.ErrorPage(http.StatusInternalServerError, func(c Context) error {
+				return c.String(http.StatusInternalServerError, "sorry: "+HandledError(c).Error())
+			})
+			e.ErrorPage(http.StatusTeapot, func(c Context) error {
+				return errors.New("teapot page failed")
+			})
+			e.GET("/users", func(c Context) error {
+				return errors.New("db is down")
+			})
+			e.GET("/teapot", func(c Context) error {
+				return NewHTTPError(http.StatusTeapot)
+			})
+			e.GET("/forbidden", func(c Context) error {
+				return ErrForbidden
+			})
+
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEcho_ErrorPage_clone(t *testing.T) {
+	e := New()
+	e.ErrorPage(http.StatusNotFound, func(c Context) error {
+		return c.String(http.StatusNotFound, "custom")
+	})
+	c := e.Clone()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	assert.Equal(t, "custom", rec.Body.String())
+}
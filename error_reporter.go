@@ -0,0 +1,85 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type (
+	// ErrorReporter reports errors to crash reporting services (i.e. Sentry). It is set with `Echo#ErrorReporter` and
+	// called by `Echo#DefaultHTTPErrorHandler` for server errors (5xx) and by `middleware.Recover` for panics, see
+	// `Echo#ReportError`.
+	//
+	// ReportError is called synchronously while request is handled so implementations should not block but queue
+	// reports and send them in background (i.e. in batches). Context must not be retained after ReportError returns,
+	// use `NewErrorReport` to copy request data reports need.
+	ErrorReporter interface {
+		ReportError(c Context, err error, stack []byte)
+	}
+
+	// ErrorReporterFunc is an adapter to use ordinary function as `ErrorReporter`.
+	ErrorReporterFunc func(c Context, err error, stack []byte)
+
+	// ErrorReport is a snapshot of reported error and request it happened in. It is safe to use after request is
+	// finished.
+	ErrorReport struct {
+		Time      time.Time
+		Error     error
+		Code      int
+		Stack     []byte
+		RequestID string
+		Method    string
+		URI       string
+		Route     string
+		RemoteIP  string
+		UserAgent string
+	}
+)
+
+// reportedErrorKey is the context store key for the last error reported with `Echo#ReportError` (`error`).
+const reportedErrorKey = "echo.reported_error"
+
+// ReportError implements `ErrorReporter`.
+func (f ErrorReporterFunc) ReportError(c Context, err error, stack []byte) {
+	f(c, err, stack)
+}
+
+// NewErrorReport creates report of error with data copied from request.
+func NewErrorReport(c Context, err error, stack []byte) ErrorReport {
+	req := c.Request()
+	r := ErrorReport{
+		Time:      time.Now(),
+		Error:     err,
+		Code:      http.StatusInternalServerError,
+		Stack:     stack,
+		RequestID: req.Header.Get(HeaderXRequestID),
+		Method:    req.Method,
+		URI:       req.RequestURI,
+		Route:     c.Path(),
+		RemoteIP:  c.RealIP(),
+		UserAgent: req.UserAgent(),
+	}
+	if r.RequestID == "" {
+		r.RequestID = c.Response().Header().Get(HeaderXRequestID)
+	}
+	var he *HTTPError
+	if errors.As(err, &he) {
+		r.Code = he.Code
+	}
+	return r
+}
+
+// ReportError passes error with its stack trace (see `StackTracer`) to `Echo#ErrorReporter`. Error is reported once
+// per request even when ReportError is called for it multiple times (i.e. by `middleware.Recover` and error
+// handler) or for error wrapping it. Nothing is done when ErrorReporter is not set.
+func (e *Echo) ReportError(c Context, err error) {
+	if e.ErrorReporter == nil || err == nil {
+		return
+	}
+	if reported, ok := c.Get(reportedErrorKey).(error); ok && errors.Is(err, reported) {
+		return
+	}
+	c.Set(reportedErrorKey, err)
+	e.ErrorReporter.ReportError(c, err, errorStack(err))
+}
@@ -0,0 +1,93 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_ErrorReporter(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenErr      error
+		expectReport bool
+		expectCode   int
+		expectStack  string
+	}{
+		{
+			name:         "ok, server error is reported",
+			whenErr:      errors.New("database unavailable"),
+			expectReport: true,
+			expectCode:   http.StatusInternalServerError,
+		},
+		{
+			name:         "ok, http error with stack is reported",
+			whenErr:      &HTTPError{Code: http.StatusBadGateway, Message: "upstream", stack: []byte("stack-marker")},
+			expectReport: true,
+			expectCode:   http.StatusBadGateway,
+			expectStack:  "stack-marker",
+		},
+		{
+			name:    "ok, client error is not reported",
+			whenErr: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var reports []ErrorReport
+			e := New()
+			e.ErrorReporter = ErrorReporterFunc(func(c Context, err error, stack []byte) {
+				reports = append(reports, NewErrorReport(c, err, stack))
+			})
+			e.GET("/orders/:id", func(c Context) error {
+				return tc.whenErr
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+			req.Header.Set(HeaderXRequestID, "rid-1")
+			req.Header.Set("User-Agent", "test-agent")
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !tc.expectReport {
+				assert.Len(t, reports, 0)
+				return
+			}
+			if assert.Len(t, reports, 1) {
+				r := reports[0]
+				assert.Equal(t, tc.whenErr, r.Error)
+				assert.Equal(t, tc.expectCode, r.Code)
+				assert.Equal(t, tc.expectStack, string(r.Stack))
+				assert.Equal(t, "rid-1", r.RequestID)
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/orders/1", r.URI)
+				assert.Equal(t, "/orders/:id", r.Route)
+				assert.Equal(t, "192.0.2.1", r.RemoteIP)
+				assert.Equal(t, "test-agent", r.UserAgent)
+				assert.False(t, r.Time.IsZero())
+			}
+		})
+	}
+}
+
+func TestEcho_ReportError_once(t *testing.T) {
+	count := 0
+	e := New()
+	e.ErrorReporter = ErrorReporterFunc(func(c Context, err error, stack []byte) {
+		count++
+	})
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	cause := errors.New("panic")
+	e.ReportError(c, cause)
+	e.ReportError(c, cause)
+	e.ReportError(c, NewHTTPError(http.StatusInternalServerError).SetInternal(cause))
+	e.ReportError(c, nil)
+	assert.Equal(t, 1, count)
+
+	e.ReportError(c, errors.New("other"))
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,125 @@
+package echo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/labstack/gommon/log"
+)
+
+// debugRequests is number of requests being served by Echo instances in debug mode. Stack traces are captured by
+// `NewHTTPError` and `WrapError` only while it is positive, so there is no cost when no instance is in debug mode.
+var debugRequests int32
+
+// maxErrorStackDepth is maximum number of frames captured in error stack trace.
+const maxErrorStackDepth = 32
+
+// WrapError creates a new HTTPError instance with err as internal error. Unlike `NewHTTPError(code).SetInternal(err)`
+// stack trace is captured at the caller of WrapError (see `HTTPError#StackTrace`).
+func WrapError(err error, code int, message ...interface{}) *HTTPError {
+	he := &HTTPError{Code: code, Message: http.StatusText(code), Internal: err, stack: captureErrorStack()}
+	if len(message) > 0 {
+		he.Message = message[0]
+	}
+	return he
+}
+
+// StackTrace returns stack trace of where error was created or nil when it was not captured. Stack traces are captured
+// while requests are served by Echo instance in debug mode (see `Echo#Debug`) and only such instance logs them and
+// shows them on error page. Implements `StackTracer`.
+func (he *HTTPError) StackTrace() []byte {
+	return he.stack
+}
+
+// stackTraceError is error with stack trace added by `Echo#DefaultHTTPErrorHandler` in debug mode.
+type stackTraceError struct {
+	error
+	stack []byte
+}
+
+// Unwrap returns wrapped error.
+func (e *stackTraceError) Unwrap() error {
+	return e.error
+}
+
+// StackTrace returns stack trace of where error was handled.
+func (e *stackTraceError) StackTrace() []byte {
+	return e.stack
+}
+
+// captureErrorStack returns stack trace of the caller of function calling it or nil when no request is served in
+// debug mode.
+func captureErrorStack() []byte {
+	if atomic.LoadInt32(&debugRequests) == 0 {
+		return nil
+	}
+	return callerStack(4) // skip runtime.Callers, callerStack, captureErrorStack and error constructor
+}
+
+// callerStack returns stack trace of current goroutine skipping given number of frames (see `runtime.Callers`).
+func callerStack(skip int) []byte {
+	pcs := make([]uintptr, maxErrorStackDepth)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	buf := new(bytes.Buffer)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(buf, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return buf.Bytes()
+}
+
+// errorChain returns messages of err and errors it wraps.
+func errorChain(err error) []string {
+	var chain []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if _, ok := e.(*stackTraceError); ok {
+			continue
+		}
+		chain = append(chain, e.Error())
+	}
+	return chain
+}
+
+// errorStack returns first non-empty stack trace of errors in err chain.
+func errorStack(err error) []byte {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if st, ok := e.(StackTracer); ok {
+			if s := st.StackTrace(); len(s) > 0 {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// logServerError logs server error with error chain and request id so it can be correlated with the request.
+// Stack trace is included in debug mode.
+func (e *Echo) logServerError(c Context, code int, err error) {
+	j := log.JSON{
+		"status": code,
+		"method": c.Request().Method,
+		"uri":    c.Request().RequestURI,
+		"error":  errorChain(err),
+	}
+	rid := c.Request().Header.Get(HeaderXRequestID)
+	if rid == "" {
+		rid = c.Response().Header().Get(HeaderXRequestID)
+	}
+	if rid != "" {
+		j["request_id"] = rid
+	}
+	if e.Debug {
+		if st := errorStack(err); st != nil {
+			j["stack"] = string(st)
+		}
+	}
+	e.Logger.Errorj(j)
+}
@@ -0,0 +1,126 @@
+package echo
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPError_StackTrace(t *testing.T) {
+	assert.Nil(t, NewHTTPError(http.StatusBadRequest).StackTrace())
+
+	var errs []*HTTPError
+	handler := func(c Context) error {
+		errs = append(errs, NewHTTPError(http.StatusBadRequest, "invalid"))
+		errs = append(errs, WrapError(errors.New("connection refused"), http.StatusServiceUnavailable, "database unavailable"))
+		return errs[len(errs)-1]
+	}
+	debug := New()
+	debug.Debug = true
+	debug.GET("/", handler)
+	production := New()
+	production.GET("/", handler)
+
+	debug.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	production.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if assert.Len(t, errs, 4) {
+		assert.Contains(t, string(errs[0].StackTrace()), "echo/v4.TestHTTPError_StackTrace")
+		assert.NotContains(t, string(errs[0].StackTrace()), "captureErrorStack")
+		assert.Equal(t, http.StatusServiceUnavailable, errs[1].Code)
+		assert.Equal(t, "database unavailable", errs[1].Message)
+		assert.Contains(t, string(errs[1].StackTrace()), "echo/v4.TestHTTPError_StackTrace")
+		// instance not in debug mode does not capture stack traces
+		assert.Nil(t, errs[2].StackTrace())
+		assert.Nil(t, errs[3].StackTrace())
+	}
+	assert.Nil(t, NewHTTPError(http.StatusBadRequest).StackTrace())
+}
+
+func TestErrorStack(t *testing.T) {
+	assert.Nil(t, errorStack(errors.New("plain")))
+	assert.Nil(t, errorStack(nil))
+
+	inner := &HTTPError{Code: http.StatusInternalServerError, stack: []byte("inner stack")}
+	outer := WrapError(inner, http.StatusBadGateway) // no stack as capturing is disabled
+	assert.Equal(t, []byte("inner stack"), errorStack(outer))
+	assert.Equal(t, []string{"code=502, message=Bad Gateway, internal=code=500, message=<nil>", "code=500, message=<nil>"}, errorChain(outer))
+}
+
+func TestEcho_LogServerErrors(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		whenLog         bool
+		whenDebug       bool
+		whenErr         error
+		expectLog       []string
+		expectNotLogged string
+	}{
+		{
+			name:      "ok, server error is logged with chain and request id",
+			whenLog:   true,
+			whenErr:   WrapError(errors.New("connection refused"), http.StatusServiceUnavailable),
+			expectLog: []string{`"request_id":"rid-1"`, `"status":503`, `"connection refused"`, `"uri":"/orders"`},
+		},
+		{
+			name:            "ok, stack is not logged without debug",
+			whenLog:         true,
+			whenErr:         &HTTPError{Code: http.StatusInternalServerError, stack: []byte("stack-marker")},
+			expectLog:       []string{`"status":500`},
+			expectNotLogged: "stack-marker",
+		},
+		{
+			name:      "ok, stack is logged in debug mode",
+			whenLog:   true,
+			whenDebug: true,
+			whenErr:   &HTTPError{Code: http.StatusInternalServerError, stack: []byte("stack-marker")},
+			expectLog: []string{`"stack":"stack-marker"`},
+		},
+		{
+			name:      "ok, stack of error handler is logged in debug mode for error without stack",
+			whenLog:   true,
+			whenDebug: true,
+			whenErr:   errors.New("failed"),
+			expectLog: []string{`"error":["failed"]`, `"stack":"github.com/labstack/echo/v4.(*Echo).ServeHTTP`},
+		},
+		{
+			name:            "ok, client errors are not logged",
+			whenLog:         true,
+			whenErr:         ErrNotFound,
+			expectNotLogged: "status",
+		},
+		{
+			name:            "ok, logging is disabled",
+			whenErr:         errors.New("failed"),
+			expectNotLogged: "failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.LogServerErrors = tc.whenLog
+			e.Debug = tc.whenDebug
+			buf := new(bytes.Buffer)
+			e.Logger.SetOutput(buf)
+			e.GET("/orders", func(c Context) error {
+				return tc.whenErr
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			req.Header.Set(HeaderXRequestID, "rid-1")
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			for _, l := range tc.expectLog {
+				assert.Contains(t, buf.String(), l)
+			}
+			if tc.expectNotLogged != "" {
+				assert.NotContains(t, buf.String(), tc.expectNotLogged)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package echo
+
+import (
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileOffloadConfig configures `Context#File`, `Context#Attachment` and `Context#Inline` to delegate sending file
+// content to reverse proxy in front of Echo (nginx `X-Accel-Redirect`, Apache/lighttpd `X-Sendfile`). Echo responds
+// with an empty body and offload header, proxy then serves the file itself.
+type FileOffloadConfig struct {
+	// Header is response header used to offload file. i.e. `HeaderXAccelRedirect` or `HeaderXSendfile`.
+	// Required.
+	Header string
+
+	// Mappings maps local file path prefixes to paths known by the proxy. i.e. for nginx `"/var/www/files": "/protected"`
+	// where `/protected` is `internal` location. Longest matching prefix is used. Files not matching any prefix are
+	// served by Echo. When empty, absolute local file path is sent (suitable for `X-Sendfile`).
+	// Optional.
+	Mappings map[string]string
+}
+
+// offloadPath returns path sent to the proxy for given local file or false when file must be served by Echo.
+func (config *FileOffloadConfig) offloadPath(file string) (string, bool) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", false
+	}
+	if len(config.Mappings) == 0 {
+		return abs, true
+	}
+
+	matched := ""
+	target := ""
+	for prefix, internal := range config.Mappings {
+		p, err := filepath.Abs(prefix)
+		if err != nil || len(p) <= len(matched) {
+			continue
+		}
+		if abs == p || strings.HasPrefix(abs, strings.TrimSuffix(p, string(filepath.Separator))+string(filepath.Separator)) {
+			matched = p
+			target = internal
+		}
+	}
+	if matched == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(matched, abs)
+	if err != nil {
+		return "", false
+	}
+	return path.Join("/", target, filepath.ToSlash(rel)), true
+}
+
+// offloadFile sends offload header instead of file content when `Echo#FileOffload` is configured and applies to file.
+func (c *context) offloadFile(file string) bool {
+	config := c.echo.FileOffload
+	if config == nil || config.Header == "" {
+		return false
+	}
+	p, ok := config.offloadPath(file)
+	if !ok {
+		return false
+	}
+	c.response.Header().Set(config.Header, p)
+	c.response.WriteHeader(http.StatusOK)
+	return true
+}
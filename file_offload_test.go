@@ -0,0 +1,99 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_FileOffload(t *testing.T) {
+	fixture, err := filepath.Abs("_fixture")
+	assert.NoError(t, err)
+
+	var testCases = []struct {
+		name         string
+		config       *FileOffloadConfig
+		file         string
+		expectHeader string
+		expectBody   bool
+	}{
+		{
+			name:       "ok, offload not configured",
+			config:     nil,
+			file:       "_fixture/images/walle.png",
+			expectBody: true,
+		},
+		{
+			name:         "ok, X-Sendfile with absolute path",
+			config:       &FileOffloadConfig{Header: HeaderXSendfile},
+			file:         "_fixture/images/walle.png",
+			expectHeader: filepath.Join(fixture, "images", "walle.png"),
+		},
+		{
+			name: "ok, X-Accel-Redirect with mapping",
+			config: &FileOffloadConfig{
+				Header:   HeaderXAccelRedirect,
+				Mappings: map[string]string{"_fixture": "/protected"},
+			},
+			file:         "_fixture/images/walle.png",
+			expectHeader: "/protected/images/walle.png",
+		},
+		{
+			name: "ok, longest mapping wins",
+			config: &FileOffloadConfig{
+				Header: HeaderXAccelRedirect,
+				Mappings: map[string]string{
+					"_fixture":        "/protected",
+					"_fixture/images": "/img/",
+				},
+			},
+			file:         "_fixture/images/walle.png",
+			expectHeader: "/img/walle.png",
+		},
+		{
+			name: "ok, file outside mappings is served by echo",
+			config: &FileOffloadConfig{
+				Header:   HeaderXAccelRedirect,
+				Mappings: map[string]string{"_fixture/folder": "/protected"},
+			},
+			file:       "_fixture/images/walle.png",
+			expectBody: true,
+		},
+		{
+			name: "ok, mapping prefix must match whole path segment",
+			config: &FileOffloadConfig{
+				Header:   HeaderXAccelRedirect,
+				Mappings: map[string]string{"_fixture/ima": "/protected"},
+			},
+			file:       "_fixture/images/walle.png",
+			expectBody: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.FileOffload = tc.config
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := c.Attachment(tc.file, "walle.png")
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, `attachment; filename="walle.png"`, rec.Header().Get(HeaderContentDisposition))
+			if tc.config != nil {
+				assert.Equal(t, tc.expectHeader, rec.Header().Get(tc.config.Header))
+			}
+			if tc.expectBody {
+				assert.NotZero(t, rec.Body.Len())
+			} else {
+				assert.Zero(t, rec.Body.Len())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package echo
 
 import (
 	"net/http"
+	"strings"
 )
 
 type (
@@ -12,6 +13,7 @@ type (
 		common
 		host       string
 		prefix     string
+		version    string
 		middleware []MiddlewareFunc
 		echo       *Echo
 	}
@@ -29,6 +31,38 @@ func (g *Group) Use(middleware ...MiddlewareFunc) {
 	g.Any("/*", NotFoundHandler)
 }
 
+// Pre implements `Echo#Pre()` for requests within the Group. Middleware is run before router only for requests
+// with path starting with group prefix and, for groups created with `Echo#Host`, for requests to group host.
+// Groups created with `Echo#Version` without path prefix scope middleware by prefix only as version is resolved
+// after routing.
+func (g *Group) Pre(middleware ...MiddlewareFunc) {
+	for _, m := range middleware {
+		g.echo.Pre(g.scoped(m))
+	}
+}
+
+// scoped returns middleware that calls m only for requests within the Group and skips it for other requests.
+func (g *Group) scoped(m MiddlewareFunc) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		h := m(next)
+		return func(c Context) error {
+			if !g.matches(c.Request()) {
+				return next(c)
+			}
+			return h(c)
+		}
+	}
+}
+
+func (g *Group) matches(r *http.Request) bool {
+	if g.echo.findRouter(r.Host) != g.echo.findRouter(g.host) {
+		return false
+	}
+	prefix := strings.TrimSuffix(g.prefix, "/")
+	path := g.echo.routingPath(r)
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
 // CONNECT implements `Echo#CONNECT()` for sub-routes within the Group.
 func (g *Group) CONNECT(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
 	return g.Add(http.MethodConnect, path, h, m...)
@@ -97,8 +131,9 @@ func (g *Group) Group(prefix string, middleware ...MiddlewareFunc) (sg *Group) {
 	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	sg = g.echo.Group(g.prefix+prefix, m...)
-	sg.host = g.host
+	// host and version are set before middleware is added so catch-all routes are registered for them too
+	sg = &Group{host: g.host, prefix: g.prefix + prefix, version: g.version, echo: g.echo}
+	sg.Use(m...)
 	return
 }
 
@@ -112,13 +147,55 @@ func (g *Group) File(path, file string) {
 	g.file(path, file, g.GET)
 }
 
+// Mount implements `Echo#Mount()` for sub-routes within the Group.
+func (g *Group) Mount(prefix string, h http.Handler, middleware ...MiddlewareFunc) []*Route {
+	return g.mount(prefix, h, g.Any, middleware...)
+}
+
+// Resource implements `Echo#Resource()` for sub-routes within the Group. Route names are derived from the path
+// without group prefix.
+func (g *Group) Resource(path string, controller interface{}, middleware ...MiddlewareFunc) []*Route {
+	return g.resource(path, controller, g.Add, middleware...)
+}
+
 // Add implements `Echo#Add()` for sub-routes within the Group.
 func (g *Group) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	if g.version != "" {
+		return g.echo.addVersioned(g.host, g.version, len(g.middleware), method, g.prefix+path, handler, g.routeMiddleware(middleware)...)
+	}
+	return g.echo.add(g.host, len(g.middleware), method, g.prefix+path, handler, g.routeMiddleware(middleware)...)
+}
+
+// TryAdd implements `Echo#TryAdd()` for sub-routes within the Group.
+func (g *Group) TryAdd(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) (*Route, error) {
+	if g.version != "" {
+		return g.echo.tryAddVersioned(g.host, g.version, len(g.middleware), method, g.prefix+path, handler, g.routeMiddleware(middleware)...)
+	}
+	return g.echo.tryAdd(g.host, len(g.middleware), method, g.prefix+path, handler, g.routeMiddleware(middleware)...)
+}
+
+// TryAny implements `Echo#TryAny()` for sub-routes within the Group.
+func (g *Group) TryAny(path string, handler HandlerFunc, middleware ...MiddlewareFunc) ([]*Route, error) {
+	return g.TryMatch(methods[:], path, handler, middleware...)
+}
+
+// TryMatch implements `Echo#TryMatch()` for sub-routes within the Group.
+func (g *Group) TryMatch(methods []string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) ([]*Route, error) {
+	return g.echo.tryMatch(methods, g.prefix+path, handler, func(method string) (*Route, error) {
+		return g.TryAdd(method, path, handler, middleware...)
+	})
+}
+
+func (g *Group) routeMiddleware(middleware []MiddlewareFunc) []MiddlewareFunc {
 	// Combine into a new slice to avoid accidentally passing the same slice for
 	// multiple routes, which would lead to later add() calls overwriting the
 	// middleware from earlier calls.
 	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
 	m = append(m, g.middleware...)
-	m = append(m, middleware...)
-	return g.echo.add(g.host, method, g.prefix+path, handler, m...)
+	return append(m, middleware...)
+}
+
+// Canary implements `Echo#Canary()` for sub-routes within the Group.
+func (g *Group) Canary(method, path string, stable, canary HandlerFunc, config CanaryRouteConfig, middleware ...MiddlewareFunc) *CanaryRoute {
+	return g.canary(method, path, stable, canary, config, g.Add, middleware...)
 }
@@ -119,3 +119,91 @@ func TestGroupRouteMiddlewareWithMatchAny(t *testing.T) {
 	assert.Equal(t, "/*", m)
 
 }
+
+func TestGroup_Pre(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenHost   string
+		whenURL    string
+		expectBody string
+	}{
+		{
+			name:       "ok, request within group is rewritten",
+			whenHost:   "admin.example.com",
+			whenURL:    "/api/old",
+			expectBody: "new",
+		},
+		{
+			name:       "ok, request to group prefix",
+			whenHost:   "admin.example.com",
+			whenURL:    "/api",
+			expectBody: "new",
+		},
+		{
+			name:       "ok, path only starting with group prefix is not within group",
+			whenHost:   "admin.example.com",
+			whenURL:    "/apiold",
+			expectBody: "apiold",
+		},
+		{
+			name:       "ok, other host is not within group",
+			whenHost:   "example.com",
+			whenURL:    "/api/old",
+			expectBody: "default old",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.GET("/api/old", func(c Context) error {
+				return c.String(http.StatusOK, "default old")
+			})
+			admin := e.Host("admin.example.com")
+			admin.GET("/apiold", func(c Context) error {
+				return c.String(http.StatusOK, "apiold")
+			})
+			g := admin.Group("/api")
+			g.Pre(func(next HandlerFunc) HandlerFunc {
+				return func(c Context) error {
+					c.Request().URL.Path = "/api/new"
+					return next(c)
+				}
+			})
+			g.GET("/new", func(c Context) error {
+				return c.String(http.StatusOK, "new")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			req.Host = tc.whenHost
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestGroup_hostGroupMiddleware(t *testing.T) {
+	e := New()
+	g := e.Host("admin.example.com").Group("/admin", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return c.String(http.StatusUnauthorized, "login")
+		}
+	})
+	g.GET("/users", func(c Context) error {
+		return c.String(http.StatusOK, "users")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/unknown", nil)
+	req.Host = "admin.example.com"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// group middleware catch-all routes must not leak to routes of other hosts
+	req = httptest.NewRequest(http.MethodGet, "/admin/unknown", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
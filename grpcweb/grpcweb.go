@@ -0,0 +1,390 @@
+/*
+Package grpcweb provides bridge serving gRPC-Web and Connect protocol (unary) requests with in-process gRPC server.
+
+Bridge translates HTTP/1.1 and HTTP/2 gRPC-Web requests to native gRPC requests handled by `http.Handler` of gRPC
+server (`*grpc.Server` from google.golang.org/grpc implements it) and translates responses back, including trailers
+that gRPC-Web sends in the body. This allows serving REST and gRPC-Web APIs from single Echo listener.
+
+Example:
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterGreeterServer(grpcServer, &greeter{})
+
+	e := echo.New()
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowHeaders:  []string{"*"},
+		ExposeHeaders: []string{"Grpc-Status", "Grpc-Message"},
+	}))
+	bridge := grpcweb.New(grpcServer)
+	e.POST("/helloworld.Greeter/*", bridge.Handler())
+	e.POST("/users", createUser)
+
+Package does not import google.golang.org/grpc itself.
+*/
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Config defines the config for Bridge.
+	Config struct {
+		// Handler is in-process gRPC server serving translated requests.
+		// Required.
+		Handler http.Handler
+
+		// Connect enables (unary) Connect protocol requests with `application/proto` and `application/json`
+		// content types. JSON requests require JSON codec to be registered in gRPC server.
+		// Optional. Default value false.
+		Connect bool
+
+		// ConnectMaxBodySize limits size of Connect request and response messages.
+		// Optional. Default value 4MB.
+		ConnectMaxBodySize int64
+	}
+
+	// Bridge translates gRPC-Web and Connect requests to gRPC requests. Bridge implements `http.Handler`.
+	Bridge struct {
+		config Config
+	}
+)
+
+// Content types
+const (
+	ContentTypeGRPC        = "application/grpc"
+	ContentTypeGRPCWeb     = "application/grpc-web"
+	ContentTypeGRPCWebText = "application/grpc-web-text"
+	ContentTypeConnectJSON = "application/json"
+	ContentTypeConnectPB   = "application/proto"
+)
+
+const (
+	frameTrailer = 0x80
+	frameHeader  = 5
+
+	defaultConnectMaxBodySize = 4 << 20
+)
+
+// New creates gRPC-Web bridge for gRPC server.
+func New(handler http.Handler) *Bridge {
+	return NewWithConfig(Config{Handler: handler})
+}
+
+// NewWithConfig creates bridge with config.
+// See: `New()`.
+func NewWithConfig(config Config) *Bridge {
+	if config.Handler == nil {
+		panic("echo: grpcweb bridge requires handler")
+	}
+	if config.ConnectMaxBodySize <= 0 {
+		config.ConnectMaxBodySize = defaultConnectMaxBodySize
+	}
+	return &Bridge{config: config}
+}
+
+// Handler returns bridge as Echo handler.
+func (b *Bridge) Handler() echo.HandlerFunc {
+	return echo.WrapHandler(b)
+}
+
+// IsGRPCWebRequest checks if request is gRPC-Web request.
+func IsGRPCWebRequest(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get(echo.HeaderContentType), ContentTypeGRPCWeb)
+}
+
+// IsConnectRequest checks if request is unary Connect protocol request.
+func IsConnectRequest(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	ct := mediaType(r.Header.Get(echo.HeaderContentType))
+	return ct == ContentTypeConnectPB || ct == ContentTypeConnectJSON
+}
+
+// ServeHTTP serves gRPC-Web and Connect requests. Other requests are responded with 415 Unsupported Media Type.
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case IsGRPCWebRequest(r):
+		b.serveGRPCWeb(w, r)
+	case b.config.Connect && IsConnectRequest(r):
+		b.serveConnect(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+	}
+}
+
+func (b *Bridge) serveGRPCWeb(w http.ResponseWriter, r *http.Request) {
+	contentType := mediaType(r.Header.Get(echo.HeaderContentType))
+	text := strings.HasPrefix(contentType, ContentTypeGRPCWebText)
+	subtype := strings.TrimPrefix(strings.TrimPrefix(contentType, ContentTypeGRPCWebText), ContentTypeGRPCWeb)
+
+	var body io.Reader = r.Body
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+	}
+	req := grpcRequest(r, ContentTypeGRPC+subtype, ioutil.NopCloser(body))
+
+	var out io.Writer = w
+	var encoder io.WriteCloser
+	if text {
+		encoder = base64.NewEncoder(base64.StdEncoding, w)
+		out = encoder
+	}
+	gw := &grpcResponseWriter{
+		target:      w,
+		out:         out,
+		header:      http.Header{},
+		contentType: contentType,
+	}
+	b.config.Handler.ServeHTTP(gw, req)
+
+	gw.writeHeaders()
+	trailers := gw.trailers()
+	var frame bytes.Buffer
+	for _, k := range sortedKeys(trailers) {
+		for _, v := range trailers[k] {
+			frame.WriteString(strings.ToLower(k) + ": " + v + "\r\n")
+		}
+	}
+	out.Write(frameBytes(frameTrailer, frame.Bytes()))
+	if encoder != nil {
+		encoder.Close()
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (b *Bridge) serveConnect(w http.ResponseWriter, r *http.Request) {
+	contentType := mediaType(r.Header.Get(echo.HeaderContentType))
+	subtype := "+proto"
+	if contentType == ContentTypeConnectJSON {
+		subtype = "+json"
+	}
+	message, err := ioutil.ReadAll(io.LimitReader(r.Body, b.config.ConnectMaxBodySize+1))
+	if err != nil {
+		writeConnectError(w, 2, err.Error())
+		return
+	}
+	if int64(len(message)) > b.config.ConnectMaxBodySize {
+		writeConnectError(w, 8, "request message is too large")
+		return
+	}
+	req := grpcRequest(r, ContentTypeGRPC+subtype, ioutil.NopCloser(bytes.NewReader(frameBytes(0, message))))
+	req.Header.Del("Connect-Protocol-Version")
+	if timeout := req.Header.Get("Connect-Timeout-Ms"); timeout != "" {
+		req.Header.Del("Connect-Timeout-Ms")
+		req.Header.Set("Grpc-Timeout", timeout+"m")
+	}
+
+	buf := new(bytes.Buffer)
+	gw := &grpcResponseWriter{
+		out:    &limitedWriter{w: buf, n: b.config.ConnectMaxBodySize + frameHeader},
+		header: http.Header{},
+	}
+	b.config.Handler.ServeHTTP(gw, req)
+
+	trailers := gw.trailers()
+	for k, v := range gw.header {
+		if k != "Trailer" && !strings.HasPrefix(k, "Grpc-") && k != echo.HeaderContentType && !isTrailer(gw.header, k) {
+			w.Header()[k] = v
+		}
+	}
+	for k, v := range trailers {
+		if !strings.HasPrefix(k, "Grpc-") {
+			w.Header()["Trailer-"+k] = v
+		}
+	}
+	code, _ := strconv.Atoi(trailers.Get("Grpc-Status"))
+	if code != 0 {
+		msg, _ := url.PathUnescape(trailers.Get("Grpc-Message"))
+		writeConnectError(w, code, msg)
+		return
+	}
+	if gw.status != 0 && gw.status != http.StatusOK {
+		writeConnectError(w, 2, "unexpected status "+strconv.Itoa(gw.status))
+		return
+	}
+	data := buf.Bytes()
+	if len(data) < frameHeader || int(binary.BigEndian.Uint32(data[1:frameHeader])) != len(data)-frameHeader {
+		writeConnectError(w, 13, "invalid response message")
+		return
+	}
+	w.Header().Set(echo.HeaderContentType, contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data[frameHeader:])
+}
+
+// grpcRequest creates gRPC (HTTP/2) request from gRPC-Web or Connect request.
+func grpcRequest(r *http.Request, contentType string, body io.ReadCloser) *http.Request {
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Body = body
+	req.ContentLength = -1
+	req.Header.Del(echo.HeaderContentLength)
+	req.Header.Del("X-Grpc-Web")
+	req.Header.Set(echo.HeaderContentType, contentType)
+	req.Header.Set("Te", "trailers")
+	return req
+}
+
+type grpcResponseWriter struct {
+	target      http.ResponseWriter // nil for buffered (Connect) responses
+	out         io.Writer
+	header      http.Header
+	contentType string
+	status      int
+}
+
+func (w *grpcResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *grpcResponseWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+	if w.target == nil {
+		return
+	}
+	// trailers declared before headers are written are sent in trailer frame
+	h := w.target.Header()
+	for k, v := range w.header {
+		if k != "Trailer" && !isTrailer(w.header, k) && !strings.HasPrefix(k, http.TrailerPrefix) {
+			h[k] = v
+		}
+	}
+	h.Set(echo.HeaderContentType, w.contentType)
+	h.Del(echo.HeaderContentLength)
+	w.target.WriteHeader(code)
+}
+
+func (w *grpcResponseWriter) writeHeaders() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (w *grpcResponseWriter) Write(b []byte) (int, error) {
+	w.writeHeaders()
+	return w.out.Write(b)
+}
+
+func (w *grpcResponseWriter) Flush() {
+	if f, ok := w.target.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// trailers returns trailers set by gRPC server. For trailers-only responses status is in headers.
+func (w *grpcResponseWriter) trailers() http.Header {
+	t := http.Header{}
+	for k, v := range w.header {
+		switch {
+		case strings.HasPrefix(k, http.TrailerPrefix):
+			t[http.CanonicalHeaderKey(strings.TrimPrefix(k, http.TrailerPrefix))] = v
+		case isTrailer(w.header, k), k == "Grpc-Status", k == "Grpc-Message", k == "Grpc-Status-Details-Bin":
+			t[k] = v
+		}
+	}
+	return t
+}
+
+func isTrailer(h http.Header, key string) bool {
+	for _, line := range h["Trailer"] {
+		for _, k := range strings.Split(line, ",") {
+			if http.CanonicalHeaderKey(strings.TrimSpace(k)) == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(b []byte) (int, error) {
+	if int64(len(b)) > l.n {
+		return 0, io.ErrShortWrite
+	}
+	l.n -= int64(len(b))
+	return l.w.Write(b)
+}
+
+func frameBytes(flag byte, message []byte) []byte {
+	frame := make([]byte, frameHeader+len(message))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:frameHeader], uint32(len(message)))
+	copy(frame[frameHeader:], message)
+	return frame
+}
+
+func mediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+func sortedKeys(h http.Header) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// connectCodes maps gRPC status codes to Connect error codes and HTTP statuses.
+var connectCodes = []struct {
+	name   string
+	status int
+}{
+	{"ok", http.StatusOK},
+	{"canceled", 499},
+	{"unknown", http.StatusInternalServerError},
+	{"invalid_argument", http.StatusBadRequest},
+	{"deadline_exceeded", http.StatusGatewayTimeout},
+	{"not_found", http.StatusNotFound},
+	{"already_exists", http.StatusConflict},
+	{"permission_denied", http.StatusForbidden},
+	{"resource_exhausted", http.StatusTooManyRequests},
+	{"failed_precondition", http.StatusBadRequest},
+	{"aborted", http.StatusConflict},
+	{"out_of_range", http.StatusBadRequest},
+	{"unimplemented", http.StatusNotImplemented},
+	{"internal", http.StatusInternalServerError},
+	{"unavailable", http.StatusServiceUnavailable},
+	{"data_loss", http.StatusInternalServerError},
+	{"unauthenticated", http.StatusUnauthorized},
+}
+
+func writeConnectError(w http.ResponseWriter, code int, message string) {
+	if code <= 0 || code >= len(connectCodes) {
+		code = 2 // unknown
+	}
+	body, _ := json.Marshal(struct {
+		Code    string `json:"code"`
+		Message string `json:"message,omitempty"`
+	}{Code: connectCodes[code].name, Message: message})
+	w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	w.WriteHeader(connectCodes[code].status)
+	w.Write(body)
+}
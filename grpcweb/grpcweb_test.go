@@ -0,0 +1,182 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGRPCServer mimics `*grpc.Server` handler transport: echoes request message back with "hello " prefix.
+type fakeGRPCServer struct {
+	status  string
+	message string
+}
+
+func (s *fakeGRPCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get(echo.HeaderContentType), ContentTypeGRPC) {
+		http.Error(w, "not grpc", http.StatusBadRequest)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	w.Header().Set(echo.HeaderContentType, r.Header.Get(echo.HeaderContentType))
+	w.Header().Add("Trailer", "Grpc-Status")
+	w.Header().Add("Trailer", "Grpc-Message")
+	w.Header().Set("X-Method", r.URL.Path)
+	w.WriteHeader(http.StatusOK)
+	if s.status == "0" {
+		w.Write(frameBytes(0, append([]byte("hello "), body[frameHeader:]...)))
+	}
+	w.Header().Set("Grpc-Status", s.status)
+	w.Header().Set("Grpc-Message", s.message)
+	w.Header().Set(http.TrailerPrefix+"X-Trace", "abc")
+}
+
+func readFrames(t *testing.T, b []byte) (messages []string, trailer string) {
+	for len(b) > 0 {
+		if !assert.True(t, len(b) >= frameHeader) {
+			return
+		}
+		n := int(binary.BigEndian.Uint32(b[1:frameHeader]))
+		data := string(b[frameHeader : frameHeader+n])
+		if b[0]&frameTrailer != 0 {
+			trailer = data
+		} else {
+			messages = append(messages, data)
+		}
+		b = b[frameHeader+n:]
+	}
+	return
+}
+
+func TestBridge_GRPCWeb(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		whenText      bool
+		givenStatus   string
+		expectMessage []string
+		expectTrailer string
+	}{
+		{
+			name:          "ok, binary",
+			givenStatus:   "0",
+			expectMessage: []string{"hello world"},
+			expectTrailer: "grpc-message: \r\ngrpc-status: 0\r\nx-trace: abc\r\n",
+		},
+		{
+			name:          "ok, text",
+			whenText:      true,
+			givenStatus:   "0",
+			expectMessage: []string{"hello world"},
+			expectTrailer: "grpc-message: \r\ngrpc-status: 0\r\nx-trace: abc\r\n",
+		},
+		{
+			name:          "ok, error status",
+			givenStatus:   "5",
+			expectTrailer: "grpc-message: user%20not%20found\r\ngrpc-status: 5\r\nx-trace: abc\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &fakeGRPCServer{status: tc.givenStatus}
+			if tc.givenStatus != "0" {
+				server.message = "user%20not%20found"
+			}
+			e := echo.New()
+			e.POST("/users.Users/*", New(server).Handler())
+
+			body := frameBytes(0, []byte("world"))
+			contentType := ContentTypeGRPCWeb + "+proto"
+			if tc.whenText {
+				body = []byte(base64.StdEncoding.EncodeToString(body))
+				contentType = ContentTypeGRPCWebText
+			}
+			req := httptest.NewRequest(http.MethodPost, "/users.Users/Get", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, contentType)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, contentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, "/users.Users/Get", rec.Header().Get("X-Method"))
+			assert.Empty(t, rec.Header().Get("Trailer"))
+
+			respBody := rec.Body.Bytes()
+			if tc.whenText {
+				var err error
+				respBody, err = base64.StdEncoding.DecodeString(rec.Body.String())
+				assert.NoError(t, err)
+			}
+			messages, trailer := readFrames(t, respBody)
+			assert.Equal(t, tc.expectMessage, messages)
+			assert.Equal(t, tc.expectTrailer, trailer)
+		})
+	}
+}
+
+func TestBridge_Connect(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenStatus  string
+		expectCode   int
+		expectBody   string
+		expectHeader string
+	}{
+		{
+			name:         "ok",
+			givenStatus:  "0",
+			expectCode:   http.StatusOK,
+			expectBody:   "hello world",
+			expectHeader: ContentTypeConnectPB,
+		},
+		{
+			name:         "nok, error status is mapped",
+			givenStatus:  "5",
+			expectCode:   http.StatusNotFound,
+			expectBody:   `{"code":"not_found","message":"user not found"}`,
+			expectHeader: echo.MIMEApplicationJSON,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &fakeGRPCServer{status: tc.givenStatus, message: "user%20not%20found"}
+			bridge := NewWithConfig(Config{Handler: server, Connect: true})
+
+			req := httptest.NewRequest(http.MethodPost, "/users.Users/Get", strings.NewReader("world"))
+			req.Header.Set(echo.HeaderContentType, ContentTypeConnectPB)
+			rec := httptest.NewRecorder()
+			bridge.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectHeader, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, "abc", rec.Header().Get("Trailer-X-Trace"))
+		})
+	}
+}
+
+func TestBridge_UnsupportedRequest(t *testing.T) {
+	bridge := New(&fakeGRPCServer{status: "0"})
+
+	req := httptest.NewRequest(http.MethodPost, "/users.Users/Get", strings.NewReader("world"))
+	req.Header.Set(echo.HeaderContentType, ContentTypeConnectPB)
+	rec := httptest.NewRecorder()
+	bridge.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestNew_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		New(nil)
+	})
+}
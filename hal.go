@@ -0,0 +1,204 @@
+package echo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type (
+	// HALResource wraps payload with HAL (Hypertext Application Language) `_links` and `_embedded` sections. Links
+	// are built from named routes so URLs do not need to be constructed manually.
+	// See: https://tools.ietf.org/html/draft-kelly-json-hal
+	//
+	// Example:
+	//
+	//	e.GET("/users/:id", getUser).Name = "user"
+	//	e.GET("/users/:id/orders", getOrders).Name = "user.orders"
+	//
+	//	return echo.NewHALResource(c.Echo(), user).
+	//		Link("self", "user", user.ID).
+	//		Link("orders", "user.orders", user.ID).
+	//		JSON(c, http.StatusOK)
+	HALResource struct {
+		echo     *Echo
+		payload  interface{}
+		rels     []string
+		links    map[string][]HALLink
+		embedded map[string]interface{}
+		embedRel []string
+		err      error
+	}
+
+	// HALLink is a single HAL link object.
+	HALLink struct {
+		Href      string `json:"href"`
+		Templated bool   `json:"templated,omitempty"`
+		Title     string `json:"title,omitempty"`
+		Name      string `json:"name,omitempty"`
+	}
+)
+
+// NewHALResource creates HAL resource for given payload. Payload must marshal to JSON object or be nil.
+func NewHALResource(e *Echo, payload interface{}) *HALResource {
+	return &HALResource{
+		echo:     e,
+		payload:  payload,
+		links:    map[string][]HALLink{},
+		embedded: map[string]interface{}{},
+	}
+}
+
+// Link adds link with relation `rel` to the route named `routeName`. Params are used to fill route path
+// parameters in order. Adding multiple links with same relation results in array of links.
+func (r *HALResource) Link(rel string, routeName string, params ...interface{}) *HALResource {
+	if _, ok := r.route(rel, routeName); !ok {
+		return r
+	}
+	return r.AddLink(rel, HALLink{Href: r.echo.Reverse(routeName, params...)})
+}
+
+// LinkTemplate adds templated link with relation `rel` to the route named `routeName`. Route path parameters are
+// written as URI template variables i.e. `/users/:id` results in `/users/{id}`, `/archive/:year?` in
+// `/archive{/year}` and `/files/*filepath` in `/files/{+filepath}`.
+func (r *HALResource) LinkTemplate(rel string, routeName string) *HALResource {
+	route, ok := r.route(rel, routeName)
+	if !ok {
+		return r
+	}
+	path := route.Path
+	if p, ok := r.echo.RouteMeta(route)[RouteMetaOptionalPath].(string); ok {
+		path = p
+	}
+	return r.AddLink(rel, HALLink{Href: uriTemplate(path), Templated: true})
+}
+
+// AddLink adds link with relation `rel`.
+func (r *HALResource) AddLink(rel string, link HALLink) *HALResource {
+	if _, ok := r.links[rel]; !ok {
+		r.rels = append(r.rels, rel)
+	}
+	r.links[rel] = append(r.links[rel], link)
+	return r
+}
+
+// Embed adds embedded resource with relation `rel`. Value is usually `*HALResource` or slice of them.
+func (r *HALResource) Embed(rel string, v interface{}) *HALResource {
+	if _, ok := r.embedded[rel]; !ok {
+		r.embedRel = append(r.embedRel, rel)
+	}
+	r.embedded[rel] = v
+	return r
+}
+
+// JSON sends resource as JSON response with `application/hal+json` content type.
+func (r *HALResource) JSON(c Context, code int) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, MIMEApplicationHALJSON, b)
+}
+
+// MarshalJSON implements `json.Marshaler`. Payload fields are followed by `_links` and `_embedded` sections.
+func (r *HALResource) MarshalJSON() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	payload := []byte("{}")
+	if r.payload != nil {
+		var err error
+		if payload, err = json.Marshal(r.payload); err != nil {
+			return nil, err
+		}
+		payload = bytes.TrimSpace(payload)
+		if len(payload) < 2 || payload[0] != '{' {
+			return nil, fmt.Errorf("echo: hal payload must be JSON object, got %T", r.payload)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	buf.Write(payload[:len(payload)-1])
+	hasFields := len(bytes.TrimSpace(payload[1:len(payload)-1])) > 0
+	writeSection := func(name string, keys []string, value func(key string) interface{}) error {
+		if len(keys) == 0 {
+			return nil
+		}
+		if hasFields {
+			buf.WriteByte(',')
+		}
+		hasFields = true
+		buf.WriteString(`"` + name + `":{`)
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := json.Marshal(map[string]interface{}{k: value(k)})
+			if err != nil {
+				return err
+			}
+			buf.Write(b[1 : len(b)-1])
+		}
+		buf.WriteByte('}')
+		return nil
+	}
+	if err := writeSection("_links", r.rels, func(rel string) interface{} {
+		if links := r.links[rel]; len(links) > 1 {
+			return links
+		}
+		return r.links[rel][0]
+	}); err != nil {
+		return nil, err
+	}
+	if err := writeSection("_embedded", r.embedRel, func(rel string) interface{} {
+		return r.embedded[rel]
+	}); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (r *HALResource) route(rel string, routeName string) (*Route, bool) {
+	for _, route := range r.echo.Routes() {
+		if route.Name == routeName {
+			return route, true
+		}
+	}
+	if r.err == nil {
+		r.err = fmt.Errorf("echo: hal link %q refers to unknown route %q", rel, routeName)
+	}
+	return nil, false
+}
+
+// uriTemplate converts route path parameters to URI template variables (RFC 6570). Optional params (`/:year?`) are
+// converted to path segment expansion (`{/year}`) and wildcards to reserved expansion named after wildcard
+// (`{+filepath}` for `*filepath`) or `{+path}` for unnamed wildcard.
+func uriTemplate(path string) string {
+	b := make([]byte, 0, len(path)+8)
+	for i, l := 0, len(path); i < l; i++ {
+		switch path[i] {
+		case ':', '*':
+			j := i + 1
+			for ; j < l && path[j] != '/'; j++ {
+			}
+			name := path[i+1 : j]
+			switch {
+			case path[i] == '*':
+				if name == "" {
+					name = "path"
+				}
+				b = append(b, "{+"+name+"}"...)
+			case strings.HasSuffix(name, "?") && len(b) > 0 && b[len(b)-1] == '/':
+				b = append(b[:len(b)-1], "{/"+strings.TrimSuffix(name, "?")+"}"...)
+			default:
+				b = append(b, "{"+strings.TrimSuffix(name, "?")+"}"...)
+			}
+			i = j - 1
+		default:
+			b = append(b, path[i])
+		}
+	}
+	return string(b)
+}
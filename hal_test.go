@@ -0,0 +1,146 @@
+package echo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHALResource_MarshalJSON(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc).Name = "user"
+	e.GET("/users/:id/orders/:oid", handlerFunc).Name = "user.order"
+	e.GET("/users", handlerFunc).Name = "users"
+
+	var testCases = []struct {
+		name        string
+		whenBuild   func() *HALResource
+		expect      string
+		expectError string
+	}{
+		{
+			name: "ok, payload with links",
+			whenBuild: func() *HALResource {
+				return NewHALResource(e, user{ID: 1, Name: "Jon Snow"}).
+					Link("self", "user", 1).
+					Link("order", "user.order", 1, 10).
+					Link("order", "user.order", 1, 11).
+					LinkTemplate("find", "user")
+			},
+			expect: `{"id":1,"name":"Jon Snow","_links":{` +
+				`"self":{"href":"/users/1"},` +
+				`"order":[{"href":"/users/1/orders/10"},{"href":"/users/1/orders/11"}],` +
+				`"find":{"href":"/users/{id}","templated":true}}}`,
+		},
+		{
+			name: "ok, nil payload with embedded resources",
+			whenBuild: func() *HALResource {
+				return NewHALResource(e, nil).
+					Link("self", "users").
+					Embed("users", []*HALResource{NewHALResource(e, user{ID: 1}).Link("self", "user", 1)})
+			},
+			expect: `{"_links":{"self":{"href":"/users"}},` +
+				`"_embedded":{"users":[{"id":1,"name":"","_links":{"self":{"href":"/users/1"}}}]}}`,
+		},
+		{
+			name: "nok, unknown route name",
+			whenBuild: func() *HALResource {
+				return NewHALResource(e, nil).Link("self", "unknown")
+			},
+			expectError: `echo: hal link "self" refers to unknown route "unknown"`,
+		},
+		{
+			name: "nok, payload is not an object",
+			whenBuild: func() *HALResource {
+				return NewHALResource(e, []int{1})
+			},
+			expectError: `echo: hal payload must be JSON object, got []int`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.whenBuild())
+
+			if tc.expectError != "" {
+				var jsonErr *json.MarshalerError
+				if assert.True(t, errors.As(err, &jsonErr)) {
+					assert.EqualError(t, jsonErr.Unwrap(), tc.expectError)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expect, string(b))
+			}
+		})
+	}
+}
+
+func TestHALResource_JSON(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", func(c Context) error {
+		return NewHALResource(c.Echo(), Map{"id": c.Param("id")}).
+			Link("self", "user", c.Param("id")).
+			JSON(c, http.StatusOK)
+	}).Name = "user"
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MIMEApplicationHALJSON, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, `{"id":"1","_links":{"self":{"href":"/users/1"}}}`, rec.Body.String())
+}
+
+func TestHALResource_LinkTemplate(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc).Name = "user"
+	e.GET("/files/*", handlerFunc).Name = "files"
+	e.GET("/static/*filepath", handlerFunc).Name = "static"
+	e.GET("/archive/:year?/:month?", handlerFunc).Name = "archive"
+	e.GET("/users/:id/tags/:tag?", handlerFunc).Name = "user.tags"
+
+	var testCases = []struct {
+		name      string
+		whenRoute string
+		expect    string
+	}{
+		{
+			name:      "ok, path params",
+			whenRoute: "user",
+			expect:    "/users/{id}",
+		},
+		{
+			name:      "ok, unnamed wildcard",
+			whenRoute: "files",
+			expect:    "/files/{+path}",
+		},
+		{
+			name:      "ok, named wildcard",
+			whenRoute: "static",
+			expect:    "/static/{+filepath}",
+		},
+		{
+			name:      "ok, optional params",
+			whenRoute: "archive",
+			expect:    "/archive{/year}{/month}",
+		},
+		{
+			name:      "ok, path and optional params",
+			whenRoute: "user.tags",
+			expect:    "/users/{id}/tags{/tag}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(NewHALResource(e, nil).LinkTemplate("find", tc.whenRoute))
+
+			assert.NoError(t, err)
+			assert.Equal(t, `{"_links":{"find":{"href":"`+tc.expect+`","templated":true}}}`, string(b))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package echo
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+// HeaderValue is single element of comma separated header field value with its parameters, i.e.
+// `text/html;level=1;q=0.8` is parsed to value "text/html" with parameters "level" and "q".
+type HeaderValue struct {
+	// Value is element value without parameters. Quoted values (i.e. entity tags) are kept as is.
+	Value string
+	// Params contains element parameters. Parameter names are lowercased and quoted parameter values are unquoted.
+	Params map[string]string
+}
+
+// HeaderValues returns all elements of request header field `name`. Multiple header lines and comma separated
+// elements are parsed according to RFC 9110 list rules, so commas and semicolons inside quoted strings (and
+// `<...>` URI references) do not split values. Empty elements are skipped.
+//
+// Example: for header `Accept: text/html, application/json;q="0.9"` result is
+// `[{Value: "text/html"}, {Value: "application/json", Params: {"q": "0.9"}}]`.
+func HeaderValues(c Context, name string) []HeaderValue {
+	return ParseHeaderValues(c.Request().Header[textproto.CanonicalMIMEHeaderKey(name)]...)
+}
+
+// ParseHeaderValues parses header field lines into elements. See `HeaderValues()`.
+func ParseHeaderValues(lines ...string) []HeaderValue {
+	var result []HeaderValue
+	for _, line := range lines {
+		for _, element := range splitHeader(line, ',') {
+			parts := splitHeader(element, ';')
+			value := strings.TrimSpace(parts[0])
+			if value == "" && len(parts) == 1 {
+				continue
+			}
+			hv := HeaderValue{Value: value}
+			for _, p := range parts[1:] {
+				p = strings.TrimSpace(p)
+				if p == "" {
+					continue
+				}
+				if hv.Params == nil {
+					hv.Params = map[string]string{}
+				}
+				name, v := p, ""
+				if i := strings.IndexByte(p, '='); i != -1 {
+					name, v = strings.TrimSpace(p[:i]), unquoteHeaderValue(strings.TrimSpace(p[i+1:]))
+				}
+				hv.Params[strings.ToLower(name)] = v
+			}
+			result = append(result, hv)
+		}
+	}
+	return result
+}
+
+// splitHeader splits s by sep ignoring separators inside quoted strings and `<...>` URI references.
+func splitHeader(s string, sep byte) []string {
+	var result []string
+	inQuotes, inAngle, escaped := false, false, false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && ch == '\\':
+			escaped = true
+		case ch == '"' && !inAngle:
+			inQuotes = !inQuotes
+		case ch == '<' && !inQuotes:
+			inAngle = true
+		case ch == '>' && !inQuotes:
+			inAngle = false
+		case ch == sep && !inQuotes && !inAngle:
+			result = append(result, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(result, s[start:])
+}
+
+// unquoteHeaderValue removes quotes and backslash escapes from quoted-string. Other values are returned as is.
+func unquoteHeaderValue(v string) string {
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return v
+	}
+	v = v[1 : len(v)-1]
+	if strings.IndexByte(v, '\\') == -1 {
+		return v
+	}
+	sb := new(strings.Builder)
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\\' && i+1 < len(v) {
+			i++
+		}
+		sb.WriteByte(v[i])
+	}
+	return sb.String()
+}
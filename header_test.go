@@ -0,0 +1,85 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeaderValues(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenLines []string
+		expect    []HeaderValue
+	}{
+		{
+			name:      "ok, single value",
+			whenLines: []string{"gzip"},
+			expect:    []HeaderValue{{Value: "gzip"}},
+		},
+		{
+			name:      "ok, multiple values with params and empty elements",
+			whenLines: []string{"text/html;level=1 , , application/json; Q=0.9"},
+			expect: []HeaderValue{
+				{Value: "text/html", Params: map[string]string{"level": "1"}},
+				{Value: "application/json", Params: map[string]string{"q": "0.9"}},
+			},
+		},
+		{
+			name:      "ok, multiple header lines",
+			whenLines: []string{"a", "b, c"},
+			expect:    []HeaderValue{{Value: "a"}, {Value: "b"}, {Value: "c"}},
+		},
+		{
+			name:      "ok, quoted param with separators and escapes",
+			whenLines: []string{`attachment; filename="a, b; \"c\".txt"`},
+			expect: []HeaderValue{
+				{Value: "attachment", Params: map[string]string{"filename": `a, b; "c".txt`}},
+			},
+		},
+		{
+			name:      "ok, quoted values are kept as is",
+			whenLines: []string{`W/"a,b", "c"`},
+			expect:    []HeaderValue{{Value: `W/"a,b"`}, {Value: `"c"`}},
+		},
+		{
+			name:      "ok, uri references",
+			whenLines: []string{`<https://example.com/?a=1,2>; rel="next", <https://example.com/?p=0>; rel=prev`},
+			expect: []HeaderValue{
+				{Value: "<https://example.com/?a=1,2>", Params: map[string]string{"rel": "next"}},
+				{Value: "<https://example.com/?p=0>", Params: map[string]string{"rel": "prev"}},
+			},
+		},
+		{
+			name:      "ok, param without value",
+			whenLines: []string{"public; must-revalidate"},
+			expect:    []HeaderValue{{Value: "public", Params: map[string]string{"must-revalidate": ""}}},
+		},
+		{
+			name:      "ok, empty",
+			whenLines: []string{""},
+			expect:    nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, ParseHeaderValues(tc.whenLines...))
+		})
+	}
+}
+
+func TestHeaderValues(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add(HeaderAcceptEncoding, "gzip;q=1.0")
+	req.Header.Add(HeaderAcceptEncoding, "br")
+	c := e.NewContext(req, nil)
+
+	assert.Equal(t, []HeaderValue{
+		{Value: "gzip", Params: map[string]string{"q": "1.0"}},
+		{Value: "br"},
+	}, HeaderValues(c, HeaderAcceptEncoding))
+}
@@ -0,0 +1,176 @@
+package echo
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type (
+	// SafeHTML is trusted HTML markup. `Context#HTMLSafe` writes it without escaping.
+	SafeHTML string
+
+	// UntrustedHTML is HTML markup from untrusted source (i.e. user generated content). `Context#HTMLSafe`
+	// sanitizes it with `Echo#HTMLPolicy`.
+	UntrustedHTML string
+
+	// HTMLPolicy is an allow-list of HTML elements and attributes kept by `HTMLPolicy#Sanitize`.
+	HTMLPolicy struct {
+		// Elements maps allowed element names to their allowed attribute names.
+		Elements map[string][]string
+		// URLSchemes are schemes allowed in `href`, `src` and `cite` attributes. Relative URLs are always allowed.
+		URLSchemes []string
+	}
+)
+
+// DefaultHTMLPolicy allows basic text formatting and links.
+var DefaultHTMLPolicy = &HTMLPolicy{
+	Elements: map[string][]string{
+		"a":          {"href", "title"},
+		"b":          nil,
+		"blockquote": {"cite"},
+		"br":         nil,
+		"code":       nil,
+		"em":         nil,
+		"i":          nil,
+		"li":         nil,
+		"ol":         nil,
+		"p":          nil,
+		"pre":        nil,
+		"s":          nil,
+		"strong":     nil,
+		"u":          nil,
+		"ul":         nil,
+	},
+	URLSchemes: []string{"http", "https", "mailto"},
+}
+
+var (
+	// htmlVoidElements have no end tag.
+	htmlVoidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+		"link": true, "meta": true, "source": true, "track": true, "wbr": true,
+	}
+	// htmlDroppedContentElements are removed together with their content.
+	htmlDroppedContentElements = map[string]bool{
+		"script": true, "style": true, "iframe": true, "object": true, "noscript": true, "template": true,
+		"textarea": true, "title": true, "xmp": true, "noembed": true, "noframes": true, "plaintext": true,
+	}
+	htmlURLAttributes = map[string]bool{"href": true, "src": true, "cite": true}
+)
+
+// Sanitize removes elements and attributes not allowed by policy from HTML fragment. Text is escaped, elements
+// like `script` and `style` are removed with their content and unclosed elements are closed.
+func (p *HTMLPolicy) Sanitize(fragment string) string {
+	b := new(strings.Builder)
+	p.sanitize(b, fragment)
+	return b.String()
+}
+
+func (p *HTMLPolicy) sanitize(w io.StringWriter, fragment string) {
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	var open []string
+	dropped := 0
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				w.WriteString("</" + open[i] + ">")
+			}
+			return
+		case html.TextToken:
+			if dropped == 0 {
+				w.WriteString(html.EscapeString(string(z.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			if htmlDroppedContentElements[t.Data] {
+				if tt == html.StartTagToken {
+					dropped++
+				}
+				continue
+			}
+			allowed, ok := p.Elements[t.Data]
+			if !ok || dropped > 0 {
+				continue
+			}
+			w.WriteString("<" + t.Data)
+			for _, a := range t.Attr {
+				if a.Namespace != "" || !containsString(allowed, a.Key) {
+					continue
+				}
+				if htmlURLAttributes[a.Key] && !p.allowedURL(a.Val) {
+					continue
+				}
+				w.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+			}
+			w.WriteString(">")
+			if !htmlVoidElements[t.Data] && tt == html.StartTagToken {
+				open = append(open, t.Data)
+			}
+		case html.EndTagToken:
+			t := z.Token()
+			if htmlDroppedContentElements[t.Data] {
+				if dropped > 0 {
+					dropped--
+				}
+				continue
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != t.Data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					w.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+}
+
+func (p *HTMLPolicy) allowedURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return u.Opaque == ""
+	}
+	for _, s := range p.URLSchemes {
+		if strings.EqualFold(s, u.Scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlPolicy returns policy configured for Echo instance or default policy.
+func (e *Echo) htmlPolicy() *HTMLPolicy {
+	if e.HTMLPolicy != nil {
+		return e.HTMLPolicy
+	}
+	return DefaultHTMLPolicy
+}
+
+func (c *context) HTMLSafe(code int, fragments ...interface{}) error {
+	b := new(strings.Builder)
+	for _, f := range fragments {
+		switch v := f.(type) {
+		case SafeHTML:
+			b.WriteString(string(v))
+		case UntrustedHTML:
+			c.echo.htmlPolicy().sanitize(b, string(v))
+		case string:
+			b.WriteString(html.EscapeString(v))
+		default:
+			b.WriteString(html.EscapeString(fmt.Sprint(v)))
+		}
+	}
+	return c.HTML(code, b.String())
+}
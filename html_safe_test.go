@@ -0,0 +1,97 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLPolicy_Sanitize(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		when   string
+		expect string
+	}{
+		{
+			name:   "ok, allowed elements are kept",
+			when:   `<p>Hello <strong>world</strong><br/>bye</p>`,
+			expect: `<p>Hello <strong>world</strong><br>bye</p>`,
+		},
+		{
+			name:   "ok, script is removed with content",
+			when:   `a<script>alert("x")</script>b<style>p{}</style>c`,
+			expect: `abc`,
+		},
+		{
+			name:   "ok, disallowed elements are removed but text is kept",
+			when:   `<div class="x"><span>text</span></div>`,
+			expect: `text`,
+		},
+		{
+			name:   "ok, disallowed attributes are removed",
+			when:   `<a href="https://example.com" onclick="steal()" title="t">link</a>`,
+			expect: `<a href="https://example.com" title="t">link</a>`,
+		},
+		{
+			name:   "ok, javascript URL is removed",
+			when:   `<a href=" JavaScript:alert(1)">x</a><a href="/relative?a=1&amp;b=2">y</a>`,
+			expect: `<a>x</a><a href="/relative?a=1&amp;b=2">y</a>`,
+		},
+		{
+			name:   "ok, unclosed elements are closed",
+			when:   `<ul><li><em>item`,
+			expect: `<ul><li><em>item</em></li></ul>`,
+		},
+		{
+			name:   "ok, end tag closes nested elements",
+			when:   `<p><b>bold</p>after</b>`,
+			expect: `<p><b>bold</b></p>after`,
+		},
+		{
+			name:   "ok, text is escaped",
+			when:   `1 &lt; 2 & "quotes" <img src=x onerror=alert(1)>`,
+			expect: `1 &lt; 2 &amp; &#34;quotes&#34; `,
+		},
+		{
+			name:   "ok, comments are removed",
+			when:   `a<!-- <script> -->b`,
+			expect: `ab`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, DefaultHTMLPolicy.Sanitize(tc.when))
+		})
+	}
+}
+
+func TestContext_HTMLSafe(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err := c.HTMLSafe(http.StatusOK,
+		SafeHTML(`<h1 class="title">`), "<Tom & Jerry>", SafeHTML("</h1>"),
+		SafeHTML("<div>"), UntrustedHTML(`<b onmouseover="x()">hi</b><script>x()</script>`), SafeHTML("</div>"),
+		42,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, MIMETextHTMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, `<h1 class="title">&lt;Tom &amp; Jerry&gt;</h1><div><b>hi</b></div>42`, rec.Body.String())
+}
+
+func TestContext_HTMLSafe_customPolicy(t *testing.T) {
+	e := New()
+	e.HTMLPolicy = &HTMLPolicy{Elements: map[string][]string{"img": {"src"}}, URLSchemes: []string{"https"}}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err := c.HTMLSafe(http.StatusOK, UntrustedHTML(`<b>x</b><img src="https://example.com/a.png"><img src="http://example.com/b.png">`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `x<img src="https://example.com/a.png"><img>`, rec.Body.String())
+}
@@ -0,0 +1,126 @@
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// htmx headers
+const (
+	HeaderHXRequest               = "HX-Request"
+	HeaderHXBoosted               = "HX-Boosted"
+	HeaderHXCurrentURL            = "HX-Current-URL"
+	HeaderHXHistoryRestoreRequest = "HX-History-Restore-Request"
+	HeaderHXPrompt                = "HX-Prompt"
+	HeaderHXTarget                = "HX-Target"
+	HeaderHXTrigger               = "HX-Trigger"
+	HeaderHXTriggerName           = "HX-Trigger-Name"
+	HeaderHXRedirect              = "HX-Redirect"
+	HeaderHXPushURL               = "HX-Push-Url"
+)
+
+// HTMXRequest contains headers sent by htmx (https://htmx.org) with request.
+type HTMXRequest struct {
+	// Boosted is true when request was made by element using `hx-boost`.
+	Boosted bool
+	// CurrentURL is the current URL of the browser.
+	CurrentURL string
+	// HistoryRestore is true when request is for history restoration after a miss in the local history cache.
+	HistoryRestore bool
+	// Prompt is the user response to `hx-prompt`.
+	Prompt string
+	// Target is the id of the target element.
+	Target string
+	// Trigger is the id of the triggered element.
+	Trigger string
+	// TriggerName is the name of the triggered element.
+	TriggerName string
+}
+
+// htmxTriggers are client side events set with `Context#HTMXTrigger` in order they were added.
+type htmxTriggers struct {
+	names   []string
+	details map[string]interface{}
+}
+
+func (c *context) IsHTMX() bool {
+	return c.request.Header.Get(HeaderHXRequest) == "true"
+}
+
+func (c *context) HTMXRequest() HTMXRequest {
+	h := c.request.Header
+	return HTMXRequest{
+		Boosted:        h.Get(HeaderHXBoosted) == "true",
+		CurrentURL:     h.Get(HeaderHXCurrentURL),
+		HistoryRestore: h.Get(HeaderHXHistoryRestoreRequest) == "true",
+		Prompt:         h.Get(HeaderHXPrompt),
+		Target:         h.Get(HeaderHXTarget),
+		Trigger:        h.Get(HeaderHXTrigger),
+		TriggerName:    h.Get(HeaderHXTriggerName),
+	}
+}
+
+func (c *context) HTMXTrigger(event string, detail interface{}) error {
+	t, _ := c.Get(htmxTriggersKey).(*htmxTriggers)
+	if t == nil {
+		t = &htmxTriggers{details: map[string]interface{}{}}
+		c.Set(htmxTriggersKey, t)
+	}
+	if _, ok := t.details[event]; !ok {
+		t.names = append(t.names, event)
+	}
+	t.details[event] = detail
+
+	withDetails := false
+	for _, d := range t.details {
+		if d != nil {
+			withDetails = true
+			break
+		}
+	}
+	if !withDetails {
+		c.response.Header().Set(HeaderHXTrigger, strings.Join(t.names, ", "))
+		return nil
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("{")
+	for i, name := range t.names {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		n, _ := json.Marshal(name)
+		d, err := json.Marshal(t.details[name])
+		if err != nil {
+			return err
+		}
+		b.Write(n)
+		b.WriteString(":")
+		b.Write(d)
+	}
+	b.WriteString("}")
+	c.response.Header().Set(HeaderHXTrigger, b.String())
+	return nil
+}
+
+func (c *context) HTMXRedirect(url string) error {
+	if !c.IsHTMX() {
+		return c.Redirect(http.StatusSeeOther, url)
+	}
+	c.response.Header().Set(HeaderHXRedirect, url)
+	return c.NoContent(http.StatusOK)
+}
+
+func (c *context) HTMXPushURL(url string) {
+	c.response.Header().Set(HeaderHXPushURL, url)
+}
+
+func (c *context) RenderHTMX(code int, page, partial string, data interface{}) error {
+	c.response.Header().Add(HeaderVary, HeaderHXRequest)
+	r := c.HTMXRequest()
+	if c.IsHTMX() && !r.Boosted && !r.HistoryRestore {
+		return c.Render(code, partial, data)
+	}
+	return c.Render(code, page, data)
+}
@@ -0,0 +1,141 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_HTMXRequest(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	assert.False(t, c.IsHTMX())
+	assert.Equal(t, HTMXRequest{}, c.HTMXRequest())
+
+	req.Header.Set(HeaderHXRequest, "true")
+	req.Header.Set(HeaderHXBoosted, "true")
+	req.Header.Set(HeaderHXCurrentURL, "https://example.com/contacts")
+	req.Header.Set(HeaderHXPrompt, "yes")
+	req.Header.Set(HeaderHXTarget, "rows")
+	req.Header.Set(HeaderHXTrigger, "load-more")
+	req.Header.Set(HeaderHXTriggerName, "page")
+	assert.True(t, c.IsHTMX())
+	assert.Equal(t, HTMXRequest{
+		Boosted:     true,
+		CurrentURL:  "https://example.com/contacts",
+		Prompt:      "yes",
+		Target:      "rows",
+		Trigger:     "load-more",
+		TriggerName: "page",
+	}, c.HTMXRequest())
+}
+
+func TestContext_HTMXTrigger(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+
+	assert.NoError(t, c.HTMXTrigger("saved", nil))
+	assert.NoError(t, c.HTMXTrigger("refresh", nil))
+	assert.Equal(t, "saved, refresh", rec.Header().Get(HeaderHXTrigger))
+
+	assert.NoError(t, c.HTMXTrigger("notify", map[string]string{"level": "info"}))
+	assert.NoError(t, c.HTMXTrigger("saved", 42))
+	assert.Equal(t, `{"saved":42,"refresh":null,"notify":{"level":"info"}}`, rec.Header().Get(HeaderHXTrigger))
+
+	assert.Error(t, c.HTMXTrigger("invalid", make(chan int)))
+}
+
+func TestContext_HTMXRedirect(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenHTMX       bool
+		expectCode     int
+		expectLocation string
+		expectRedirect string
+	}{
+		{
+			name:           "ok, htmx request",
+			whenHTMX:       true,
+			expectCode:     http.StatusOK,
+			expectRedirect: "/login",
+		},
+		{
+			name:           "ok, regular request",
+			expectCode:     http.StatusSeeOther,
+			expectLocation: "/login",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.whenHTMX {
+				req.Header.Set(HeaderHXRequest, "true")
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.HTMXPushURL("/contacts")
+
+			assert.NoError(t, c.HTMXRedirect("/login"))
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectLocation, rec.Header().Get(HeaderLocation))
+			assert.Equal(t, tc.expectRedirect, rec.Header().Get(HeaderHXRedirect))
+			assert.Equal(t, "/contacts", rec.Header().Get(HeaderHXPushURL))
+		})
+	}
+}
+
+func TestContext_RenderHTMX(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenHeader map[string]string
+		expect     string
+	}{
+		{
+			name:   "ok, regular request renders page",
+			expect: "<main><li>Jon</li></main>",
+		},
+		{
+			name:       "ok, htmx request renders partial",
+			whenHeader: map[string]string{HeaderHXRequest: "true"},
+			expect:     "<li>Jon</li>",
+		},
+		{
+			name:       "ok, boosted request renders page",
+			whenHeader: map[string]string{HeaderHXRequest: "true", HeaderHXBoosted: "true"},
+			expect:     "<main><li>Jon</li></main>",
+		},
+		{
+			name:       "ok, history restore request renders page",
+			whenHeader: map[string]string{HeaderHXRequest: "true", HeaderHXHistoryRestoreRequest: "true"},
+			expect:     "<main><li>Jon</li></main>",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Renderer = &Template{
+				templates: template.Must(template.New("page").Parse(
+					`{{define "row"}}<li>{{.}}</li>{{end}}<main>{{template "row" .}}</main>`,
+				)),
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.whenHeader {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			assert.NoError(t, c.RenderHTMX(http.StatusOK, "page", "row", "Jon"))
+			assert.Equal(t, tc.expect, rec.Body.String())
+			assert.Equal(t, HeaderHXRequest, rec.Header().Get(HeaderVary))
+		})
+	}
+}
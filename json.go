@@ -21,7 +21,11 @@ func (d DefaultJSONSerializer) Serialize(c Context, i interface{}, indent string
 
 // Deserialize reads a JSON from a request body and converts it into an interface.
 func (d DefaultJSONSerializer) Deserialize(c Context, i interface{}) error {
-	err := json.NewDecoder(c.Request().Body).Decode(i)
+	return jsonDecodeError(json.NewDecoder(c.Request().Body).Decode(i))
+}
+
+// jsonDecodeError converts errors returned by json.Decoder into HTTPErrors with human readable description
+func jsonDecodeError(err error) error {
 	if ute, ok := err.(*json.UnmarshalTypeError); ok {
 		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
 	} else if se, ok := err.(*json.SyntaxError); ok {
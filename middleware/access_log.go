@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// AccessLogConfig defines the config for AccessLog middleware.
+	AccessLogConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Format is access log format. Possible values are `AccessLogFormatCommon` and `AccessLogFormatCombined`.
+		// Optional. Default value AccessLogFormatCombined.
+		Format string
+
+		// Output is a writer where access log lines are written. Use `NewRotatingFile` for log file with rotation.
+		// Optional. Default value os.Stdout.
+		Output io.Writer
+	}
+)
+
+// Access log formats.
+const (
+	// AccessLogFormatCommon is NCSA Common Log Format: `host ident user [time] "request" status bytes`.
+	AccessLogFormatCommon = "common"
+	// AccessLogFormatCombined is Apache Combined Log Format: Common Log Format followed by quoted referer and
+	// user agent.
+	AccessLogFormatCombined = "combined"
+)
+
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+var (
+	// DefaultAccessLogConfig is the default AccessLog middleware config.
+	DefaultAccessLogConfig = AccessLogConfig{
+		Skipper: DefaultSkipper,
+		Format:  AccessLogFormatCombined,
+		Output:  os.Stdout,
+	}
+)
+
+// AccessLog returns a middleware that writes access log in Apache Combined Log Format to stdout.
+func AccessLog() echo.MiddlewareFunc {
+	return AccessLogWithConfig(DefaultAccessLogConfig)
+}
+
+// AccessLogWithConfig returns an AccessLog middleware with config.
+// See: `AccessLog()`.
+func AccessLogWithConfig(config AccessLogConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultAccessLogConfig.Skipper
+	}
+	if config.Format == "" {
+		config.Format = DefaultAccessLogConfig.Format
+	}
+	if config.Format != AccessLogFormatCommon && config.Format != AccessLogFormatCombined {
+		panic("echo: access log middleware has unknown format: " + config.Format)
+	}
+	if config.Output == nil {
+		config.Output = DefaultAccessLogConfig.Output
+	}
+	combined := config.Format == AccessLogFormatCombined
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			start := time.Now()
+			if err = next(c); err != nil {
+				c.Error(err)
+			}
+
+			req := c.Request()
+			res := c.Response()
+			buf := c.Echo().AcquireBuffer()
+			defer c.Echo().ReleaseBuffer(buf)
+
+			user := "-"
+			if u, _, ok := req.BasicAuth(); ok && u != "" {
+				user = u
+			}
+			buf.WriteString(c.RealIP())
+			buf.WriteString(" - ")
+			buf.WriteString(user)
+			buf.WriteString(" [")
+			buf.WriteString(start.Format(accessLogTimeFormat))
+			buf.WriteString(`] "`)
+			buf.WriteString(req.Method + " " + req.RequestURI + " " + req.Proto)
+			buf.WriteString(`" `)
+			buf.WriteString(strconv.Itoa(res.Status))
+			buf.WriteByte(' ')
+			if res.Size > 0 {
+				buf.WriteString(strconv.FormatInt(res.Size, 10))
+			} else {
+				buf.WriteByte('-')
+			}
+			if combined {
+				buf.WriteString(` "`)
+				buf.WriteString(accessLogValue(req.Referer()))
+				buf.WriteString(`" "`)
+				buf.WriteString(accessLogValue(req.UserAgent()))
+				buf.WriteByte('"')
+			}
+			buf.WriteByte('\n')
+
+			_, err = config.Output.Write(buf.Bytes())
+			return
+		}
+	}
+}
+
+// accessLogValue returns "-" for empty value and escapes quotes so value can not break log line.
+func accessLogValue(v string) string {
+	if v == "" {
+		return "-"
+	}
+	q := strconv.Quote(v)
+	return q[1 : len(q)-1]
+}
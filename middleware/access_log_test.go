@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		format        string
+		givenHeaders  map[string]string
+		givenBasic    string
+		whenHandler   echo.HandlerFunc
+		expectPattern string
+	}{
+		{
+			name:   "ok, combined format",
+			format: AccessLogFormatCombined,
+			givenHeaders: map[string]string{
+				echo.HeaderXRealIP: "10.0.0.1",
+				"Referer":          "http://example.com/",
+				"User-Agent":       `agent "quoted"`,
+			},
+			whenHandler: func(c echo.Context) error {
+				return c.String(http.StatusOK, "hello")
+			},
+			expectPattern: `^10\.0\.0\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /test\?q=1 HTTP/1\.1" 200 5 "http://example\.com/" "agent \\"quoted\\""\n$`,
+		},
+		{
+			name:   "ok, common format with basic auth user",
+			format: AccessLogFormatCommon,
+			givenHeaders: map[string]string{
+				echo.HeaderXRealIP: "10.0.0.1",
+			},
+			givenBasic: "frank",
+			whenHandler: func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			},
+			expectPattern: `^10\.0\.0\.1 - frank \[[^\]]+\] "GET /test\?q=1 HTTP/1\.1" 204 -\n$`,
+		},
+		{
+			name:   "ok, default format and handler error",
+			format: "",
+			givenHeaders: map[string]string{
+				echo.HeaderXRealIP: "10.0.0.1",
+			},
+			whenHandler: func(c echo.Context) error {
+				return errors.New("failed")
+			},
+			expectPattern: `^10\.0\.0\.1 - - \[[^\]]+\] "GET /test\?q=1 HTTP/1\.1" 500 \d+ "-" "-"\n$`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/test?q=1", nil)
+			for k, v := range tc.givenHeaders {
+				req.Header.Set(k, v)
+			}
+			if tc.givenBasic != "" {
+				req.SetBasicAuth(tc.givenBasic, "secret")
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			buf := new(bytes.Buffer)
+			mw := AccessLogWithConfig(AccessLogConfig{Format: tc.format, Output: buf})
+			err := mw(tc.whenHandler)(c)
+
+			assert.NoError(t, err)
+			assert.Regexp(t, regexp.MustCompile(tc.expectPattern), buf.String())
+		})
+	}
+}
+
+func TestAccessLog_panicsOnUnknownFormat(t *testing.T) {
+	assert.Panics(t, func() {
+		AccessLogWithConfig(AccessLogConfig{Format: "json"})
+	})
+}
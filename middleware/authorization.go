@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// AuthorizationConfig defines the config for Authorization middleware.
+	AuthorizationConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Scopes are required for all requests in addition to scopes required by route metadata
+		// `echo.RouteMetaRequiredScopes`.
+		// Optional.
+		Scopes []string
+
+		// Permission is required for all requests without permission in route metadata
+		// `echo.RouteMetaRequiredPermission`.
+		// Optional.
+		Permission string
+
+		// Decider makes the authorization decision. Use it to delegate decisions to policy engines like OPA or
+		// casbin.
+		// Optional. Default value `DefaultAuthorizationDecider`.
+		Decider AuthorizationDecider
+	}
+
+	// AuthorizationRequest describes what principal needs to access the route.
+	AuthorizationRequest struct {
+		// Scopes principal must be granted.
+		Scopes []string
+		// Permission principal must have. Empty when route does not require permission.
+		Permission string
+	}
+
+	// AuthorizationDecider decides if principal is allowed to access the request. When access is denied
+	// the returned reason is sent to the client as problem detail.
+	AuthorizationDecider func(c echo.Context, p *echo.Principal, r AuthorizationRequest) (allowed bool, reason string, err error)
+
+	// ProblemDetails is the RFC 7807 problem details response body returned by Authorization middleware.
+	ProblemDetails struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+)
+
+var (
+	// DefaultAuthorizationConfig is the default Authorization middleware config.
+	DefaultAuthorizationConfig = AuthorizationConfig{
+		Skipper: DefaultSkipper,
+		Decider: DefaultAuthorizationDecider,
+	}
+)
+
+// DefaultAuthorizationDecider allows access when principal is granted all required scopes and the required
+// permission as a scope.
+func DefaultAuthorizationDecider(c echo.Context, p *echo.Principal, r AuthorizationRequest) (bool, string, error) {
+	var missing []string
+	for _, s := range r.Scopes {
+		if !p.HasScope(s) {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return false, "missing required scopes: " + strings.Join(missing, ", "), nil
+	}
+	if r.Permission != "" && !p.HasScope(r.Permission) {
+		return false, "missing required permission: " + r.Permission, nil
+	}
+	return true, "", nil
+}
+
+// RequireScopes returns a middleware that requires authenticated principal (`Context#User`) to be granted
+// `scopes` and scopes declared with route metadata `echo.RouteMetaRequiredScopes`. Unauthenticated requests are
+// responded with 401 and denied requests with 403 problem details (`application/problem+json`).
+//
+// Example:
+//
+//	e.Use(middleware.OIDC(issuer, clientID, redirectURL), middleware.RequireScopes())
+//	e.RouteMeta(e.POST("/orders", create))[echo.RouteMetaRequiredScopes] = []string{"orders:write"}
+func RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	c := DefaultAuthorizationConfig
+	c.Scopes = scopes
+	return AuthorizationWithConfig(c)
+}
+
+// RequirePermission returns a middleware that requires authenticated principal (`Context#User`) to have the
+// permission declared with route metadata `echo.RouteMetaRequiredPermission` or `permission` for routes without
+// it. See `RequireScopes()` for responses.
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	c := DefaultAuthorizationConfig
+	c.Permission = permission
+	return AuthorizationWithConfig(c)
+}
+
+// AuthorizationWithConfig returns an Authorization middleware with config.
+// See: `RequireScopes()`.
+func AuthorizationWithConfig(config AuthorizationConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultAuthorizationConfig.Skipper
+	}
+	if config.Decider == nil {
+		config.Decider = DefaultAuthorizationConfig.Decider
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			ar := AuthorizationRequest{Permission: config.Permission}
+			ar.Scopes = append(ar.Scopes, config.Scopes...)
+			if r := c.Route(); r != nil {
+				meta := c.Echo().RouteMeta(r)
+				scopes, _ := meta[echo.RouteMetaRequiredScopes].([]string)
+				ar.Scopes = append(ar.Scopes, scopes...)
+				if permission, _ := meta[echo.RouteMetaRequiredPermission].(string); permission != "" {
+					ar.Permission = permission
+				}
+			}
+
+			p := c.User()
+			if p == nil {
+				return problem(c, http.StatusUnauthorized, "authentication required")
+			}
+			allowed, reason, err := config.Decider(c, p, ar)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return problem(c, http.StatusForbidden, reason)
+			}
+			return next(c)
+		}
+	}
+}
+
+// problem sends RFC 7807 problem details response.
+func problem(c echo.Context, status int, detail string) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationProblemJSON)
+	return c.JSON(status, ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request().URL.Path,
+	})
+}
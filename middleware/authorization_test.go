@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireScopes(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		middleware  echo.MiddlewareFunc
+		whenUser    *echo.Principal
+		whenPath    string
+		expectCode  int
+		expectBody  string
+		expectCType string
+	}{
+		{
+			name:       "ok, principal has route scopes",
+			middleware: RequireScopes(),
+			whenUser:   &echo.Principal{Subject: "1", Scopes: []string{"orders:read", "orders:write"}},
+			whenPath:   "/orders",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:        "nok, principal is missing route scope",
+			middleware:  RequireScopes(),
+			whenUser:    &echo.Principal{Subject: "1", Scopes: []string{"orders:read"}},
+			whenPath:    "/orders",
+			expectCode:  http.StatusForbidden,
+			expectBody:  `{"type":"about:blank","title":"Forbidden","status":403,"detail":"missing required scopes: orders:write","instance":"/orders"}` + "\n",
+			expectCType: echo.MIMEApplicationProblemJSON,
+		},
+		{
+			name:       "nok, principal is missing middleware scope",
+			middleware: RequireScopes("admin"),
+			whenUser:   &echo.Principal{Subject: "1", Scopes: []string{"orders:read", "orders:write"}},
+			whenPath:   "/orders",
+			expectCode: http.StatusForbidden,
+		},
+		{
+			name:        "nok, unauthenticated",
+			middleware:  RequireScopes(),
+			whenPath:    "/orders",
+			expectCode:  http.StatusUnauthorized,
+			expectBody:  `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"authentication required","instance":"/orders"}` + "\n",
+			expectCType: echo.MIMEApplicationProblemJSON,
+		},
+		{
+			name:       "ok, route permission overrides middleware permission",
+			middleware: RequirePermission("default"),
+			whenUser:   &echo.Principal{Subject: "1", Scopes: []string{"reports.view"}},
+			whenPath:   "/reports",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "nok, middleware permission for route without permission",
+			middleware: RequirePermission("default"),
+			whenUser:   &echo.Principal{Subject: "1", Scopes: []string{"reports.view"}},
+			whenPath:   "/public",
+			expectCode: http.StatusForbidden,
+			expectBody: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"missing required permission: default","instance":"/public"}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if tc.whenUser != nil {
+						c.SetUser(tc.whenUser)
+					}
+					return next(c)
+				}
+			})
+			e.Use(tc.middleware)
+			ok := func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			}
+			e.RouteMeta(e.GET("/orders", ok))[echo.RouteMetaRequiredScopes] = []string{"orders:read", "orders:write"}
+			e.RouteMeta(e.GET("/reports", ok))[echo.RouteMetaRequiredPermission] = "reports.view"
+			e.GET("/public", ok)
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenPath, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectBody != "" {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+			if tc.expectCType != "" {
+				assert.Equal(t, tc.expectCType, rec.Header().Get(echo.HeaderContentType))
+			}
+		})
+	}
+}
+
+func TestAuthorizationWithConfig_decider(t *testing.T) {
+	var got AuthorizationRequest
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetUser(&echo.Principal{Subject: "1"})
+			return next(c)
+		}
+	})
+	e.Use(AuthorizationWithConfig(AuthorizationConfig{
+		Scopes: []string{"a"},
+		Decider: func(c echo.Context, p *echo.Principal, r AuthorizationRequest) (bool, string, error) {
+			got = r
+			if c.QueryParam("fail") != "" {
+				return false, "", errors.New("policy engine unavailable")
+			}
+			return p.Subject == "1", "", nil
+		},
+	}))
+	e.RouteMeta(e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}))[echo.RouteMetaRequiredPermission] = "p"
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, AuthorizationRequest{Scopes: []string{"a"}, Permission: "p"}, got)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?fail=1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+type (
+	// BandwidthLimitConfig defines the config for BandwidthLimit middleware.
+	BandwidthLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// ReadRate is number of bytes per second request body can be read with. Zero means unlimited.
+		// Optional.
+		ReadRate int
+
+		// WriteRate is number of bytes per second response body can be written with. Zero means unlimited.
+		// Optional.
+		WriteRate int
+
+		// Burst is number of bytes that can be read or written at once without waiting. Reads and writes are split
+		// into parts not larger than burst.
+		// Optional. Default value is the rate (one second of data).
+		Burst int
+
+		// KeyExtractor returns key requests share limits by (i.e. user ID). Limits are shared only by requests
+		// being served at the same time.
+		// Optional. Default value returns client address and port so limits apply per connection.
+		KeyExtractor func(c echo.Context) string
+	}
+
+	bandwidthLimiters struct {
+		mu      sync.Mutex
+		entries map[string]*bandwidthLimiter
+	}
+
+	bandwidthLimiter struct {
+		read  *rate.Limiter
+		write *rate.Limiter
+		refs  int
+	}
+
+	bandwidthLimitReader struct {
+		io.ReadCloser
+		ctx     context.Context
+		limiter *rate.Limiter
+	}
+
+	bandwidthLimitWriter struct {
+		http.ResponseWriter
+		ctx     context.Context
+		limiter *rate.Limiter
+	}
+)
+
+var (
+	// DefaultBandwidthLimitConfig is the default BandwidthLimit middleware config.
+	DefaultBandwidthLimitConfig = BandwidthLimitConfig{
+		Skipper: DefaultSkipper,
+		KeyExtractor: func(c echo.Context) string {
+			return c.Request().RemoteAddr
+		},
+	}
+)
+
+// BandwidthLimit returns a middleware that throttles reading request body and writing response body to given
+// number of bytes per second per connection, i.e. for fairness between clients of large file endpoints.
+func BandwidthLimit(readRate, writeRate int) echo.MiddlewareFunc {
+	c := DefaultBandwidthLimitConfig
+	c.ReadRate = readRate
+	c.WriteRate = writeRate
+	return BandwidthLimitWithConfig(c)
+}
+
+// BandwidthLimitWithConfig returns a BandwidthLimit middleware with config.
+// See: `BandwidthLimit()`.
+func BandwidthLimitWithConfig(config BandwidthLimitConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultBandwidthLimitConfig.Skipper
+	}
+	if config.KeyExtractor == nil {
+		config.KeyExtractor = DefaultBandwidthLimitConfig.KeyExtractor
+	}
+	limiters := &bandwidthLimiters{entries: map[string]*bandwidthLimiter{}}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) || (config.ReadRate <= 0 && config.WriteRate <= 0) {
+				return next(c)
+			}
+
+			key := config.KeyExtractor(c)
+			l := limiters.acquire(key, config)
+			defer limiters.release(key)
+
+			req := c.Request()
+			if l.read != nil && req.Body != nil {
+				req.Body = &bandwidthLimitReader{ReadCloser: req.Body, ctx: req.Context(), limiter: l.read}
+			}
+			if l.write != nil {
+				res := c.Response()
+				original := res.Writer
+				res.Writer = &bandwidthLimitWriter{ResponseWriter: original, ctx: req.Context(), limiter: l.write}
+				defer func() { res.Writer = original }()
+			}
+			return next(c)
+		}
+	}
+}
+
+func (s *bandwidthLimiters) acquire(key string, config BandwidthLimitConfig) *bandwidthLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.entries[key]
+	if !ok {
+		l = &bandwidthLimiter{
+			read:  newBandwidthLimiter(config.ReadRate, config.Burst),
+			write: newBandwidthLimiter(config.WriteRate, config.Burst),
+		}
+		s.entries[key] = l
+	}
+	l.refs++
+	return l
+}
+
+func (s *bandwidthLimiters) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l := s.entries[key]; l != nil {
+		l.refs--
+		if l.refs == 0 {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func newBandwidthLimiter(bytesPerSecond, burst int) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = bytesPerSecond
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+func (r *bandwidthLimitReader) Read(p []byte) (int, error) {
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+func (w *bandwidthLimitWriter) Write(b []byte) (int, error) {
+	written := 0
+	burst := w.limiter.Burst()
+	for written < len(b) {
+		part := b[written:]
+		if len(part) > burst {
+			part = part[:burst]
+		}
+		if err := w.limiter.WaitN(w.ctx, len(part)); err != nil {
+			return written, err
+		}
+		n, err := w.ResponseWriter.Write(part)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (w *bandwidthLimitWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter so `echo.Response#Hijack` can reach it.
+func (w *bandwidthLimitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandwidthLimitWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenConfig BandwidthLimitConfig
+		whenBody    string
+		expectMin   time.Duration
+		expectMax   time.Duration
+	}{
+		{
+			name:        "ok, unlimited",
+			givenConfig: BandwidthLimitConfig{},
+			whenBody:    strings.Repeat("a", 300),
+			expectMax:   100 * time.Millisecond,
+		},
+		{
+			name:        "ok, read is throttled",
+			givenConfig: BandwidthLimitConfig{ReadRate: 1000, Burst: 100},
+			whenBody:    strings.Repeat("a", 300),
+			expectMin:   150 * time.Millisecond,
+		},
+		{
+			name:        "ok, write is throttled",
+			givenConfig: BandwidthLimitConfig{WriteRate: 1000, Burst: 100},
+			whenBody:    strings.Repeat("a", 300),
+			expectMin:   150 * time.Millisecond,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(BandwidthLimitWithConfig(tc.givenConfig))
+			e.POST("/", func(c echo.Context) error {
+				b, err := ioutil.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+				return c.Blob(http.StatusOK, echo.MIMEOctetStream, b)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.whenBody))
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			e.ServeHTTP(rec, req)
+			elapsed := time.Since(start)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.whenBody, rec.Body.String())
+			assert.GreaterOrEqual(t, int64(elapsed), int64(tc.expectMin))
+			if tc.expectMax != 0 {
+				assert.Less(t, int64(elapsed), int64(tc.expectMax))
+			}
+		})
+	}
+}
+
+func TestBandwidthLimit_sharedKey(t *testing.T) {
+	l := &bandwidthLimiters{entries: map[string]*bandwidthLimiter{}}
+	config := BandwidthLimitConfig{ReadRate: 10, WriteRate: 20}
+
+	a := l.acquire("user1", config)
+	b := l.acquire("user1", config)
+	other := l.acquire("user2", config)
+	assert.Same(t, a, b)
+	assert.False(t, a == other)
+	assert.Equal(t, 10, a.read.Burst())
+	assert.Equal(t, 20, a.write.Burst())
+
+	l.release("user1")
+	l.release("user2")
+	assert.Len(t, l.entries, 1)
+	l.release("user1")
+	assert.Len(t, l.entries, 0)
+}
@@ -3,10 +3,13 @@ package middleware
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -20,9 +23,20 @@ type (
 		// Handler receives request and response payload.
 		// Required.
 		Handler BodyDumpHandler
+
+		// Decompress enables decompressing of payloads encoded with gzip or deflate (according to
+		// `Content-Encoding` header) before they are passed to Handler, so they can be logged in readable form.
+		// Payloads that fail to decompress are passed as is.
+		// Optional. Default value false.
+		Decompress bool
+
+		// DecompressLimit is maximum size of decompressed payload in bytes. Larger payloads are truncated.
+		// Optional. Default value 1MB.
+		DecompressLimit int64
 	}
 
-	// BodyDumpHandler receives the request and response payload.
+	// BodyDumpHandler receives the request and response payload. Payload slices are backed by buffers from
+	// `Echo#BufferPool` and are valid only until handler returns - copy them if they need to be retained.
 	BodyDumpHandler func(echo.Context, []byte, []byte)
 
 	bodyDumpResponseWriter struct {
@@ -34,7 +48,8 @@ type (
 var (
 	// DefaultBodyDumpConfig is the default BodyDump middleware config.
 	DefaultBodyDumpConfig = BodyDumpConfig{
-		Skipper: DefaultSkipper,
+		Skipper:         DefaultSkipper,
+		DecompressLimit: 1 << 20,
 	}
 )
 
@@ -58,6 +73,9 @@ func BodyDumpWithConfig(config BodyDumpConfig) echo.MiddlewareFunc {
 	if config.Skipper == nil {
 		config.Skipper = DefaultBodyDumpConfig.Skipper
 	}
+	if config.DecompressLimit == 0 {
+		config.DecompressLimit = DefaultBodyDumpConfig.DecompressLimit
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
@@ -66,14 +84,17 @@ func BodyDumpWithConfig(config BodyDumpConfig) echo.MiddlewareFunc {
 			}
 
 			// Request
-			reqBody := []byte{}
+			reqBuf := c.Echo().AcquireBuffer()
+			defer c.Echo().ReleaseBuffer(reqBuf)
 			if c.Request().Body != nil { // Read
-				reqBody, _ = ioutil.ReadAll(c.Request().Body)
+				_, _ = reqBuf.ReadFrom(c.Request().Body)
 			}
-			c.Request().Body = ioutil.NopCloser(bytes.NewBuffer(reqBody)) // Reset
+			reqBody := reqBuf.Bytes()
+			c.Request().Body = ioutil.NopCloser(bytes.NewReader(reqBody)) // Reset
 
 			// Response
-			resBody := new(bytes.Buffer)
+			resBody := c.Echo().AcquireBuffer()
+			defer c.Echo().ReleaseBuffer(resBody)
 			mw := io.MultiWriter(c.Response().Writer, resBody)
 			writer := &bodyDumpResponseWriter{Writer: mw, ResponseWriter: c.Response().Writer}
 			c.Response().Writer = writer
@@ -83,13 +104,43 @@ func BodyDumpWithConfig(config BodyDumpConfig) echo.MiddlewareFunc {
 			}
 
 			// Callback
-			config.Handler(c, reqBody, resBody.Bytes())
+			resBytes := resBody.Bytes()
+			if config.Decompress {
+				reqBody = decodeBody(c.Request().Header.Get(echo.HeaderContentEncoding), reqBody, config.DecompressLimit)
+				resBytes = decodeBody(c.Response().Header().Get(echo.HeaderContentEncoding), resBytes, config.DecompressLimit)
+			}
+			config.Handler(c, reqBody, resBytes)
 
 			return
 		}
 	}
 }
 
+// decodeBody decompresses body encoded with given content encoding. At most limit bytes are returned. Body is returned
+// as is when encoding is not supported or body is not validly encoded (i.e. it was already decompressed by other
+// middleware).
+func decodeBody(encoding string, body []byte, limit int64) []byte {
+	var r io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case gzipScheme:
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	default:
+		return body
+	}
+	if err != nil {
+		return body
+	}
+	defer r.Close()
+	decoded, err := ioutil.ReadAll(io.LimitReader(r, limit))
+	if err != nil && len(decoded) == 0 {
+		return body
+	}
+	return decoded
+}
+
 func (w *bodyDumpResponseWriter) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 }
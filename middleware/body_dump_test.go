@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -89,3 +91,76 @@ func TestBodyDumpFails(t *testing.T) {
 		}
 	})
 }
+
+func TestBodyDumpDecompress(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		givenDecompress bool
+		givenLimit      int64
+		expectReqBody   string
+		expectResBody   string
+		expectRawRes    bool
+	}{
+		{
+			name:            "ok, decompress",
+			givenDecompress: true,
+			expectReqBody:   "hello request",
+			expectResBody:   strings.Repeat("hello response ", 100),
+		},
+		{
+			name:            "ok, decompress with limit",
+			givenDecompress: true,
+			givenLimit:      5,
+			expectReqBody:   "hello",
+			expectResBody:   "hello",
+		},
+		{
+			name:            "ok, no decompress",
+			givenDecompress: false,
+			expectRawRes:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			var reqBody, resBody []byte
+			e.Use(BodyDumpWithConfig(BodyDumpConfig{
+				Decompress:      tc.givenDecompress,
+				DecompressLimit: tc.givenLimit,
+				Handler: func(c echo.Context, req, res []byte) {
+					reqBody = req
+					resBody = res
+				},
+			}))
+			e.Use(Gzip())
+			e.POST("/", func(c echo.Context) error {
+				return c.String(http.StatusOK, strings.Repeat("hello response ", 100))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipBytes(t, "hello request")))
+			req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+			req.Header.Set(echo.HeaderAcceptEncoding, gzipScheme)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if tc.expectRawRes {
+				assert.Equal(t, rec.Body.Bytes(), resBody)
+				assert.Equal(t, gzipBytes(t, "hello request"), reqBody)
+				return
+			}
+			assert.Equal(t, tc.expectReqBody, string(reqBody))
+			assert.Equal(t, tc.expectResBody, string(resBody))
+		})
+	}
+}
+
+func gzipBytes(t *testing.T, s string) []byte {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	_, err := w.Write([]byte(s))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// BodyTeeConfig defines the config for BodyTee middleware.
+	BodyTeeConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Store creates writers request bodies are copied to.
+		// Required.
+		Store BodyTeeStore
+
+		// MaxSize is maximum size of request body in bytes that is stored. Bodies exceeding the limit are still
+		// readable by handler but stored copy is discarded.
+		// Optional. Default value 10 MB.
+		MaxSize int64
+	}
+
+	// BodyTeeStore creates storage writers for request bodies.
+	BodyTeeStore interface {
+		// Create returns writer request body is copied to. Writer is closed after handler has returned and
+		// rest of the body (not read by handler) has been copied.
+		Create(c echo.Context) (io.WriteCloser, error)
+		// Discard is called after writer is closed when stored copy is not complete, i.e. handler returned error,
+		// body exceeded MaxSize or reading/writing body failed. Discard should clean up partially stored body.
+		Discard(c echo.Context, w io.WriteCloser) error
+	}
+
+	// BodyTeeWriterFunc is BodyTeeStore for writers that need no cleanup (i.e. buffers, hash functions).
+	BodyTeeWriterFunc func(c echo.Context) (io.WriteCloser, error)
+
+	bodyTeeDirStore struct {
+		dir string
+	}
+
+	teeBody struct {
+		io.ReadCloser
+		w        io.Writer
+		limit    int64
+		written  int64
+		storeErr error
+		readErr  error
+	}
+)
+
+var (
+	// DefaultBodyTeeConfig is the default BodyTee middleware config.
+	DefaultBodyTeeConfig = BodyTeeConfig{
+		Skipper: DefaultSkipper,
+		MaxSize: 10 << 20,
+	}
+
+	errBodyTeeTooLarge = errors.New("request body exceeds body tee size limit")
+)
+
+// BodyTee returns a BodyTee middleware.
+//
+// BodyTee middleware copies request body to writer created by store while handler reads the body, so payloads can
+// be archived or reprocessed later without buffering them in memory. Part of the body not read by handler is copied
+// after handler has returned. Stored copy is discarded when handler returns error.
+//
+// Example:
+//
+//	e.POST("/events", ingest, middleware.BodyTee(middleware.NewBodyTeeDirStore("/var/lib/app/payloads")))
+func BodyTee(store BodyTeeStore) echo.MiddlewareFunc {
+	c := DefaultBodyTeeConfig
+	c.Store = store
+	return BodyTeeWithConfig(c)
+}
+
+// BodyTeeWithConfig returns a BodyTee middleware with config.
+// See: `BodyTee()`.
+func BodyTeeWithConfig(config BodyTeeConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Store == nil {
+		panic("echo: body-tee middleware requires store")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultBodyTeeConfig.Skipper
+	}
+	if config.MaxSize == 0 {
+		config.MaxSize = DefaultBodyTeeConfig.MaxSize
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			w, err := config.Store.Create(c)
+			if err != nil {
+				return err
+			}
+			req := c.Request()
+			body := &teeBody{ReadCloser: req.Body, w: w, limit: config.MaxSize}
+			req.Body = body
+
+			err = next(c)
+
+			if err == nil && body.storeErr == nil && body.readErr == nil {
+				// copy rest of the body handler did not read, reading stops when size limit is exceeded
+				io.Copy(ioutil.Discard, io.LimitReader(body, config.MaxSize-body.written+1))
+			}
+			closeErr := w.Close()
+			if err != nil || body.storeErr != nil || body.readErr != nil || closeErr != nil {
+				if dErr := config.Store.Discard(c, w); dErr != nil {
+					c.Logger().Errorf("body-tee: failed to discard stored request body: %v", dErr)
+				}
+			}
+			return err
+		}
+	}
+}
+
+// Create implements `BodyTeeStore.Create`.
+func (f BodyTeeWriterFunc) Create(c echo.Context) (io.WriteCloser, error) {
+	return f(c)
+}
+
+// Discard implements `BodyTeeStore.Discard`. Does nothing.
+func (f BodyTeeWriterFunc) Discard(c echo.Context, w io.WriteCloser) error {
+	return nil
+}
+
+// NewBodyTeeDirStore returns BodyTeeStore that stores request bodies as files in dir. File names are random with
+// ".body" extension. Files of discarded bodies are removed.
+func NewBodyTeeDirStore(dir string) BodyTeeStore {
+	return &bodyTeeDirStore{dir: dir}
+}
+
+func (s *bodyTeeDirStore) Create(c echo.Context) (io.WriteCloser, error) {
+	return ioutil.TempFile(s.dir, "*.body")
+}
+
+func (s *bodyTeeDirStore) Discard(c echo.Context, w io.WriteCloser) error {
+	return os.Remove(w.(*os.File).Name())
+}
+
+func (b *teeBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	if n > 0 && b.storeErr == nil {
+		if b.written+int64(n) > b.limit {
+			b.storeErr = errBodyTeeTooLarge
+		} else if _, wErr := b.w.Write(p[:n]); wErr != nil {
+			b.storeErr = wErr
+		}
+		b.written += int64(n)
+	}
+	if err != nil && err != io.EOF {
+		b.readErr = err
+	}
+	return
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+type recordingTeeStore struct {
+	writer    *bufferCloser
+	discarded bool
+}
+
+func (s *recordingTeeStore) Create(c echo.Context) (io.WriteCloser, error) {
+	s.writer = &bufferCloser{}
+	return s.writer, nil
+}
+
+func (s *recordingTeeStore) Discard(c echo.Context, w io.WriteCloser) error {
+	s.discarded = true
+	return nil
+}
+
+func TestBodyTee(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		givenMaxSize    int64
+		whenReadBytes   int64
+		whenHandlerErr  error
+		expectStored    string
+		expectDiscarded bool
+	}{
+		{
+			name:          "ok, body read by handler is stored",
+			whenReadBytes: -1,
+			expectStored:  "hello world",
+		},
+		{
+			name:          "ok, body not read by handler is stored",
+			whenReadBytes: 5,
+			expectStored:  "hello world",
+		},
+		{
+			name:            "nok, handler error discards stored body",
+			whenReadBytes:   -1,
+			whenHandlerErr:  errors.New("handler error"),
+			expectStored:    "hello world",
+			expectDiscarded: true,
+		},
+		{
+			name:            "nok, body exceeding max size is discarded",
+			givenMaxSize:    5,
+			whenReadBytes:   -1,
+			expectStored:    "",
+			expectDiscarded: true,
+		},
+		{
+			name:            "nok, unread body exceeding max size is discarded",
+			givenMaxSize:    5,
+			whenReadBytes:   0,
+			expectStored:    "",
+			expectDiscarded: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			store := &recordingTeeStore{}
+			mw := BodyTeeWithConfig(BodyTeeConfig{Store: store, MaxSize: tc.givenMaxSize})
+			err := mw(func(c echo.Context) error {
+				var r io.Reader = c.Request().Body
+				if tc.whenReadBytes >= 0 {
+					r = io.LimitReader(r, tc.whenReadBytes)
+				}
+				b, err := ioutil.ReadAll(r)
+				assert.NoError(t, err)
+				if tc.whenReadBytes < 0 {
+					assert.Equal(t, "hello world", string(b))
+				}
+				return tc.whenHandlerErr
+			})(c)
+
+			assert.Equal(t, tc.whenHandlerErr, err)
+			assert.True(t, store.writer.closed)
+			assert.Equal(t, tc.expectStored, store.writer.String())
+			assert.Equal(t, tc.expectDiscarded, store.discarded)
+		})
+	}
+}
+
+func TestBodyTee_dirStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "body-tee")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	e := echo.New()
+	e.POST("/ok", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, BodyTee(NewBodyTeeDirStore(dir)))
+	e.POST("/fail", func(c echo.Context) error {
+		return echo.ErrBadRequest
+	}, BodyTee(NewBodyTeeDirStore(dir)))
+
+	for _, path := range []string{"/ok", "/fail"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("payload of "+path))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.body"))
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		b, err := ioutil.ReadFile(files[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "payload of /ok", string(b))
+	}
+}
+
+func TestBodyTeeWriterFunc(t *testing.T) {
+	buf := &bufferCloser{}
+	store := BodyTeeWriterFunc(func(c echo.Context) (io.WriteCloser, error) {
+		return buf, nil
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("data"))
+	c := e.NewContext(req, httptest.NewRecorder())
+	err := BodyTee(store)(func(c echo.Context) error {
+		return nil
+	})(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "data", buf.String())
+}
+
+func TestBodyTeeWithConfig_panicsWithoutStore(t *testing.T) {
+	assert.Panics(t, func() {
+		BodyTeeWithConfig(BodyTeeConfig{})
+	})
+}
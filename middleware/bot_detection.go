@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// BotDetectionConfig defines the config for BotDetection middleware.
+	BotDetectionConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Crawlers are known crawlers verified with reverse DNS lookup.
+		// Optional. Default value DefaultCrawlers.
+		Crawlers []Crawler
+
+		// BotPattern matches User-Agent of generic automated clients (libraries, tools, unknown bots).
+		// Optional. Default value DefaultBotPattern.
+		BotPattern *regexp.Regexp
+
+		// Resolver is used for reverse and forward DNS lookups when verifying crawlers.
+		// Optional. Default value net.DefaultResolver.
+		Resolver DNSResolver
+
+		// CacheTTL is duration crawler verification results are cached for by IP.
+		// Optional. Default value 1 hour.
+		CacheTTL time.Duration
+
+		// ContextKey is the key used to store client class (`string`) in context.
+		// Optional. Default value "client_class".
+		ContextKey string
+
+		// BlockUnverified responds 403 Forbidden to clients claiming to be known crawler that fail verification.
+		// Optional. Default value false.
+		BlockUnverified bool
+
+		// IPExtractor extracts address of client verified as crawler. Configure it (or `Echo#IPExtractor`) with
+		// trusted proxies when Echo is behind reverse proxy.
+		// Optional. Default value uses `Echo#IPExtractor` and when it is not set address of direct peer
+		// (`echo.ExtractIPDirect`). Proxy headers are never trusted by default.
+		IPExtractor echo.IPExtractor
+
+		// BotMiddleware is applied to requests of bots and unverified crawlers (i.e. `middleware.RateLimiter(store)`
+		// to rate-limit them separately from browsers).
+		// Optional.
+		BotMiddleware echo.MiddlewareFunc
+	}
+
+	// Crawler describes well known crawler that can be verified with reverse DNS lookup
+	// (https://developers.google.com/search/docs/crawling-indexing/verifying-googlebot).
+	Crawler struct {
+		// Name of the crawler (i.e. "Googlebot").
+		Name string
+		// UserAgent matches User-Agent header of requests claiming to be the crawler.
+		UserAgent *regexp.Regexp
+		// Domains are domains (i.e. "googlebot.com") host names of crawler IP addresses belong to.
+		Domains []string
+	}
+
+	// DNSResolver performs DNS lookups. It is implemented by `*net.Resolver`.
+	DNSResolver interface {
+		LookupAddr(ctx context.Context, addr string) ([]string, error)
+		LookupHost(ctx context.Context, host string) ([]string, error)
+	}
+
+	crawlerVerification struct {
+		verified bool
+		expires  time.Time
+	}
+)
+
+// botDetectionCacheSize is maximum number of cached crawler verification results.
+const botDetectionCacheSize = 10000
+
+// Client classes stored in context by BotDetection middleware.
+const (
+	// ClientClassBrowser is client not recognized as bot.
+	ClientClassBrowser = "browser"
+	// ClientClassBot is automated client (library, tool or unknown bot).
+	ClientClassBot = "bot"
+	// ClientClassVerifiedCrawler is known crawler verified with reverse DNS lookup.
+	ClientClassVerifiedCrawler = "verified_crawler"
+	// ClientClassUnverifiedCrawler is client claiming to be known crawler that failed verification.
+	ClientClassUnverifiedCrawler = "unverified_crawler"
+)
+
+var (
+	// DefaultCrawlers are major search engine crawlers.
+	DefaultCrawlers = []Crawler{
+		{Name: "Googlebot", UserAgent: regexp.MustCompile(`(?i)googlebot|google-inspectiontool`), Domains: []string{"googlebot.com", "google.com"}},
+		{Name: "Bingbot", UserAgent: regexp.MustCompile(`(?i)bingbot`), Domains: []string{"search.msn.com"}},
+		{Name: "Applebot", UserAgent: regexp.MustCompile(`(?i)applebot`), Domains: []string{"applebot.apple.com"}},
+		{Name: "YandexBot", UserAgent: regexp.MustCompile(`(?i)yandex(bot|images)`), Domains: []string{"yandex.ru", "yandex.net", "yandex.com"}},
+		{Name: "Baiduspider", UserAgent: regexp.MustCompile(`(?i)baiduspider`), Domains: []string{"baidu.com", "baidu.jp"}},
+	}
+
+	// DefaultBotPattern matches User-Agent of common bots, HTTP libraries and tools.
+	DefaultBotPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|curl|wget|python|go-http-client|java/|okhttp|httpclient|headless|scrapy`)
+
+	// DefaultBotDetectionConfig is the default BotDetection middleware config.
+	DefaultBotDetectionConfig = BotDetectionConfig{
+		Skipper:    DefaultSkipper,
+		CacheTTL:   time.Hour,
+		ContextKey: "client_class",
+	}
+)
+
+// BotDetection returns a BotDetection middleware that classifies requests by User-Agent and verifies clients claiming
+// to be major crawlers with reverse DNS lookup. Client class (`ClientClassBrowser`, `ClientClassBot`,
+// `ClientClassVerifiedCrawler` or `ClientClassUnverifiedCrawler`) is stored in context as "client_class".
+func BotDetection() echo.MiddlewareFunc {
+	return BotDetectionWithConfig(DefaultBotDetectionConfig)
+}
+
+// BotDetectionWithConfig returns a BotDetection middleware with config.
+// See: `BotDetection()`.
+func BotDetectionWithConfig(config BotDetectionConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultBotDetectionConfig.Skipper
+	}
+	if config.Crawlers == nil {
+		config.Crawlers = DefaultCrawlers
+	}
+	if config.BotPattern == nil {
+		config.BotPattern = DefaultBotPattern
+	}
+	if config.Resolver == nil {
+		config.Resolver = net.DefaultResolver
+	}
+	if config.CacheTTL == 0 {
+		config.CacheTTL = DefaultBotDetectionConfig.CacheTTL
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultBotDetectionConfig.ContextKey
+	}
+
+	var mu sync.Mutex
+	cache := map[string]crawlerVerification{}
+	verify := func(c echo.Context, crawler Crawler, ip string) bool {
+		key := crawler.Name + "|" + ip
+		now := time.Now()
+		mu.Lock()
+		v, ok := cache[key]
+		mu.Unlock()
+		if ok && now.Before(v.expires) {
+			return v.verified
+		}
+		verified := verifyCrawler(c.Request().Context(), config.Resolver, crawler, ip)
+		mu.Lock()
+		if len(cache) >= botDetectionCacheSize {
+			cache = map[string]crawlerVerification{} // bounds memory when many clients claim to be crawlers
+		}
+		cache[key] = crawlerVerification{verified: verified, expires: now.Add(config.CacheTTL)}
+		mu.Unlock()
+		return verified
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		botNext := next
+		if config.BotMiddleware != nil {
+			botNext = config.BotMiddleware(next)
+		}
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			class := ClientClassBrowser
+			ua := c.Request().UserAgent()
+			if crawler, ok := matchCrawler(config.Crawlers, ua); ok {
+				class = ClientClassUnverifiedCrawler
+				if verify(c, crawler, trustedIP(c, config.IPExtractor)) {
+					class = ClientClassVerifiedCrawler
+				}
+			} else if ua == "" || config.BotPattern.MatchString(ua) {
+				class = ClientClassBot
+			}
+			c.Set(config.ContextKey, class)
+
+			switch class {
+			case ClientClassUnverifiedCrawler:
+				if config.BlockUnverified {
+					return echo.ErrForbidden
+				}
+				return botNext(c)
+			case ClientClassBot:
+				return botNext(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+func matchCrawler(crawlers []Crawler, ua string) (Crawler, bool) {
+	for _, c := range crawlers {
+		if c.UserAgent.MatchString(ua) {
+			return c, true
+		}
+	}
+	return Crawler{}, false
+}
+
+// verifyCrawler checks that reverse DNS name of ip belongs to crawler domains and that the name resolves back to ip.
+func verifyCrawler(ctx context.Context, resolver DNSResolver, crawler Crawler, ip string) bool {
+	if net.ParseIP(ip) == nil {
+		return false
+	}
+	names, err := resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		name = strings.TrimSuffix(strings.ToLower(name), ".")
+		if !hasDomain(name, crawler.Domains) {
+			continue
+		}
+		addrs, err := resolver.LookupHost(ctx, name)
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if net.ParseIP(a).Equal(net.ParseIP(ip)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasDomain(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDNSResolver struct {
+	addr    map[string][]string
+	host    map[string][]string
+	lookups int
+}
+
+func (r *fakeDNSResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r.lookups++
+	if names, ok := r.addr[addr]; ok {
+		return names, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *fakeDNSResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.host[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("not found")
+}
+
+const googlebotUA = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+func newBotTestResolver() *fakeDNSResolver {
+	return &fakeDNSResolver{
+		addr: map[string][]string{
+			"66.249.66.1": {"crawl-66-249-66-1.googlebot.com."},
+			"192.0.2.1":   {"crawl.googlebot.com.attacker.example."},
+			"192.0.2.2":   {"crawl-66-249-66-1.googlebot.com."}, // forward lookup does not match
+		},
+		host: map[string][]string{
+			"crawl-66-249-66-1.googlebot.com": {"66.249.66.1"},
+		},
+	}
+}
+
+func TestBotDetectionWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		givenBlock      bool
+		whenUA          string
+		whenIP          string
+		whenXFF         string
+		expectClass     string
+		expectErr       string
+		expectBotMWUsed bool
+	}{
+		{
+			name:        "ok, browser",
+			whenUA:      "Mozilla/5.0 (X11; Linux x86_64) Firefox/89.0",
+			whenIP:      "198.51.100.1",
+			expectClass: ClientClassBrowser,
+		},
+		{
+			name:            "ok, generic bot",
+			whenUA:          "curl/7.68.0",
+			whenIP:          "198.51.100.1",
+			expectClass:     ClientClassBot,
+			expectBotMWUsed: true,
+		},
+		{
+			name:            "ok, empty user agent is bot",
+			whenUA:          "",
+			whenIP:          "198.51.100.1",
+			expectClass:     ClientClassBot,
+			expectBotMWUsed: true,
+		},
+		{
+			name:        "ok, verified crawler",
+			whenUA:      googlebotUA,
+			whenIP:      "66.249.66.1",
+			expectClass: ClientClassVerifiedCrawler,
+		},
+		{
+			name:            "ok, crawler with foreign domain is not verified",
+			whenUA:          googlebotUA,
+			whenIP:          "192.0.2.1",
+			expectClass:     ClientClassUnverifiedCrawler,
+			expectBotMWUsed: true,
+		},
+		{
+			name:            "ok, crawler with mismatching forward lookup is not verified",
+			whenUA:          googlebotUA,
+			whenIP:          "192.0.2.2",
+			expectClass:     ClientClassUnverifiedCrawler,
+			expectBotMWUsed: true,
+		},
+		{
+			name:            "nok, crawler address from proxy header is not trusted by default",
+			whenUA:          googlebotUA,
+			whenIP:          "192.0.2.3",
+			whenXFF:         "66.249.66.1",
+			expectClass:     ClientClassUnverifiedCrawler,
+			expectBotMWUsed: true,
+		},
+		{
+			name:        "nok, unverified crawler blocked",
+			givenBlock:  true,
+			whenUA:      googlebotUA,
+			whenIP:      "192.0.2.3",
+			expectClass: ClientClassUnverifiedCrawler,
+			expectErr:   "code=403, message=Forbidden",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("User-Agent", tc.whenUA)
+			req.RemoteAddr = tc.whenIP + ":1234"
+			if tc.whenXFF != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.whenXFF)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			botMWUsed := false
+			mw := BotDetectionWithConfig(BotDetectionConfig{
+				Resolver:        newBotTestResolver(),
+				BlockUnverified: tc.givenBlock,
+				BotMiddleware: func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						botMWUsed = true
+						return next(c)
+					}
+				},
+			})
+			err := mw(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectClass, c.Get("client_class"))
+			assert.Equal(t, tc.expectBotMWUsed, botMWUsed)
+		})
+	}
+}
+
+func TestBotDetection_cachesVerification(t *testing.T) {
+	resolver := newBotTestResolver()
+	h := BotDetectionWithConfig(BotDetectionConfig{Resolver: resolver})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	e := echo.New()
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("User-Agent", googlebotUA)
+		req.RemoteAddr = "66.249.66.1:1234"
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		assert.NoError(t, h(c))
+		assert.Equal(t, ClientClassVerifiedCrawler, c.Get("client_class"))
+	}
+	assert.Equal(t, 1, resolver.lookups)
+}
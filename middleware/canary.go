@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// CanaryConfig defines the config for Canary middleware.
+	CanaryConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Weight is the share of requests routed to canary. Weight can be changed at runtime.
+		// Required.
+		Weight *CanaryWeight
+
+		// Handler serves requests routed to canary.
+		// Required when Target is not set.
+		Handler echo.HandlerFunc
+
+		// Target is upstream URL where requests routed to canary are proxied. Used when Handler is not set.
+		Target *url.URL
+
+		// CookieName is the name of cookie used for sticky assignment. When request has no such cookie a random
+		// value is assigned and cookie is set, so subsequent requests of the same client stay on the same variant.
+		// Optional. Default value "echo_canary".
+		CookieName string
+
+		// Header is the name of request header (i.e. "X-User-ID") used for sticky assignment. When set it takes
+		// precedence over cookie and requests without the header are assigned randomly.
+		// Optional.
+		Header string
+
+		// ContextKey is the key used to store canary assignment (`bool`) in context.
+		// Optional. Default value "canary".
+		ContextKey string
+	}
+
+	// CanaryWeight is share of requests routed to canary that can be safely changed while serving requests.
+	CanaryWeight struct {
+		basisPoints uint32
+	}
+)
+
+var (
+	// DefaultCanaryConfig is the default Canary middleware config.
+	DefaultCanaryConfig = CanaryConfig{
+		Skipper:    DefaultSkipper,
+		CookieName: "echo_canary",
+		ContextKey: "canary",
+	}
+)
+
+// NewCanaryWeight creates weight with given percentage (0-100) of requests routed to canary.
+func NewCanaryWeight(percent float64) *CanaryWeight {
+	w := new(CanaryWeight)
+	w.Set(percent)
+	return w
+}
+
+// Set sets percentage (0-100) of requests routed to canary. Values out of range are clamped.
+func (w *CanaryWeight) Set(percent float64) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	atomic.StoreUint32(&w.basisPoints, uint32(percent*echo.CanaryBuckets/100))
+}
+
+// Get returns percentage of requests routed to canary.
+func (w *CanaryWeight) Get() float64 {
+	return float64(atomic.LoadUint32(&w.basisPoints)) * 100 / echo.CanaryBuckets
+}
+
+// Canary returns a Canary middleware that routes given percentage of requests to canary handler.
+func Canary(percent float64, handler echo.HandlerFunc) echo.MiddlewareFunc {
+	c := DefaultCanaryConfig
+	c.Weight = NewCanaryWeight(percent)
+	c.Handler = handler
+	return CanaryWithConfig(c)
+}
+
+// CanaryWithConfig returns a Canary middleware with config.
+// See: `Canary()`.
+func CanaryWithConfig(config CanaryConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Weight == nil {
+		panic("echo: canary middleware requires weight")
+	}
+	if config.Handler == nil {
+		if config.Target == nil {
+			panic("echo: canary middleware requires handler or target")
+		}
+		config.Handler = echo.WrapHandler(httputil.NewSingleHostReverseProxy(config.Target))
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultCanaryConfig.Skipper
+	}
+	if config.CookieName == "" {
+		config.CookieName = DefaultCanaryConfig.CookieName
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultCanaryConfig.ContextKey
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			bucket := echo.CanaryBucket(c, config.Header, config.CookieName)
+			canary := bucket < atomic.LoadUint32(&config.Weight.basisPoints)
+			c.Set(config.ContextKey, canary)
+			if canary {
+				return config.Handler(c)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryWeight(t *testing.T) {
+	w := NewCanaryWeight(12.5)
+	assert.Equal(t, 12.5, w.Get())
+
+	w.Set(150)
+	assert.Equal(t, 100.0, w.Get())
+
+	w.Set(-1)
+	assert.Equal(t, 0.0, w.Get())
+}
+
+func TestCanary(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenPercent float64
+		expectBody   string
+	}{
+		{name: "ok, all to canary", givenPercent: 100, expectBody: "canary"},
+		{name: "ok, none to canary", givenPercent: 0, expectBody: "stable"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(Canary(tc.givenPercent, func(c echo.Context) error {
+				return c.String(http.StatusOK, "canary")
+			}))
+			e.GET("/", func(c echo.Context) error {
+				return c.String(http.StatusOK, "stable")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Contains(t, rec.Header().Get(echo.HeaderSetCookie), "echo_canary=")
+		})
+	}
+}
+
+func TestCanary_StickyHeaderAndRuntimeWeight(t *testing.T) {
+	e := echo.New()
+	weight := NewCanaryWeight(50)
+	e.Use(CanaryWithConfig(CanaryConfig{
+		Weight: weight,
+		Header: "X-User-ID",
+		Handler: func(c echo.Context) error {
+			return c.String(http.StatusOK, "canary")
+		},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		assert.Equal(t, false, c.Get("canary"))
+		return c.String(http.StatusOK, "stable")
+	})
+
+	serve := func(userID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	canaries := 0
+	for i := 0; i < 1000; i++ {
+		id := strconv.Itoa(i)
+		first := serve(id)
+		assert.Equal(t, first, serve(id), "assignment must be sticky")
+		if first == "canary" {
+			canaries++
+		}
+	}
+	assert.InDelta(t, 500, canaries, 100)
+
+	weight.Set(0)
+	assert.Equal(t, "stable", serve("1"))
+}
+
+func TestCanary_Target(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("upstream:" + r.URL.Path))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	e := echo.New()
+	e.Use(CanaryWithConfig(CanaryConfig{Weight: NewCanaryWeight(100), Target: target}))
+	e.GET("/users", func(c echo.Context) error {
+		return c.String(http.StatusOK, "stable")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "upstream:/users", rec.Body.String())
+}
+
+func TestCanaryWithConfig_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		CanaryWithConfig(CanaryConfig{Weight: NewCanaryWeight(1)})
+	})
+	assert.Panics(t, func() {
+		CanaryWithConfig(CanaryConfig{Handler: func(c echo.Context) error { return nil }})
+	})
+}
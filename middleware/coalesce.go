@@ -0,0 +1,301 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// CoalesceConfig defines the config for Coalesce middleware.
+	CoalesceConfig struct {
+		// Skipper defines a function to skip middleware.
+		// Optional. Default value skips requests with `Authorization` or `Cookie` header as their responses are
+		// usually private to the client.
+		Skipper Skipper
+
+		// KeyExtractor returns key identifying requests that are served by single handler execution.
+		// Optional. Default value returns request method, host and URI.
+		KeyExtractor func(c echo.Context) string
+
+		// MaxBodySize is maximum size of response body in bytes that is buffered and shared with waiting requests.
+		// Larger responses are streamed to the client and waiting requests execute the handler themselves.
+		// Optional. Default value 1MB.
+		MaxBodySize int
+	}
+
+	coalesceGroup struct {
+		mu    sync.Mutex
+		calls map[string]*coalesceCall
+	}
+
+	coalesceCall struct {
+		done chan struct{}
+		// res is nil when response could not be shared.
+		res *coalescedResponse
+	}
+
+	coalescedResponse struct {
+		status int
+		header http.Header
+		body   []byte
+	}
+
+	coalesceRecorder struct {
+		writer      http.ResponseWriter
+		header      http.Header
+		status      int
+		body        bytes.Buffer
+		limit       int
+		passthrough bool
+	}
+)
+
+var (
+	// DefaultCoalesceConfig is the default Coalesce middleware config.
+	DefaultCoalesceConfig = CoalesceConfig{
+		Skipper: func(c echo.Context) bool {
+			h := c.Request().Header
+			return h.Get(echo.HeaderAuthorization) != "" || h.Get(echo.HeaderCookie) != ""
+		},
+		KeyExtractor: func(c echo.Context) string {
+			req := c.Request()
+			return req.Method + " " + req.Host + req.RequestURI
+		},
+		MaxBodySize: 1 << 20,
+	}
+)
+
+// Coalesce returns a middleware that coalesces concurrent GET and HEAD requests for the same key into single handler
+// execution, i.e. to prevent dog-pile of revalidation requests on an expensive resource when cached copies expire.
+// Handler is executed without `If-None-Match` and `If-Modified-Since` headers and its response is shared with all
+// waiting requests. Conditional headers of each request are then evaluated against shared `ETag` and
+// `Last-Modified` headers so clients with current copy receive 304 Not Modified.
+//
+// Responses other than 200 OK and responses with `Set-Cookie`, `Vary` or `Cache-Control: private/no-store` headers
+// are not shared, waiting requests execute the handler themselves instead.
+func Coalesce() echo.MiddlewareFunc {
+	return CoalesceWithConfig(DefaultCoalesceConfig)
+}
+
+// CoalesceWithConfig returns a Coalesce middleware with config.
+// See: `Coalesce()`.
+func CoalesceWithConfig(config CoalesceConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultCoalesceConfig.Skipper
+	}
+	if config.KeyExtractor == nil {
+		config.KeyExtractor = DefaultCoalesceConfig.KeyExtractor
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultCoalesceConfig.MaxBodySize
+	}
+	group := &coalesceGroup{calls: map[string]*coalesceCall{}}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			method := c.Request().Method
+			if config.Skipper(c) || (method != http.MethodGet && method != http.MethodHead) {
+				return next(c)
+			}
+
+			key := config.KeyExtractor(c)
+			call, leader := group.join(key)
+			if !leader {
+				select {
+				case <-call.done:
+				case <-c.Request().Context().Done():
+					return c.Request().Context().Err()
+				}
+				if call.res == nil {
+					return next(c)
+				}
+				return writeCoalescedResponse(c, call.res, false)
+			}
+
+			res, err := executeCoalesced(c, next, config.MaxBodySize)
+			call.res = res
+			group.leave(key, call)
+			if err != nil || res == nil {
+				return err
+			}
+			return writeCoalescedResponse(c, res, true)
+		}
+	}
+}
+
+func (g *coalesceGroup) join(key string) (*coalesceCall, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call, false
+	}
+	call := &coalesceCall{done: make(chan struct{})}
+	g.calls[key] = call
+	return call, true
+}
+
+func (g *coalesceGroup) leave(key string, call *coalesceCall) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+}
+
+// executeCoalesced executes handler without revalidation headers and records its response. Nil response is returned
+// when response was already sent to the client or it can not be shared. Recorded response is not sent to the client,
+// but `echo.Response` is already committed and its before hooks are applied to recorded headers.
+func executeCoalesced(c echo.Context, next echo.HandlerFunc, limit int) (res *coalescedResponse, err error) {
+	req := c.Request()
+	ifNoneMatch, hasIfNoneMatch := req.Header[echo.HeaderIfNoneMatch]
+	ifModifiedSince, hasIfModifiedSince := req.Header[echo.HeaderIfModifiedSince]
+	req.Header.Del(echo.HeaderIfNoneMatch)
+	req.Header.Del(echo.HeaderIfModifiedSince)
+
+	response := c.Response()
+	original := response.Writer
+	// only headers set by the handler are recorded, so per request headers set before are not shared
+	rec := &coalesceRecorder{writer: original, header: http.Header{}, limit: limit}
+	response.Writer = rec
+	defer func() {
+		response.Writer = original
+		if hasIfNoneMatch {
+			req.Header[echo.HeaderIfNoneMatch] = ifNoneMatch
+		}
+		if hasIfModifiedSince {
+			req.Header[echo.HeaderIfModifiedSince] = ifModifiedSince
+		}
+	}()
+
+	if err = next(c); err != nil {
+		if rec.status != 0 && !rec.passthrough {
+			rec.startPassthrough()
+		}
+		return nil, err
+	}
+	if rec.passthrough || rec.status == 0 {
+		return nil, nil
+	}
+	res = &coalescedResponse{status: rec.status, header: rec.header, body: rec.body.Bytes()}
+	if !res.shareable() {
+		// response is sent to the leader as is, without evaluating its conditional headers
+		rec.startPassthrough()
+		return nil, nil
+	}
+	return res, nil
+}
+
+func (r *coalescedResponse) shareable() bool {
+	if r.status != http.StatusOK || r.header.Get(echo.HeaderSetCookie) != "" || r.header.Get(echo.HeaderVary) != "" {
+		return false
+	}
+	cc := strings.ToLower(r.header.Get(echo.HeaderCacheControl))
+	return !strings.Contains(cc, "private") && !strings.Contains(cc, "no-store")
+}
+
+// writeCoalescedResponse writes shared response or 304 Not Modified when request conditional headers match it.
+// Committed response (of the request that executed the handler) is written directly to the underlying writer as
+// before hooks were already applied to shared headers.
+func writeCoalescedResponse(c echo.Context, res *coalescedResponse, committed bool) error {
+	response := c.Response()
+	header := response.Header()
+	for k, v := range res.header {
+		if _, ok := header[k]; ok && !committed {
+			continue
+		}
+		header[k] = append([]string(nil), v...)
+	}
+
+	status, body := res.status, res.body
+	lastModified, _ := time.Parse(http.TimeFormat, res.header.Get(echo.HeaderLastModified))
+	if s, ok := c.EvaluatePreconditions(res.header.Get(echo.HeaderETag), lastModified); !ok {
+		header.Del(echo.HeaderContentLength)
+		header.Del(echo.HeaderContentType)
+		status, body = s, nil
+	}
+	if c.Request().Method == http.MethodHead {
+		body = nil
+	}
+
+	if !committed {
+		response.WriteHeader(status)
+		if len(body) == 0 {
+			return nil
+		}
+		_, err := response.Write(body)
+		return err
+	}
+	response.Status = status
+	response.Writer.WriteHeader(status)
+	n, err := response.Writer.Write(body)
+	response.Size = int64(n)
+	return err
+}
+
+func (r *coalesceRecorder) Header() http.Header {
+	if r.passthrough {
+		return r.writer.Header()
+	}
+	return r.header
+}
+
+func (r *coalesceRecorder) WriteHeader(code int) {
+	if r.passthrough {
+		r.writer.WriteHeader(code)
+		return
+	}
+	if r.status == 0 {
+		r.status = code
+	}
+}
+
+func (r *coalesceRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.passthrough && r.body.Len()+len(b) > r.limit {
+		r.startPassthrough()
+	}
+	if r.passthrough {
+		return r.writer.Write(b)
+	}
+	return r.body.Write(b)
+}
+
+// Flush switches to streaming the response to the client as streamed responses are not shared.
+func (r *coalesceRecorder) Flush() {
+	if !r.passthrough {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		r.startPassthrough()
+	}
+	if f, ok := r.writer.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// startPassthrough writes recorded status, headers and body to the client and forwards all following writes.
+func (r *coalesceRecorder) startPassthrough() {
+	r.passthrough = true
+	header := r.writer.Header()
+	for k, v := range r.header {
+		header[k] = v
+	}
+	r.writer.WriteHeader(r.status)
+	if r.body.Len() > 0 {
+		r.writer.Write(r.body.Bytes())
+		r.body.Reset()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter so `echo.Response#Hijack` can reach it.
+func (r *coalesceRecorder) Unwrap() http.ResponseWriter {
+	return r.writer
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		whenMethod       string
+		whenHeader       http.Header
+		givenSetCookie   bool
+		expectExecutions int32
+		expectStatus     int
+		expectBody       string
+	}{
+		{
+			name:             "ok, concurrent requests are served by single execution",
+			whenMethod:       http.MethodGet,
+			expectExecutions: 1,
+			expectStatus:     http.StatusOK,
+			expectBody:       "resource",
+		},
+		{
+			name:             "ok, revalidation with current etag is not modified",
+			whenMethod:       http.MethodGet,
+			whenHeader:       http.Header{echo.HeaderIfNoneMatch: []string{`"v1"`}},
+			expectExecutions: 1,
+			expectStatus:     http.StatusNotModified,
+		},
+		{
+			name:             "ok, revalidation with stale etag gets refreshed resource",
+			whenMethod:       http.MethodGet,
+			whenHeader:       http.Header{echo.HeaderIfNoneMatch: []string{`"v0"`}},
+			expectExecutions: 1,
+			expectStatus:     http.StatusOK,
+			expectBody:       "resource",
+		},
+		{
+			name:             "ok, HEAD requests are coalesced",
+			whenMethod:       http.MethodHead,
+			expectExecutions: 1,
+			expectStatus:     http.StatusOK,
+		},
+		{
+			name:             "ok, response with cookie is not shared",
+			whenMethod:       http.MethodGet,
+			givenSetCookie:   true,
+			expectExecutions: 3,
+			expectStatus:     http.StatusOK,
+			expectBody:       "resource",
+		},
+		{
+			name:             "ok, requests with authorization are skipped",
+			whenMethod:       http.MethodGet,
+			whenHeader:       http.Header{echo.HeaderAuthorization: []string{"Bearer token"}},
+			expectExecutions: 3,
+			expectStatus:     http.StatusOK,
+			expectBody:       "resource",
+		},
+		{
+			name:             "ok, POST requests are not coalesced",
+			whenMethod:       http.MethodPost,
+			expectExecutions: 3,
+			expectStatus:     http.StatusOK,
+			expectBody:       "resource",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			const requests = 3
+			arrived := make(chan struct{}, requests)
+			release := make(chan struct{})
+			var executions int32
+
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					arrived <- struct{}{}
+					return next(c)
+				}
+			})
+			e.Use(Coalesce())
+			e.Match([]string{http.MethodGet, http.MethodHead, http.MethodPost}, "/", func(c echo.Context) error {
+				atomic.AddInt32(&executions, 1)
+				<-release
+				assert.Empty(t, c.Request().Header.Get(echo.HeaderIfNoneMatch))
+				c.Response().Header().Set(echo.HeaderETag, `"v1"`)
+				if tc.givenSetCookie {
+					c.SetCookie(&http.Cookie{Name: "session", Value: "1"})
+				}
+				return c.String(http.StatusOK, "resource")
+			})
+
+			wg := sync.WaitGroup{}
+			recs := make([]*httptest.ResponseRecorder, requests)
+			for i := range recs {
+				recs[i] = httptest.NewRecorder()
+				req := httptest.NewRequest(tc.whenMethod, "/", nil)
+				for k, v := range tc.whenHeader {
+					req.Header[k] = v
+				}
+				wg.Add(1)
+				go func(rec *httptest.ResponseRecorder) {
+					defer wg.Done()
+					e.ServeHTTP(rec, req)
+				}(recs[i])
+			}
+			for i := 0; i < requests; i++ {
+				<-arrived
+			}
+			time.Sleep(20 * time.Millisecond) // let waiting requests join the execution
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, tc.expectExecutions, atomic.LoadInt32(&executions))
+			for _, rec := range recs {
+				assert.Equal(t, tc.expectStatus, rec.Code)
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+				assert.Equal(t, `"v1"`, rec.Header().Get(echo.HeaderETag))
+			}
+		})
+	}
+}
+
+func TestCoalesce_perRequestHeadersAreNotShared(t *testing.T) {
+	arrived := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderXRequestID, c.Request().Header.Get(echo.HeaderXRequestID))
+			return next(c)
+		}
+	})
+	e.Use(CoalesceWithConfig(CoalesceConfig{
+		KeyExtractor: func(c echo.Context) string {
+			arrived <- struct{}{}
+			return "key"
+		},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		<-release
+		c.Response().Header().Set("X-Version", "1")
+		return c.String(http.StatusOK, "resource")
+	})
+
+	wg := sync.WaitGroup{}
+	recs := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	for i, id := range []string{"a", "b"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXRequestID, id)
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			e.ServeHTTP(rec, req)
+		}(recs[i])
+	}
+	<-arrived
+	<-arrived
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, "a", recs[0].Header().Get(echo.HeaderXRequestID))
+	assert.Equal(t, "b", recs[1].Header().Get(echo.HeaderXRequestID))
+	for _, rec := range recs {
+		assert.Equal(t, "1", rec.Header().Get("X-Version"))
+		assert.Equal(t, "resource", rec.Body.String())
+	}
+}
+
+func TestCoalesce_largeResponseIsStreamed(t *testing.T) {
+	e := echo.New()
+	e.Use(CoalesceWithConfig(CoalesceConfig{MaxBodySize: 4}))
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "larger than limit")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderIfNoneMatch, `"v1"`)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "larger than limit", rec.Body.String())
+	assert.Equal(t, `"v1"`, req.Header.Get(echo.HeaderIfNoneMatch))
+}
+
+func TestCoalesce_handlerError(t *testing.T) {
+	e := echo.New()
+	e.Use(Coalesce())
+	e.GET("/", func(c echo.Context) error {
+		return echo.ErrNotFound
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "{\"message\":\"Not Found\"}\n", rec.Body.String())
+}
@@ -28,6 +28,12 @@ type (
 		io.Writer
 		http.ResponseWriter
 	}
+
+	// countingWriter counts bytes written to underlying writer.
+	countingWriter struct {
+		io.Writer
+		n int64
+	}
 )
 
 const (
@@ -77,7 +83,8 @@ func GzipWithConfig(config GzipConfig) echo.MiddlewareFunc {
 					return echo.NewHTTPError(http.StatusInternalServerError, i.(error).Error())
 				}
 				rw := res.Writer
-				w.Reset(rw)
+				cw := &countingWriter{Writer: rw}
+				w.Reset(cw)
 				defer func() {
 					if res.Size == 0 {
 						if res.Header().Get(echo.HeaderContentEncoding) == gzipScheme {
@@ -91,6 +98,10 @@ func GzipWithConfig(config GzipConfig) echo.MiddlewareFunc {
 					}
 					w.Close()
 					pool.Put(w)
+					if res.Size > 0 {
+						res.ContentEncoding = gzipScheme
+						res.EncodedSize = cw.n
+					}
 				}()
 				grw := &gzipResponseWriter{Writer: w, ResponseWriter: rw}
 				res.Writer = grw
@@ -100,6 +111,12 @@ func GzipWithConfig(config GzipConfig) echo.MiddlewareFunc {
 	}
 }
 
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.Writer.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
 func (w *gzipResponseWriter) WriteHeader(code int) {
 	if code == http.StatusNoContent { // Issue #489
 		w.ResponseWriter.Header().Del(echo.HeaderContentEncoding)
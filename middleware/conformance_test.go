@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware/middlewaretest"
+)
+
+func TestMiddlewareConformance(t *testing.T) {
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer authServer.Close()
+
+	var testCases = map[string]middlewaretest.Factory{
+		"Secure": func(s func(echo.Context) bool) echo.MiddlewareFunc { return SecureWithConfig(SecureConfig{Skipper: s}) },
+		"CORS":   func(s func(echo.Context) bool) echo.MiddlewareFunc { return CORSWithConfig(CORSConfig{Skipper: s}) },
+		"RequestID": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return RequestIDWithConfig(RequestIDConfig{Skipper: s})
+		},
+		"Logger": func(s func(echo.Context) bool) echo.MiddlewareFunc { return LoggerWithConfig(LoggerConfig{Skipper: s}) },
+		"Gzip":   func(s func(echo.Context) bool) echo.MiddlewareFunc { return GzipWithConfig(GzipConfig{Skipper: s}) },
+		"BodyLimit": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return BodyLimitWithConfig(BodyLimitConfig{Skipper: s, Limit: "1M"})
+		},
+		"Recover": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return RecoverWithConfig(RecoverConfig{Skipper: s})
+		},
+		"Timeout": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return TimeoutWithConfig(TimeoutConfig{Skipper: s})
+		},
+		"Deprecation": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return DeprecationWithConfig(DeprecationConfig{Skipper: s})
+		},
+		"ForwardAuth": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return ForwardAuthWithConfig(ForwardAuthConfig{Skipper: s, Address: authServer.URL})
+		},
+		"Coalesce": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return CoalesceWithConfig(CoalesceConfig{Skipper: s})
+		},
+		"BandwidthLimit": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return BandwidthLimitWithConfig(BandwidthLimitConfig{Skipper: s, ReadRate: 1 << 20, WriteRate: 1 << 20})
+		},
+		"PriorityScheduler": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return PrioritySchedulerWithConfig(PrioritySchedulerConfig{Skipper: s, Classes: []PriorityClass{{Name: "default", Weight: 1}}})
+		},
+		"LoadShedding": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return LoadSheddingWithConfig(LoadSheddingConfig{Skipper: s})
+		},
+		"RequestDeadline": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return RequestDeadlineWithConfig(RequestDeadlineConfig{Skipper: s})
+		},
+		"HeaderHardening": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return HeaderHardeningWithConfig(HeaderHardeningConfig{Skipper: s})
+		},
+		"DebugDump": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return DebugDumpWithConfig(DebugDumpConfig{Skipper: s, Activate: func(c echo.Context) bool { return true }})
+		},
+		"HeaderPropagation": func(s func(echo.Context) bool) echo.MiddlewareFunc {
+			return HeaderPropagationWithConfig(HeaderPropagationConfig{
+				Skipper: s,
+				Rules:   []HeaderPropagationRule{{Header: echo.HeaderXRequestID, ResponseHeader: echo.HeaderXRequestID}},
+			})
+		},
+	}
+	for name, f := range testCases {
+		t.Run(name, func(t *testing.T) {
+			middlewaretest.Run(t, f)
+		})
+	}
+}
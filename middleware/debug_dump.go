@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type (
+	// DebugDumpConfig defines the config for DebugDump middleware.
+	DebugDumpConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Header is request header activating dump when its value equals Token.
+		// Optional. Default value "X-Debug-Dump".
+		Header string
+
+		// Token is secret value of Header activating dump. Header activation is disabled when Token is empty so
+		// dumps can not be triggered by clients without it.
+		// Optional.
+		Token string
+
+		// RequestIDs are request ids (`X-Request-ID` request or response header) of requests to dump.
+		// Optional.
+		RequestIDs []string
+
+		// Activate decides if request is dumped in addition to Header and RequestIDs activation, i.e. to toggle
+		// dumps for requests ids at runtime.
+		// Optional.
+		Activate func(c echo.Context) bool
+
+		// RedactHeaders are request and response headers which values are replaced with "[REDACTED]" in addition
+		// to headers always redacted by `Context#DumpRequest`.
+		// Optional. Default value ["Set-Cookie"].
+		RedactHeaders []string
+
+		// MaxBodySize is maximum number of request and response body bytes included in dump. Longer bodies are
+		// truncated.
+		// Optional. Default value 4KB.
+		MaxBodySize int64
+
+		// Sink receives dump after response is written.
+		// Optional. Default value logs dump with `Context#Logger` on INFO level.
+		Sink func(c echo.Context, d *echo.RequestDump)
+	}
+
+	debugDumpResponseWriter struct {
+		http.ResponseWriter
+		body      bytes.Buffer
+		limit     int64
+		truncated bool
+	}
+)
+
+var (
+	// DefaultDebugDumpConfig is the default DebugDump middleware config.
+	DefaultDebugDumpConfig = DebugDumpConfig{
+		Skipper:       DefaultSkipper,
+		Header:        "X-Debug-Dump",
+		RedactHeaders: []string{echo.HeaderSetCookie},
+		MaxBodySize:   4 << 10,
+		Sink:          logDebugDump,
+	}
+)
+
+// DebugDump returns a DebugDump middleware dumping requests with header `X-Debug-Dump: <token>`.
+//
+// DebugDump middleware records sanitized dump of request and response (sensitive headers redacted, bodies
+// truncated) for requests activated by header, request id or custom function and passes it to a sink. It is meant
+// for debugging single requests in production without logging all traffic. Place it after RequestID middleware so
+// generated request ids are known.
+func DebugDump(token string) echo.MiddlewareFunc {
+	c := DefaultDebugDumpConfig
+	c.Token = token
+	return DebugDumpWithConfig(c)
+}
+
+// DebugDumpWithConfig returns a DebugDump middleware with config.
+// See: `DebugDump()`.
+func DebugDumpWithConfig(config DebugDumpConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDebugDumpConfig.Skipper
+	}
+	if config.Header == "" {
+		config.Header = DefaultDebugDumpConfig.Header
+	}
+	if config.RedactHeaders == nil {
+		config.RedactHeaders = DefaultDebugDumpConfig.RedactHeaders
+	}
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = DefaultDebugDumpConfig.MaxBodySize
+	}
+	if config.Sink == nil {
+		config.Sink = DefaultDebugDumpConfig.Sink
+	}
+
+	activated := func(c echo.Context) bool {
+		if config.Token != "" {
+			v := c.Request().Header.Get(config.Header)
+			if subtle.ConstantTimeCompare([]byte(v), []byte(config.Token)) == 1 {
+				return true
+			}
+		}
+		if len(config.RequestIDs) > 0 {
+			rid := c.Request().Header.Get(echo.HeaderXRequestID)
+			if rid == "" {
+				rid = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+			for _, id := range config.RequestIDs {
+				if rid != "" && rid == id {
+					return true
+				}
+			}
+		}
+		return config.Activate != nil && config.Activate(c)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			if config.Skipper(c) || !activated(c) {
+				return next(c)
+			}
+
+			dump, err := c.DumpRequest(config.MaxBodySize)
+			if err != nil {
+				return err
+			}
+			// activation header contains the token and must not leak into dumps
+			dump.Redact(config.Header)
+
+			res := c.Response()
+			writer := &debugDumpResponseWriter{ResponseWriter: res.Writer, limit: config.MaxBodySize}
+			res.Writer = writer
+			defer func() {
+				res.Writer = writer.ResponseWriter
+			}()
+
+			start := time.Now()
+			if err = next(c); err != nil {
+				dump.Error = err.Error()
+				c.Error(err)
+			}
+
+			dump.Latency = time.Since(start)
+			dump.Status = res.Status
+			dump.ResponseHeader = res.Header().Clone()
+			dump.ResponseBody = writer.body.String()
+			dump.ResponseBodyTruncated = writer.truncated
+			if dump.RequestID == "" {
+				dump.RequestID = res.Header().Get(echo.HeaderXRequestID)
+			}
+			dump.Redact(config.RedactHeaders...)
+			config.Sink(c, dump)
+			return
+		}
+	}
+}
+
+func logDebugDump(c echo.Context, d *echo.RequestDump) {
+	c.Logger().Infoj(log.JSON{"debug_dump": d})
+}
+
+func (w *debugDumpResponseWriter) Write(b []byte) (int, error) {
+	if room := w.limit - int64(w.body.Len()); room > 0 {
+		if int64(len(b)) > room {
+			w.body.Write(b[:room])
+			w.truncated = true
+		} else {
+			w.body.Write(b)
+		}
+	} else if len(b) > 0 {
+		w.truncated = true
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *debugDumpResponseWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *debugDumpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugDump(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		config     DebugDumpConfig
+		whenHeader map[string]string
+		whenPath   string
+		expectDump bool
+	}{
+		{
+			name:       "ok, not activated",
+			config:     DebugDumpConfig{Token: "t0ken"},
+			whenPath:   "/users",
+			expectDump: false,
+		},
+		{
+			name:       "ok, activated by header",
+			config:     DebugDumpConfig{Token: "t0ken"},
+			whenHeader: map[string]string{"X-Debug-Dump": "t0ken"},
+			whenPath:   "/users",
+			expectDump: true,
+		},
+		{
+			name:       "nok, wrong token",
+			config:     DebugDumpConfig{Token: "t0ken"},
+			whenHeader: map[string]string{"X-Debug-Dump": "guess"},
+			whenPath:   "/users",
+			expectDump: false,
+		},
+		{
+			name:       "nok, header activation disabled without token",
+			config:     DebugDumpConfig{},
+			whenHeader: map[string]string{"X-Debug-Dump": ""},
+			whenPath:   "/users",
+			expectDump: false,
+		},
+		{
+			name:       "ok, activated by request id",
+			config:     DebugDumpConfig{RequestIDs: []string{"rid-1"}},
+			whenHeader: map[string]string{echo.HeaderXRequestID: "rid-1"},
+			whenPath:   "/users",
+			expectDump: true,
+		},
+		{
+			name: "ok, activated by function",
+			config: DebugDumpConfig{Activate: func(c echo.Context) bool {
+				return c.QueryParam("debug") == "1"
+			}},
+			whenPath:   "/users?debug=1",
+			expectDump: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dump *echo.RequestDump
+			tc.config.Sink = func(c echo.Context, d *echo.RequestDump) {
+				dump = d
+			}
+			e := echo.New()
+			e.Use(DebugDumpWithConfig(tc.config))
+			e.POST("/users", func(c echo.Context) error {
+				body, _ := ioutil.ReadAll(c.Request().Body)
+				c.SetCookie(&http.Cookie{Name: "session", Value: "secret"})
+				return c.String(http.StatusCreated, "created "+string(body))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tc.whenPath, strings.NewReader("Jon"))
+			for k, v := range tc.whenHeader {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, "created Jon", rec.Body.String())
+			if !tc.expectDump {
+				assert.Nil(t, dump)
+				return
+			}
+			if assert.NotNil(t, dump) {
+				assert.Equal(t, "Jon", dump.RequestBody)
+				assert.Equal(t, http.StatusCreated, dump.Status)
+				assert.Equal(t, "created Jon", dump.ResponseBody)
+				assert.Equal(t, "[REDACTED]", dump.ResponseHeader.Get(echo.HeaderSetCookie))
+				if v := dump.RequestHeader.Get("X-Debug-Dump"); v != "" {
+					assert.Equal(t, "[REDACTED]", v)
+				}
+			}
+		})
+	}
+}
+
+func TestDebugDump_truncateAndError(t *testing.T) {
+	var dump *echo.RequestDump
+	e := echo.New()
+	e.Use(RequestID())
+	e.Use(DebugDumpWithConfig(DebugDumpConfig{
+		Activate:    func(c echo.Context) bool { return true },
+		MaxBodySize: 4,
+		Sink: func(c echo.Context, d *echo.RequestDump) {
+			dump = d
+		},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		return errors.New("database unavailable")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	if assert.NotNil(t, dump) {
+		assert.Equal(t, "database unavailable", dump.Error)
+		assert.Equal(t, http.StatusInternalServerError, dump.Status)
+		assert.Equal(t, `{"me`, dump.ResponseBody)
+		assert.True(t, dump.ResponseBodyTruncated)
+		assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), dump.RequestID)
+		assert.NotEmpty(t, dump.RequestID)
+	}
+}
@@ -103,15 +103,16 @@ func DecompressWithConfig(config DecompressConfig) echo.MiddlewareFunc {
 					}
 					return err
 				}
-				var buf bytes.Buffer
-				io.Copy(&buf, gr)
+				buf := c.Echo().AcquireBuffer()
+				defer c.Echo().ReleaseBuffer(buf)
+				io.Copy(buf, gr)
 
 				gr.Close()
 				pool.Put(gr)
 
 				b.Close() // http.Request.Body is closed by the Server, but because we are replacing it, it must be closed here
 
-				r := ioutil.NopCloser(&buf)
+				r := ioutil.NopCloser(buf)
 				c.Request().Body = r
 			}
 			return next(c)
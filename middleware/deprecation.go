@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// DeprecationConfig defines the config for Deprecation middleware.
+	DeprecationConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// CallerExtractor identifies client calling deprecated route (i.e. by API key or client id) so owners of
+		// clients can be contacted to migrate.
+		// Optional. Default value returns real IP and user agent of request.
+		CallerExtractor func(c echo.Context) string
+
+		// OnUse is called for every request of deprecated route with identification of caller.
+		// Optional. Default value logs usage as warning with `Context#Logger`.
+		OnUse func(c echo.Context, d echo.Deprecation, caller string)
+
+		// EnforceSunset responds 410 Gone to requests of deprecated routes after their sunset time.
+		// Optional. Default value false.
+		EnforceSunset bool
+	}
+)
+
+var (
+	// DefaultDeprecationConfig is the default Deprecation middleware config.
+	DefaultDeprecationConfig = DeprecationConfig{
+		Skipper: DefaultSkipper,
+		CallerExtractor: func(c echo.Context) string {
+			return c.RealIP() + " " + c.Request().UserAgent()
+		},
+		OnUse: func(c echo.Context, d echo.Deprecation, caller string) {
+			c.Logger().Warnf("deprecated route %s %s called by %s", c.Request().Method, c.Path(), caller)
+		},
+	}
+)
+
+// Deprecation returns a middleware that sends `Deprecation`, `Sunset` and `Link` headers for routes deprecated
+// with route metadata `echo.RouteMetaDeprecation` and logs usage of deprecated routes with caller identification.
+//
+// Example:
+//
+//	e.Use(middleware.Deprecation())
+//	e.RouteMeta(e.GET("/users", listUsers))[echo.RouteMetaDeprecation] = echo.Deprecation{
+//		Sunset: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Link:   "https://example.com/docs/migrate-users",
+//	}
+func Deprecation() echo.MiddlewareFunc {
+	return DeprecationWithConfig(DefaultDeprecationConfig)
+}
+
+// DeprecationWithConfig returns a Deprecation middleware with config.
+// See: `Deprecation()`.
+func DeprecationWithConfig(config DeprecationConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDeprecationConfig.Skipper
+	}
+	if config.CallerExtractor == nil {
+		config.CallerExtractor = DefaultDeprecationConfig.CallerExtractor
+	}
+	if config.OnUse == nil {
+		config.OnUse = DefaultDeprecationConfig.OnUse
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			r := c.Route()
+			if r == nil {
+				return next(c)
+			}
+			d, ok := c.Echo().RouteMeta(r)[echo.RouteMetaDeprecation].(echo.Deprecation)
+			if !ok {
+				return next(c)
+			}
+
+			d.WriteHeaders(c.Response().Header())
+			config.OnUse(c, d, config.CallerExtractor(c))
+			if config.EnforceSunset && !d.Sunset.IsZero() && !now().Before(d.Sunset) {
+				return echo.NewHTTPError(http.StatusGone, "route was removed at "+d.Sunset.UTC().Format(http.TimeFormat))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2021, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	var testCases = []struct {
+		name              string
+		whenEnforce       bool
+		whenURL           string
+		whenNow           time.Time
+		expectCode        int
+		expectDeprecation string
+		expectSunset      string
+		expectLink        string
+		expectCaller      string
+	}{
+		{
+			name:              "ok, deprecated route",
+			whenURL:           "/users",
+			whenNow:           sunset.Add(-time.Hour),
+			expectCode:        http.StatusOK,
+			expectDeprecation: "@1609459200",
+			expectSunset:      "Fri, 31 Dec 2021 23:59:59 GMT",
+			expectLink:        `<https://example.com/migrate>; rel="deprecation"`,
+			expectCaller:      "192.0.2.1 client/1.0",
+		},
+		{
+			name:              "ok, sunset is not enforced by default",
+			whenURL:           "/users",
+			whenNow:           sunset,
+			expectCode:        http.StatusOK,
+			expectDeprecation: "@1609459200",
+			expectSunset:      "Fri, 31 Dec 2021 23:59:59 GMT",
+			expectLink:        `<https://example.com/migrate>; rel="deprecation"`,
+			expectCaller:      "192.0.2.1 client/1.0",
+		},
+		{
+			name:              "ok, before enforced sunset",
+			whenEnforce:       true,
+			whenURL:           "/users",
+			whenNow:           sunset.Add(-time.Second),
+			expectCode:        http.StatusOK,
+			expectDeprecation: "@1609459200",
+			expectSunset:      "Fri, 31 Dec 2021 23:59:59 GMT",
+			expectLink:        `<https://example.com/migrate>; rel="deprecation"`,
+			expectCaller:      "192.0.2.1 client/1.0",
+		},
+		{
+			name:              "nok, after enforced sunset",
+			whenEnforce:       true,
+			whenURL:           "/users",
+			whenNow:           sunset,
+			expectCode:        http.StatusGone,
+			expectDeprecation: "@1609459200",
+			expectSunset:      "Fri, 31 Dec 2021 23:59:59 GMT",
+			expectLink:        `<https://example.com/migrate>; rel="deprecation"`,
+			expectCaller:      "192.0.2.1 client/1.0",
+		},
+		{
+			name:              "ok, deprecated without dates",
+			whenEnforce:       true,
+			whenURL:           "/legacy",
+			whenNow:           sunset,
+			expectCode:        http.StatusOK,
+			expectDeprecation: "true",
+			expectCaller:      "192.0.2.1 client/1.0",
+		},
+		{
+			name:       "ok, route is not deprecated",
+			whenURL:    "/accounts",
+			whenNow:    sunset,
+			expectCode: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now = func() time.Time { return tc.whenNow }
+			defer func() { now = time.Now }()
+
+			caller := ""
+			e := echo.New()
+			e.Use(DeprecationWithConfig(DeprecationConfig{
+				EnforceSunset: tc.whenEnforce,
+				OnUse: func(c echo.Context, d echo.Deprecation, who string) {
+					caller = who
+				},
+			}))
+			ok := func(c echo.Context) error {
+				return c.String(http.StatusOK, "OK")
+			}
+			e.RouteMeta(e.GET("/users", ok))[echo.RouteMetaDeprecation] = echo.Deprecation{
+				Since:  since,
+				Sunset: sunset,
+				Link:   "https://example.com/migrate",
+			}
+			e.RouteMeta(e.GET("/legacy", ok))[echo.RouteMetaDeprecation] = echo.Deprecation{}
+			e.GET("/accounts", ok)
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			req.Header.Set("User-Agent", "client/1.0")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectDeprecation, rec.Header().Get(echo.HeaderDeprecation))
+			assert.Equal(t, tc.expectSunset, rec.Header().Get(echo.HeaderSunset))
+			assert.Equal(t, tc.expectLink, rec.Header().Get(echo.HeaderLink))
+			assert.Equal(t, tc.expectCaller, caller)
+		})
+	}
+}
+
+func TestDeprecation_logsUsage(t *testing.T) {
+	e := echo.New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.Logger.SetLevel(log.WARN)
+	e.Use(Deprecation())
+	e.RouteMeta(e.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}))[echo.RouteMetaDeprecation] = echo.Deprecation{}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("User-Agent", "client/1.0")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Contains(t, buf.String(), "deprecated route GET /users called by 192.0.2.1 client/1.0")
+}
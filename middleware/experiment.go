@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/random"
+)
+
+type (
+	// ExperimentConfig defines the config for Experiment middleware.
+	ExperimentConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Name is the name of experiment. Handlers get assigned variant with `c.Experiment(name)`.
+		// Required.
+		Name string
+
+		// Variants are the buckets requests are assigned to, i.e. `[]string{"control", "treatment"}`.
+		// Required.
+		Variants []string
+
+		// Weights are relative weights of variants. When not set variants have equal weights.
+		// Optional.
+		Weights []uint32
+
+		// Header is the name of request header (i.e. "X-User-ID") with user identifier assignment is derived from.
+		// Requests without the header get random identifier.
+		// Optional.
+		Header string
+
+		// CookieName is the name of cookie assigned variant is stored in so client stays in the same variant.
+		// Optional. Default value "echo_exp_" + Name.
+		CookieName string
+
+		// CookieMaxAge is the max age (in seconds) of assignment cookie.
+		// Optional. Default value 2592000 (30 days).
+		CookieMaxAge int
+	}
+)
+
+var (
+	// DefaultExperimentConfig is the default Experiment middleware config.
+	DefaultExperimentConfig = ExperimentConfig{
+		Skipper:      DefaultSkipper,
+		CookieMaxAge: int((30 * 24 * time.Hour).Seconds()),
+	}
+)
+
+// Experiment returns an Experiment middleware that assigns requests to variants of experiment with equal weights.
+// Assignment is deterministic for the same user identifier and is stored in cookie and context.
+func Experiment(name string, variants ...string) echo.MiddlewareFunc {
+	c := DefaultExperimentConfig
+	c.Name = name
+	c.Variants = variants
+	return ExperimentWithConfig(c)
+}
+
+// ExperimentWithConfig returns an Experiment middleware with config.
+// See: `Experiment()`.
+func ExperimentWithConfig(config ExperimentConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Name == "" {
+		panic("echo: experiment middleware requires name")
+	}
+	if len(config.Variants) == 0 {
+		panic("echo: experiment middleware requires variants")
+	}
+	if config.Weights != nil && len(config.Weights) != len(config.Variants) {
+		panic("echo: experiment middleware requires weight for each variant")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultExperimentConfig.Skipper
+	}
+	if config.CookieName == "" {
+		config.CookieName = "echo_exp_" + config.Name
+	}
+	if config.CookieMaxAge == 0 {
+		config.CookieMaxAge = DefaultExperimentConfig.CookieMaxAge
+	}
+	weights := config.Weights
+	if weights == nil {
+		weights = make([]uint32, len(config.Variants))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	total := uint32(0)
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		panic("echo: experiment middleware requires at least one non-zero weight")
+	}
+	known := make(map[string]bool, len(config.Variants))
+	for _, v := range config.Variants {
+		known[v] = true
+	}
+
+	assign := func(key string) string {
+		bucket := echo.HashBucket(config.Name+":"+key, total)
+		for i, w := range weights {
+			if bucket < w {
+				return config.Variants[i]
+			}
+			bucket -= w
+		}
+		return config.Variants[len(config.Variants)-1]
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			variant := ""
+			if cookie, err := c.Cookie(config.CookieName); err == nil && known[cookie.Value] {
+				variant = cookie.Value
+			} else {
+				key := ""
+				if config.Header != "" {
+					key = c.Request().Header.Get(config.Header)
+				}
+				if key == "" {
+					key = random.String(32)
+				}
+				variant = assign(key)
+				c.SetCookie(&http.Cookie{
+					Name:     config.CookieName,
+					Value:    variant,
+					Path:     "/",
+					MaxAge:   config.CookieMaxAge,
+					HttpOnly: true,
+				})
+			}
+			c.SetExperiment(config.Name, variant)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperiment(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenCookie      string
+		expectVariant    string
+		expectSetsCookie bool
+	}{
+		{
+			name:          "ok, assignment from cookie",
+			givenCookie:   "b",
+			expectVariant: "b",
+		},
+		{
+			name:             "ok, unknown variant in cookie is reassigned",
+			givenCookie:      "x",
+			expectSetsCookie: true,
+		},
+		{
+			name:             "ok, new assignment",
+			expectSetsCookie: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(Experiment("checkout", "a", "b"))
+			e.GET("/", func(c echo.Context) error {
+				return c.String(http.StatusOK, c.Experiment("checkout"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.givenCookie != "" {
+				req.AddCookie(&http.Cookie{Name: "echo_exp_checkout", Value: tc.givenCookie})
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			variant := rec.Body.String()
+			if tc.expectVariant != "" {
+				assert.Equal(t, tc.expectVariant, variant)
+			} else {
+				assert.Contains(t, []string{"a", "b"}, variant)
+			}
+			if tc.expectSetsCookie {
+				assert.Contains(t, rec.Header().Get(echo.HeaderSetCookie), "echo_exp_checkout="+variant)
+			} else {
+				assert.Empty(t, rec.Header().Get(echo.HeaderSetCookie))
+			}
+		})
+	}
+}
+
+func TestExperiment_DeterministicWeightedHeader(t *testing.T) {
+	e := echo.New()
+	e.Use(ExperimentWithConfig(ExperimentConfig{
+		Name:     "pricing",
+		Variants: []string{"control", "treatment"},
+		Weights:  []uint32{3, 1},
+		Header:   "X-User-ID",
+	}))
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Experiment("pricing"))
+	})
+
+	serve := func(userID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	treatments := 0
+	for i := 0; i < 1000; i++ {
+		id := strconv.Itoa(i)
+		first := serve(id)
+		assert.Equal(t, first, serve(id), "assignment must be deterministic")
+		if first == "treatment" {
+			treatments++
+		}
+	}
+	assert.InDelta(t, 250, treatments, 60)
+}
+
+func TestExperimentWithConfig_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		ExperimentWithConfig(ExperimentConfig{Variants: []string{"a"}})
+	})
+	assert.Panics(t, func() {
+		ExperimentWithConfig(ExperimentConfig{Name: "x"})
+	})
+	assert.Panics(t, func() {
+		ExperimentWithConfig(ExperimentConfig{Name: "x", Variants: []string{"a", "b"}, Weights: []uint32{1}})
+	})
+	assert.Panics(t, func() {
+		ExperimentWithConfig(ExperimentConfig{Name: "x", Variants: []string{"a"}, Weights: []uint32{0}})
+	})
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// ForwardAuthConfig defines the config for ForwardAuth middleware.
+	ForwardAuthConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Address is the URL of the authorization service.
+		// Required.
+		Address string
+
+		// Client is used to send requests to the authorization service.
+		// Optional. Default value is a client with 10s timeout that does not follow redirects.
+		Client *http.Client
+
+		// AuthRequestHeaders lists request headers copied to the authorization request.
+		// Optional. Default value copies all headers.
+		AuthRequestHeaders []string
+
+		// AuthResponseHeaders lists headers copied from a successful authorization response into the
+		// request, i.e. "X-User-ID". Values sent by the client for these headers are always removed, so
+		// handlers can trust them.
+		// Optional.
+		AuthResponseHeaders []string
+
+		// MaxErrorBodySize limits the size of the authorization response body relayed to the client when
+		// the request is rejected.
+		// Optional. Default value 64KB.
+		MaxErrorBodySize int64
+	}
+)
+
+var (
+	// DefaultForwardAuthConfig is the default ForwardAuth middleware config.
+	DefaultForwardAuthConfig = ForwardAuthConfig{
+		Skipper: DefaultSkipper,
+		Client: &http.Client{
+			Timeout: 10 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		MaxErrorBodySize: 64 * 1024,
+	}
+)
+
+// ForwardAuth returns a ForwardAuth middleware.
+//
+// ForwardAuth delegates authorization to an external service. For every request a GET request with the
+// original request headers and `X-Forwarded-Method`, `X-Forwarded-Proto`, `X-Forwarded-Host`,
+// `X-Forwarded-Uri` and `X-Forwarded-For` headers is sent to the `address`. 2xx response allows the
+// request, any other response (status, headers and body) is sent back to the client.
+func ForwardAuth(address string) echo.MiddlewareFunc {
+	c := DefaultForwardAuthConfig
+	c.Address = address
+	return ForwardAuthWithConfig(c)
+}
+
+// ForwardAuthWithConfig returns a ForwardAuth middleware with config.
+// See: `ForwardAuth()`.
+func ForwardAuthWithConfig(config ForwardAuthConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultForwardAuthConfig.Skipper
+	}
+	if config.Address == "" {
+		panic("echo: forward auth middleware requires address")
+	}
+	if config.Client == nil {
+		config.Client = DefaultForwardAuthConfig.Client
+	}
+	if config.MaxErrorBodySize <= 0 {
+		config.MaxErrorBodySize = DefaultForwardAuthConfig.MaxErrorBodySize
+	}
+	authResponseHeaders := make([]string, len(config.AuthResponseHeaders))
+	for i, h := range config.AuthResponseHeaders {
+		authResponseHeaders[i] = textproto.CanonicalMIMEHeaderKey(h)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			authReq, err := http.NewRequest(http.MethodGet, config.Address, nil)
+			if err != nil {
+				return err
+			}
+			authReq = authReq.WithContext(req.Context())
+			if len(config.AuthRequestHeaders) == 0 {
+				for k, v := range req.Header {
+					authReq.Header[k] = v
+				}
+			} else {
+				for _, k := range config.AuthRequestHeaders {
+					k = textproto.CanonicalMIMEHeaderKey(k)
+					if v := req.Header[k]; len(v) > 0 {
+						authReq.Header[k] = v
+					}
+				}
+			}
+			authReq.Header.Set(echo.HeaderXForwardedMethod, req.Method)
+			authReq.Header.Set(echo.HeaderXForwardedProto, c.Scheme())
+			authReq.Header.Set(echo.HeaderXForwardedHost, req.Host)
+			authReq.Header.Set(echo.HeaderXForwardedURI, req.RequestURI)
+			authReq.Header.Set(echo.HeaderXForwardedFor, c.RealIP())
+
+			authRes, err := config.Client.Do(authReq)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "authorization service unavailable").SetInternal(err)
+			}
+			defer authRes.Body.Close()
+
+			if authRes.StatusCode < http.StatusOK || authRes.StatusCode >= http.StatusMultipleChoices {
+				res := c.Response()
+				for k, v := range authRes.Header {
+					switch k {
+					case echo.HeaderContentLength, "Connection", "Transfer-Encoding":
+						continue
+					}
+					res.Header()[k] = v
+				}
+				res.WriteHeader(authRes.StatusCode)
+				_, err := io.Copy(res, io.LimitReader(authRes.Body, config.MaxErrorBodySize))
+				return err
+			}
+
+			for _, k := range authResponseHeaders {
+				req.Header.Del(k)
+				if v := authRes.Header[k]; len(v) > 0 {
+					req.Header[k] = v
+				}
+			}
+			return next(c)
+		}
+	}
+}
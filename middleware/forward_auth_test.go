@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardAuth(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		config           ForwardAuthConfig
+		whenHeaders      map[string]string
+		expectCode       int
+		expectBody       string
+		expectHeader     map[string]string
+		expectUser       string
+		expectAuthHeader map[string]string
+	}{
+		{
+			name:        "ok, allowed request gets headers from auth response",
+			config:      ForwardAuthConfig{AuthResponseHeaders: []string{"x-user-id"}},
+			whenHeaders: map[string]string{echo.HeaderAuthorization: "Bearer valid", "X-User-Id": "spoofed"},
+			expectCode:  http.StatusOK,
+			expectBody:  "user=42",
+			expectAuthHeader: map[string]string{
+				echo.HeaderAuthorization:    "Bearer valid",
+				echo.HeaderXForwardedMethod: http.MethodGet,
+				echo.HeaderXForwardedProto:  "http",
+				echo.HeaderXForwardedHost:   "example.com",
+				echo.HeaderXForwardedURI:    "/api/users?page=2",
+				echo.HeaderXForwardedFor:    "192.0.2.1",
+			},
+		},
+		{
+			name:        "ok, client supplied trusted header is removed",
+			config:      ForwardAuthConfig{AuthResponseHeaders: []string{"X-Role"}},
+			whenHeaders: map[string]string{echo.HeaderAuthorization: "Bearer valid", "X-Role": "admin"},
+			expectCode:  http.StatusOK,
+			expectBody:  "user=spoofed-less",
+		},
+		{
+			name:        "ok, only selected request headers are sent",
+			config:      ForwardAuthConfig{AuthRequestHeaders: []string{"authorization"}},
+			whenHeaders: map[string]string{echo.HeaderAuthorization: "Bearer valid", "X-Other": "x"},
+			expectCode:  http.StatusOK,
+			expectAuthHeader: map[string]string{
+				echo.HeaderAuthorization: "Bearer valid",
+				"X-Other":                "",
+			},
+		},
+		{
+			name:         "nok, auth response is sent to client",
+			whenHeaders:  map[string]string{echo.HeaderAuthorization: "Bearer invalid"},
+			expectCode:   http.StatusUnauthorized,
+			expectBody:   "invalid token",
+			expectHeader: map[string]string{echo.HeaderWWWAuthenticate: `Bearer realm="api"`},
+		},
+		{
+			name:         "nok, redirect is not followed",
+			expectCode:   http.StatusFound,
+			expectHeader: map[string]string{echo.HeaderLocation: "https://login.example.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var authHeader http.Header
+			authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authHeader = r.Header
+				switch r.Header.Get(echo.HeaderAuthorization) {
+				case "Bearer valid":
+					w.Header().Set("X-User-Id", "42")
+					w.WriteHeader(http.StatusOK)
+				case "Bearer invalid":
+					w.Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte("invalid token"))
+				default:
+					http.Redirect(w, r, "https://login.example.com", http.StatusFound)
+				}
+			}))
+			defer authServer.Close()
+
+			config := tc.config
+			config.Address = authServer.URL
+			e := echo.New()
+			e.Use(ForwardAuthWithConfig(config))
+			e.GET("/api/users", func(c echo.Context) error {
+				if c.Request().Header.Get("X-Role") != "" {
+					return c.String(http.StatusOK, "user=spoofed")
+				}
+				if id := c.Request().Header.Get("X-User-Id"); id != "" && id != "spoofed" {
+					return c.String(http.StatusOK, "user="+id)
+				}
+				return c.String(http.StatusOK, "user=spoofed-less")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/users?page=2", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectBody != "" {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+			for k, v := range tc.expectHeader {
+				assert.Equal(t, v, rec.Header().Get(k))
+			}
+			for k, v := range tc.expectAuthHeader {
+				assert.Equal(t, v, authHeader.Get(k))
+			}
+		})
+	}
+}
+
+func TestForwardAuth_serviceUnavailable(t *testing.T) {
+	e := echo.New()
+	e.Use(ForwardAuth("http://127.0.0.1:1"))
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestForwardAuth_panicsWithoutAddress(t *testing.T) {
+	assert.Panics(t, func() {
+		ForwardAuthWithConfig(ForwardAuthConfig{})
+	})
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// GeoInfo is geographical and network information of client IP resolved by GeoIP middleware.
+	GeoInfo struct {
+		// IP is the resolved client IP.
+		IP string
+		// Country is ISO 3166-1 alpha-2 country code (i.e. "EE"). Empty when unknown.
+		Country string
+		// ASN is autonomous system number. Zero when unknown.
+		ASN uint
+		// Organization is autonomous system organization. Empty when unknown.
+		Organization string
+	}
+
+	// GeoResolver resolves geographical information of IP address.
+	GeoResolver interface {
+		Resolve(ip net.IP) (*GeoInfo, error)
+	}
+
+	// GeoResolverFunc is an adapter to use function as GeoResolver.
+	GeoResolverFunc func(ip net.IP) (*GeoInfo, error)
+
+	// MaxMindReader is database reader of MaxMind GeoIP2/GeoLite2 databases. It is implemented by `*maxminddb.Reader`
+	// from "github.com/oschwald/maxminddb-golang".
+	MaxMindReader interface {
+		Lookup(ip net.IP, result interface{}) error
+	}
+
+	// GeoIPConfig defines the config for GeoIP middleware.
+	GeoIPConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Resolver resolves client IP to geographical information.
+		// Required.
+		Resolver GeoResolver
+
+		// IPExtractor extracts client IP from request. Configure it (or `Echo#IPExtractor`) with trusted proxies
+		// when Echo is behind reverse proxy.
+		// Optional. Default value uses `echo.Context#RealIP` when only resolving information. When `BlockCountries`
+		// or `AllowCountries` is set default value uses `Echo#IPExtractor` and when it is not set address of direct
+		// peer (`echo.ExtractIPDirect`) as proxy headers can be spoofed to bypass blocking.
+		IPExtractor echo.IPExtractor
+
+		// ContextKey is the key used to store resolved information (`*GeoInfo`) in context.
+		// Optional. Default value "geo".
+		ContextKey string
+
+		// BlockCountries are country codes (ISO 3166-1 alpha-2) that are denied with 403 Forbidden.
+		// Optional.
+		BlockCountries []string
+
+		// AllowCountries are country codes (ISO 3166-1 alpha-2) that are allowed. When set, requests from other
+		// countries (including requests with unknown country) are denied with 403 Forbidden.
+		// Optional.
+		AllowCountries []string
+	}
+
+	maxMindResolver struct {
+		country MaxMindReader
+		asn     MaxMindReader
+	}
+
+	// maxMindRecord contains fields of MaxMind Country and ASN database records.
+	maxMindRecord struct {
+		Country struct {
+			ISOCode string `maxminddb:"iso_code"`
+		} `maxminddb:"country"`
+		ASN          uint   `maxminddb:"autonomous_system_number"`
+		Organization string `maxminddb:"autonomous_system_organization"`
+	}
+)
+
+var (
+	// DefaultGeoIPConfig is the default GeoIP middleware config.
+	DefaultGeoIPConfig = GeoIPConfig{
+		Skipper:    DefaultSkipper,
+		ContextKey: "geo",
+	}
+)
+
+// geoContextKey is the context key `GeoFromContext` uses regardless of configured key.
+const geoContextKey = "echo.geo"
+
+// Resolve calls f(ip).
+func (f GeoResolverFunc) Resolve(ip net.IP) (*GeoInfo, error) {
+	return f(ip)
+}
+
+// GeoIP returns a GeoIP middleware that resolves client IP to country and ASN with resolver and stores result in
+// context. Resolved information is available with `GeoFromContext`.
+//
+// Example:
+//
+//	db, err := maxminddb.Open("GeoLite2-Country.mmdb")
+//	...
+//	e.Use(middleware.GeoIP(middleware.NewMaxMindGeoResolver(db, nil)))
+func GeoIP(resolver GeoResolver) echo.MiddlewareFunc {
+	c := DefaultGeoIPConfig
+	c.Resolver = resolver
+	return GeoIPWithConfig(c)
+}
+
+// GeoIPWithConfig returns a GeoIP middleware with config.
+// See: `GeoIP()`.
+func GeoIPWithConfig(config GeoIPConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Resolver == nil {
+		panic("echo: geoip middleware requires resolver")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultGeoIPConfig.Skipper
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultGeoIPConfig.ContextKey
+	}
+	block := countrySet(config.BlockCountries)
+	allow := countrySet(config.AllowCountries)
+	extractIP := func(c echo.Context) string {
+		if config.IPExtractor == nil && len(block) == 0 && len(allow) == 0 {
+			return c.RealIP()
+		}
+		return trustedIP(c, config.IPExtractor)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			ip := extractIP(c)
+			info := &GeoInfo{IP: ip}
+			if parsed := net.ParseIP(ip); parsed != nil {
+				resolved, err := config.Resolver.Resolve(parsed)
+				if err != nil {
+					return err
+				}
+				if resolved != nil {
+					info = resolved
+					info.IP = ip
+				}
+			}
+			c.Set(config.ContextKey, info)
+			c.Set(geoContextKey, info)
+
+			country := strings.ToUpper(info.Country)
+			if _, ok := block[country]; ok && country != "" {
+				return echo.ErrForbidden
+			}
+			if _, ok := allow[country]; len(allow) > 0 && !ok {
+				return echo.ErrForbidden
+			}
+			return next(c)
+		}
+	}
+}
+
+// GeoFromContext returns information resolved by GeoIP middleware or nil.
+func GeoFromContext(c echo.Context) *GeoInfo {
+	g, _ := c.Get(geoContextKey).(*GeoInfo)
+	return g
+}
+
+// NewMaxMindGeoResolver returns GeoResolver using MaxMind Country (or City) database for country and ASN database
+// for autonomous system. Either reader can be nil.
+func NewMaxMindGeoResolver(country MaxMindReader, asn MaxMindReader) GeoResolver {
+	return &maxMindResolver{country: country, asn: asn}
+}
+
+func (r *maxMindResolver) Resolve(ip net.IP) (*GeoInfo, error) {
+	info := &GeoInfo{}
+	if r.country != nil {
+		var record maxMindRecord
+		if err := r.country.Lookup(ip, &record); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		info.Country = record.Country.ISOCode
+	}
+	if r.asn != nil {
+		var record maxMindRecord
+		if err := r.asn.Lookup(ip, &record); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		info.ASN = record.ASN
+		info.Organization = record.Organization
+	}
+	return info, nil
+}
+
+func countrySet(countries []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(countries))
+	for _, c := range countries {
+		set[strings.ToUpper(c)] = struct{}{}
+	}
+	return set
+}
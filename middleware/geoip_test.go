@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMaxMindReader struct {
+	country string
+	asn     uint
+	org     string
+	err     error
+}
+
+func (r fakeMaxMindReader) Lookup(ip net.IP, result interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	record := result.(*maxMindRecord)
+	record.Country.ISOCode = r.country
+	record.ASN = r.asn
+	record.Organization = r.org
+	return nil
+}
+
+func TestGeoIPWithConfig(t *testing.T) {
+	countries := GeoResolverFunc(func(ip net.IP) (*GeoInfo, error) {
+		switch ip.String() {
+		case "192.0.2.1":
+			return &GeoInfo{Country: "EE"}, nil
+		case "192.0.2.2":
+			return &GeoInfo{Country: "ru"}, nil
+		case "192.0.2.3":
+			return nil, errors.New("lookup failed")
+		}
+		return nil, nil
+	})
+
+	var testCases = []struct {
+		name        string
+		givenConfig GeoIPConfig
+		whenIP      string
+		whenXFF     string
+		expectGeo   *GeoInfo
+		expectErr   string
+	}{
+		{
+			name:        "ok, resolved",
+			givenConfig: GeoIPConfig{},
+			whenIP:      "192.0.2.1",
+			expectGeo:   &GeoInfo{IP: "192.0.2.1", Country: "EE"},
+		},
+		{
+			name:        "ok, unknown IP",
+			givenConfig: GeoIPConfig{},
+			whenIP:      "198.51.100.1",
+			expectGeo:   &GeoInfo{IP: "198.51.100.1"},
+		},
+		{
+			name:        "nok, blocked country",
+			givenConfig: GeoIPConfig{BlockCountries: []string{"RU"}},
+			whenIP:      "192.0.2.2",
+			expectGeo:   &GeoInfo{IP: "192.0.2.2", Country: "ru"},
+			expectErr:   "code=403, message=Forbidden",
+		},
+		{
+			name:        "nok, proxy header is not trusted when blocking",
+			givenConfig: GeoIPConfig{BlockCountries: []string{"RU"}},
+			whenIP:      "192.0.2.2",
+			whenXFF:     "192.0.2.1",
+			expectGeo:   &GeoInfo{IP: "192.0.2.2", Country: "ru"},
+			expectErr:   "code=403, message=Forbidden",
+		},
+		{
+			name:        "ok, proxy header is used when only resolving",
+			givenConfig: GeoIPConfig{},
+			whenIP:      "192.0.2.2",
+			whenXFF:     "192.0.2.1",
+			expectGeo:   &GeoInfo{IP: "192.0.2.1", Country: "EE"},
+		},
+		{
+			name:        "ok, allowed country",
+			givenConfig: GeoIPConfig{AllowCountries: []string{"ee", "fi"}},
+			whenIP:      "192.0.2.1",
+			expectGeo:   &GeoInfo{IP: "192.0.2.1", Country: "EE"},
+		},
+		{
+			name:        "nok, unknown country is not allowed",
+			givenConfig: GeoIPConfig{AllowCountries: []string{"EE"}},
+			whenIP:      "198.51.100.1",
+			expectGeo:   &GeoInfo{IP: "198.51.100.1"},
+			expectErr:   "code=403, message=Forbidden",
+		},
+		{
+			name:        "nok, resolver error",
+			givenConfig: GeoIPConfig{},
+			whenIP:      "192.0.2.3",
+			expectErr:   "lookup failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.whenIP + ":1234"
+			if tc.whenXFF != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.whenXFF)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			config := tc.givenConfig
+			config.Resolver = countries
+			err := GeoIPWithConfig(config)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectGeo, GeoFromContext(c))
+			if tc.expectGeo != nil {
+				assert.Equal(t, tc.expectGeo, c.Get("geo"))
+			}
+		})
+	}
+}
+
+func TestGeoIP_panicsWithoutResolver(t *testing.T) {
+	assert.Panics(t, func() {
+		GeoIP(nil)
+	})
+}
+
+func TestNewMaxMindGeoResolver(t *testing.T) {
+	ip := net.ParseIP("192.0.2.1")
+
+	r := NewMaxMindGeoResolver(fakeMaxMindReader{country: "EE"}, fakeMaxMindReader{asn: 64496, org: "Example"})
+	info, err := r.Resolve(ip)
+	assert.NoError(t, err)
+	assert.Equal(t, &GeoInfo{Country: "EE", ASN: 64496, Organization: "Example"}, info)
+
+	r = NewMaxMindGeoResolver(nil, fakeMaxMindReader{asn: 64496})
+	info, err = r.Resolve(ip)
+	assert.NoError(t, err)
+	assert.Equal(t, &GeoInfo{ASN: 64496}, info)
+
+	r = NewMaxMindGeoResolver(fakeMaxMindReader{err: errors.New("corrupt db")}, nil)
+	_, err = r.Resolve(ip)
+	assert.EqualError(t, err, "code=500, message=Internal Server Error, internal=corrupt db")
+}
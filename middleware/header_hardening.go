@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// HeaderHardeningConfig defines the config for HeaderHardening middleware.
+	HeaderHardeningConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// MaxHeaders is maximum number of header values request can have. Requests with more are rejected with
+		// 431 Request Header Fields Too Large.
+		// Optional. Default value 100.
+		MaxHeaders int
+
+		// AllowUnderscores allows underscores in header names. Proxies treat `X_Forwarded_For` differently (some
+		// drop it, some treat it as `X-Forwarded-For`) so such headers are rejected by default.
+		// Optional. Default value false.
+		AllowUnderscores bool
+
+		// OnReject is called for every rejected request with reason of rejection (one of `HeaderHardeningReason*`
+		// constants). Use it to count rejections in metrics.
+		// Optional.
+		OnReject func(c echo.Context, reason string)
+	}
+)
+
+// Reasons of requests rejected by HeaderHardening middleware.
+const (
+	HeaderHardeningReasonConflictingLength = "conflicting_length"
+	HeaderHardeningReasonInvalidName       = "invalid_header_name"
+	HeaderHardeningReasonInvalidValue      = "invalid_header_value"
+	HeaderHardeningReasonTooManyHeaders    = "too_many_headers"
+)
+
+var (
+	// DefaultHeaderHardeningConfig is the default HeaderHardening middleware config.
+	DefaultHeaderHardeningConfig = HeaderHardeningConfig{
+		Skipper:    DefaultSkipper,
+		MaxHeaders: 100,
+	}
+)
+
+// HeaderHardening returns a middleware that rejects requests with headers that different servers and proxies can
+// interpret differently, as defense-in-depth against request smuggling when Echo is behind proxies. Rejected are
+// requests with both Content-Length and Transfer-Encoding or conflicting Content-Length values, with header names
+// that are not valid tokens or contain underscores, with control characters in header values and with too many
+// headers. Rejected requests receive 400 Bad Request (431 for too many headers).
+func HeaderHardening() echo.MiddlewareFunc {
+	return HeaderHardeningWithConfig(DefaultHeaderHardeningConfig)
+}
+
+// HeaderHardeningWithConfig returns a HeaderHardening middleware with config.
+// See: `HeaderHardening()`.
+func HeaderHardeningWithConfig(config HeaderHardeningConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultHeaderHardeningConfig.Skipper
+	}
+	if config.MaxHeaders == 0 {
+		config.MaxHeaders = DefaultHeaderHardeningConfig.MaxHeaders
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			reason := checkHeaders(c.Request(), config)
+			if reason == "" {
+				return next(c)
+			}
+			if config.OnReject != nil {
+				config.OnReject(c, reason)
+			}
+			if reason == HeaderHardeningReasonTooManyHeaders {
+				return echo.NewHTTPError(http.StatusRequestHeaderFieldsTooLarge)
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request headers")
+		}
+	}
+}
+
+func checkHeaders(r *http.Request, config HeaderHardeningConfig) string {
+	count := 0
+	for name, values := range r.Header {
+		count += len(values)
+		if !isValidHeaderName(name, config.AllowUnderscores) {
+			return HeaderHardeningReasonInvalidName
+		}
+		for _, v := range values {
+			if !isValidHeaderValue(v) {
+				return HeaderHardeningReasonInvalidValue
+			}
+		}
+	}
+	if count > config.MaxHeaders {
+		return HeaderHardeningReasonTooManyHeaders
+	}
+
+	// net/http moves Transfer-Encoding header to `Request.TransferEncoding`
+	lengths := r.Header[echo.HeaderContentLength]
+	if len(lengths) > 0 && (len(r.TransferEncoding) > 0 || r.Header.Get("Transfer-Encoding") != "") {
+		return HeaderHardeningReasonConflictingLength
+	}
+	for i := 1; i < len(lengths); i++ {
+		if lengths[i] != lengths[0] {
+			return HeaderHardeningReasonConflictingLength
+		}
+	}
+	return ""
+}
+
+// isValidHeaderName checks that name is token as defined in RFC 9110 5.6.2.
+func isValidHeaderName(name string, allowUnderscores bool) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		b := name[i]
+		switch {
+		case b == '_':
+			if !allowUnderscores {
+				return false
+			}
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		case b < 0x80 && strings.IndexByte("!#$%&'*+-.^`|~", b) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isValidHeaderValue checks that value has no control characters other than horizontal tab.
+func isValidHeaderValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if b := value[i]; (b < ' ' && b != '\t') || b == 0x7f {
+			return false
+		}
+	}
+	return true
+}
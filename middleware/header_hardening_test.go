@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderHardeningWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenConfig      HeaderHardeningConfig
+		whenHeaders      http.Header
+		whenTransferEnc  []string
+		expectErr        string
+		expectRejectedBy string
+	}{
+		{
+			name:        "ok",
+			whenHeaders: http.Header{"Content-Length": {"5"}, "X-Request-Id": {"abc"}},
+		},
+		{
+			name:             "nok, content length with transfer encoding",
+			whenHeaders:      http.Header{"Content-Length": {"5"}},
+			whenTransferEnc:  []string{"chunked"},
+			expectErr:        "code=400, message=invalid request headers",
+			expectRejectedBy: HeaderHardeningReasonConflictingLength,
+		},
+		{
+			name:             "nok, conflicting content lengths",
+			whenHeaders:      http.Header{"Content-Length": {"5", "6"}},
+			expectErr:        "code=400, message=invalid request headers",
+			expectRejectedBy: HeaderHardeningReasonConflictingLength,
+		},
+		{
+			name:             "nok, underscore in header name",
+			whenHeaders:      http.Header{"X_forwarded_for": {"127.0.0.1"}},
+			expectErr:        "code=400, message=invalid request headers",
+			expectRejectedBy: HeaderHardeningReasonInvalidName,
+		},
+		{
+			name:        "ok, underscore in header name allowed",
+			givenConfig: HeaderHardeningConfig{AllowUnderscores: true},
+			whenHeaders: http.Header{"X_forwarded_for": {"127.0.0.1"}},
+		},
+		{
+			name:             "nok, space in header name",
+			whenHeaders:      http.Header{"X-Forwarded-For ": {"127.0.0.1"}},
+			expectErr:        "code=400, message=invalid request headers",
+			expectRejectedBy: HeaderHardeningReasonInvalidName,
+		},
+		{
+			name:             "nok, control character in header value",
+			whenHeaders:      http.Header{"X-Tenant": {"a\nTransfer-Encoding: chunked"}},
+			expectErr:        "code=400, message=invalid request headers",
+			expectRejectedBy: HeaderHardeningReasonInvalidValue,
+		},
+		{
+			name:             "nok, too many headers",
+			givenConfig:      HeaderHardeningConfig{MaxHeaders: 2},
+			whenHeaders:      http.Header{"X-A": {"1", "2"}, "X-B": {"3"}},
+			expectErr:        "code=431, message=Request Header Fields Too Large",
+			expectRejectedBy: HeaderHardeningReasonTooManyHeaders,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header = tc.whenHeaders
+			req.TransferEncoding = tc.whenTransferEnc
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			rejectedBy := ""
+			config := tc.givenConfig
+			config.OnReject = func(c echo.Context, reason string) {
+				rejectedBy = reason
+			}
+			err := HeaderHardeningWithConfig(config)(func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "ok", rec.Body.String())
+			}
+			assert.Equal(t, tc.expectRejectedBy, rejectedBy)
+		})
+	}
+}
+
+func TestHeaderHardening_defaultMaxHeaders(t *testing.T) {
+	e := echo.New()
+	e.Use(HeaderHardening())
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for i := 0; i < 101; i++ {
+		req.Header.Set(fmt.Sprintf("X-Header-%d", i), "v")
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, rec.Code)
+}
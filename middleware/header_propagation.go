@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// HeaderPropagationConfig defines the config for HeaderPropagation middleware.
+	HeaderPropagationConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Rules define which request headers are propagated.
+		// Required.
+		Rules []HeaderPropagationRule
+	}
+
+	// HeaderPropagationRule defines how single request header is stored in context and mirrored to response.
+	HeaderPropagationRule struct {
+		// Header is name of the request header. Name is canonicalized (i.e. `x-tenant-id` is `X-Tenant-Id`).
+		// Required.
+		Header string
+
+		// ContextKey is the key value is stored under in context (see `echo.Context#Get`).
+		// Optional. Default value is canonical header name.
+		ContextKey string
+
+		// ResponseHeader is the name of the response header value is mirrored to.
+		// Optional. Default value "" (value is not mirrored).
+		ResponseHeader string
+
+		// Default is value used when request does not have the header.
+		// Optional. Default value "" (nothing is stored for missing header).
+		Default string
+
+		// Transform modifies value before it is stored (i.e. `strings.ToLower`). Empty result is treated as missing
+		// header.
+		// Optional. By default surrounding whitespace is trimmed.
+		Transform func(value string) string
+	}
+)
+
+var (
+	// DefaultHeaderPropagationConfig is the default HeaderPropagation middleware config.
+	DefaultHeaderPropagationConfig = HeaderPropagationConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// HeaderPropagation returns a middleware that stores given request headers in context under their canonical names
+// and mirrors them to response with the same name.
+func HeaderPropagation(headers ...string) echo.MiddlewareFunc {
+	c := DefaultHeaderPropagationConfig
+	for _, h := range headers {
+		c.Rules = append(c.Rules, HeaderPropagationRule{Header: h, ResponseHeader: h})
+	}
+	return HeaderPropagationWithConfig(c)
+}
+
+// HeaderPropagationWithConfig returns a HeaderPropagation middleware with config.
+// See: `HeaderPropagation()`.
+func HeaderPropagationWithConfig(config HeaderPropagationConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultHeaderPropagationConfig.Skipper
+	}
+	if len(config.Rules) == 0 {
+		panic("echo: header propagation middleware requires rules")
+	}
+	rules := make([]HeaderPropagationRule, len(config.Rules))
+	for i, r := range config.Rules {
+		if r.Header == "" {
+			panic("echo: header propagation rule requires header name")
+		}
+		r.Header = http.CanonicalHeaderKey(r.Header)
+		if r.ContextKey == "" {
+			r.ContextKey = r.Header
+		}
+		if r.ResponseHeader != "" {
+			r.ResponseHeader = http.CanonicalHeaderKey(r.ResponseHeader)
+		}
+		if r.Transform == nil {
+			r.Transform = strings.TrimSpace
+		}
+		rules[i] = r
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			for _, r := range rules {
+				v := r.Transform(c.Request().Header.Get(r.Header))
+				if v == "" {
+					v = r.Default
+				}
+				if v == "" {
+					continue
+				}
+				c.Set(r.ContextKey, v)
+				if r.ResponseHeader != "" {
+					c.Response().Header().Set(r.ResponseHeader, v)
+				}
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderPropagation(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-Id", "acme")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var stored interface{}
+	h := HeaderPropagation("x-tenant-id", "X-Correlation-ID")(func(c echo.Context) error {
+		stored = c.Get("X-Tenant-Id")
+		return c.NoContent(http.StatusOK)
+	})
+
+	assert.NoError(t, h(c))
+	assert.Equal(t, "acme", stored)
+	assert.Equal(t, "acme", rec.Header().Get("X-Tenant-Id"))
+	assert.Equal(t, "", rec.Header().Get("X-Correlation-Id"))
+}
+
+func TestHeaderPropagationWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenHeaders   map[string]string
+		rule           HeaderPropagationRule
+		expectContext  interface{}
+		expectResponse string
+	}{
+		{
+			name:          "ok, renamed context key without mirroring",
+			givenHeaders:  map[string]string{"Accept-Language": " et "},
+			rule:          HeaderPropagationRule{Header: "accept-language", ContextKey: "locale"},
+			expectContext: "et",
+		},
+		{
+			name:         "ok, renamed response header with transform",
+			givenHeaders: map[string]string{"X-Tenant": "ACME"},
+			rule: HeaderPropagationRule{
+				Header:         "X-Tenant",
+				ContextKey:     "tenant",
+				ResponseHeader: "x-tenant-id",
+				Transform:      strings.ToLower,
+			},
+			expectContext:  "acme",
+			expectResponse: "acme",
+		},
+		{
+			name:           "ok, default value for missing header",
+			rule:           HeaderPropagationRule{Header: "Accept-Language", ContextKey: "locale", ResponseHeader: "Content-Language", Default: "en"},
+			expectContext:  "en",
+			expectResponse: "en",
+		},
+		{
+			name:          "ok, transform drops value",
+			givenHeaders:  map[string]string{"X-Tenant": "invalid"},
+			rule:          HeaderPropagationRule{Header: "X-Tenant", ContextKey: "tenant", Transform: func(string) string { return "" }},
+			expectContext: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.givenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			mw := HeaderPropagationWithConfig(HeaderPropagationConfig{Rules: []HeaderPropagationRule{tc.rule}})
+			err := mw(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectContext, c.Get(tc.rule.ContextKey))
+			if tc.rule.ResponseHeader != "" {
+				assert.Equal(t, tc.expectResponse, rec.Header().Get(tc.rule.ResponseHeader))
+			}
+		})
+	}
+}
+
+func TestHeaderPropagationWithConfig_panics(t *testing.T) {
+	assert.Panics(t, func() {
+		HeaderPropagationWithConfig(HeaderPropagationConfig{})
+	})
+	assert.Panics(t, func() {
+		HeaderPropagationWithConfig(HeaderPropagationConfig{Rules: []HeaderPropagationRule{{ContextKey: "x"}}})
+	})
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// HoneypotConfig defines the config for Honeypot middleware.
+	HoneypotConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Paths are decoy paths (`path.Match` patterns, i.e. "/wp-admin/*") that legitimate clients never request.
+		// Optional. Default value DefaultHoneypotPaths.
+		Paths []string
+
+		// IdentifierExtractor extracts identifier of client.
+		// Optional. Default value uses `Echo#IPExtractor` and when it is not set address of direct peer
+		// (`echo.ExtractIPDirect`). Proxy headers are never trusted by default so clients can not get other
+		// addresses blocked by spoofing them.
+		IdentifierExtractor Extractor
+
+		// Delay is tarpit delay for the first hit of decoy path. Delay is doubled for every following hit up to
+		// MaxDelay. Decoy paths are responded with 404 Not Found after delay.
+		// Optional. Default value 1 second.
+		Delay time.Duration
+
+		// MaxDelay is maximum tarpit delay.
+		// Optional. Default value 30 seconds.
+		MaxDelay time.Duration
+
+		// BlockAfter is the number of decoy path hits after which all requests of client are denied with
+		// 403 Forbidden.
+		// Optional. Default value 0 (clients are not blocked).
+		BlockAfter int
+
+		// Store limits requests of clients that have hit decoy path (i.e. `NewRateLimiterMemoryStore(0.5)`).
+		// Requests denied by store are responded with 429 Too Many Requests.
+		// Optional. Default value nil (requests are not limited).
+		Store RateLimiterStore
+
+		// ExpiresIn is duration after last decoy path hit after which client is no longer treated as offender.
+		// Optional. Default value 1 hour.
+		ExpiresIn time.Duration
+
+		// MaxOffenders is maximum number of tracked offenders. When limit is reached the offender seen least recently
+		// is forgotten.
+		// Optional. Default value 10000.
+		MaxOffenders int
+
+		// OnHit is called when decoy path is requested (i.e. for logging or feeding firewall).
+		// Optional.
+		OnHit func(c echo.Context, identifier string, hits int)
+	}
+
+	honeypotOffender struct {
+		hits     int
+		lastSeen time.Time
+	}
+)
+
+var (
+	// DefaultHoneypotPaths are paths commonly probed by vulnerability scanners.
+	DefaultHoneypotPaths = []string{
+		"/wp-login.php",
+		"/wp-admin",
+		"/wp-admin/*",
+		"/xmlrpc.php",
+		"/.env",
+		"/.git/*",
+		"/phpmyadmin",
+		"/phpmyadmin/*",
+		"/admin.php",
+		"/config.php",
+	}
+
+	// DefaultHoneypotConfig is the default Honeypot middleware config.
+	DefaultHoneypotConfig = HoneypotConfig{
+		Skipper:  DefaultSkipper,
+		Paths:    DefaultHoneypotPaths,
+		Delay:    time.Second,
+		MaxDelay: 30 * time.Second,
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return trustedIP(c, nil), nil
+		},
+		ExpiresIn:    time.Hour,
+		MaxOffenders: 10000,
+	}
+)
+
+// Honeypot returns a Honeypot middleware that tarpits requests to decoy paths probed by vulnerability scanners with
+// escalating delay. Add it with `Echo#Pre` so decoy paths are matched before routing.
+func Honeypot() echo.MiddlewareFunc {
+	return HoneypotWithConfig(DefaultHoneypotConfig)
+}
+
+// HoneypotWithConfig returns a Honeypot middleware with config.
+// See: `Honeypot()`.
+func HoneypotWithConfig(config HoneypotConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultHoneypotConfig.Skipper
+	}
+	if len(config.Paths) == 0 {
+		config.Paths = DefaultHoneypotConfig.Paths
+	}
+	for _, p := range config.Paths {
+		if _, err := path.Match(p, ""); err != nil {
+			panic("echo: invalid honeypot path pattern: " + p)
+		}
+	}
+	if config.IdentifierExtractor == nil {
+		config.IdentifierExtractor = DefaultHoneypotConfig.IdentifierExtractor
+	}
+	if config.Delay == 0 {
+		config.Delay = DefaultHoneypotConfig.Delay
+	}
+	if config.MaxDelay == 0 {
+		config.MaxDelay = DefaultHoneypotConfig.MaxDelay
+	}
+	if config.ExpiresIn == 0 {
+		config.ExpiresIn = DefaultHoneypotConfig.ExpiresIn
+	}
+	if config.MaxOffenders == 0 {
+		config.MaxOffenders = DefaultHoneypotConfig.MaxOffenders
+	}
+
+	var mu sync.Mutex
+	offenders := map[string]*honeypotOffender{}
+	lastCleanup := now()
+	// record returns number of decoy path hits of client and records new hit when hit is true.
+	record := func(id string, hit bool) int {
+		mu.Lock()
+		defer mu.Unlock()
+		t := now()
+		if t.Sub(lastCleanup) > config.ExpiresIn {
+			for k, o := range offenders {
+				if t.Sub(o.lastSeen) > config.ExpiresIn {
+					delete(offenders, k)
+				}
+			}
+			lastCleanup = t
+		}
+		o, ok := offenders[id]
+		if ok && t.Sub(o.lastSeen) > config.ExpiresIn {
+			delete(offenders, id)
+			o, ok = nil, false
+		}
+		if !hit {
+			if !ok {
+				return 0
+			}
+			return o.hits
+		}
+		if !ok {
+			if len(offenders) >= config.MaxOffenders {
+				evictHoneypotOffender(offenders)
+			}
+			o = &honeypotOffender{}
+			offenders[id] = o
+		}
+		o.hits++
+		o.lastSeen = t
+		return o.hits
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			id, err := config.IdentifierExtractor(c)
+			if err != nil {
+				return next(c)
+			}
+
+			if !isHoneypotPath(config.Paths, c.Request().URL.Path) {
+				hits := record(id, false)
+				if hits == 0 {
+					return next(c)
+				}
+				if config.BlockAfter > 0 && hits >= config.BlockAfter {
+					return echo.ErrForbidden
+				}
+				if config.Store != nil {
+					if allow, err := config.Store.Allow(id); err != nil || !allow {
+						return ErrRateLimitExceeded
+					}
+				}
+				return next(c)
+			}
+
+			hits := record(id, true)
+			if config.OnHit != nil {
+				config.OnHit(c, id, hits)
+			}
+			if config.BlockAfter > 0 && hits > config.BlockAfter {
+				return echo.ErrForbidden
+			}
+			timer := time.NewTimer(tarpitDelay(config.Delay, config.MaxDelay, hits))
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-c.Request().Context().Done():
+			}
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+	}
+}
+
+// evictHoneypotOffender removes offender seen least recently.
+func evictHoneypotOffender(offenders map[string]*honeypotOffender) {
+	var oldest string
+	var oldestSeen time.Time
+	for k, o := range offenders {
+		if oldestSeen.IsZero() || o.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = k, o.lastSeen
+		}
+	}
+	delete(offenders, oldest)
+}
+
+// tarpitDelay returns delay doubled for every hit after the first one, capped to max.
+func tarpitDelay(delay, max time.Duration, hits int) time.Duration {
+	for i := 1; i < hits && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+func isHoneypotPath(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRateLimiterStore struct {
+	allow bool
+	calls int
+}
+
+func (s *countingRateLimiterStore) Allow(identifier string) (bool, error) {
+	s.calls++
+	return s.allow, nil
+}
+
+func serveHoneypot(e *echo.Echo, ip string, path string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func newHoneypotTestEcho(config HoneypotConfig) *echo.Echo {
+	e := echo.New()
+	e.Pre(HoneypotWithConfig(config))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	return e
+}
+
+func TestHoneypot(t *testing.T) {
+	var hits []int
+	store := &countingRateLimiterStore{allow: true}
+	e := newHoneypotTestEcho(HoneypotConfig{
+		Delay:    time.Millisecond,
+		MaxDelay: 2 * time.Millisecond,
+		Store:    store,
+		OnHit: func(c echo.Context, identifier string, h int) {
+			assert.Equal(t, "192.0.2.1", identifier)
+			hits = append(hits, h)
+		},
+	})
+
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.1", "/"))
+	assert.Equal(t, 0, store.calls) // not an offender yet
+
+	assert.Equal(t, http.StatusNotFound, serveHoneypot(e, "192.0.2.1", "/wp-login.php"))
+	assert.Equal(t, http.StatusNotFound, serveHoneypot(e, "192.0.2.1", "/wp-admin/install.php"))
+	assert.Equal(t, []int{1, 2}, hits)
+
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.1", "/"))
+	assert.Equal(t, 1, store.calls)
+
+	store.allow = false
+	assert.Equal(t, http.StatusTooManyRequests, serveHoneypot(e, "192.0.2.1", "/"))
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.2", "/")) // other clients are not affected
+}
+
+func TestHoneypot_BlockAfter(t *testing.T) {
+	e := newHoneypotTestEcho(HoneypotConfig{
+		Paths:      []string{"/.env"},
+		Delay:      time.Millisecond,
+		BlockAfter: 2,
+	})
+
+	assert.Equal(t, http.StatusNotFound, serveHoneypot(e, "192.0.2.1", "/.env"))
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.1", "/"))
+	assert.Equal(t, http.StatusNotFound, serveHoneypot(e, "192.0.2.1", "/.env"))
+	assert.Equal(t, http.StatusForbidden, serveHoneypot(e, "192.0.2.1", "/"))
+	assert.Equal(t, http.StatusForbidden, serveHoneypot(e, "192.0.2.1", "/.env"))
+}
+
+func TestHoneypot_offenderExpires(t *testing.T) {
+	current := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	e := newHoneypotTestEcho(HoneypotConfig{Delay: time.Millisecond, BlockAfter: 1, ExpiresIn: time.Minute})
+
+	assert.Equal(t, http.StatusNotFound, serveHoneypot(e, "192.0.2.1", "/.env"))
+	assert.Equal(t, http.StatusForbidden, serveHoneypot(e, "192.0.2.1", "/"))
+
+	current = current.Add(2 * time.Minute)
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.1", "/"))
+}
+
+func TestHoneypot_spoofedProxyHeaderIsIgnored(t *testing.T) {
+	e := newHoneypotTestEcho(HoneypotConfig{Paths: []string{"/.env"}, Delay: time.Millisecond, BlockAfter: 1})
+
+	req := httptest.NewRequest(http.MethodGet, "/.env", nil)
+	req.RemoteAddr = "203.0.113.1:1234"
+	req.Header.Set(echo.HeaderXForwardedFor, "192.0.2.1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.1", "/")) // victim is not blocked
+	assert.Equal(t, http.StatusForbidden, serveHoneypot(e, "203.0.113.1", "/"))
+}
+
+func TestHoneypot_MaxOffenders(t *testing.T) {
+	current := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	e := newHoneypotTestEcho(HoneypotConfig{Delay: time.Millisecond, BlockAfter: 1, MaxOffenders: 2})
+	for _, ip := range []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"} {
+		assert.Equal(t, http.StatusNotFound, serveHoneypot(e, ip, "/.env"))
+		current = current.Add(time.Second)
+	}
+
+	assert.Equal(t, http.StatusOK, serveHoneypot(e, "192.0.2.1", "/")) // least recently seen is forgotten
+	assert.Equal(t, http.StatusForbidden, serveHoneypot(e, "192.0.2.2", "/"))
+	assert.Equal(t, http.StatusForbidden, serveHoneypot(e, "192.0.2.3", "/"))
+}
+
+func TestTarpitDelay(t *testing.T) {
+	assert.Equal(t, time.Second, tarpitDelay(time.Second, 30*time.Second, 1))
+	assert.Equal(t, 4*time.Second, tarpitDelay(time.Second, 30*time.Second, 3))
+	assert.Equal(t, 30*time.Second, tarpitDelay(time.Second, 30*time.Second, 10))
+	assert.Equal(t, 30*time.Second, tarpitDelay(time.Second, 30*time.Second, 1000))
+}
+
+func TestHoneypotWithConfig_panicsOnInvalidPattern(t *testing.T) {
+	assert.Panics(t, func() {
+		HoneypotWithConfig(HoneypotConfig{Paths: []string{"/["}})
+	})
+}
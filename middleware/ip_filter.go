@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// IPFilterConfig defines the config for IPFilter middleware.
+	IPFilterConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Allow is list of IP addresses and CIDR ranges (i.e. "10.0.0.0/8", "192.168.1.10") allowed to access.
+		// When empty all addresses not in Deny list are allowed.
+		// Optional.
+		Allow []string
+
+		// Deny is list of IP addresses and CIDR ranges denied to access. Deny list takes precedence over Allow list.
+		// Optional.
+		Deny []string
+
+		// IPExtractor extracts client IP from request. Configure it (or `Echo#IPExtractor`) with trusted proxies
+		// (i.e. `echo.ExtractIPFromXFFHeader(echo.TrustIPRange(proxies))`) when Echo is behind reverse proxy.
+		// Optional. Default value uses `Echo#IPExtractor` and when it is not set address of direct peer
+		// (`echo.ExtractIPDirect`). Proxy headers are never trusted by default.
+		IPExtractor echo.IPExtractor
+
+		// DenyStatus is status code of error returned for denied requests. Use `http.StatusNotFound` to hide
+		// existence of the resource.
+		// Optional. Default value http.StatusForbidden.
+		DenyStatus int
+
+		// OnDenied is called for every denied request (i.e. to feed fail2ban style blocking).
+		// Optional.
+		OnDenied func(c echo.Context, ip string)
+	}
+)
+
+var (
+	// DefaultIPFilterConfig is the default IPFilter middleware config.
+	DefaultIPFilterConfig = IPFilterConfig{
+		Skipper:    DefaultSkipper,
+		DenyStatus: http.StatusForbidden,
+	}
+)
+
+// IPFilter returns a middleware that allows access only from given IP addresses and CIDR ranges. Other requests
+// receive 403 Forbidden.
+func IPFilter(allow ...string) echo.MiddlewareFunc {
+	c := DefaultIPFilterConfig
+	c.Allow = allow
+	return IPFilterWithConfig(c)
+}
+
+// IPFilterWithConfig returns an IPFilter middleware with config.
+// See: `IPFilter()`.
+func IPFilterWithConfig(config IPFilterConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultIPFilterConfig.Skipper
+	}
+	if config.DenyStatus == 0 {
+		config.DenyStatus = DefaultIPFilterConfig.DenyStatus
+	}
+	allow := mustParseIPNets(config.Allow)
+	deny := mustParseIPNets(config.Deny)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			ip := trustedIP(c, config.IPExtractor)
+			if isIPAllowed(net.ParseIP(ip), allow, deny) {
+				return next(c)
+			}
+			if config.OnDenied != nil {
+				config.OnDenied(c, ip)
+			}
+			return echo.NewHTTPError(config.DenyStatus)
+		}
+	}
+}
+
+// isIPAllowed checks ip against deny and allow lists. Requests with unknown IP are denied.
+func isIPAllowed(ip net.IP, allow []*net.IPNet, deny []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	if containsIP(deny, ip) {
+		return false
+	}
+	return len(allow) == 0 || containsIP(allow, ip)
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// mustParseIPNets parses IP addresses and CIDR ranges. Single IP address is treated as range containing only itself.
+func mustParseIPNets(values []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				panic("echo: invalid IP address: " + v)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			panic("echo: invalid CIDR range: " + v)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
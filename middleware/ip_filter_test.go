@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPFilterWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenConfig  IPFilterConfig
+		whenRemote   string
+		whenXFF      string
+		expectErr    string
+		expectDenied string
+	}{
+		{
+			name:        "ok, no lists allows all",
+			givenConfig: IPFilterConfig{},
+			whenRemote:  "203.0.113.1:1234",
+		},
+		{
+			name:        "ok, allowed by CIDR",
+			givenConfig: IPFilterConfig{Allow: []string{"10.0.0.0/8"}},
+			whenRemote:  "10.1.2.3:1234",
+		},
+		{
+			name:        "ok, allowed by single IPv6 address",
+			givenConfig: IPFilterConfig{Allow: []string{"2001:db8::1"}},
+			whenRemote:  "[2001:db8::1]:1234",
+		},
+		{
+			name:         "nok, not in allow list",
+			givenConfig:  IPFilterConfig{Allow: []string{"10.0.0.0/8", "192.168.1.10"}},
+			whenRemote:   "192.168.1.11:1234",
+			expectErr:    "code=403, message=Forbidden",
+			expectDenied: "192.168.1.11",
+		},
+		{
+			name:         "nok, deny takes precedence over allow",
+			givenConfig:  IPFilterConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.0/16"}},
+			whenRemote:   "10.0.5.5:1234",
+			expectErr:    "code=403, message=Forbidden",
+			expectDenied: "10.0.5.5",
+		},
+		{
+			name:         "nok, denied with 404",
+			givenConfig:  IPFilterConfig{Deny: []string{"203.0.113.1"}, DenyStatus: http.StatusNotFound},
+			whenRemote:   "203.0.113.1:1234",
+			expectErr:    "code=404, message=Not Found",
+			expectDenied: "203.0.113.1",
+		},
+		{
+			name:         "nok, proxy header is not trusted by default",
+			givenConfig:  IPFilterConfig{Allow: []string{"198.51.100.7"}},
+			whenRemote:   "203.0.113.1:1234",
+			whenXFF:      "198.51.100.7",
+			expectErr:    "code=403, message=Forbidden",
+			expectDenied: "203.0.113.1",
+		},
+		{
+			name:        "ok, client IP from trusted proxy header",
+			givenConfig: IPFilterConfig{Allow: []string{"198.51.100.7"}, IPExtractor: echo.ExtractIPFromXFFHeader()},
+			whenRemote:  "127.0.0.1:1234",
+			whenXFF:     "198.51.100.7",
+		},
+		{
+			name: "nok, header from untrusted proxy is ignored",
+			givenConfig: IPFilterConfig{
+				Allow:       []string{"198.51.100.7"},
+				IPExtractor: echo.ExtractIPFromXFFHeader(echo.TrustLoopback(false)),
+			},
+			whenRemote:   "127.0.0.1:1234",
+			whenXFF:      "198.51.100.7",
+			expectErr:    "code=403, message=Forbidden",
+			expectDenied: "127.0.0.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.whenRemote
+			if tc.whenXFF != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.whenXFF)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			denied := ""
+			config := tc.givenConfig
+			config.OnDenied = func(c echo.Context, ip string) {
+				denied = ip
+			}
+			err := IPFilterWithConfig(config)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectDenied, denied)
+		})
+	}
+}
+
+func TestIPFilter(t *testing.T) {
+	e := echo.New()
+	e.GET("/admin", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, IPFilter("127.0.0.1"))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req.RemoteAddr = "127.0.0.1:1234"
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestMustParseIPNets(t *testing.T) {
+	nets := mustParseIPNets([]string{"10.0.0.1", " 192.168.0.0/16 ", "::1"})
+	assert.Equal(t, "10.0.0.1/32", nets[0].String())
+	assert.Equal(t, "192.168.0.0/16", nets[1].String())
+	assert.True(t, nets[2].Contains(net.ParseIP("::1")))
+
+	assert.PanicsWithValue(t, "echo: invalid IP address: x", func() { mustParseIPNets([]string{"x"}) })
+	assert.PanicsWithValue(t, "echo: invalid CIDR range: 10.0.0.0/33", func() { mustParseIPNets([]string{"10.0.0.0/33"}) })
+}
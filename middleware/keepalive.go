@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// KeepAliveConfig defines the config for KeepAlive middleware.
+	KeepAliveConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Interval between keepalive writes.
+		// Optional. Default value 15s.
+		Interval time.Duration
+
+		// Heartbeat is written to `text/event-stream` responses (after handler has written response headers)
+		// at every interval. Heartbeat is written only between events, when handler has not written any
+		// data yet or last written data ended with blank line. Default value is SSE comment which clients ignore.
+		// Optional. Default value ": keepalive\n\n".
+		Heartbeat string
+
+		// Processing enables sending `102 Processing` informational responses at every interval until handler
+		// writes the response. Informational responses require Go 1.19 or newer HTTP server, the option is
+		// ignored when built with older Go.
+		// Optional. Default value false.
+		Processing bool
+	}
+
+	keepAliveWriter struct {
+		http.ResponseWriter
+		lock    sync.Mutex
+		written bool
+		// tail holds last bytes written by handler
+		tail []byte
+	}
+)
+
+var (
+	// DefaultKeepAliveConfig is the default KeepAlive middleware config.
+	DefaultKeepAliveConfig = KeepAliveConfig{
+		Skipper:   DefaultSkipper,
+		Interval:  15 * time.Second,
+		Heartbeat: ": keepalive\n\n",
+	}
+)
+
+// KeepAlive returns a middleware that keeps long running requests from being timed out by intermediaries (proxies,
+// load balancers) by periodically writing heartbeat to server-sent events streams. Usually used as route level
+// middleware for long running routes.
+func KeepAlive() echo.MiddlewareFunc {
+	return KeepAliveWithConfig(DefaultKeepAliveConfig)
+}
+
+// KeepAliveWithConfig returns a KeepAlive middleware with config.
+// See: `KeepAlive()`.
+func KeepAliveWithConfig(config KeepAliveConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultKeepAliveConfig.Skipper
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultKeepAliveConfig.Interval
+	}
+	if config.Heartbeat == "" {
+		config.Heartbeat = DefaultKeepAliveConfig.Heartbeat
+	}
+	config.Processing = config.Processing && informationalResponses
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			kw := &keepAliveWriter{ResponseWriter: original}
+			res.Writer = kw
+
+			done := make(chan struct{})
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ticker := time.NewTicker(config.Interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						kw.keepAlive(config)
+					}
+				}
+			}()
+			defer func() {
+				close(done)
+				wg.Wait()
+				res.Writer = original
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+func (w *keepAliveWriter) keepAlive(config KeepAliveConfig) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.written {
+		if config.Processing {
+			w.ResponseWriter.WriteHeader(http.StatusProcessing)
+		}
+		return
+	}
+	if !strings.HasPrefix(w.Header().Get(echo.HeaderContentType), "text/event-stream") || !w.betweenEvents() {
+		return
+	}
+	if _, err := w.ResponseWriter.Write([]byte(config.Heartbeat)); err != nil {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *keepAliveWriter) WriteHeader(code int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *keepAliveWriter) Write(b []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.written = true
+	n, err := w.ResponseWriter.Write(b)
+	w.tail = append(w.tail, b[:n]...)
+	if len(w.tail) > 4 {
+		w.tail = append(w.tail[:0], w.tail[len(w.tail)-4:]...)
+	}
+	return n, err
+}
+
+// betweenEvents checks if written event stream does not end with partially written event, so heartbeat does not
+// corrupt it. Events are terminated by blank line. Must be called with lock held.
+func (w *keepAliveWriter) betweenEvents() bool {
+	if len(w.tail) == 0 {
+		return true
+	}
+	tail := string(w.tail)
+	return strings.HasSuffix(tail, "\n\n") || strings.HasSuffix(tail, "\r\r") || strings.HasSuffix(tail, "\r\n\r\n")
+}
+
+func (w *keepAliveWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *keepAliveWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
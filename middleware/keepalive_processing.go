@@ -0,0 +1,8 @@
+//go:build !go1.19
+// +build !go1.19
+
+package middleware
+
+// informationalResponses is false as before Go 1.19 `http.ResponseWriter#WriteHeader` with 1xx status code sends
+// it as the final status of the response.
+const informationalResponses = false
@@ -0,0 +1,8 @@
+//go:build go1.19
+// +build go1.19
+
+package middleware
+
+// informationalResponses is true as `http.ResponseWriter#WriteHeader` sends 1xx status codes as informational
+// responses since Go 1.19.
+const informationalResponses = true
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type informationalRecorder struct {
+	*httptest.ResponseRecorder
+	lock  sync.Mutex
+	codes []int
+}
+
+func (r *informationalRecorder) WriteHeader(code int) {
+	r.lock.Lock()
+	r.codes = append(r.codes, code)
+	r.lock.Unlock()
+	if code >= 200 {
+		r.ResponseRecorder.WriteHeader(code)
+	}
+}
+
+func TestKeepAlive_SSEHeartbeat(t *testing.T) {
+	e := echo.New()
+	e.GET("/events", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+		c.Response().Flush()
+		time.Sleep(60 * time.Millisecond)
+		_, err := c.Response().Write([]byte("data: done\n\n"))
+		return err
+	}, KeepAliveWithConfig(KeepAliveConfig{Interval: 10 * time.Millisecond}))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, ": keepalive\n\n"), body)
+	assert.Contains(t, body, "\n\ndata: done\n\n")
+}
+
+func TestKeepAlive_NoHeartbeatInsideEvent(t *testing.T) {
+	e := echo.New()
+	e.GET("/events", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		if _, err := c.Response().Write([]byte("event: update\r\n")); err != nil {
+			return err
+		}
+		c.Response().Flush()
+		time.Sleep(40 * time.Millisecond)
+		if _, err := c.Response().Write([]byte("data: 1\r\n\r")); err != nil {
+			return err
+		}
+		c.Response().Flush()
+		time.Sleep(40 * time.Millisecond)
+		_, err := c.Response().Write([]byte("\n"))
+		return err
+	}, KeepAliveWithConfig(KeepAliveConfig{Interval: 10 * time.Millisecond}))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	// heartbeat may follow completed event
+	assert.True(t, strings.HasPrefix(body, "event: update\r\ndata: 1\r\n\r\n"), body)
+}
+
+func TestKeepAlive_NoHeartbeatForOtherContentTypes(t *testing.T) {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlain)
+		c.Response().WriteHeader(http.StatusOK)
+		time.Sleep(40 * time.Millisecond)
+		_, err := c.Response().Write([]byte("done"))
+		return err
+	}, KeepAliveWithConfig(KeepAliveConfig{Interval: 10 * time.Millisecond}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "done", rec.Body.String())
+}
+
+func TestKeepAlive_Processing(t *testing.T) {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		time.Sleep(40 * time.Millisecond)
+		return c.String(http.StatusOK, "done")
+	}, KeepAliveWithConfig(KeepAliveConfig{Interval: 10 * time.Millisecond, Processing: true}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := &informationalRecorder{ResponseRecorder: httptest.NewRecorder()}
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+	if !informationalResponses {
+		assert.Equal(t, []int{http.StatusOK}, rec.codes)
+		return
+	}
+	if assert.True(t, len(rec.codes) > 1) {
+		assert.Equal(t, http.StatusProcessing, rec.codes[0])
+		assert.Equal(t, http.StatusOK, rec.codes[len(rec.codes)-1])
+	}
+}
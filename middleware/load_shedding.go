@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// LoadSheddingConfig defines the config for LoadShedding middleware.
+	LoadSheddingConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Probe reports current load of the server from 0 (idle) to 1 (saturated), i.e. from request queue depth
+		// or scheduling latency. Values outside of this range are clamped.
+		// Optional. Default value measures requests in flight through middleware relative to MaxInFlight.
+		Probe func() float64
+
+		// MaxInFlight is number of concurrent requests default Probe considers saturated.
+		// Optional. Default value 1000.
+		MaxInFlight int64
+
+		// Threshold is load at which shedding starts. Above threshold requests with priority lower than
+		// `(load - Threshold) / (1 - Threshold) * 100` are rejected, so lowest priority requests are rejected
+		// first and only requests with priority 100 are served when server is saturated. Threshold 1 or more
+		// disables shedding.
+		// Optional. Default value 0.8.
+		Threshold float64
+
+		// Priority returns priority of request from 0 (shed first) to 100 (never shed).
+		// Optional. Default value returns 0 for all requests.
+		Priority func(c echo.Context) int
+
+		// RetryAfter is sent with Retry-After header of rejected requests.
+		// Optional. Default value 5 seconds.
+		RetryAfter time.Duration
+	}
+)
+
+var (
+	// DefaultLoadSheddingConfig is the default LoadShedding middleware config.
+	DefaultLoadSheddingConfig = LoadSheddingConfig{
+		Skipper:     DefaultSkipper,
+		MaxInFlight: 1000,
+		Threshold:   0.8,
+		Priority:    func(c echo.Context) int { return 0 },
+		RetryAfter:  5 * time.Second,
+	}
+)
+
+// LoadShedding returns a middleware that protects overloaded server by rejecting lowest priority requests with
+// 503 Service Unavailable and Retry-After header while load reported by probe is above threshold.
+func LoadShedding() echo.MiddlewareFunc {
+	return LoadSheddingWithConfig(DefaultLoadSheddingConfig)
+}
+
+// LoadSheddingWithConfig returns a LoadShedding middleware with config.
+// See: `LoadShedding()`.
+func LoadSheddingWithConfig(config LoadSheddingConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultLoadSheddingConfig.Skipper
+	}
+	if config.MaxInFlight == 0 {
+		config.MaxInFlight = DefaultLoadSheddingConfig.MaxInFlight
+	}
+	if config.Threshold == 0 {
+		config.Threshold = DefaultLoadSheddingConfig.Threshold
+	}
+	if config.Priority == nil {
+		config.Priority = DefaultLoadSheddingConfig.Priority
+	}
+	if config.RetryAfter == 0 {
+		config.RetryAfter = DefaultLoadSheddingConfig.RetryAfter
+	}
+	var inFlight int64
+	if config.Probe == nil {
+		config.Probe = func() float64 {
+			return float64(atomic.LoadInt64(&inFlight)) / float64(config.MaxInFlight)
+		}
+	}
+	retryAfter := retryAfterSeconds(config.RetryAfter)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			if load := clampLoad(config.Probe()); load > config.Threshold {
+				cutoff := (load - config.Threshold) / (1 - config.Threshold) * 100
+				if priority := config.Priority(c); priority < 100 && float64(priority) < cutoff {
+					c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+					return echo.NewHTTPError(http.StatusServiceUnavailable)
+				}
+			}
+
+			atomic.AddInt64(&inFlight, 1)
+			defer atomic.AddInt64(&inFlight, -1)
+			return next(c)
+		}
+	}
+}
+
+// clampLoad limits load reported by probe to range from 0 to 1, i.e. default probe exceeds 1 when there are more
+// requests in flight than MaxInFlight.
+func clampLoad(load float64) float64 {
+	if load < 0 {
+		return 0
+	}
+	if load > 1 {
+		return 1
+	}
+	return load
+}
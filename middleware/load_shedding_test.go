@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSheddingWithConfig(t *testing.T) {
+	priority := func(c echo.Context) int {
+		p, _ := strconv.Atoi(c.Request().Header.Get("X-Priority"))
+		return p
+	}
+	var testCases = []struct {
+		name             string
+		givenLoad        float64
+		givenThreshold   float64
+		whenPriority     string
+		expectErr        string
+		expectRetryAfter string
+	}{
+		{
+			name:         "ok, below threshold",
+			givenLoad:    0.8,
+			whenPriority: "0",
+		},
+		{
+			name:             "nok, lowest priority is shed first",
+			givenLoad:        0.81,
+			whenPriority:     "0",
+			expectErr:        "code=503, message=Service Unavailable",
+			expectRetryAfter: "3",
+		},
+		{
+			name:         "ok, higher priority is served",
+			givenLoad:    0.9,
+			whenPriority: "60",
+		},
+		{
+			name:             "nok, priority below cutoff",
+			givenLoad:        0.9,
+			whenPriority:     "49",
+			expectErr:        "code=503, message=Service Unavailable",
+			expectRetryAfter: "3",
+		},
+		{
+			name:         "ok, highest priority is served when saturated",
+			givenLoad:    1,
+			whenPriority: "100",
+		},
+		{
+			name:         "ok, highest priority is served when load exceeds 1",
+			givenLoad:    2.5,
+			whenPriority: "100",
+		},
+		{
+			name:         "ok, priority above 100 is never shed",
+			givenLoad:    2.5,
+			whenPriority: "150",
+		},
+		{
+			name:             "nok, load exceeding 1 is clamped",
+			givenLoad:        2.5,
+			whenPriority:     "99",
+			expectErr:        "code=503, message=Service Unavailable",
+			expectRetryAfter: "3",
+		},
+		{
+			name:           "ok, threshold 1 disables shedding",
+			givenLoad:      2.5,
+			givenThreshold: 1,
+			whenPriority:   "0",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Priority", tc.whenPriority)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			mw := LoadSheddingWithConfig(LoadSheddingConfig{
+				Probe:      func() float64 { return tc.givenLoad },
+				Threshold:  tc.givenThreshold,
+				Priority:   priority,
+				RetryAfter: 2500 * time.Millisecond,
+			})
+			err := mw(func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})(c)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectRetryAfter, rec.Header().Get(echo.HeaderRetryAfter))
+		})
+	}
+}
+
+func TestLoadShedding_inFlightProbe(t *testing.T) {
+	e := echo.New()
+	e.Use(LoadSheddingWithConfig(LoadSheddingConfig{MaxInFlight: 2, Threshold: 0.5}))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	e.GET("/slow", func(c echo.Context) error {
+		started <- struct{}{}
+		<-release
+		return c.String(http.StatusOK, "slow")
+	})
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+		}()
+		<-started
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get(echo.HeaderRetryAfter))
+
+	close(release)
+	wg.Wait()
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
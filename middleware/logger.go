@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -40,8 +40,14 @@ type (
 		// - error
 		// - latency (In nanoseconds)
 		// - latency_human (Human readable)
-		// - bytes_in (Bytes received)
-		// - bytes_out (Bytes sent)
+		// - bytes_in (Bytes received, from Content-Length header)
+		// - bytes_in_read (Bytes read from request body)
+		// - bytes_out (Bytes written by handler, before content encoding)
+		// - bytes_out_encoded (Bytes sent, after content encoding)
+		// - compression_ratio (bytes_out / bytes_out_encoded, 1 for not encoded responses)
+		// - content_encoding (Content encoding applied to response body, i.e. gzip)
+		// - request_body (Request body read by handler, up to BodyLimit bytes, JSON escaped)
+		// - response_body (Response body sent, up to BodyLimit bytes, JSON escaped)
 		// - header:<NAME>
 		// - query:<NAME>
 		// - form:<NAME>
@@ -58,9 +64,31 @@ type (
 		// Optional. Default value os.Stdout.
 		Output io.Writer
 
+		// BodyLimit is maximum number of bytes of request and response body logged with `request_body` and
+		// `response_body` tags. Bodies are captured only when format contains these tags.
+		// Optional. Default value 4KB.
+		BodyLimit int64
+
+		// Decompress enables decompressing of bodies encoded with gzip or deflate (according to `Content-Encoding`
+		// header) before they are logged with `request_body` and `response_body` tags, so they are logged in
+		// readable form instead of compressed bytes. Decompressed body is limited to BodyLimit bytes.
+		// Optional. Default value false.
+		Decompress bool
+
 		template *fasttemplate.Template
 		colorer  *color.Color
-		pool     *sync.Pool
+	}
+
+	loggerBodyReader struct {
+		io.ReadCloser
+		body  *bytes.Buffer
+		limit int64
+	}
+
+	loggerBodyWriter struct {
+		http.ResponseWriter
+		body  *bytes.Buffer
+		limit int64
 	}
 )
 
@@ -73,6 +101,7 @@ var (
 			`"status":${status},"error":"${error}","latency":${latency},"latency_human":"${latency_human}"` +
 			`,"bytes_in":${bytes_in},"bytes_out":${bytes_out}}` + "\n",
 		CustomTimeFormat: "2006-01-02 15:04:05.00000",
+		BodyLimit:        4 << 10,
 		colorer:          color.New(),
 	}
 )
@@ -95,15 +124,15 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 	if config.Output == nil {
 		config.Output = DefaultLoggerConfig.Output
 	}
+	if config.BodyLimit <= 0 {
+		config.BodyLimit = DefaultLoggerConfig.BodyLimit
+	}
+	captureRequestBody := strings.Contains(config.Format, "${request_body}")
+	captureResponseBody := strings.Contains(config.Format, "${response_body}")
 
 	config.template = fasttemplate.New(config.Format, "${", "}")
 	config.colorer = color.New()
 	config.colorer.SetOutput(config.Output)
-	config.pool = &sync.Pool{
-		New: func() interface{} {
-			return bytes.NewBuffer(make([]byte, 256))
-		},
-	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
@@ -113,14 +142,24 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 
 			req := c.Request()
 			res := c.Response()
+			var reqBody, resBody *bytes.Buffer
+			if captureRequestBody && req.Body != nil {
+				reqBody = new(bytes.Buffer)
+				req.Body = &loggerBodyReader{ReadCloser: req.Body, body: reqBody, limit: config.BodyLimit}
+			}
+			if captureResponseBody {
+				resBody = new(bytes.Buffer)
+				original := res.Writer
+				res.Writer = &loggerBodyWriter{ResponseWriter: original, body: resBody, limit: config.BodyLimit}
+				defer func() { res.Writer = original }()
+			}
 			start := time.Now()
 			if err = next(c); err != nil {
 				c.Error(err)
 			}
 			stop := time.Now()
-			buf := config.pool.Get().(*bytes.Buffer)
-			buf.Reset()
-			defer config.pool.Put(buf)
+			buf := c.Echo().AcquireBuffer()
+			defer c.Echo().ReleaseBuffer(buf)
 
 			if _, err = config.template.ExecuteFunc(buf, func(w io.Writer, tag string) (int, error) {
 				switch tag {
@@ -190,8 +229,28 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 						cl = "0"
 					}
 					return buf.WriteString(cl)
+				case "bytes_in_read":
+					return buf.WriteString(strconv.FormatInt(c.RequestBodyBytesRead(), 10))
 				case "bytes_out":
 					return buf.WriteString(strconv.FormatInt(res.Size, 10))
+				case "bytes_out_encoded":
+					return buf.WriteString(strconv.FormatInt(res.BytesSent(), 10))
+				case "compression_ratio":
+					ratio := 1.0
+					if sent := res.BytesSent(); sent > 0 {
+						ratio = float64(res.Size) / float64(sent)
+					}
+					return buf.WriteString(strconv.FormatFloat(ratio, 'f', 2, 64))
+				case "content_encoding":
+					return buf.WriteString(res.ContentEncoding)
+				case "request_body":
+					if reqBody != nil {
+						return writeLoggedBody(buf, req.Header.Get(echo.HeaderContentEncoding), reqBody.Bytes(), config)
+					}
+				case "response_body":
+					if resBody != nil {
+						return writeLoggedBody(buf, res.Header().Get(echo.HeaderContentEncoding), resBody.Bytes(), config)
+					}
 				default:
 					switch {
 					case strings.HasPrefix(tag, "header:"):
@@ -221,3 +280,44 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// writeLoggedBody writes body decompressed according to content encoding (when enabled) and JSON escaped.
+func writeLoggedBody(buf *bytes.Buffer, encoding string, body []byte, config LoggerConfig) (int, error) {
+	if config.Decompress {
+		body = decodeBody(encoding, body, config.BodyLimit)
+	}
+	b, _ := json.Marshal(string(body))
+	return buf.Write(b[1 : len(b)-1])
+}
+
+func captureBody(body *bytes.Buffer, b []byte, limit int64) {
+	if remaining := limit - int64(body.Len()); remaining > 0 {
+		if int64(len(b)) > remaining {
+			b = b[:remaining]
+		}
+		body.Write(b)
+	}
+}
+
+func (r *loggerBodyReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	captureBody(r.body, p[:n], r.limit)
+	return n, err
+}
+
+func (w *loggerBodyWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	captureBody(w.body, b[:n], w.limit)
+	return n, err
+}
+
+func (w *loggerBodyWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter so `echo.Response#Hijack` can reach it.
+func (w *loggerBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
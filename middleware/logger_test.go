@@ -2,11 +2,13 @@ package middleware
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -171,3 +173,139 @@ func TestLoggerCustomTimestamp(t *testing.T) {
 	_, err := time.Parse(customTimeFormat, loggedTime)
 	assert.Error(t, err)
 }
+
+func TestLoggerContentEncodingFields(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenGzip       bool
+		expectEncoding string
+	}{
+		{
+			name:           "ok, gzip encoded",
+			whenGzip:       true,
+			expectEncoding: "gzip",
+		},
+		{
+			name:           "ok, not encoded",
+			whenGzip:       false,
+			expectEncoding: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			buf := new(bytes.Buffer)
+			e.Use(LoggerWithConfig(LoggerConfig{
+				Format: `${bytes_out_encoded}|${bytes_out}|${compression_ratio}|${content_encoding}`,
+				Output: buf,
+			}))
+			e.Use(Gzip())
+			e.GET("/", func(c echo.Context) error {
+				return c.String(http.StatusOK, strings.Repeat("test", 1000))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.whenGzip {
+				req.Header.Set(echo.HeaderAcceptEncoding, gzipScheme)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			parts := strings.Split(buf.String(), "|")
+			assert.Len(t, parts, 4)
+			assert.Equal(t, strconv.Itoa(rec.Body.Len()), parts[0])
+			assert.Equal(t, "4000", parts[1])
+			assert.Equal(t, tc.expectEncoding, parts[3])
+			if tc.whenGzip {
+				assert.NotEqual(t, "1.00", parts[2])
+			} else {
+				assert.Equal(t, "1.00", parts[2])
+			}
+		})
+	}
+}
+
+func TestLoggerBodyFields(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		givenConfig     LoggerConfig
+		whenGzipRequest bool
+		whenGzip        bool
+		expect          string
+	}{
+		{
+			name:        "ok, plain bodies",
+			givenConfig: LoggerConfig{},
+			expect:      `{\"name\":\"Jon\"}|resp:\"Jon\"`,
+		},
+		{
+			name:        "ok, bodies are limited",
+			givenConfig: LoggerConfig{BodyLimit: 5},
+			expect:      `{\"nam|resp:`,
+		},
+		{
+			name:            "ok, encoded bodies are logged as is",
+			givenConfig:     LoggerConfig{},
+			whenGzipRequest: true,
+			whenGzip:        true,
+		},
+		{
+			name:            "ok, encoded bodies are decompressed",
+			givenConfig:     LoggerConfig{Decompress: true},
+			whenGzipRequest: true,
+			whenGzip:        true,
+			expect:          `{\"name\":\"Jon\"}|resp:\"Jon\"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			buf := new(bytes.Buffer)
+			config := tc.givenConfig
+			config.Format = `${request_body}|${response_body}`
+			config.Output = buf
+			e.Use(LoggerWithConfig(config))
+			e.Use(Decompress())
+			e.Use(Gzip())
+			e.POST("/", func(c echo.Context) error {
+				var u struct {
+					Name string `json:"name"`
+				}
+				if err := c.Bind(&u); err != nil {
+					return err
+				}
+				return c.String(http.StatusOK, `resp:"`+u.Name+`"`)
+			})
+
+			body := []byte(`{"name":"Jon"}`)
+			if tc.whenGzipRequest {
+				zb := new(bytes.Buffer)
+				zw := gzip.NewWriter(zb)
+				zw.Write(body)
+				zw.Close()
+				body = zb.Bytes()
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tc.whenGzipRequest {
+				req.Header.Set(echo.HeaderContentEncoding, gzipScheme)
+			}
+			if tc.whenGzip {
+				req.Header.Set(echo.HeaderAcceptEncoding, gzipScheme)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if tc.expect == "" {
+				// gzip magic number is logged escaped
+				assert.True(t, strings.HasPrefix(buf.String(), `\u001f`), buf.String())
+				assert.Contains(t, buf.String(), `|\u001f`)
+				return
+			}
+			assert.Equal(t, tc.expect, buf.String())
+		})
+	}
+}
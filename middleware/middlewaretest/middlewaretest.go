@@ -0,0 +1,269 @@
+/*
+Package middlewaretest provides conformance test suite for Echo middlewares.
+
+Suite checks that middleware
+  - calls next handler without doing anything else when skipper returns true,
+  - propagates errors returned by next handler,
+  - does not write response again after next handler has committed it,
+  - passes `http.Flusher` and `http.Hijacker` calls through to underlying response writer,
+  - does not use context after request has finished (context is returned to the pool and reused).
+
+Example:
+
+	func TestMyMiddleware_conformance(t *testing.T) {
+		middlewaretest.Run(t, func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+			return MyMiddlewareWithConfig(MyMiddlewareConfig{Skipper: skipper})
+		})
+	}
+*/
+package middlewaretest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// Factory creates middleware under test with given skipper. Middlewares without skipper support can ignore it
+// and use `Config.SkipSkipper`.
+type Factory func(skipper func(c echo.Context) bool) echo.MiddlewareFunc
+
+// Config defines the config for conformance suite.
+type Config struct {
+	// New creates middleware under test.
+	// Required.
+	New Factory
+
+	// Request creates request middleware lets through to next handler, i.e. request with valid credentials for
+	// authentication middleware.
+	// Optional. Default is `GET /` request.
+	Request func() *http.Request
+
+	// SkipSkipper disables skipper check for middlewares without skipper support.
+	SkipSkipper bool
+
+	// SkipHijack disables hijack passthrough check for middlewares that do not support hijacking by design
+	// (i.e. buffer whole response).
+	SkipHijack bool
+}
+
+type check struct {
+	name string
+	run  func(config Config) error
+}
+
+var checks = []check{
+	{name: "skipper", run: checkSkipper},
+	{name: "error propagation", run: checkErrorPropagation},
+	{name: "committed response", run: checkCommittedResponse},
+	{name: "flush passthrough", run: checkFlush},
+	{name: "hijack passthrough", run: checkHijack},
+	{name: "context pooling", run: checkContextPooling},
+}
+
+// Run runs conformance suite for middleware created by factory with default config.
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+	RunWithConfig(t, Config{New: factory})
+}
+
+// RunWithConfig runs conformance suite with config. Every check is run as subtest.
+func RunWithConfig(t *testing.T, config Config) {
+	t.Helper()
+	if config.New == nil {
+		t.Fatal("middlewaretest: config requires middleware factory")
+	}
+	if config.Request == nil {
+		config.Request = func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/", nil)
+		}
+	}
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if c.name == "skipper" && config.SkipSkipper || c.name == "hijack passthrough" && config.SkipHijack {
+				t.Skip("disabled by config")
+			}
+			if err := c.run(config); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// serve serves request created by config with middleware and handler. Logger output is returned.
+func serve(config Config, mw echo.MiddlewareFunc, handler echo.HandlerFunc, w http.ResponseWriter) (logs string, err error) {
+	e := echo.New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.Logger.SetLevel(log.WARN)
+	e.HTTPErrorHandler = func(e error, c echo.Context) {
+		err = e
+	}
+	e.Any("/*", handler, mw)
+	e.Any("/", handler, mw)
+
+	e.ServeHTTP(w, config.Request())
+	return buf.String(), err
+}
+
+func checkSkipper(config Config) error {
+	skipperCalled := false
+	mw := config.New(func(c echo.Context) bool {
+		skipperCalled = true
+		return true
+	})
+	rec := httptest.NewRecorder()
+	_, err := serve(config, mw, func(c echo.Context) error {
+		return c.String(http.StatusTeapot, "next")
+	}, rec)
+
+	switch {
+	case err != nil:
+		return fmt.Errorf("skipped middleware returned error: %v", err)
+	case !skipperCalled:
+		return errors.New("skipper was not called")
+	case rec.Code != http.StatusTeapot || rec.Body.String() != "next":
+		return fmt.Errorf("skipped middleware changed response, got status %d and body %q", rec.Code, rec.Body.String())
+	}
+	return nil
+}
+
+func checkErrorPropagation(config Config) error {
+	handlerErr := errors.New("handler error")
+	_, err := serve(config, config.New(neverSkip), func(c echo.Context) error {
+		return handlerErr
+	}, httptest.NewRecorder())
+
+	if !errors.Is(err, handlerErr) {
+		return fmt.Errorf("error returned by next handler was not propagated, got: %v", err)
+	}
+	return nil
+}
+
+func checkCommittedResponse(config Config) error {
+	rec := httptest.NewRecorder()
+	logs, err := serve(config, config.New(neverSkip), func(c echo.Context) error {
+		return c.String(http.StatusCreated, "created")
+	}, rec)
+
+	switch {
+	case err != nil:
+		return fmt.Errorf("middleware returned error for successful handler: %v", err)
+	case rec.Code != http.StatusCreated:
+		return fmt.Errorf("middleware changed status of committed response to %d", rec.Code)
+	case strings.Contains(logs, "response already committed"):
+		return errors.New("middleware wrote status of already committed response")
+	}
+	return nil
+}
+
+func checkFlush(config Config) error {
+	rec := httptest.NewRecorder()
+	_, err := serve(config, config.New(neverSkip), func(c echo.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("flush panicked: %v", r)
+			}
+		}()
+		if _, err := c.Response().Write([]byte("data")); err != nil {
+			return err
+		}
+		c.Response().Flush()
+		return nil
+	}, rec)
+
+	switch {
+	case err != nil:
+		return err
+	case !rec.Flushed:
+		return errors.New("flush was not passed through to underlying response writer")
+	}
+	return nil
+}
+
+func checkHijack(config Config) error {
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	_, err := serve(config, config.New(neverSkip), func(c echo.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("hijack panicked: %v", r)
+			}
+		}()
+		_, _, err = c.Response().Hijack()
+		return err
+	}, w)
+
+	switch {
+	case err != nil:
+		return err
+	case !w.hijacked:
+		return errors.New("hijack was not passed through to underlying response writer")
+	}
+	return nil
+}
+
+func checkContextPooling(config Config) error {
+	e := echo.New()
+	e.GuardContextPool = true
+	var handlerErr error
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handlerErr = err
+	}
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Request().URL.Path)
+	}
+	mw := config.New(neverSkip)
+	e.Any("/*", h, mw)
+	e.Any("/", h, mw)
+
+	for i := 0; i < 3; i++ {
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("request %d: %v", i+1, r)
+				}
+			}()
+			req := config.Request()
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if handlerErr != nil {
+				return fmt.Errorf("request %d: middleware returned error: %v", i+1, handlerErr)
+			}
+			if rec.Code != http.StatusOK {
+				return fmt.Errorf("request %d: unexpected status %d", i+1, rec.Code)
+			}
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func neverSkip(c echo.Context) bool {
+	return false
+}
+
+// hijackRecorder is response recorder that supports hijacking.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	server, client := net.Pipe()
+	client.Close()
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
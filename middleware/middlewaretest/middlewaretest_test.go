@@ -0,0 +1,134 @@
+package middlewaretest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func passThrough(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+			c.Response().Header().Set("X-Test", "yes")
+			return next(c)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	Run(t, passThrough)
+}
+
+func TestChecks_detectBrokenMiddleware(t *testing.T) {
+	var retained echo.Context
+	var testCases = []struct {
+		name        string
+		whenCheck   func(Config) error
+		givenMW     Factory
+		expectError string
+	}{
+		{
+			name:      "skipper is ignored",
+			whenCheck: checkSkipper,
+			givenMW: func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+				return func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						return echo.ErrUnauthorized
+					}
+				}
+			},
+			expectError: "skipped middleware returned error: code=401, message=Unauthorized",
+		},
+		{
+			name:      "error is swallowed",
+			whenCheck: checkErrorPropagation,
+			givenMW: func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+				return func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						next(c)
+						return nil
+					}
+				}
+			},
+			expectError: "error returned by next handler was not propagated, got: <nil>",
+		},
+		{
+			name:      "committed response is written again",
+			whenCheck: checkCommittedResponse,
+			givenMW: func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+				return func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						if err := next(c); err != nil {
+							return err
+						}
+						return c.NoContent(http.StatusOK)
+					}
+				}
+			},
+			expectError: "middleware wrote status of already committed response",
+		},
+		{
+			name:      "writer without flusher",
+			whenCheck: checkFlush,
+			givenMW: func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+				return func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						c.Response().Writer = struct{ http.ResponseWriter }{c.Response().Writer}
+						return next(c)
+					}
+				}
+			},
+			expectError: "flush panicked: interface conversion: struct { http.ResponseWriter } is not http.Flusher: missing method Flush",
+		},
+		{
+			name:      "writer without hijacker",
+			whenCheck: checkHijack,
+			givenMW: func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+				return func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						c.Response().Writer = httptest.NewRecorder()
+						return next(c)
+					}
+				}
+			},
+			expectError: "feature not supported", // Response.Hijack returns http.ErrNotSupported
+		},
+		{
+			name:      "context retained between requests",
+			whenCheck: checkContextPooling,
+			givenMW: func(skipper func(c echo.Context) bool) echo.MiddlewareFunc {
+				return func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c echo.Context) error {
+						if retained != nil {
+							retained.Request()
+						}
+						retained = c
+						return next(c)
+					}
+				}
+			},
+			expectError: "request 2: echo: context used after request was finished",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retained = nil
+			err := tc.whenCheck(Config{
+				New: tc.givenMW,
+				Request: func() *http.Request {
+					return httptest.NewRequest(http.MethodGet, "/", nil)
+				},
+			})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,668 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// OIDCConfig defines the config for OIDC middleware.
+	OIDCConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Issuer is the URL of the OpenID provider. Provider configuration is discovered from
+		// `<Issuer>/.well-known/openid-configuration`.
+		// Required.
+		Issuer string
+
+		// ClientID is the client identifier registered at the provider.
+		// Required.
+		ClientID string
+
+		// ClientSecret is the client secret. Leave empty for public clients, which rely on PKCE only.
+		// Optional.
+		ClientSecret string
+
+		// RedirectURL is the absolute callback URL registered at the provider, i.e.
+		// "https://example.com/auth/callback". Middleware handles requests to its path.
+		// Required.
+		RedirectURL string
+
+		// Scopes requested from the provider.
+		// Optional. Default value []string{"openid", "profile", "email"}.
+		Scopes []string
+
+		// Store keeps sessions and logins in progress.
+		// Optional. Default value is in-memory store created with `NewOIDCMemoryStore`.
+		Store OIDCSessionStore
+
+		// CookieName is the name of the session cookie.
+		// Optional. Default value "echo_oidc_session".
+		CookieName string
+
+		// CookieSecure sets Secure attribute of the session cookie for all requests. Cookie of HTTPS requests
+		// (including requests forwarded by TLS terminating proxy, see `echo.Context#Scheme`) is always Secure.
+		// Optional. Default value false.
+		CookieSecure bool
+
+		// SessionTTL is the lifetime of the session. It is extended when access token is refreshed.
+		// Optional. Default value 24h.
+		SessionTTL time.Duration
+
+		// Client is used for requests to the provider.
+		// Optional. Default value is a client with 10s timeout.
+		Client *http.Client
+	}
+
+	// OIDCSession is the state of a browser session kept in `OIDCSessionStore`.
+	OIDCSession struct {
+		// User is the authenticated principal. Nil while login is in progress.
+		User *echo.Principal `json:"user,omitempty"`
+
+		IDToken      string `json:"id_token,omitempty"`
+		AccessToken  string `json:"access_token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		// Expiry is the access token expiry time. Zero value means token does not expire.
+		Expiry time.Time `json:"expiry,omitempty"`
+
+		// State, Nonce and CodeVerifier are set while login is in progress.
+		State        string `json:"state,omitempty"`
+		Nonce        string `json:"nonce,omitempty"`
+		CodeVerifier string `json:"code_verifier,omitempty"`
+		// ReturnTo is the URI user is redirected to after login.
+		ReturnTo string `json:"return_to,omitempty"`
+	}
+
+	// OIDCSessionStore stores sessions of OIDC middleware.
+	OIDCSessionStore interface {
+		// Load returns session with id or nil when session does not exist or has expired.
+		Load(ctx context.Context, id string) (*OIDCSession, error)
+		// Save stores session with id for ttl.
+		Save(ctx context.Context, id string, s *OIDCSession, ttl time.Duration) error
+		// Delete removes session with id.
+		Delete(ctx context.Context, id string) error
+	}
+
+	oidcMemoryStore struct {
+		mutex    sync.Mutex
+		sessions map[string]oidcMemoryEntry
+	}
+
+	oidcMemoryEntry struct {
+		session OIDCSession
+		expires time.Time
+	}
+
+	oidcProviderMetadata struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+
+	oidcTokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		IDToken      string `json:"id_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		Scope        string `json:"scope"`
+	}
+
+	oidcJWK struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+
+	oidcProvider struct {
+		config OIDCConfig
+
+		mutex    sync.Mutex
+		metadata *oidcProviderMetadata
+		keys     map[string]interface{}
+	}
+)
+
+const (
+	// oidcLoginTTL is the time user has to complete the login at the provider.
+	oidcLoginTTL = 10 * time.Minute
+	// oidcRefreshLeeway refreshes access tokens a bit before they expire.
+	oidcRefreshLeeway = 10 * time.Second
+)
+
+var (
+	// DefaultOIDCConfig is the default OIDC middleware config.
+	DefaultOIDCConfig = OIDCConfig{
+		Skipper:    DefaultSkipper,
+		Scopes:     []string{"openid", "profile", "email"},
+		CookieName: "echo_oidc_session",
+		SessionTTL: 24 * time.Hour,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+
+	errOIDCInvalidIDToken = errors.New("invalid id token")
+)
+
+// OIDC returns an OpenID Connect relying party middleware.
+//
+// Unauthenticated GET requests are redirected to the provider to log in with authorization code flow and PKCE,
+// other unauthenticated requests get "401 - Unauthorized" response. After login the principal from ID token
+// claims is available with `Context#User`. Access tokens are refreshed with refresh token when they expire.
+func OIDC(issuer, clientID, redirectURL string) echo.MiddlewareFunc {
+	c := DefaultOIDCConfig
+	c.Issuer = issuer
+	c.ClientID = clientID
+	c.RedirectURL = redirectURL
+	return OIDCWithConfig(c)
+}
+
+// OIDCWithConfig returns an OIDC middleware with config.
+// See: `OIDC()`.
+func OIDCWithConfig(config OIDCConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultOIDCConfig.Skipper
+	}
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		panic("echo: oidc middleware requires issuer, client id and redirect url")
+	}
+	redirectURL, err := url.Parse(config.RedirectURL)
+	if err != nil || !redirectURL.IsAbs() {
+		panic("echo: oidc middleware requires absolute redirect url")
+	}
+	callbackPath := redirectURL.Path
+	if callbackPath == "" {
+		callbackPath = "/"
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultOIDCConfig.Scopes
+	}
+	if config.Store == nil {
+		config.Store = NewOIDCMemoryStore()
+	}
+	if config.CookieName == "" {
+		config.CookieName = DefaultOIDCConfig.CookieName
+	}
+	if config.SessionTTL <= 0 {
+		config.SessionTTL = DefaultOIDCConfig.SessionTTL
+	}
+	if config.Client == nil {
+		config.Client = DefaultOIDCConfig.Client
+	}
+	p := &oidcProvider{config: config}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			ctx := c.Request().Context()
+
+			id, s, err := p.loadSession(c)
+			if err != nil {
+				return err
+			}
+			if c.Request().URL.Path == callbackPath {
+				return p.callback(c, id, s)
+			}
+
+			if s != nil && s.User != nil {
+				if s.RefreshToken != "" && !s.Expiry.IsZero() && time.Now().Add(oidcRefreshLeeway).After(s.Expiry) {
+					if err := p.refresh(ctx, s); err != nil {
+						c.Logger().Warnf("oidc token refresh failed: %v", err)
+						s.User = nil
+					} else if err := config.Store.Save(ctx, id, s, config.SessionTTL); err != nil {
+						return err
+					}
+				}
+				if s.User != nil && (s.Expiry.IsZero() || time.Now().Before(s.Expiry)) {
+					c.SetUser(s.User)
+					return next(c)
+				}
+			}
+			return p.login(c)
+		}
+	}
+}
+
+// NewOIDCMemoryStore returns an in-memory OIDCSessionStore. Sessions are lost on restart and not shared between
+// instances of the application.
+func NewOIDCMemoryStore() OIDCSessionStore {
+	return &oidcMemoryStore{sessions: make(map[string]oidcMemoryEntry)}
+}
+
+func (m *oidcMemoryStore) Load(ctx context.Context, id string) (*OIDCSession, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e, ok := m.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(e.expires) {
+		delete(m.sessions, id)
+		return nil, nil
+	}
+	s := e.session
+	return &s, nil
+}
+
+func (m *oidcMemoryStore) Save(ctx context.Context, id string, s *OIDCSession, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	for k, e := range m.sessions {
+		if now.After(e.expires) {
+			delete(m.sessions, k)
+		}
+	}
+	m.sessions[id] = oidcMemoryEntry{session: *s, expires: now.Add(ttl)}
+	return nil
+}
+
+func (m *oidcMemoryStore) Delete(ctx context.Context, id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+func (p *oidcProvider) loadSession(c echo.Context) (string, *OIDCSession, error) {
+	cookie, err := c.Cookie(p.config.CookieName)
+	if err != nil || cookie.Value == "" {
+		return "", nil, nil
+	}
+	s, err := p.config.Store.Load(c.Request().Context(), cookie.Value)
+	if err != nil {
+		return "", nil, err
+	}
+	return cookie.Value, s, nil
+}
+
+func (p *oidcProvider) setSessionCookie(c echo.Context, id string, ttl time.Duration) {
+	c.SetCookie(&http.Cookie{
+		Name:     p.config.CookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(ttl / time.Second),
+		Secure:   p.config.CookieSecure || c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// login starts authorization code flow by redirecting user to the provider.
+func (p *oidcProvider) login(c echo.Context) error {
+	req := c.Request()
+	if req.Method != http.MethodGet {
+		return echo.ErrUnauthorized
+	}
+	md, err := p.discover(req.Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "openid provider unavailable").SetInternal(err)
+	}
+
+	s := &OIDCSession{
+		State:        oidcRandomString(),
+		Nonce:        oidcRandomString(),
+		CodeVerifier: oidcRandomString(),
+		ReturnTo:     req.RequestURI,
+	}
+	id := oidcRandomString()
+	if err := p.config.Store.Save(req.Context(), id, s, oidcLoginTTL); err != nil {
+		return err
+	}
+	p.setSessionCookie(c, id, oidcLoginTTL)
+
+	challenge := sha256.Sum256([]byte(s.CodeVerifier))
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.config.ClientID)
+	q.Set("redirect_uri", p.config.RedirectURL)
+	q.Set("scope", strings.Join(p.config.Scopes, " "))
+	q.Set("state", s.State)
+	q.Set("nonce", s.Nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return c.Redirect(http.StatusFound, md.AuthorizationEndpoint+sep+q.Encode())
+}
+
+// callback completes authorization code flow started by login.
+func (p *oidcProvider) callback(c echo.Context, id string, s *OIDCSession) error {
+	ctx := c.Request().Context()
+	if s == nil || s.State == "" || c.QueryParam("state") != s.State {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid login state")
+	}
+	if e := c.QueryParam("error"); e != "" {
+		p.config.Store.Delete(ctx, id)
+		return echo.NewHTTPError(http.StatusUnauthorized, "login failed: "+e)
+	}
+	code := c.QueryParam("code")
+	if code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing authorization code")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", s.CodeVerifier)
+	tr, err := p.token(ctx, form)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "authorization code exchange failed").SetInternal(err)
+	}
+	user, err := p.verifyIDToken(ctx, tr.IDToken, s.Nonce)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid id token").SetInternal(err)
+	}
+
+	returnTo := s.ReturnTo
+	session := &OIDCSession{}
+	p.applyTokens(session, tr, user)
+
+	// new session id protects against session fixation
+	if err := p.config.Store.Delete(ctx, id); err != nil {
+		return err
+	}
+	newID := oidcRandomString()
+	if err := p.config.Store.Save(ctx, newID, session, p.config.SessionTTL); err != nil {
+		return err
+	}
+	p.setSessionCookie(c, newID, p.config.SessionTTL)
+
+	return c.Redirect(http.StatusFound, oidcLocalReturnTo(returnTo))
+}
+
+// oidcLocalReturnTo returns returnTo when it is path of this site and "/" otherwise to prevent open redirects.
+// Backslashes are rejected as browsers treat `/\evil.com` as protocol-relative URL.
+func oidcLocalReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.Contains(returnTo, "\\") {
+		return "/"
+	}
+	u, err := url.Parse(returnTo)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return "/"
+	}
+	return returnTo
+}
+
+// refresh gets new tokens with the refresh token of the session.
+func (p *oidcProvider) refresh(ctx context.Context, s *OIDCSession) error {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", s.RefreshToken)
+	tr, err := p.token(ctx, form)
+	if err != nil {
+		return err
+	}
+	user := s.User
+	if tr.IDToken != "" {
+		if user, err = p.verifyIDToken(ctx, tr.IDToken, ""); err != nil {
+			return err
+		}
+	}
+	p.applyTokens(s, tr, user)
+	return nil
+}
+
+func (p *oidcProvider) applyTokens(s *OIDCSession, tr *oidcTokenResponse, user *echo.Principal) {
+	if tr.Scope != "" {
+		user.Scopes = strings.Fields(tr.Scope)
+	} else if s.User != nil {
+		user.Scopes = s.User.Scopes
+	}
+	s.User = user
+	s.AccessToken = tr.AccessToken
+	if tr.IDToken != "" {
+		s.IDToken = tr.IDToken
+	}
+	if tr.RefreshToken != "" {
+		s.RefreshToken = tr.RefreshToken
+	}
+	s.Expiry = time.Time{}
+	if tr.ExpiresIn > 0 {
+		s.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+}
+
+// token sends request to the token endpoint of the provider.
+func (p *oidcProvider) token(ctx context.Context, form url.Values) (*oidcTokenResponse, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form.Set("client_id", p.config.ClientID)
+	req, err := http.NewRequest(http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+	res, err := p.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned status %d", res.StatusCode)
+	}
+	tr := new(oidcTokenResponse)
+	if err := json.NewDecoder(res.Body).Decode(tr); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// verifyIDToken checks signature, issuer, audience, expiry and nonce of the ID token and returns principal
+// created from its claims.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, raw string, nonce string) (*echo.Principal, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claims.VerifyIssuer(md.Issuer, true) {
+		return nil, errOIDCInvalidIDToken
+	}
+	if !oidcAudienceContains(claims["aud"], p.config.ClientID) {
+		return nil, errOIDCInvalidIDToken
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errOIDCInvalidIDToken
+	}
+	if nonce != "" && claims["nonce"] != nonce {
+		return nil, errOIDCInvalidIDToken
+	}
+
+	user := &echo.Principal{Claims: claims}
+	user.Subject, _ = claims["sub"].(string)
+	user.Name, _ = claims["name"].(string)
+	user.Email, _ = claims["email"].(string)
+	if user.Subject == "" {
+		return nil, errOIDCInvalidIDToken
+	}
+	return user, nil
+}
+
+func oidcAudienceContains(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, a := range v {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// discover fetches provider metadata once and caches it. Failed discovery is retried on next call. Metadata is
+// fetched without holding the lock so slow provider does not block requests using cached keys.
+func (p *oidcProvider) discover(ctx context.Context) (*oidcProviderMetadata, error) {
+	p.mutex.Lock()
+	md := p.metadata
+	p.mutex.Unlock()
+	if md != nil {
+		return md, nil
+	}
+
+	md = new(oidcProviderMetadata)
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", md); err != nil {
+		return nil, err
+	}
+	if md.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("openid provider metadata issuer %q does not match issuer %q", md.Issuer, p.config.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("openid provider metadata is incomplete")
+	}
+	p.mutex.Lock()
+	p.metadata = md
+	p.mutex.Unlock()
+	return md, nil
+}
+
+// key returns signing key with kid. Keys are fetched again when kid is unknown to support key rotation.
+func (p *oidcProvider) key(ctx context.Context, kid string) (interface{}, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mutex.Lock()
+	k, ok := p.keys[kid]
+	p.mutex.Unlock()
+	if ok {
+		return k, nil
+	}
+
+	var set struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := p.getJSON(ctx, md.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if k, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = k
+		}
+	}
+	p.mutex.Lock()
+	p.keys = keys
+	p.mutex.Unlock()
+	if k, ok := keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *oidcProvider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	res, err := p.config.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", u, res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func (k oidcJWK) publicKey() (interface{}, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func oidcRandomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
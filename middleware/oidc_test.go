@@ -0,0 +1,339 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOIDCProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mutex sync.Mutex
+	// codes maps issued authorization codes to nonce and code challenge
+	codes map[string][2]string
+	// nonceOverride replaces nonce in issued ID tokens when set
+	nonceOverride string
+	// issuerOverride replaces issuer in provider metadata when set
+	issuerOverride *string
+	refreshCount   int
+	expiresIn      int64
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testOIDCProvider{key: key, codes: map[string][2]string{}, expiresIn: 3600}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := p.URL
+		if p.issuerOverride != nil {
+			issuer = *p.issuerOverride
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "k1",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		r.ParseForm()
+		nonce := ""
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			c, ok := p.codes[r.Form.Get("code")]
+			challenge := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if !ok || c[1] != base64.RawURLEncoding.EncodeToString(challenge[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			nonce = c[0]
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			p.refreshCount++
+		}
+		if p.nonceOverride != "" {
+			nonce = p.nonceOverride
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access",
+			"refresh_token": "refresh-1",
+			"id_token":      p.idToken(nonce),
+			"expires_in":    p.expiresIn,
+			"scope":         "openid orders:read",
+		})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *testOIDCProvider) idToken(nonce string) string {
+	claims := jwt.MapClaims{
+		"iss":  p.URL,
+		"aud":  []interface{}{"client"},
+		"sub":  "user-1",
+		"name": "Jon Snow",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "k1"
+	s, _ := token.SignedString(p.key)
+	return s
+}
+
+// authorize simulates user logging in at the provider and returns the code and state for callback.
+func (p *testOIDCProvider) authorize(t *testing.T, location string) (string, string) {
+	u, err := url.Parse(location)
+	if !assert.NoError(t, err) {
+		return "", ""
+	}
+	q := u.Query()
+	assert.Equal(t, p.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "client", q.Get("client_id"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, "openid profile email", q.Get("scope"))
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.codes["code-1"] = [2]string{q.Get("nonce"), q.Get("code_challenge")}
+	return "code-1", q.Get("state")
+}
+
+func oidcServe(e *echo.Echo, target string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func oidcSessionCookie(rec *httptest.ResponseRecorder) *http.Cookie {
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == DefaultOIDCConfig.CookieName {
+			return c
+		}
+	}
+	return nil
+}
+
+func newTestOIDCEcho(provider *testOIDCProvider, store OIDCSessionStore) *echo.Echo {
+	e := echo.New()
+	e.Use(OIDCWithConfig(OIDCConfig{
+		Issuer:      provider.URL,
+		ClientID:    "client",
+		RedirectURL: "http://example.com/auth/callback",
+		Store:       store,
+	}))
+	e.GET("/orders", func(c echo.Context) error {
+		u := c.User()
+		return c.String(http.StatusOK, u.Subject+" "+u.Name+" "+u.Scopes[1])
+	})
+	e.POST("/orders", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+	return e
+}
+
+func TestOIDC(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	defer provider.Close()
+	store := NewOIDCMemoryStore()
+	e := newTestOIDCEcho(provider, store)
+
+	// unauthenticated request is redirected to provider
+	rec := oidcServe(e, "/orders?page=2", nil)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	loginCookie := oidcSessionCookie(rec)
+	if !assert.NotNil(t, loginCookie) {
+		return
+	}
+	code, state := provider.authorize(t, rec.Header().Get(echo.HeaderLocation))
+
+	// callback with wrong state is rejected
+	rec = oidcServe(e, "/auth/callback?code="+code+"&state=invalid", loginCookie)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = oidcServe(e, "/auth/callback?code="+code+"&state="+state, loginCookie)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/orders?page=2", rec.Header().Get(echo.HeaderLocation))
+	sessionCookie := oidcSessionCookie(rec)
+	if !assert.NotNil(t, sessionCookie) {
+		return
+	}
+	assert.NotEqual(t, loginCookie.Value, sessionCookie.Value)
+	assert.True(t, sessionCookie.HttpOnly)
+
+	rec = oidcServe(e, "/orders?page=2", sessionCookie)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user-1 Jon Snow orders:read", rec.Body.String())
+
+	// login session can not be reused
+	rec = oidcServe(e, "/orders", loginCookie)
+	assert.Equal(t, http.StatusFound, rec.Code)
+
+	// expired access token is refreshed
+	ctx := context.Background()
+	s, err := store.Load(ctx, sessionCookie.Value)
+	if !assert.NoError(t, err) {
+		return
+	}
+	s.Expiry = time.Now().Add(-time.Minute)
+	store.Save(ctx, sessionCookie.Value, s, time.Hour)
+
+	rec = oidcServe(e, "/orders", sessionCookie)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, provider.refreshCount)
+	s, _ = store.Load(ctx, sessionCookie.Value)
+	assert.True(t, s.Expiry.After(time.Now()))
+}
+
+func TestOIDC_unauthenticatedNonGET(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	defer provider.Close()
+	e := newTestOIDCEcho(provider, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestOIDC_invalidNonce(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	defer provider.Close()
+	provider.nonceOverride = "other"
+	e := newTestOIDCEcho(provider, nil)
+
+	rec := oidcServe(e, "/orders", nil)
+	loginCookie := oidcSessionCookie(rec)
+	code, state := provider.authorize(t, rec.Header().Get(echo.HeaderLocation))
+
+	rec = oidcServe(e, "/auth/callback?code="+code+"&state="+state, loginCookie)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, oidcSessionCookie(rec))
+}
+
+func TestOIDC_issuerMismatch(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenIssuer string
+	}{
+		{name: "nok, other issuer", givenIssuer: "https://other.example.com"},
+		{name: "nok, empty issuer", givenIssuer: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := newTestOIDCProvider(t)
+			defer provider.Close()
+			provider.issuerOverride = &tc.givenIssuer
+			e := newTestOIDCEcho(provider, nil)
+
+			rec := oidcServe(e, "/orders", nil)
+			assert.Equal(t, http.StatusBadGateway, rec.Code)
+			assert.Empty(t, rec.Header().Get(echo.HeaderLocation))
+		})
+	}
+}
+
+func TestOIDCLocalReturnTo(t *testing.T) {
+	var testCases = []struct {
+		whenReturnTo string
+		expect       string
+	}{
+		{whenReturnTo: "/orders?page=2", expect: "/orders?page=2"},
+		{whenReturnTo: "", expect: "/"},
+		{whenReturnTo: "https://evil.example.com", expect: "/"},
+		{whenReturnTo: "//evil.example.com", expect: "/"},
+		{whenReturnTo: "/\\evil.example.com", expect: "/"},
+		{whenReturnTo: "/orders\\..\\x", expect: "/"},
+		{whenReturnTo: "/\t/evil.example.com", expect: "/"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenReturnTo, func(t *testing.T) {
+			assert.Equal(t, tc.expect, oidcLocalReturnTo(tc.whenReturnTo))
+		})
+	}
+}
+
+func TestOIDC_secureCookieBehindTLSProxy(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	defer provider.Close()
+	e := newTestOIDCEcho(provider, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	cookie := oidcSessionCookie(rec)
+	if assert.NotNil(t, cookie) {
+		assert.True(t, cookie.Secure)
+	}
+}
+
+func TestOIDCMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewOIDCMemoryStore()
+
+	assert.NoError(t, store.Save(ctx, "a", &OIDCSession{State: "s"}, time.Hour))
+	assert.NoError(t, store.Save(ctx, "b", &OIDCSession{State: "s"}, -time.Second))
+
+	s, err := store.Load(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", s.State)
+
+	s, err = store.Load(ctx, "b")
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	assert.NoError(t, store.Delete(ctx, "a"))
+	s, _ = store.Load(ctx, "a")
+	assert.Nil(t, s)
+}
+
+func TestOIDCWithConfig_panics(t *testing.T) {
+	assert.Panics(t, func() {
+		OIDCWithConfig(OIDCConfig{Issuer: "https://issuer", ClientID: "client"})
+	})
+	assert.Panics(t, func() {
+		OIDCWithConfig(OIDCConfig{Issuer: "https://issuer", ClientID: "client", RedirectURL: "/callback"})
+	})
+}
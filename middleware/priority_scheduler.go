@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// PrioritySchedulerConfig defines the config for PriorityScheduler middleware.
+	PrioritySchedulerConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Classes are priority classes requests are scheduled in. Each class gets share of MaxConcurrent slots
+		// proportional to its weight (at least one) so requests of one class can not starve other classes.
+		// Required.
+		Classes []PriorityClass
+
+		// MaxConcurrent is total number of requests handled concurrently by all classes.
+		// Optional. Default value 100.
+		MaxConcurrent int
+
+		// DefaultClass is class of requests without class or with unknown class.
+		// Optional. Default value is name of the last class.
+		DefaultClass string
+
+		// Header is request header class is read from when route has no class in `echo.RouteMetaPriorityClass`
+		// metadata. Clients can pick any class with header so use it only for trusted clients.
+		// Optional. Default value "" (header is not used).
+		Header string
+
+		// QueueTimeout is how long request waits for free slot of its class before it is rejected with
+		// 503 Service Unavailable.
+		// Optional. Default value 1 second.
+		QueueTimeout time.Duration
+
+		// RetryAfter is sent with Retry-After header of rejected requests.
+		// Optional. Default value 5 seconds.
+		RetryAfter time.Duration
+	}
+
+	// PriorityClass is class of requests sharing concurrency limit.
+	PriorityClass struct {
+		Name   string
+		Weight int
+	}
+)
+
+var (
+	// DefaultPrioritySchedulerConfig is the default PriorityScheduler middleware config.
+	DefaultPrioritySchedulerConfig = PrioritySchedulerConfig{
+		Skipper:       DefaultSkipper,
+		MaxConcurrent: 100,
+		QueueTimeout:  time.Second,
+		RetryAfter:    5 * time.Second,
+	}
+)
+
+// PriorityScheduler returns a middleware that bounds number of concurrently handled requests per priority class,
+// so i.e. health checks and admin endpoints stay responsive while public endpoints are overloaded. Class of request
+// is read from route metadata `echo.RouteMetaPriorityClass`.
+//
+// Example:
+//
+//	e.Use(middleware.PriorityScheduler(
+//		middleware.PriorityClass{Name: "critical", Weight: 1},
+//		middleware.PriorityClass{Name: "default", Weight: 9},
+//	))
+//	e.RouteMeta(e.GET("/health", health))[echo.RouteMetaPriorityClass] = "critical"
+func PriorityScheduler(classes ...PriorityClass) echo.MiddlewareFunc {
+	c := DefaultPrioritySchedulerConfig
+	c.Classes = classes
+	return PrioritySchedulerWithConfig(c)
+}
+
+// PrioritySchedulerWithConfig returns a PriorityScheduler middleware with config.
+// See: `PriorityScheduler()`.
+func PrioritySchedulerWithConfig(config PrioritySchedulerConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultPrioritySchedulerConfig.Skipper
+	}
+	if len(config.Classes) == 0 {
+		panic("echo: priority scheduler middleware requires classes")
+	}
+	if config.MaxConcurrent == 0 {
+		config.MaxConcurrent = DefaultPrioritySchedulerConfig.MaxConcurrent
+	}
+	if config.DefaultClass == "" {
+		config.DefaultClass = config.Classes[len(config.Classes)-1].Name
+	}
+	if config.QueueTimeout == 0 {
+		config.QueueTimeout = DefaultPrioritySchedulerConfig.QueueTimeout
+	}
+	if config.RetryAfter == 0 {
+		config.RetryAfter = DefaultPrioritySchedulerConfig.RetryAfter
+	}
+
+	totalWeight := 0
+	for _, class := range config.Classes {
+		totalWeight += class.Weight
+	}
+	slots := make(map[string]chan struct{}, len(config.Classes))
+	for _, class := range config.Classes {
+		n := 1
+		if totalWeight > 0 && config.MaxConcurrent*class.Weight/totalWeight > 1 {
+			n = config.MaxConcurrent * class.Weight / totalWeight
+		}
+		slots[class.Name] = make(chan struct{}, n)
+	}
+	if _, ok := slots[config.DefaultClass]; !ok {
+		panic("echo: priority scheduler middleware default class is not one of classes: " + config.DefaultClass)
+	}
+	retryAfter := retryAfterSeconds(config.RetryAfter)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			class := ""
+			if r := c.Route(); r != nil {
+				class, _ = c.Echo().RouteMeta(r)[echo.RouteMetaPriorityClass].(string)
+			}
+			if class == "" && config.Header != "" {
+				class = c.Request().Header.Get(config.Header)
+			}
+			slot, ok := slots[class]
+			if !ok {
+				slot = slots[config.DefaultClass]
+			}
+
+			select {
+			case slot <- struct{}{}:
+			default:
+				timer := time.NewTimer(config.QueueTimeout)
+				defer timer.Stop()
+				select {
+				case slot <- struct{}{}:
+				case <-timer.C:
+					c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+					return echo.NewHTTPError(http.StatusServiceUnavailable)
+				case <-c.Request().Context().Done():
+					return echo.NewHTTPError(http.StatusServiceUnavailable).SetInternal(c.Request().Context().Err())
+				}
+			}
+			defer func() { <-slot }()
+			return next(c)
+		}
+	}
+}
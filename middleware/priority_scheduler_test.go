@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityScheduler(t *testing.T) {
+	e := echo.New()
+	e.Use(PrioritySchedulerWithConfig(PrioritySchedulerConfig{
+		Classes: []PriorityClass{
+			{Name: "critical", Weight: 1},
+			{Name: "default", Weight: 1},
+		},
+		MaxConcurrent: 2,
+		Header:        "X-Priority-Class",
+		QueueTimeout:  20 * time.Millisecond,
+	}))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	e.GET("/slow", func(c echo.Context) error {
+		started <- struct{}{}
+		<-release
+		return c.String(http.StatusOK, "slow")
+	})
+	e.GET("/api", func(c echo.Context) error {
+		return c.String(http.StatusOK, "api")
+	})
+	e.RouteMeta(e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "health")
+	}))[echo.RouteMetaPriorityClass] = "critical"
+
+	// occupy the only slot of default class
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	}()
+	<-started
+
+	var testCases = []struct {
+		name             string
+		whenURL          string
+		whenHeader       string
+		expectStatus     int
+		expectRetryAfter string
+	}{
+		{
+			name:         "ok, critical route is served while default class is busy",
+			whenURL:      "/health",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, class from header",
+			whenURL:      "/api",
+			whenHeader:   "critical",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:             "nok, default class is full",
+			whenURL:          "/api",
+			expectStatus:     http.StatusServiceUnavailable,
+			expectRetryAfter: "5",
+		},
+		{
+			name:             "nok, unknown class from header falls back to default class",
+			whenURL:          "/api",
+			whenHeader:       "vip",
+			expectStatus:     http.StatusServiceUnavailable,
+			expectRetryAfter: "5",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			if tc.whenHeader != "" {
+				req.Header.Set("X-Priority-Class", tc.whenHeader)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectRetryAfter, rec.Header().Get(echo.HeaderRetryAfter))
+		})
+	}
+
+	close(release)
+	wg.Wait()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPriorityScheduler_queued(t *testing.T) {
+	e := echo.New()
+	mw := PrioritySchedulerWithConfig(PrioritySchedulerConfig{
+		Classes:       []PriorityClass{{Name: "default", Weight: 1}},
+		MaxConcurrent: 1,
+		QueueTimeout:  time.Second,
+	})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := mw(func(c echo.Context) error {
+		if c.QueryParam("block") != "" {
+			started <- struct{}{}
+			<-release
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+
+	go func() {
+		_ = h(e.NewContext(httptest.NewRequest(http.MethodGet, "/?block=1", nil), httptest.NewRecorder()))
+	}()
+	<-started
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+
+	rec := httptest.NewRecorder()
+	err := h(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", rec.Body.String())
+}
+
+func TestPriorityScheduler_panics(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: priority scheduler middleware requires classes", func() {
+		PriorityScheduler()
+	})
+	assert.PanicsWithValue(t, "echo: priority scheduler middleware default class is not one of classes: vip", func() {
+		PrioritySchedulerWithConfig(PrioritySchedulerConfig{
+			Classes:      []PriorityClass{{Name: "default", Weight: 1}},
+			DefaultClass: "vip",
+		})
+	})
+}
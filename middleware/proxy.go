@@ -56,6 +56,24 @@ type (
 
 		// ModifyResponse defines function to modify response from ProxyTarget.
 		ModifyResponse func(*http.Response) error
+
+		// OnConnect is called when a streaming connection (protocol upgrade such as WebSocket, or
+		// server-sent events) to the target has been established.
+		// Optional.
+		OnConnect func(c echo.Context, target *ProxyTarget)
+
+		// OnDisconnect is called when a streaming connection to the target ends. `err` is the error
+		// that terminated the connection or nil when both sides closed it cleanly.
+		// Optional.
+		OnDisconnect func(c echo.Context, target *ProxyTarget, err error)
+
+		// Retry configures retrying of failed requests to the selected target.
+		// Optional. Disabled by default.
+		Retry ProxyRetryConfig
+
+		// Hedge configures sending duplicate requests to the selected target when it is slow to respond.
+		// Optional. Disabled by default.
+		Hedge ProxyHedgeConfig
 	}
 
 	// ProxyTarget defines the upstream target.
@@ -72,8 +90,26 @@ type (
 		Next(echo.Context) *ProxyTarget
 	}
 
+	// ProxyTargetReleaser is an optional interface for ProxyBalancer implementations that track requests in
+	// progress. Proxy middleware calls Release when the request to the target returned by Next has finished.
+	ProxyTargetReleaser interface {
+		Release(echo.Context, *ProxyTarget)
+	}
+
+	// ProxyHealthAwareBalancer is implemented by balancers able to eject unhealthy targets from rotation.
+	// All balancers provided by this package implement it. Targets are identified by pointer so targets without
+	// name are supported.
+	ProxyHealthAwareBalancer interface {
+		ProxyBalancer
+		Targets() []*ProxyTarget
+		SetTargetHealthy(target *ProxyTarget, healthy bool)
+	}
+
 	commonBalancer struct {
-		targets []*ProxyTarget
+		targets   []*ProxyTarget
+		unhealthy map[*ProxyTarget]bool
+		// version is incremented every time list of available targets changes
+		version uint64
 		mutex   sync.RWMutex
 	}
 
@@ -98,18 +134,23 @@ var (
 	}
 )
 
-func proxyRaw(t *ProxyTarget, c echo.Context) http.Handler {
+// closeWriter is implemented by connections that support half-close (i.e. *net.TCPConn, *tls.Conn).
+type closeWriter interface {
+	CloseWrite() error
+}
+
+func proxyRaw(t *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		in, _, err := c.Response().Hijack()
+		in, brw, err := c.Response().Hijack()
 		if err != nil {
-			c.Set("_error", fmt.Sprintf("proxy raw, hijack error=%v, url=%s", t.URL, err))
+			c.Set("_error", fmt.Errorf("proxy raw, hijack error=%v, url=%s", err, t.URL))
 			return
 		}
 		defer in.Close()
 
 		out, err := net.Dial("tcp", t.URL.Host)
 		if err != nil {
-			c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, dial error=%v, url=%s", t.URL, err)))
+			c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, dial error=%v, url=%s", err, t.URL)))
 			return
 		}
 		defer out.Close()
@@ -117,21 +158,53 @@ func proxyRaw(t *ProxyTarget, c echo.Context) http.Handler {
 		// Write header
 		err = r.Write(out)
 		if err != nil {
-			c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, request header copy error=%v, url=%s", t.URL, err)))
+			c.Set("_error", echo.NewHTTPError(http.StatusBadGateway, fmt.Sprintf("proxy raw, request header copy error=%v, url=%s", err, t.URL)))
 			return
 		}
+		if config.OnConnect != nil {
+			config.OnConnect(c, t)
+		}
+
+		// Data the client sent right after the request may already sit in the hijacked read buffer.
+		var src io.Reader = in
+		if brw != nil && brw.Reader.Buffered() > 0 {
+			src = brw.Reader
+		}
 
 		errCh := make(chan error, 2)
-		cp := func(dst io.Writer, src io.Reader) {
-			_, err = io.Copy(dst, src)
+		cp := func(dst net.Conn, src io.Reader) {
+			_, err := io.Copy(dst, src)
+			if err == nil {
+				// Source finished sending - forward half-close so the other side can still respond.
+				if cw, ok := dst.(closeWriter); ok {
+					err = cw.CloseWrite()
+				} else {
+					err = dst.Close()
+				}
+			}
 			errCh <- err
 		}
 
-		go cp(out, in)
+		go cp(out, src)
 		go cp(in, out)
-		err = <-errCh
-		if err != nil && err != io.EOF {
-			c.Set("_error", fmt.Errorf("proxy raw, copy body error=%v, url=%s", t.URL, err))
+
+		var copyErr error
+		for i := 0; i < 2; i++ {
+			if err := <-errCh; err != nil && copyErr == nil {
+				copyErr = err
+				// Unblock the other direction.
+				in.Close()
+				out.Close()
+			}
+		}
+		if copyErr != nil && !isClosedConnError(copyErr) {
+			copyErr = fmt.Errorf("proxy raw, copy body error=%v, url=%s", copyErr, t.URL)
+			c.Set("_error", copyErr)
+		} else {
+			copyErr = nil
+		}
+		if config.OnDisconnect != nil {
+			config.OnDisconnect(c, t, copyErr)
 		}
 	})
 }
@@ -160,6 +233,7 @@ func (b *commonBalancer) AddTarget(target *ProxyTarget) bool {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 	b.targets = append(b.targets, target)
+	b.version++
 	return true
 }
 
@@ -170,12 +244,59 @@ func (b *commonBalancer) RemoveTarget(name string) bool {
 	for i, t := range b.targets {
 		if t.Name == name {
 			b.targets = append(b.targets[:i], b.targets[i+1:]...)
+			delete(b.unhealthy, t)
+			b.version++
 			return true
 		}
 	}
 	return false
 }
 
+// Targets returns a copy of the upstream target list.
+func (b *commonBalancer) Targets() []*ProxyTarget {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	targets := make([]*ProxyTarget, len(b.targets))
+	copy(targets, b.targets)
+	return targets
+}
+
+// SetTargetHealthy marks an upstream target as healthy or unhealthy. Unhealthy targets are not returned by
+// Next unless all targets are unhealthy.
+func (b *commonBalancer) SetTargetHealthy(target *ProxyTarget, healthy bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.unhealthy[target] == !healthy {
+		return
+	}
+	if healthy {
+		delete(b.unhealthy, target)
+	} else {
+		if b.unhealthy == nil {
+			b.unhealthy = make(map[*ProxyTarget]bool)
+		}
+		b.unhealthy[target] = true
+	}
+	b.version++
+}
+
+// available returns healthy targets or all targets when none of them is healthy. Must be called with mutex held.
+func (b *commonBalancer) available() []*ProxyTarget {
+	if len(b.unhealthy) == 0 {
+		return b.targets
+	}
+	healthy := make([]*ProxyTarget, 0, len(b.targets))
+	for _, t := range b.targets {
+		if !b.unhealthy[t] {
+			healthy = append(healthy, t)
+		}
+	}
+	if len(healthy) == 0 {
+		return b.targets
+	}
+	return healthy
+}
+
 // Next randomly returns an upstream target.
 func (b *randomBalancer) Next(c echo.Context) *ProxyTarget {
 	if b.random == nil {
@@ -183,15 +304,17 @@ func (b *randomBalancer) Next(c echo.Context) *ProxyTarget {
 	}
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
-	return b.targets[b.random.Intn(len(b.targets))]
+	targets := b.available()
+	return targets[b.random.Intn(len(targets))]
 }
 
 // Next returns an upstream target using round-robin technique.
 func (b *roundRobinBalancer) Next(c echo.Context) *ProxyTarget {
-	b.i = b.i % uint32(len(b.targets))
-	t := b.targets[b.i]
-	atomic.AddUint32(&b.i, 1)
-	return t
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	targets := b.available()
+	i := atomic.AddUint32(&b.i, 1) - 1
+	return targets[i%uint32(len(targets))]
 }
 
 // Proxy returns a Proxy middleware.
@@ -213,6 +336,9 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 	if config.Balancer == nil {
 		panic("echo: proxy middleware requires balancer")
 	}
+	if config.Retry.MaxAttempts > 1 || config.Hedge.Delay > 0 {
+		config.Transport = newProxyRetryTransport(config.Transport, config.Retry, config.Hedge)
+	}
 
 	if config.Rewrite != nil {
 		if config.RegexRewrite == nil {
@@ -233,6 +359,9 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 			res := c.Response()
 			tgt := config.Balancer.Next(c)
 			c.Set(config.ContextKey, tgt)
+			if r, ok := config.Balancer.(ProxyTargetReleaser); ok {
+				defer r.Release(c, tgt)
+			}
 
 			if err := rewriteURL(config.RegexRewrite, req); err != nil {
 				return err
@@ -247,15 +376,17 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 			if req.Header.Get(echo.HeaderXForwardedProto) == "" {
 				req.Header.Set(echo.HeaderXForwardedProto, c.Scheme())
 			}
-			if c.IsWebSocket() && req.Header.Get(echo.HeaderXForwardedFor) == "" { // For HTTP, it is automatically set by Go HTTP reverse proxy.
+			upgrade := c.IsWebSocket() || isUpgradeRequest(req)
+			if upgrade && req.Header.Get(echo.HeaderXForwardedFor) == "" { // For HTTP, it is automatically set by Go HTTP reverse proxy.
 				req.Header.Set(echo.HeaderXForwardedFor, c.RealIP())
 			}
 
 			// Proxy
 			switch {
-			case c.IsWebSocket():
-				proxyRaw(tgt, c).ServeHTTP(res, req)
-			case req.Header.Get(echo.HeaderAccept) == "text/event-stream":
+			case upgrade:
+				proxyRaw(tgt, c, config).ServeHTTP(res, req)
+			case isEventStreamRequest(req):
+				proxyStream(tgt, c, config).ServeHTTP(res, req)
 			default:
 				proxyHTTP(tgt, c, config).ServeHTTP(res, req)
 			}
@@ -301,3 +432,56 @@ func proxyHTTP(tgt *ProxyTarget, c echo.Context, config ProxyConfig) http.Handle
 	proxy.ModifyResponse = config.ModifyResponse
 	return proxy
 }
+
+// proxyStream proxies long-lived streaming responses (i.e. server-sent events). Every write from the
+// target is flushed to the client immediately.
+func proxyStream(tgt *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
+	proxy := proxyHTTP(tgt, c, config).(*httputil.ReverseProxy)
+	proxy.FlushInterval = -1
+
+	connected := false
+	proxy.ModifyResponse = func(res *http.Response) error {
+		connected = true
+		if config.OnConnect != nil {
+			config.OnConnect(c, tgt)
+		}
+		if config.ModifyResponse != nil {
+			return config.ModifyResponse(res)
+		}
+		return nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxy.ServeHTTP(w, r)
+		if connected && config.OnDisconnect != nil {
+			err, _ := c.Get("_error").(error)
+			config.OnDisconnect(c, tgt, err)
+		}
+	})
+}
+
+// isUpgradeRequest checks if request asks for protocol upgrade (i.e. WebSocket) with `Connection: Upgrade`
+// and `Upgrade` headers.
+func isUpgradeRequest(r *http.Request) bool {
+	if r.Header.Get(echo.HeaderUpgrade) == "" {
+		return false
+	}
+	for _, v := range r.Header["Connection"] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isClosedConnError checks if error is caused by using already closed connection. Standard library does
+// not export this error before Go 1.16 so substring check is required.
+func isClosedConnError(err error) bool {
+	return strings.Contains(err.Error(), "use of closed network connection")
+}
+
+// isEventStreamRequest checks if client accepts server-sent events (`text/event-stream`) response.
+func isEventStreamRequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get(echo.HeaderAccept), "text/event-stream")
+}
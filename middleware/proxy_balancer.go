@@ -0,0 +1,379 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// stickySessionBalancer pins a client to the target stored in a cookie.
+	stickySessionBalancer struct {
+		*roundRobinBalancer
+		cookieName string
+	}
+
+	// consistentHashBalancer maps requests to targets on a consistent hash ring.
+	consistentHashBalancer struct {
+		*commonBalancer
+		key func(c echo.Context) string
+
+		ringMutex   sync.Mutex
+		ringVersion uint64
+		ring        []uint32
+		ringTargets map[uint32]*ProxyTarget
+	}
+
+	// leastConnectionsBalancer picks the target with the fewest requests in progress.
+	leastConnectionsBalancer struct {
+		*commonBalancer
+		inFlightMutex sync.Mutex
+		inFlight      map[*ProxyTarget]int
+	}
+
+	// ProxyHealthCheckConfig defines the config for active health checking of proxy targets.
+	ProxyHealthCheckConfig struct {
+		// Interval between health check rounds.
+		// Optional. Default value 10s.
+		Interval time.Duration
+
+		// Timeout of a single target check.
+		// Optional. Default value 2s.
+		Timeout time.Duration
+
+		// Path is requested from the target with GET method. Any 2xx or 3xx response is considered healthy.
+		// Optional. Default value "/".
+		Path string
+
+		// Client is used to send health check requests.
+		// Optional. Default value http.DefaultClient.
+		Client *http.Client
+
+		// Check replaces the HTTP check with a custom one. Returning an error marks check as failed.
+		// Optional.
+		Check func(ctx context.Context, target *ProxyTarget) error
+
+		// UnhealthyThreshold is the number of consecutive failed checks after which target is ejected.
+		// Optional. Default value 3.
+		UnhealthyThreshold int
+
+		// HealthyThreshold is the number of consecutive successful checks after which ejected target is
+		// returned into rotation.
+		// Optional. Default value 2.
+		HealthyThreshold int
+
+		// OnChange is called when target health state changes.
+		// Optional.
+		OnChange func(target *ProxyTarget, healthy bool)
+	}
+
+	proxyHealthChecker struct {
+		config   ProxyHealthCheckConfig
+		balancer ProxyHealthAwareBalancer
+		// state holds count of consecutive results for each target, positive for successes and negative
+		// for failures
+		state   map[*ProxyTarget]int
+		ejected map[*ProxyTarget]bool
+	}
+)
+
+const (
+	// DefaultStickySessionCookieName is the cookie used by sticky session balancer when name is not provided.
+	DefaultStickySessionCookieName = "echo_proxy_target"
+
+	consistentHashReplicas = 100
+)
+
+var (
+	// DefaultProxyHealthCheckConfig is the default proxy health check config.
+	DefaultProxyHealthCheckConfig = ProxyHealthCheckConfig{
+		Interval:           10 * time.Second,
+		Timeout:            2 * time.Second,
+		Path:               "/",
+		UnhealthyThreshold: 3,
+		HealthyThreshold:   2,
+	}
+)
+
+// NewStickySessionBalancer returns a proxy balancer that pins clients to a target with a cookie. The first
+// request of a client is balanced using round-robin technique and the selected target name is stored in
+// the cookie. Clients are moved to another target when their target is removed or becomes unhealthy.
+// Targets must have unique, non-empty names.
+func NewStickySessionBalancer(targets []*ProxyTarget, cookieName string) ProxyBalancer {
+	if cookieName == "" {
+		cookieName = DefaultStickySessionCookieName
+	}
+	b := &stickySessionBalancer{
+		roundRobinBalancer: &roundRobinBalancer{commonBalancer: new(commonBalancer)},
+		cookieName:         cookieName,
+	}
+	b.targets = targets
+	return b
+}
+
+// Next returns the target stored in the session cookie or selects and stores a new one.
+func (b *stickySessionBalancer) Next(c echo.Context) *ProxyTarget {
+	if cookie, err := c.Cookie(b.cookieName); err == nil {
+		if name, err := url.QueryUnescape(cookie.Value); err == nil {
+			b.mutex.RLock()
+			for _, t := range b.available() {
+				if t.Name == name {
+					b.mutex.RUnlock()
+					return t
+				}
+			}
+			b.mutex.RUnlock()
+		}
+	}
+
+	t := b.roundRobinBalancer.Next(c)
+	c.SetCookie(&http.Cookie{
+		Name:     b.cookieName,
+		Value:    url.QueryEscape(t.Name),
+		Path:     "/",
+		HttpOnly: true,
+	})
+	return t
+}
+
+// NewConsistentHashBalancer returns a proxy balancer that uses consistent hashing of a request key to select
+// the target, so requests with the same key go to the same target and only a small share of keys move when
+// targets are added, removed or ejected. `key` extracts the hashed value from the request, i.e.
+// `func(c echo.Context) string { return c.Request().Header.Get("X-Tenant-ID") }`. When `key` is nil or
+// returns an empty string, the client IP address (`Context#RealIP`) is used.
+func NewConsistentHashBalancer(targets []*ProxyTarget, key func(c echo.Context) string) ProxyBalancer {
+	b := &consistentHashBalancer{commonBalancer: new(commonBalancer), key: key}
+	b.targets = targets
+	b.version = 1 // differ from ringVersion so that the ring is built on first use
+	return b
+}
+
+// Next returns the target owning the request key on the hash ring.
+func (b *consistentHashBalancer) Next(c echo.Context) *ProxyTarget {
+	key := ""
+	if b.key != nil {
+		key = b.key(c)
+	}
+	if key == "" {
+		key = c.RealIP()
+	}
+	h := crc32.ChecksumIEEE([]byte(key))
+
+	b.ringMutex.Lock()
+	defer b.ringMutex.Unlock()
+	b.mutex.RLock()
+	if b.ringVersion != b.version {
+		b.buildRing()
+	}
+	b.mutex.RUnlock()
+
+	i := sort.Search(len(b.ring), func(i int) bool { return b.ring[i] >= h })
+	if i == len(b.ring) {
+		i = 0
+	}
+	return b.ringTargets[b.ring[i]]
+}
+
+// buildRing places virtual nodes of available targets on the ring. Must be called with both mutexes held.
+func (b *consistentHashBalancer) buildRing() {
+	targets := b.available()
+	b.ring = make([]uint32, 0, len(targets)*consistentHashReplicas)
+	b.ringTargets = make(map[uint32]*ProxyTarget, len(targets)*consistentHashReplicas)
+	for _, t := range targets {
+		id := t.Name
+		if id == "" && t.URL != nil {
+			id = t.URL.String()
+		}
+		for r := 0; r < consistentHashReplicas; r++ {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(r) + "-" + id))
+			if _, ok := b.ringTargets[h]; ok {
+				continue
+			}
+			b.ring = append(b.ring, h)
+			b.ringTargets[h] = t
+		}
+	}
+	sort.Slice(b.ring, func(i, j int) bool { return b.ring[i] < b.ring[j] })
+	b.ringVersion = b.version
+}
+
+// NewLeastConnectionsBalancer returns a proxy balancer that selects the target with the fewest requests in
+// progress. Ties are resolved in favour of the target added first.
+func NewLeastConnectionsBalancer(targets []*ProxyTarget) ProxyBalancer {
+	b := &leastConnectionsBalancer{
+		commonBalancer: new(commonBalancer),
+		inFlight:       make(map[*ProxyTarget]int),
+	}
+	b.targets = targets
+	return b
+}
+
+// Next returns the target with the fewest requests in progress.
+func (b *leastConnectionsBalancer) Next(c echo.Context) *ProxyTarget {
+	b.mutex.RLock()
+	targets := b.available()
+	b.mutex.RUnlock()
+
+	b.inFlightMutex.Lock()
+	defer b.inFlightMutex.Unlock()
+	var selected *ProxyTarget
+	for _, t := range targets {
+		if selected == nil || b.inFlight[t] < b.inFlight[selected] {
+			selected = t
+		}
+	}
+	b.inFlight[selected]++
+	return selected
+}
+
+// Release marks request to the target as finished.
+func (b *leastConnectionsBalancer) Release(c echo.Context, target *ProxyTarget) {
+	b.inFlightMutex.Lock()
+	defer b.inFlightMutex.Unlock()
+	if b.inFlight[target] <= 1 {
+		delete(b.inFlight, target)
+		return
+	}
+	b.inFlight[target]--
+}
+
+// StartProxyHealthCheck starts actively checking targets of the balancer in a background goroutine until
+// ctx is cancelled. Targets failing `UnhealthyThreshold` consecutive checks are ejected from rotation and
+// returned after passing `HealthyThreshold` consecutive checks. When all targets are unhealthy the balancer
+// uses all of them.
+func StartProxyHealthCheck(ctx context.Context, balancer ProxyBalancer, config ProxyHealthCheckConfig) {
+	b, ok := balancer.(ProxyHealthAwareBalancer)
+	if !ok {
+		panic("echo: proxy health check requires balancer implementing ProxyHealthAwareBalancer")
+	}
+	// Defaults
+	if config.Interval <= 0 {
+		config.Interval = DefaultProxyHealthCheckConfig.Interval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultProxyHealthCheckConfig.Timeout
+	}
+	if config.Path == "" {
+		config.Path = DefaultProxyHealthCheckConfig.Path
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.UnhealthyThreshold <= 0 {
+		config.UnhealthyThreshold = DefaultProxyHealthCheckConfig.UnhealthyThreshold
+	}
+	if config.HealthyThreshold <= 0 {
+		config.HealthyThreshold = DefaultProxyHealthCheckConfig.HealthyThreshold
+	}
+
+	hc := &proxyHealthChecker{
+		config:   config,
+		balancer: b,
+		state:    make(map[*ProxyTarget]int),
+		ejected:  make(map[*ProxyTarget]bool),
+	}
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+		for {
+			hc.round(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// round checks all targets concurrently and updates their health in the balancer.
+func (hc *proxyHealthChecker) round(ctx context.Context) {
+	targets := hc.balancer.Targets()
+	results := make([]error, len(targets))
+	wg := sync.WaitGroup{}
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t *ProxyTarget) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, hc.config.Timeout)
+			defer cancel()
+			results[i] = hc.check(checkCtx, t)
+		}(i, t)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	seen := make(map[*ProxyTarget]bool, len(targets))
+	for i, t := range targets {
+		seen[t] = true
+		n := hc.state[t]
+		if results[i] == nil {
+			if n < 0 {
+				n = 0
+			}
+			n++
+		} else {
+			if n > 0 {
+				n = 0
+			}
+			n--
+		}
+		hc.state[t] = n
+
+		switch {
+		case hc.ejected[t] && n >= hc.config.HealthyThreshold:
+			delete(hc.ejected, t)
+			hc.setHealthy(t, true)
+		case !hc.ejected[t] && -n >= hc.config.UnhealthyThreshold:
+			hc.ejected[t] = true
+			hc.setHealthy(t, false)
+		}
+	}
+	// forget targets removed from the balancer
+	for t := range hc.state {
+		if !seen[t] {
+			delete(hc.state, t)
+			delete(hc.ejected, t)
+		}
+	}
+}
+
+func (hc *proxyHealthChecker) setHealthy(t *ProxyTarget, healthy bool) {
+	hc.balancer.SetTargetHealthy(t, healthy)
+	if hc.config.OnChange != nil {
+		hc.config.OnChange(t, healthy)
+	}
+}
+
+func (hc *proxyHealthChecker) check(ctx context.Context, t *ProxyTarget) error {
+	if hc.config.Check != nil {
+		return hc.config.Check(ctx, t)
+	}
+	u := *t.URL
+	u.Path = hc.config.Path
+	u.RawPath = ""
+	u.RawQuery = ""
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := hc.config.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("health check returned status %d", res.StatusCode)
+	}
+	return nil
+}
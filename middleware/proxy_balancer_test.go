@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProxyTargets(names ...string) []*ProxyTarget {
+	targets := make([]*ProxyTarget, len(names))
+	for i, n := range names {
+		u, _ := url.Parse("http://" + n + ".example.com")
+		targets[i] = &ProxyTarget{Name: n, URL: u}
+	}
+	return targets
+}
+
+func TestStickySessionBalancer(t *testing.T) {
+	e := echo.New()
+	b := NewStickySessionBalancer(testProxyTargets("a", "b", "c"), "")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	first := b.Next(e.NewContext(req, rec))
+	cookie := rec.Result().Cookies()[0]
+	assert.Equal(t, DefaultStickySessionCookieName, cookie.Name)
+	assert.Equal(t, first.Name, cookie.Value)
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		assert.Equal(t, first, b.Next(e.NewContext(req, rec)))
+		assert.Empty(t, rec.Header().Get(echo.HeaderSetCookie))
+	}
+
+	// unhealthy target moves client to another one
+	b.(ProxyHealthAwareBalancer).SetTargetHealthy(first, false)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	moved := b.Next(e.NewContext(req, rec))
+	assert.NotEqual(t, first, moved)
+	assert.Equal(t, moved.Name, rec.Result().Cookies()[0].Value)
+}
+
+func TestConsistentHashBalancer(t *testing.T) {
+	e := echo.New()
+	targets := testProxyTargets("a", "b", "c", "d")
+	b := NewConsistentHashBalancer(targets, func(c echo.Context) string {
+		return c.Request().Header.Get("X-Tenant")
+	})
+
+	next := func(tenant string) *ProxyTarget {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", tenant)
+		return b.Next(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	before := map[string]*ProxyTarget{}
+	used := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		tenant := "tenant-" + strconv.Itoa(i)
+		before[tenant] = next(tenant)
+		used[before[tenant].Name] = true
+		assert.Equal(t, before[tenant], next(tenant))
+	}
+	assert.Len(t, used, 4)
+
+	// ejecting a target moves only keys owned by that target
+	b.(ProxyHealthAwareBalancer).SetTargetHealthy(targets[1], false)
+	for tenant, target := range before {
+		after := next(tenant)
+		assert.NotEqual(t, "b", after.Name)
+		if target.Name != "b" {
+			assert.Equal(t, target, after)
+		}
+	}
+
+	// empty key falls back to client IP
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	target := b.Next(e.NewContext(req, httptest.NewRecorder()))
+	assert.Equal(t, target, b.Next(e.NewContext(req, httptest.NewRecorder())))
+}
+
+func TestLeastConnectionsBalancer(t *testing.T) {
+	e := echo.New()
+	targets := testProxyTargets("a", "b")
+	b := NewLeastConnectionsBalancer(targets)
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	assert.Equal(t, targets[0], b.Next(c))
+	assert.Equal(t, targets[1], b.Next(c))
+	assert.Equal(t, targets[0], b.Next(c))
+
+	b.(ProxyTargetReleaser).Release(c, targets[1])
+	assert.Equal(t, targets[1], b.Next(c))
+	assert.Equal(t, targets[1], b.Next(c))
+}
+
+func TestProxyLeastConnectionsReleasesTarget(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+	targets := []*ProxyTarget{{Name: "a", URL: u}, {Name: "b", URL: u}}
+
+	b := NewLeastConnectionsBalancer(targets)
+	e := echo.New()
+	e.Use(Proxy(b))
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	// without releasing targets "a" would have 2 requests in progress and "b" would be selected
+	assert.Equal(t, targets[0], b.Next(c))
+}
+
+func TestRoundRobinBalancerSkipsUnhealthy(t *testing.T) {
+	e := echo.New()
+	targets := testProxyTargets("a", "b", "c")
+	b := NewRoundRobinBalancer(targets)
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	b.(ProxyHealthAwareBalancer).SetTargetHealthy(targets[1], false)
+	for i := 0; i < 4; i++ {
+		assert.NotEqual(t, "b", b.Next(c).Name)
+	}
+
+	// all targets unhealthy - balancer falls back to all targets
+	b.(ProxyHealthAwareBalancer).SetTargetHealthy(targets[0], false)
+	b.(ProxyHealthAwareBalancer).SetTargetHealthy(targets[2], false)
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		seen[b.Next(c).Name] = true
+	}
+	assert.Len(t, seen, 3)
+}
+
+func TestProxyHealthChecker(t *testing.T) {
+	targets := testProxyTargets("a", "b")
+	b := NewRoundRobinBalancer(targets).(ProxyHealthAwareBalancer)
+	failing := map[string]bool{"b": true}
+	var changes []string
+
+	hc := &proxyHealthChecker{
+		config: ProxyHealthCheckConfig{
+			Timeout:            DefaultProxyHealthCheckConfig.Timeout,
+			UnhealthyThreshold: 2,
+			HealthyThreshold:   2,
+			Check: func(ctx context.Context, target *ProxyTarget) error {
+				if failing[target.Name] {
+					return errors.New("down")
+				}
+				return nil
+			},
+			OnChange: func(target *ProxyTarget, healthy bool) {
+				changes = append(changes, target.Name+":"+strconv.FormatBool(healthy))
+			},
+		},
+		balancer: b,
+		state:    map[*ProxyTarget]int{},
+		ejected:  map[*ProxyTarget]bool{},
+	}
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	hc.round(context.Background())
+	assert.Empty(t, changes)
+	hc.round(context.Background())
+	assert.Equal(t, []string{"b:false"}, changes)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "a", b.Next(c).Name)
+	}
+
+	failing["b"] = false
+	hc.round(context.Background())
+	assert.Equal(t, []string{"b:false"}, changes)
+	hc.round(context.Background())
+	assert.Equal(t, []string{"b:false", "b:true"}, changes)
+}
+
+func TestProxyHealthChecker_unnamedTargets(t *testing.T) {
+	targets := testProxyTargets("a", "b", "c")
+	for _, target := range targets {
+		target.Name = ""
+	}
+	b := NewRoundRobinBalancer(targets).(ProxyHealthAwareBalancer)
+	hc := &proxyHealthChecker{
+		config: ProxyHealthCheckConfig{
+			Timeout:            DefaultProxyHealthCheckConfig.Timeout,
+			UnhealthyThreshold: 1,
+			HealthyThreshold:   1,
+			Check: func(ctx context.Context, target *ProxyTarget) error {
+				if target == targets[1] {
+					return errors.New("down")
+				}
+				return nil
+			},
+		},
+		balancer: b,
+		state:    map[*ProxyTarget]int{},
+		ejected:  map[*ProxyTarget]bool{},
+	}
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	hc.round(context.Background())
+	assert.Equal(t, map[*ProxyTarget]bool{targets[1]: true}, hc.ejected)
+	for i := 0; i < 4; i++ {
+		assert.NotEqual(t, targets[1], b.Next(c))
+	}
+}
+
+func TestProxyHealthChecker_httpCheck(t *testing.T) {
+	status := http.StatusOK
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL + "/api")
+	target := &ProxyTarget{Name: "a", URL: u}
+
+	hc := &proxyHealthChecker{config: ProxyHealthCheckConfig{Path: "/health", Client: http.DefaultClient}}
+	assert.NoError(t, hc.check(context.Background(), target))
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, hc.check(context.Background(), target), "health check returned status 503")
+}
+
+func TestStartProxyHealthCheck_unsupportedBalancer(t *testing.T) {
+	assert.Panics(t, func() {
+		StartProxyHealthCheck(context.Background(), &testBalancer{}, ProxyHealthCheckConfig{})
+	})
+}
+
+type testBalancer struct{}
+
+func (b *testBalancer) AddTarget(*ProxyTarget) bool    { return false }
+func (b *testBalancer) RemoveTarget(string) bool       { return false }
+func (b *testBalancer) Next(echo.Context) *ProxyTarget { return nil }
@@ -0,0 +1,286 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// ProxyRetryConfig defines retry policy of the Proxy middleware. Only requests with idempotent methods
+	// (GET, HEAD, OPTIONS, TRACE, PUT, DELETE) and without a body (or with replayable body, `Request.GetBody`)
+	// are retried.
+	ProxyRetryConfig struct {
+		// MaxAttempts is the maximum number of attempts including the first one. Values below 2 disable retries.
+		// Optional. Default value 0.
+		MaxAttempts int
+
+		// Backoff is the delay before each retry.
+		// Optional. Default value 0.
+		Backoff time.Duration
+
+		// RetryOn decides if the result of an attempt should be retried.
+		// Optional. Default value retries transport errors and 502, 503 and 504 responses.
+		RetryOn func(res *http.Response, err error) bool
+
+		// BudgetRatio limits retries and hedged requests to this ratio of original requests, so a failing
+		// target does not receive multiplied load.
+		// Optional. Default value 0.2 (one extra request per five requests).
+		BudgetRatio float64
+
+		// MinRetries is the number of retries and hedged requests always allowed on top of BudgetRatio.
+		// Optional. Default value 10.
+		MinRetries int
+	}
+
+	// ProxyHedgeConfig defines request hedging policy of the Proxy middleware. When the target has not
+	// responded within Delay, a duplicate request is sent and the first successful response is used.
+	// Hedging applies to the same requests as retries and consumes the retry budget.
+	ProxyHedgeConfig struct {
+		// Delay after which a duplicate request is sent. Zero disables hedging.
+		// Optional. Default value 0.
+		Delay time.Duration
+
+		// MaxHedges is the maximum number of duplicate requests sent for a request.
+		// Optional. Default value 1.
+		MaxHedges int
+	}
+
+	proxyRetryTransport struct {
+		next   http.RoundTripper
+		retry  ProxyRetryConfig
+		hedge  ProxyHedgeConfig
+		budget *retryBudget
+	}
+
+	// retryBudget is a token bucket where every original request deposits `ratio` tokens and every retry or
+	// hedged request withdraws one.
+	retryBudget struct {
+		mutex  sync.Mutex
+		ratio  float64
+		tokens float64
+		max    float64
+	}
+
+	hedgeResult struct {
+		index  int
+		res    *http.Response
+		err    error
+		cancel context.CancelFunc
+	}
+
+	// cancelOnCloseBody cancels context of the request when response body is closed.
+	cancelOnCloseBody struct {
+		io.ReadCloser
+		cancel context.CancelFunc
+	}
+)
+
+var (
+	// DefaultProxyRetryConfig is the default retry policy of the Proxy middleware.
+	DefaultProxyRetryConfig = ProxyRetryConfig{
+		RetryOn:     defaultProxyRetryOn,
+		BudgetRatio: 0.2,
+		MinRetries:  10,
+	}
+)
+
+func defaultProxyRetryOn(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func newProxyRetryTransport(next http.RoundTripper, retry ProxyRetryConfig, hedge ProxyHedgeConfig) *proxyRetryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if retry.RetryOn == nil {
+		retry.RetryOn = DefaultProxyRetryConfig.RetryOn
+	}
+	if retry.BudgetRatio <= 0 {
+		retry.BudgetRatio = DefaultProxyRetryConfig.BudgetRatio
+	}
+	if retry.MinRetries <= 0 {
+		retry.MinRetries = DefaultProxyRetryConfig.MinRetries
+	}
+	if hedge.MaxHedges <= 0 {
+		hedge.MaxHedges = 1
+	}
+	return &proxyRetryTransport{
+		next:  next,
+		retry: retry,
+		hedge: hedge,
+		budget: &retryBudget{
+			ratio:  retry.BudgetRatio,
+			tokens: float64(retry.MinRetries),
+			max:    float64(retry.MinRetries),
+		},
+	}
+}
+
+func (b *retryBudget) deposit() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+func (b *retryBudget) withdraw() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// isReplayableRequest checks if request can be safely sent to the target more than once.
+func isReplayableRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
+
+// RoundTrip sends the request to the target, retrying and hedging it according to the policy.
+func (t *proxyRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReplayableRequest(req) {
+		return t.next.RoundTrip(req)
+	}
+	t.budget.deposit()
+
+	for attempt := 1; ; attempt++ {
+		res, err := t.roundTripHedged(req)
+		if attempt >= t.retry.MaxAttempts || req.Context().Err() != nil || !t.retry.RetryOn(res, err) || !t.budget.withdraw() {
+			return res, err
+		}
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+		if t.retry.Backoff > 0 {
+			timer := time.NewTimer(t.retry.Backoff)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func (t *proxyRetryTransport) roundTripHedged(req *http.Request) (*http.Response, error) {
+	if t.hedge.Delay <= 0 {
+		return t.send(req)
+	}
+
+	results := make(chan hedgeResult, t.hedge.MaxHedges+1)
+	var cancels []context.CancelFunc
+	launch := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		r, err := t.cloneRequest(ctx, req)
+		if err != nil {
+			results <- hedgeResult{index: index, err: err, cancel: cancel}
+			return
+		}
+		go func() {
+			res, err := t.next.RoundTrip(r)
+			results <- hedgeResult{index: index, res: res, err: err, cancel: cancel}
+		}()
+	}
+
+	launch()
+	inFlight, hedges := 1, 0
+	timer := time.NewTimer(t.hedge.Delay)
+	defer timer.Stop()
+	var last hedgeResult
+	for {
+		select {
+		case <-timer.C:
+			if hedges < t.hedge.MaxHedges && t.budget.withdraw() {
+				hedges++
+				inFlight++
+				launch()
+				timer.Reset(t.hedge.Delay)
+			}
+			continue
+		case last = <-results:
+			inFlight--
+		}
+		if (last.err == nil && !t.retry.RetryOn(last.res, nil)) || inFlight == 0 {
+			break
+		}
+		// failed attempt - wait for the hedged ones still in flight
+		if last.res != nil {
+			last.res.Body.Close()
+		}
+		last.cancel()
+	}
+
+	// cancel the losing requests and discard their responses
+	for i, cancel := range cancels {
+		if i != last.index {
+			cancel()
+		}
+	}
+	for i := 0; i < inFlight; i++ {
+		go func() {
+			r := <-results
+			if r.res != nil {
+				r.res.Body.Close()
+			}
+		}()
+	}
+
+	winner := last
+	if winner.err != nil {
+		winner.cancel()
+		return nil, winner.err
+	}
+	winner.res.Body = &cancelOnCloseBody{ReadCloser: winner.res.Body, cancel: winner.cancel}
+	return winner.res, nil
+}
+
+func (t *proxyRetryTransport) send(req *http.Request) (*http.Response, error) {
+	r, err := t.cloneRequest(req.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(r)
+}
+
+// cloneRequest returns a copy of the request with a fresh body so it can be sent again.
+func (t *proxyRetryTransport) cloneRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	r := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyRetry(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		method         string
+		body           string
+		failures       int32
+		retry          ProxyRetryConfig
+		expectCode     int
+		expectAttempts int32
+	}{
+		{
+			name:           "ok, retries until success",
+			method:         http.MethodGet,
+			failures:       2,
+			retry:          ProxyRetryConfig{MaxAttempts: 3},
+			expectCode:     http.StatusOK,
+			expectAttempts: 3,
+		},
+		{
+			name:           "ok, returns last failure when attempts are exhausted",
+			method:         http.MethodGet,
+			failures:       5,
+			retry:          ProxyRetryConfig{MaxAttempts: 2},
+			expectCode:     http.StatusServiceUnavailable,
+			expectAttempts: 2,
+		},
+		{
+			name:           "ok, non idempotent method is not retried",
+			method:         http.MethodPost,
+			failures:       1,
+			retry:          ProxyRetryConfig{MaxAttempts: 3},
+			expectCode:     http.StatusServiceUnavailable,
+			expectAttempts: 1,
+		},
+		{
+			name:           "ok, request with body is not retried",
+			method:         http.MethodPut,
+			body:           "data",
+			failures:       1,
+			retry:          ProxyRetryConfig{MaxAttempts: 3},
+			expectCode:     http.StatusServiceUnavailable,
+			expectAttempts: 1,
+		},
+		{
+			name:     "ok, custom RetryOn",
+			method:   http.MethodGet,
+			failures: 1,
+			retry: ProxyRetryConfig{
+				MaxAttempts: 3,
+				RetryOn:     func(res *http.Response, err error) bool { return err != nil },
+			},
+			expectCode:     http.StatusServiceUnavailable,
+			expectAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) <= tc.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte("ok"))
+			}))
+			defer upstream.Close()
+			u, _ := url.Parse(upstream.URL)
+
+			e := echo.New()
+			e.Use(ProxyWithConfig(ProxyConfig{
+				Balancer: NewRoundRobinBalancer([]*ProxyTarget{{URL: u}}),
+				Retry:    tc.retry,
+			}))
+
+			var req *http.Request
+			if tc.body != "" {
+				req = httptest.NewRequest(tc.method, "/", strings.NewReader(tc.body))
+			} else {
+				req = httptest.NewRequest(tc.method, "/", nil)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestProxyRetryBudget(t *testing.T) {
+	var attempts int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+
+	e := echo.New()
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{URL: u}}),
+		Retry:    ProxyRetryConfig{MaxAttempts: 5, MinRetries: 2, BudgetRatio: 0.1},
+	}))
+
+	for i := 0; i < 3; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	// 3 original requests + 2 retries allowed by budget
+	assert.Equal(t, int32(5), atomic.LoadInt32(&attempts))
+}
+
+func TestProxyHedge(t *testing.T) {
+	var attempts int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// first request is slow, hedged one must win
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			w.Write([]byte("slow"))
+			return
+		}
+		w.Write([]byte("fast"))
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+
+	e := echo.New()
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{URL: u}}),
+		Hedge:    ProxyHedgeConfig{Delay: 20 * time.Millisecond},
+	}))
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "fast", rec.Body.String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestProxyHedge_notNeeded(t *testing.T) {
+	var attempts int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+
+	e := echo.New()
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{URL: u}}),
+		Hedge:    ProxyHedgeConfig{Delay: time.Second},
+	}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "ok", rec.Body.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
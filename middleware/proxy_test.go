@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -375,3 +376,104 @@ func TestClientCancelConnectionResultsHTTPCode499(t *testing.T) {
 	timeoutStop.Done()
 	assert.Equal(t, 499, rec.Code)
 }
+
+func TestProxyUpgradeHalfClose(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		br := bufio.NewReader(conn)
+		if _, err := http.ReadRequest(br); err != nil {
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n"))
+		// read until client half-closes its side, then respond
+		data, _ := ioutil.ReadAll(br)
+		conn.Write(append([]byte("got:"), data...))
+	}()
+	targetURL, _ := url.Parse("http://" + ln.Addr().String())
+
+	events := make(chan string, 2)
+	e := echo.New()
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{Name: "ws", URL: targetURL}}),
+		OnConnect: func(c echo.Context, target *ProxyTarget) {
+			events <- "connect " + target.Name
+		},
+		OnDisconnect: func(c echo.Context, target *ProxyTarget, err error) {
+			events <- fmt.Sprintf("disconnect %s %v", target.Name, err)
+		},
+	}))
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n"))
+	conn.Write([]byte("hello"))
+	assert.NoError(t, conn.(*net.TCPConn).CloseWrite())
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	res, err := ioutil.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Contains(t, string(res), "HTTP/1.1 101 Switching Protocols")
+	assert.True(t, bytes.HasSuffix(res, []byte("\r\n\r\ngot:hello")))
+
+	assert.Equal(t, "connect ws", <-events)
+	assert.Equal(t, "disconnect ws <nil>", <-events)
+}
+
+func TestProxyEventStream(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(echo.HeaderContentType, "text/event-stream")
+		w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		w.Write([]byte("data: 2\n\n"))
+	}))
+	defer target.Close()
+	targetURL, _ := url.Parse(target.URL)
+
+	var connects, disconnects int
+	e := echo.New()
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer:     NewRoundRobinBalancer([]*ProxyTarget{{URL: targetURL}}),
+		OnConnect:    func(c echo.Context, target *ProxyTarget) { connects++ },
+		OnDisconnect: func(c echo.Context, target *ProxyTarget, err error) { disconnects++ },
+	}))
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/events", nil)
+	req.Header.Set(echo.HeaderAccept, "text/event-stream")
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		close(release)
+		return
+	}
+	defer res.Body.Close()
+
+	// first event must arrive while target is still holding the stream open
+	br := bufio.NewReader(res.Body)
+	line, err := br.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data: 1\n", line)
+
+	close(release)
+	rest, err := ioutil.ReadAll(br)
+	assert.NoError(t, err)
+	assert.Equal(t, "\ndata: 2\n\n", string(rest))
+	assert.Equal(t, 1, connects)
+	assert.Equal(t, 1, disconnects)
+}
@@ -45,7 +45,8 @@ var (
 )
 
 // Recover returns a middleware which recovers from panics anywhere in the chain
-// and handles the control to the centralized HTTPErrorHandler.
+// and handles the control to the centralized HTTPErrorHandler. Panics are reported to `Echo#ErrorReporter`
+// even when custom HTTPErrorHandler does not report errors.
 func Recover() echo.MiddlewareFunc {
 	return RecoverWithConfig(DefaultRecoverConfig)
 }
@@ -75,6 +76,7 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 					}
 					stack := make([]byte, config.StackSize)
 					length := runtime.Stack(stack, !config.DisableStackAll)
+					err = &panicError{err: err, stack: stack[:length]}
 					if !config.DisablePrintStack {
 						msg := fmt.Sprintf("[PANIC RECOVER] %v %s\n", err, stack[:length])
 						switch config.LogLevel {
@@ -92,6 +94,7 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 							c.Logger().Print(msg)
 						}
 					}
+					c.Echo().ReportError(c, err)
 					c.Error(err)
 				}
 			}()
@@ -99,3 +102,22 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// panicError is error recovered from panic with stack trace of the panic.
+type panicError struct {
+	err   error
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return e.err.Error()
+}
+
+func (e *panicError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace implements `echo.StackTracer`.
+func (e *panicError) StackTrace() []byte {
+	return e.stack
+}
@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -27,6 +29,68 @@ func TestRecover(t *testing.T) {
 	assert.Contains(t, buf.String(), "PANIC RECOVER")
 }
 
+func TestRecover_stackTrace(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	var handled error
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handled = err
+	}
+	h := RecoverWithConfig(RecoverConfig{DisablePrintStack: true})(func(c echo.Context) error {
+		panic(io.EOF)
+	})
+	h(c)
+
+	assert.True(t, errors.Is(handled, io.EOF))
+	var st echo.StackTracer
+	if assert.True(t, errors.As(handled, &st)) {
+		assert.Contains(t, string(st.StackTrace()), "goroutine")
+	}
+}
+
+func TestRecover_errorReporter(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		errorHandler echo.HTTPErrorHandler
+	}{
+		{
+			name: "ok, default error handler",
+		},
+		{
+			name:         "ok, custom error handler not reporting errors",
+			errorHandler: func(err error, c echo.Context) {},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var reported []error
+			var stack string
+			e := echo.New()
+			if tc.errorHandler != nil {
+				e.HTTPErrorHandler = tc.errorHandler
+			}
+			e.ErrorReporter = echo.ErrorReporterFunc(func(c echo.Context, err error, s []byte) {
+				reported = append(reported, err)
+				stack = string(s)
+			})
+			e.Use(RecoverWithConfig(RecoverConfig{DisablePrintStack: true}))
+			e.GET("/", func(c echo.Context) error {
+				panic(io.EOF)
+			})
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if assert.Len(t, reported, 1) {
+				assert.True(t, errors.Is(reported[0], io.EOF))
+				assert.Contains(t, stack, "goroutine")
+			}
+		})
+	}
+}
+
 func TestRecoverWithConfig_LogLevel(t *testing.T) {
 	tests := []struct {
 		logLevel  log.Lvl
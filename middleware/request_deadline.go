@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// RequestDeadlineConfig defines the config for RequestDeadline middleware.
+	RequestDeadlineConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Headers are request headers timeout is read from. First header with valid value is used. Value is number
+		// of seconds (i.e. "2.5") or duration (i.e. "2500ms").
+		// Optional. Default value []string{"Request-Timeout", "X-Request-Timeout"}.
+		Headers []string
+
+		// MaxTimeout caps timeout requested by client.
+		// Optional. Default value 30 seconds.
+		MaxTimeout time.Duration
+
+		// DefaultTimeout is used when request has no timeout header or its value is not valid.
+		// Optional. Default value 0 (no deadline).
+		DefaultTimeout time.Duration
+	}
+)
+
+var (
+	// DefaultRequestDeadlineConfig is the default RequestDeadline middleware config.
+	DefaultRequestDeadlineConfig = RequestDeadlineConfig{
+		Skipper:    DefaultSkipper,
+		Headers:    []string{echo.HeaderRequestTimeout, echo.HeaderXRequestTimeout},
+		MaxTimeout: 30 * time.Second,
+	}
+)
+
+// RequestDeadline returns a middleware that sets request context deadline from timeout requested by client with
+// `Request-Timeout` or `X-Request-Timeout` header so handlers (and services they call with request context) can
+// stop work client is not going to wait for. Unlike `Timeout` middleware it does not respond on its own, handlers
+// cooperate by checking request context.
+func RequestDeadline() echo.MiddlewareFunc {
+	return RequestDeadlineWithConfig(DefaultRequestDeadlineConfig)
+}
+
+// RequestDeadlineWithConfig returns a RequestDeadline middleware with config.
+// See: `RequestDeadline()`.
+func RequestDeadlineWithConfig(config RequestDeadlineConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestDeadlineConfig.Skipper
+	}
+	if len(config.Headers) == 0 {
+		config.Headers = DefaultRequestDeadlineConfig.Headers
+	}
+	if config.MaxTimeout == 0 {
+		config.MaxTimeout = DefaultRequestDeadlineConfig.MaxTimeout
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			timeout := config.DefaultTimeout
+			for _, h := range config.Headers {
+				if d, ok := parseRequestTimeout(c.Request().Header.Get(h)); ok {
+					timeout = d
+					break
+				}
+			}
+			if timeout <= 0 {
+				return next(c)
+			}
+			if timeout > config.MaxTimeout {
+				timeout = config.MaxTimeout
+			}
+
+			req := c.Request()
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+func parseRequestTimeout(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 || seconds > float64(1<<62)/float64(time.Second) {
+			return 0, false
+		}
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
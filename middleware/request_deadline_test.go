@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDeadlineWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenConfig    RequestDeadlineConfig
+		whenHeaders    map[string]string
+		expectDeadline time.Duration
+	}{
+		{
+			name:           "ok, seconds",
+			whenHeaders:    map[string]string{echo.HeaderRequestTimeout: "2.5"},
+			expectDeadline: 2500 * time.Millisecond,
+		},
+		{
+			name:           "ok, duration from X- header",
+			whenHeaders:    map[string]string{echo.HeaderXRequestTimeout: "750ms"},
+			expectDeadline: 750 * time.Millisecond,
+		},
+		{
+			name:           "ok, capped by max timeout",
+			givenConfig:    RequestDeadlineConfig{MaxTimeout: time.Second},
+			whenHeaders:    map[string]string{echo.HeaderRequestTimeout: "60"},
+			expectDeadline: time.Second,
+		},
+		{
+			name:           "ok, invalid value falls back to next header",
+			whenHeaders:    map[string]string{echo.HeaderRequestTimeout: "soon", echo.HeaderXRequestTimeout: "1"},
+			expectDeadline: time.Second,
+		},
+		{
+			name:           "ok, default timeout",
+			givenConfig:    RequestDeadlineConfig{DefaultTimeout: 3 * time.Second},
+			whenHeaders:    map[string]string{echo.HeaderRequestTimeout: "-1"},
+			expectDeadline: 3 * time.Second,
+		},
+		{
+			name:        "ok, no header no deadline",
+			whenHeaders: map[string]string{},
+		},
+		{
+			name:           "ok, custom header",
+			givenConfig:    RequestDeadlineConfig{Headers: []string{"Grpc-Timeout-Seconds"}},
+			whenHeaders:    map[string]string{"Grpc-Timeout-Seconds": "4", echo.HeaderRequestTimeout: "1"},
+			expectDeadline: 4 * time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			var deadline time.Time
+			var hasDeadline bool
+			start := time.Now()
+			err := RequestDeadlineWithConfig(tc.givenConfig)(func(c echo.Context) error {
+				deadline, hasDeadline = c.Request().Context().Deadline()
+				return nil
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectDeadline != 0, hasDeadline)
+			if tc.expectDeadline != 0 {
+				assert.WithinDuration(t, start.Add(tc.expectDeadline), deadline, 100*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestRequestDeadline_cancelsContext(t *testing.T) {
+	e := echo.New()
+	e.Use(RequestDeadline())
+	e.GET("/", func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+			return c.String(http.StatusGatewayTimeout, c.Request().Context().Err().Error())
+		case <-time.After(time.Second):
+			return c.String(http.StatusOK, "done")
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderRequestTimeout, "10ms")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, "context deadline exceeded", rec.Body.String())
+}
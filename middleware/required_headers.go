@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// RequiredHeadersConfig defines the config for RequiredHeaders middleware.
+	RequiredHeadersConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Headers are required for all requests in addition to headers required by route metadata
+		// `echo.RouteMetaRequiredHeaders`.
+		// Optional.
+		Headers []string
+
+		// Message is the error message returned along with list of missing headers.
+		// Optional. Default value "missing required headers".
+		Message string
+	}
+
+	// MissingHeaders is the message of 400 error returned by RequiredHeaders middleware.
+	MissingHeaders struct {
+		Message string   `json:"message"`
+		Headers []string `json:"missing_headers"`
+	}
+)
+
+var (
+	// DefaultRequiredHeadersConfig is the default RequiredHeaders middleware config.
+	DefaultRequiredHeadersConfig = RequiredHeadersConfig{
+		Skipper: DefaultSkipper,
+		Message: "missing required headers",
+	}
+)
+
+// RequiredHeaders returns a middleware that enforces request headers declared with route metadata
+// `echo.RouteMetaRequiredHeaders`. Requests missing any of required headers are responded with 400 error listing
+// all missing headers.
+//
+// Example:
+//
+//	e.Use(middleware.RequiredHeaders())
+//	e.RouteMeta(e.POST("/payments", pay))[echo.RouteMetaRequiredHeaders] = []string{"X-Tenant-Id", "Idempotency-Key"}
+func RequiredHeaders() echo.MiddlewareFunc {
+	return RequiredHeadersWithConfig(DefaultRequiredHeadersConfig)
+}
+
+// RequiredHeadersWithConfig returns a RequiredHeaders middleware with config.
+// See: `RequiredHeaders()`.
+func RequiredHeadersWithConfig(config RequiredHeadersConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequiredHeadersConfig.Skipper
+	}
+	if config.Message == "" {
+		config.Message = DefaultRequiredHeadersConfig.Message
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			var missing []string
+			check := func(headers []string) {
+				for _, h := range headers {
+					if c.Request().Header.Get(h) == "" {
+						missing = append(missing, http.CanonicalHeaderKey(h))
+					}
+				}
+			}
+			check(config.Headers)
+			if r := c.Route(); r != nil {
+				routeHeaders, _ := c.Echo().RouteMeta(r)[echo.RouteMetaRequiredHeaders].([]string)
+				check(routeHeaders)
+			}
+			if len(missing) > 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, MissingHeaders{Message: config.Message, Headers: missing})
+			}
+			return next(c)
+		}
+	}
+}
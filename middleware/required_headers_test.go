@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredHeaders(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenConfig RequiredHeadersConfig
+		whenURL     string
+		whenHeaders map[string]string
+		expectCode  int
+		expectBody  string
+	}{
+		{
+			name:        "ok, route headers present",
+			whenURL:     "/payments",
+			whenHeaders: map[string]string{"X-Tenant-Id": "1", "Idempotency-Key": "abc"},
+			expectCode:  http.StatusOK,
+		},
+		{
+			name:        "nok, missing route headers are listed",
+			whenURL:     "/payments",
+			whenHeaders: map[string]string{"X-Tenant-Id": "1"},
+			expectCode:  http.StatusBadRequest,
+			expectBody:  `{"message":"missing required headers","missing_headers":["Idempotency-Key"]}` + "\n",
+		},
+		{
+			name:       "ok, route without metadata",
+			whenURL:    "/other",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:        "nok, config headers are required for all routes",
+			givenConfig: RequiredHeadersConfig{Headers: []string{"x-api-version"}, Message: "bad headers"},
+			whenURL:     "/other",
+			expectCode:  http.StatusBadRequest,
+			expectBody:  `{"message":"bad headers","missing_headers":["X-Api-Version"]}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RequiredHeadersWithConfig(tc.givenConfig))
+			ok := func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			}
+			e.RouteMeta(e.GET("/payments", ok))[echo.RouteMetaRequiredHeaders] = []string{"X-Tenant-Id", "Idempotency-Key"}
+			e.GET("/other", ok)
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectBody != "" {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
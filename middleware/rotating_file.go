@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// RotatingFileConfig defines the config for RotatingFile.
+	RotatingFileConfig struct {
+		// Filename is path of the file logs are written to. Rotated files are renamed to `<Filename>.<timestamp>`.
+		// Required.
+		Filename string
+
+		// MaxSize is size in bytes after which file is rotated.
+		// Optional. Default value 0 (no size based rotation).
+		MaxSize int64
+
+		// Interval is time after which file is rotated (i.e. `24 * time.Hour` for daily log files).
+		// Optional. Default value 0 (no time based rotation).
+		Interval time.Duration
+
+		// MaxBackups is the number of rotated files to keep. Oldest files are removed first.
+		// Optional. Default value 0 (all rotated files are kept).
+		MaxBackups int
+	}
+
+	// RotatingFile is io.WriteCloser writing to a file that is rotated by size and/or time. It is safe for concurrent
+	// use. Each Write is written to single file so log lines are not split between files.
+	RotatingFile struct {
+		config   RotatingFileConfig
+		mu       sync.Mutex
+		file     *os.File
+		size     int64
+		openedAt time.Time
+		now      func() time.Time
+	}
+)
+
+const rotatingFileTimeFormat = "20060102T150405.000000000"
+
+// ErrRotatingFileClosed is returned when writing to closed RotatingFile.
+var ErrRotatingFileClosed = errors.New("rotating file is closed")
+
+// NewRotatingFile opens (or creates) file for appending with rotation by size and/or time.
+func NewRotatingFile(config RotatingFileConfig) (*RotatingFile, error) {
+	if config.Filename == "" {
+		return nil, errors.New("echo: rotating file requires filename")
+	}
+	rf := &RotatingFile{config: config, now: time.Now}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write writes p to the current file. File is rotated before writing when it would exceed `MaxSize` or `Interval`
+// has passed since file was opened.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, ErrRotatingFileClosed
+	}
+	if rf.needsRotation(int64(len(p))) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates file immediately (i.e. on SIGHUP).
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return ErrRotatingFileClosed
+	}
+	return rf.rotate()
+}
+
+// Close closes current file.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+func (rf *RotatingFile) needsRotation(n int64) bool {
+	if rf.config.MaxSize > 0 && rf.size > 0 && rf.size+n > rf.config.MaxSize {
+		return true
+	}
+	return rf.config.Interval > 0 && rf.now().Sub(rf.openedAt) >= rf.config.Interval
+}
+
+func (rf *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(rf.config.Filename), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(rf.config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = fi.Size()
+	rf.openedAt = rf.now()
+	return nil
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	rf.file = nil
+	backup := rf.config.Filename + "." + rf.now().Format(rotatingFileTimeFormat)
+	if err := os.Rename(rf.config.Filename, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+	return rf.removeOldBackups()
+}
+
+func (rf *RotatingFile) removeOldBackups() error {
+	if rf.config.MaxBackups <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(rf.config.Filename + ".*")
+	if err != nil {
+		return err
+	}
+	backups := matches[:0]
+	prefix := rf.config.Filename + "."
+	for _, m := range matches {
+		if _, err := time.Parse(rotatingFileTimeFormat, strings.TrimPrefix(m, prefix)); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	if len(backups) <= rf.config.MaxBackups {
+		return nil
+	}
+	sort.Strings(backups) // timestamp format sorts chronologically
+	for _, b := range backups[:len(backups)-rf.config.MaxBackups] {
+		if err := os.Remove(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readRotatedFiles(t *testing.T, filename string) []string {
+	matches, err := filepath.Glob(filename + ".*")
+	assert.NoError(t, err)
+	sort.Strings(matches)
+	result := make([]string, 0, len(matches))
+	for _, m := range matches {
+		b, err := ioutil.ReadFile(m)
+		assert.NoError(t, err)
+		result = append(result, string(b))
+	}
+	return result
+}
+
+func TestRotatingFile_MaxSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotating_file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "logs", "access.log")
+	rf, err := NewRotatingFile(RotatingFileConfig{Filename: filename, MaxSize: 11})
+	assert.NoError(t, err)
+	defer rf.Close()
+
+	for _, line := range []string{"first\n", "second\n", "3rd\n", "fourth line over limit\n"} {
+		_, err := rf.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+
+	current, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "fourth line over limit\n", string(current))
+	assert.Equal(t, []string{"first\n", "second\n3rd\n"}, readRotatedFiles(t, filename))
+}
+
+func TestRotatingFile_Interval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotating_file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "access.log")
+	rf, err := NewRotatingFile(RotatingFileConfig{Filename: filename, Interval: time.Hour})
+	assert.NoError(t, err)
+	defer rf.Close()
+
+	now := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	rf.now = func() time.Time { return now }
+	rf.openedAt = now
+
+	_, err = rf.Write([]byte("a\n"))
+	assert.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	_, err = rf.Write([]byte("b\n"))
+	assert.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	_, err = rf.Write([]byte("c\n"))
+	assert.NoError(t, err)
+
+	current, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "c\n", string(current))
+	assert.Equal(t, []string{"a\nb\n"}, readRotatedFiles(t, filename))
+}
+
+func TestRotatingFile_MaxBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotating_file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "access.log")
+	rf, err := NewRotatingFile(RotatingFileConfig{Filename: filename, MaxBackups: 2})
+	assert.NoError(t, err)
+	defer rf.Close()
+
+	now := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+	rf.now = func() time.Time { return now }
+	for _, line := range []string{"1\n", "2\n", "3\n", "4\n"} {
+		_, err := rf.Write([]byte(line))
+		assert.NoError(t, err)
+		now = now.Add(time.Second)
+		assert.NoError(t, rf.Rotate())
+	}
+
+	assert.Equal(t, []string{"3\n", "4\n"}, readRotatedFiles(t, filename))
+}
+
+func TestRotatingFile_Closed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotating_file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rf, err := NewRotatingFile(RotatingFileConfig{Filename: filepath.Join(dir, "access.log")})
+	assert.NoError(t, err)
+	assert.NoError(t, rf.Close())
+
+	_, err = rf.Write([]byte("x"))
+	assert.Equal(t, ErrRotatingFileClosed, err)
+	assert.Equal(t, ErrRotatingFileClosed, rf.Rotate())
+}
+
+func TestNewRotatingFile_requiresFilename(t *testing.T) {
+	_, err := NewRotatingFile(RotatingFileConfig{})
+	assert.EqualError(t, err, "echo: rotating file requires filename")
+}
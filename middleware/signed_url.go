@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// SignedURLConfig defines the config for SignedURL middleware.
+	SignedURLConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Key is HMAC key URLs are signed with.
+		// Required.
+		Key []byte
+	}
+)
+
+var (
+	// DefaultSignedURLConfig is the default SignedURL middleware config.
+	DefaultSignedURLConfig = SignedURLConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// SignedURL returns a middleware that allows only requests to URLs signed with `echo.SignURL` using the same key.
+// Requests with missing or invalid signature, expired URL or method/IP not matching URL claims are responded with
+// 403 Forbidden. IP claim is checked against `Context#RealIP`.
+//
+// Example:
+//
+//	e.GET("/files/:id", download, middleware.SignedURL(key)).Name = "download"
+//	...
+//	link, err := e.SignedReverse(key, echo.SignedURLClaims{Expires: time.Now().Add(time.Hour)}, "download", id)
+func SignedURL(key []byte) echo.MiddlewareFunc {
+	c := DefaultSignedURLConfig
+	c.Key = key
+	return SignedURLWithConfig(c)
+}
+
+// SignedURLWithConfig returns a SignedURL middleware with config.
+// See: `SignedURL()`.
+func SignedURLWithConfig(config SignedURLConfig) echo.MiddlewareFunc {
+	// Defaults
+	if len(config.Key) == 0 {
+		panic("echo: signed url middleware requires key")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultSignedURLConfig.Skipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			err := echo.VerifySignedURL(c.Request().URL, config.Key, c.Request().Method, c.RealIP())
+			if err != nil {
+				message := http.StatusText(http.StatusForbidden)
+				if errors.Is(err, echo.ErrSignedURLExpired) {
+					message = err.Error()
+				}
+				return &echo.HTTPError{
+					Code:     http.StatusForbidden,
+					Message:  message,
+					Internal: err,
+				}
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedURL(t *testing.T) {
+	key := []byte("secret")
+	valid, err := echo.SignURL("/files/1", key, echo.SignedURLClaims{Expires: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	expired, err := echo.SignURL("/files/1", key, echo.SignedURLClaims{Expires: time.Now().Add(-time.Hour)})
+	assert.NoError(t, err)
+	postOnly, err := echo.SignURL("/files/1", key, echo.SignedURLClaims{Expires: time.Now().Add(time.Hour), Method: http.MethodPost})
+	assert.NoError(t, err)
+
+	var testCases = []struct {
+		name        string
+		whenURL     string
+		expectCode  int
+		expectError string
+	}{
+		{
+			name:       "ok",
+			whenURL:    valid,
+			expectCode: http.StatusOK,
+		},
+		{
+			name:        "nok, unsigned",
+			whenURL:     "/files/1",
+			expectError: "code=403, message=Forbidden, internal=invalid url signature",
+		},
+		{
+			name:        "nok, expired",
+			whenURL:     expired,
+			expectError: "code=403, message=signed url has expired, internal=signed url has expired",
+		},
+		{
+			name:        "nok, method claim",
+			whenURL:     postOnly,
+			expectError: "code=403, message=Forbidden, internal=signed url claims do not match request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := SignedURL(key)(func(c echo.Context) error {
+				return c.String(http.StatusOK, "file")
+			})
+
+			err := h(c)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestSignedURLWithConfig_panicsWithoutKey(t *testing.T) {
+	assert.Panics(t, func() {
+		SignedURLWithConfig(SignedURLConfig{})
+	})
+}
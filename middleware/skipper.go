@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SkipPaths returns Skipper that skips requests with URL path equal to one of given paths.
+//
+// Example: `middleware.LoggerWithConfig(middleware.LoggerConfig{Skipper: middleware.SkipPaths("/health", "/metrics")})`
+func SkipPaths(paths ...string) Skipper {
+	set := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		set[p] = struct{}{}
+	}
+	return func(c echo.Context) bool {
+		_, ok := set[c.Request().URL.Path]
+		return ok
+	}
+}
+
+// SkipPathPrefixes returns Skipper that skips requests with URL path starting with one of given prefixes.
+// Prefix is matched as string so "/static" matches also "/static-v2/app.js" - use "/static/" to match only directory.
+func SkipPathPrefixes(prefixes ...string) Skipper {
+	return func(c echo.Context) bool {
+		p := c.Request().URL.Path
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(p, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipPathGlobs returns Skipper that skips requests with URL path matching one of given glob patterns
+// (see `path.Match`, i.e. "/assets/*.js" or "/api/*/health"). Panics when pattern is malformed.
+func SkipPathGlobs(patterns ...string) Skipper {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			panic("echo: invalid skipper glob pattern: " + p)
+		}
+	}
+	return func(c echo.Context) bool {
+		p := c.Request().URL.Path
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipPathRegexps returns Skipper that skips requests with URL path matching one of given regular expressions.
+// Panics when expression can not be compiled.
+func SkipPathRegexps(exprs ...string) Skipper {
+	regexps := make([]*regexp.Regexp, len(exprs))
+	for i, e := range exprs {
+		regexps[i] = regexp.MustCompile(e)
+	}
+	return func(c echo.Context) bool {
+		p := c.Request().URL.Path
+		for _, re := range regexps {
+			if re.MatchString(p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipMethods returns Skipper that skips requests with one of given HTTP methods (i.e. `http.MethodOptions`).
+func SkipMethods(methods ...string) Skipper {
+	set := make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		set[strings.ToUpper(m)] = struct{}{}
+	}
+	return func(c echo.Context) bool {
+		_, ok := set[c.Request().Method]
+		return ok
+	}
+}
+
+// SkipHeader returns Skipper that skips requests having header with one of given values. When no values are given
+// requests having the header with any value are skipped.
+//
+// Example: `middleware.SkipHeader(echo.HeaderUpgrade, "websocket")`
+func SkipHeader(header string, values ...string) Skipper {
+	header = http.CanonicalHeaderKey(header)
+	return func(c echo.Context) bool {
+		actual, ok := c.Request().Header[header]
+		if !ok {
+			return false
+		}
+		if len(values) == 0 {
+			return true
+		}
+		for _, a := range actual {
+			for _, v := range values {
+				if a == v {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// SkipAny returns Skipper that skips requests skipped by any of given skippers.
+//
+// Example: `middleware.SkipAny(middleware.SkipPaths("/health"), middleware.SkipMethods(http.MethodOptions))`
+func SkipAny(skippers ...Skipper) Skipper {
+	return func(c echo.Context) bool {
+		for _, s := range skippers {
+			if s(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipAll returns Skipper that skips requests skipped by all given skippers.
+//
+// Example: `middleware.SkipAll(middleware.SkipPathPrefixes("/static/"), middleware.SkipMethods(http.MethodGet))`
+func SkipAll(skippers ...Skipper) Skipper {
+	return func(c echo.Context) bool {
+		for _, s := range skippers {
+			if !s(c) {
+				return false
+			}
+		}
+		return len(skippers) > 0
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkippers(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		skipper     Skipper
+		whenMethod  string
+		whenURL     string
+		whenHeaders map[string]string
+		expect      bool
+	}{
+		{name: "paths, match", skipper: SkipPaths("/health", "/metrics"), whenURL: "/metrics?x=1", expect: true},
+		{name: "paths, no match on sub path", skipper: SkipPaths("/health"), whenURL: "/health/db", expect: false},
+		{name: "prefixes, match", skipper: SkipPathPrefixes("/static/", "/assets/"), whenURL: "/assets/app.js", expect: true},
+		{name: "prefixes, no match", skipper: SkipPathPrefixes("/static/"), whenURL: "/static", expect: false},
+		{name: "globs, match", skipper: SkipPathGlobs("/api/*/health"), whenURL: "/api/v1/health", expect: true},
+		{name: "globs, star does not match slash", skipper: SkipPathGlobs("/assets/*.js"), whenURL: "/assets/js/app.js", expect: false},
+		{name: "regexps, match", skipper: SkipPathRegexps(`^/users/\d+$`), whenURL: "/users/42", expect: true},
+		{name: "regexps, no match", skipper: SkipPathRegexps(`^/users/\d+$`), whenURL: "/users/me", expect: false},
+		{name: "methods, match", skipper: SkipMethods("options"), whenMethod: http.MethodOptions, expect: true},
+		{name: "methods, no match", skipper: SkipMethods(http.MethodOptions), whenMethod: http.MethodGet, expect: false},
+		{
+			name:        "header, any value",
+			skipper:     SkipHeader("x-internal"),
+			whenHeaders: map[string]string{"X-Internal": "1"},
+			expect:      true,
+		},
+		{
+			name:        "header, value match",
+			skipper:     SkipHeader(echo.HeaderUpgrade, "websocket"),
+			whenHeaders: map[string]string{echo.HeaderUpgrade: "websocket"},
+			expect:      true,
+		},
+		{
+			name:        "header, value does not match",
+			skipper:     SkipHeader(echo.HeaderUpgrade, "websocket"),
+			whenHeaders: map[string]string{echo.HeaderUpgrade: "h2c"},
+			expect:      false,
+		},
+		{name: "header, missing", skipper: SkipHeader("X-Internal"), expect: false},
+		{
+			name:    "any, one matches",
+			skipper: SkipAny(SkipPaths("/health"), SkipMethods(http.MethodOptions)),
+			whenURL: "/health",
+			expect:  true,
+		},
+		{name: "any, empty", skipper: SkipAny(), expect: false},
+		{
+			name:       "all, all match",
+			skipper:    SkipAll(SkipPathPrefixes("/static/"), SkipMethods(http.MethodGet)),
+			whenMethod: http.MethodGet,
+			whenURL:    "/static/app.css",
+			expect:     true,
+		},
+		{
+			name:       "all, one does not match",
+			skipper:    SkipAll(SkipPathPrefixes("/static/"), SkipMethods(http.MethodGet)),
+			whenMethod: http.MethodPost,
+			whenURL:    "/static/app.css",
+			expect:     false,
+		},
+		{name: "all, empty", skipper: SkipAll(), expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.whenMethod
+			if method == "" {
+				method = http.MethodGet
+			}
+			url := tc.whenURL
+			if url == "" {
+				url = "/"
+			}
+			req := httptest.NewRequest(method, url, nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			assert.Equal(t, tc.expect, tc.skipper(c))
+		})
+	}
+}
+
+func TestSkipPathGlobs_panicsOnInvalidPattern(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: invalid skipper glob pattern: /[", func() {
+		SkipPathGlobs("/[")
+	})
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Tenant is tenant resolved for request by Tenancy middleware.
+	Tenant struct {
+		// ID identifies tenant.
+		ID string
+
+		// Renderer renders templates of tenant. Used by `TenantRenderer` when set.
+		Renderer echo.Renderer
+
+		// Filesystem is filesystem with tenant assets. Use `http.FS(fsys)` to serve `fs.FS`.
+		Filesystem http.FileSystem
+
+		// Data is application specific tenant data (i.e. configuration, database handle).
+		Data interface{}
+	}
+
+	// TenantResolver resolves tenant of request. Resolver returns nil tenant when tenant is unknown.
+	TenantResolver func(c echo.Context) (*Tenant, error)
+
+	// TenantLookup returns tenant by its identifier or nil when tenant with given identifier does not exist.
+	TenantLookup func(id string) (*Tenant, error)
+
+	// TenancyConfig defines the config for Tenancy middleware.
+	TenancyConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Resolver resolves tenant of request.
+		// Required.
+		Resolver TenantResolver
+
+		// ContextKey is the key used to store resolved tenant (`*Tenant`) in context.
+		// Optional. Default value "tenant".
+		ContextKey string
+	}
+)
+
+var (
+	// DefaultTenancyConfig is the default Tenancy middleware config.
+	DefaultTenancyConfig = TenancyConfig{
+		Skipper:    DefaultSkipper,
+		ContextKey: "tenant",
+	}
+)
+
+// Tenancy returns a Tenancy middleware that resolves tenant of request with resolver and stores it in context. Requests
+// of unknown tenants are responded with 404 Not Found. Resolved tenant is available with `TenantFromContext`.
+//
+// Example:
+//
+//	e.Use(middleware.Tenancy(middleware.HostTenantResolver(middleware.StaticTenants(map[string]*middleware.Tenant{
+//		"acme": {ID: "acme", Renderer: acmeTemplates},
+//	}))))
+//	e.Renderer = middleware.TenantRenderer(defaultTemplates)
+func Tenancy(resolver TenantResolver) echo.MiddlewareFunc {
+	c := DefaultTenancyConfig
+	c.Resolver = resolver
+	return TenancyWithConfig(c)
+}
+
+// TenancyWithConfig returns a Tenancy middleware with config.
+// See: `Tenancy()`.
+func TenancyWithConfig(config TenancyConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Resolver == nil {
+		panic("echo: tenancy middleware requires resolver")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultTenancyConfig.Skipper
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultTenancyConfig.ContextKey
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			tenant, err := config.Resolver(c)
+			if err != nil {
+				return err
+			}
+			if tenant == nil {
+				return echo.ErrNotFound
+			}
+			c.Set(config.ContextKey, tenant)
+			c.Set(tenantContextKey, tenant)
+			return next(c)
+		}
+	}
+}
+
+// tenantContextKey is the context key `TenantFromContext` and `TenantRenderer` use regardless of configured key.
+const tenantContextKey = "echo.tenant"
+
+// TenantFromContext returns tenant resolved by Tenancy middleware or nil.
+func TenantFromContext(c echo.Context) *Tenant {
+	t, _ := c.Get(tenantContextKey).(*Tenant)
+	return t
+}
+
+// StaticTenants returns lookup of tenants from map.
+func StaticTenants(tenants map[string]*Tenant) TenantLookup {
+	return func(id string) (*Tenant, error) {
+		return tenants[id], nil
+	}
+}
+
+// HostTenantResolver returns resolver that uses first label of request host as tenant identifier, i.e. "acme" for
+// "acme.example.com". Hosts without subdomain belong to unknown tenant.
+func HostTenantResolver(lookup TenantLookup) TenantResolver {
+	return func(c echo.Context) (*Tenant, error) {
+		host := c.Request().Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		i := strings.IndexByte(host, '.')
+		if i <= 0 || net.ParseIP(host) != nil {
+			return nil, nil
+		}
+		return lookupTenant(lookup, strings.ToLower(host[:i]))
+	}
+}
+
+// HeaderTenantResolver returns resolver that uses value of request header (i.e. "X-Tenant-ID") as tenant identifier.
+func HeaderTenantResolver(header string, lookup TenantLookup) TenantResolver {
+	return func(c echo.Context) (*Tenant, error) {
+		return lookupTenant(lookup, c.Request().Header.Get(header))
+	}
+}
+
+// PathTenantResolver returns resolver that uses path parameter (i.e. "tenant" for route "/:tenant/*") as tenant
+// identifier. Tenancy middleware using this resolver must be route or group level middleware as path parameters
+// are not known before routing.
+func PathTenantResolver(param string, lookup TenantLookup) TenantResolver {
+	return func(c echo.Context) (*Tenant, error) {
+		return lookupTenant(lookup, c.Param(param))
+	}
+}
+
+func lookupTenant(lookup TenantLookup, id string) (*Tenant, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return lookup(id)
+}
+
+// TenantRenderer returns renderer that renders templates with Renderer of tenant resolved for request. Fallback is
+// used for requests without tenant or for tenants without Renderer.
+func TenantRenderer(fallback echo.Renderer) echo.Renderer {
+	return tenantRenderer{fallback: fallback}
+}
+
+type tenantRenderer struct {
+	fallback echo.Renderer
+}
+
+func (r tenantRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	if t := TenantFromContext(c); t != nil && t.Renderer != nil {
+		return t.Renderer.Render(w, name, data, c)
+	}
+	if r.fallback == nil {
+		return errors.New("echo: tenant has no renderer")
+	}
+	return r.fallback.Render(w, name, data, c)
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type nameRenderer string
+
+func (r nameRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	_, err := w.Write([]byte(string(r) + ":" + name))
+	return err
+}
+
+func TestTenancy(t *testing.T) {
+	tenants := StaticTenants(map[string]*Tenant{
+		"acme":   {ID: "acme", Renderer: nameRenderer("acme")},
+		"globex": {ID: "globex"},
+	})
+
+	var testCases = []struct {
+		name          string
+		givenResolver TenantResolver
+		whenHost      string
+		whenHeader    string
+		expectCode    int
+		expectBody    string
+	}{
+		{
+			name:          "ok, host with tenant renderer",
+			givenResolver: HostTenantResolver(tenants),
+			whenHost:      "acme.example.com:8080",
+			expectCode:    http.StatusOK,
+			expectBody:    "acme:index",
+		},
+		{
+			name:          "ok, tenant without renderer uses fallback",
+			givenResolver: HostTenantResolver(tenants),
+			whenHost:      "globex.example.com",
+			expectCode:    http.StatusOK,
+			expectBody:    "default:index",
+		},
+		{
+			name:          "nok, unknown tenant",
+			givenResolver: HostTenantResolver(tenants),
+			whenHost:      "initech.example.com",
+			expectCode:    http.StatusNotFound,
+		},
+		{
+			name:          "nok, host without subdomain",
+			givenResolver: HostTenantResolver(tenants),
+			whenHost:      "localhost",
+			expectCode:    http.StatusNotFound,
+		},
+		{
+			name:          "ok, header",
+			givenResolver: HeaderTenantResolver("X-Tenant-ID", tenants),
+			whenHeader:    "acme",
+			expectCode:    http.StatusOK,
+			expectBody:    "acme:index",
+		},
+		{
+			name: "nok, resolver error",
+			givenResolver: func(c echo.Context) (*Tenant, error) {
+				return nil, echo.NewHTTPError(http.StatusServiceUnavailable)
+			},
+			expectCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Renderer = TenantRenderer(nameRenderer("default"))
+			e.Use(Tenancy(tc.givenResolver))
+			e.GET("/", func(c echo.Context) error {
+				assert.Equal(t, TenantFromContext(c), c.Get("tenant"))
+				return c.Render(http.StatusOK, "index", nil)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tc.whenHost
+			if tc.whenHeader != "" {
+				req.Header.Set("X-Tenant-ID", tc.whenHeader)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectBody != "" {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestTenancy_PathResolver(t *testing.T) {
+	e := echo.New()
+	lookup := func(id string) (*Tenant, error) {
+		if id == "broken" {
+			return nil, errors.New("lookup failed")
+		}
+		return &Tenant{ID: id}, nil
+	}
+	g := e.Group("/:tenant", Tenancy(PathTenantResolver("tenant", lookup)))
+	g.GET("/users", func(c echo.Context) error {
+		return c.String(http.StatusOK, TenantFromContext(c).ID)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/acme/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "acme", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/broken/users", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestTenantRenderer_NoFallback(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	err := TenantRenderer(nil).Render(ioutil.Discard, "index", nil, c)
+	assert.EqualError(t, err, "echo: tenant has no renderer")
+}
+
+func TestTenancyWithConfig_Panics(t *testing.T) {
+	assert.Panics(t, func() {
+		TenancyWithConfig(TenancyConfig{})
+	})
+}
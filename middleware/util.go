@@ -1,9 +1,26 @@
 package middleware
 
 import (
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
 )
 
+// trustedIP returns client IP address for security decisions (filtering, blocking, verification). Proxy headers are
+// trusted only when extractor or `Echo#IPExtractor` is configured, otherwise address of direct peer is used as
+// headers like `X-Forwarded-For` can be set by any client.
+func trustedIP(c echo.Context, extractor echo.IPExtractor) string {
+	if extractor == nil {
+		extractor = c.Echo().IPExtractor
+	}
+	if extractor == nil {
+		extractor = echo.ExtractIPDirect()
+	}
+	return extractor(c.Request())
+}
+
 func matchScheme(domain, pattern string) bool {
 	didx := strings.Index(domain, ":")
 	pidx := strings.Index(pattern, ":")
@@ -52,3 +69,8 @@ func matchSubdomain(domain, pattern string) bool {
 	}
 	return false
 }
+
+// retryAfterSeconds formats duration as Retry-After header value rounding up to whole seconds.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int((d + time.Second - 1) / time.Second))
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// WebhookConfig defines the config for Webhook middleware.
+	WebhookConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Secret is shared HMAC key webhook sender signs requests with.
+		// Required.
+		Secret []byte
+
+		// Hash is hash function used for HMAC.
+		// Optional. Default value sha256.New.
+		Hash func() hash.Hash
+
+		// SignatureHeader is name of the request header containing signature.
+		// Optional. Default value "X-Webhook-Signature".
+		SignatureHeader string
+
+		// SignaturePrefix is prefix signature in header is prepended with, i.e. "sha256=".
+		// Optional.
+		SignaturePrefix string
+
+		// SignatureEncoding is encoding of signature in header. Possible values "hex" and "base64".
+		// Optional. Default value "hex".
+		SignatureEncoding string
+
+		// TimestampHeader is name of the request header containing time of signing as unix seconds. When set
+		// signed payload is `<timestamp>.<body>` and requests with timestamp outside Tolerance are rejected to
+		// prevent replay attacks.
+		// Optional.
+		TimestampHeader string
+
+		// Tolerance is maximum difference between timestamp in TimestampHeader and current time.
+		// Optional. Default value 5 minutes.
+		Tolerance time.Duration
+
+		// MaxBodySize is maximum size of request body in bytes that is buffered for verification.
+		// Optional. Default value 1 MB.
+		MaxBodySize int64
+	}
+)
+
+// Webhook signature encodings
+const (
+	WebhookEncodingHex    = "hex"
+	WebhookEncodingBase64 = "base64"
+)
+
+var (
+	// DefaultWebhookConfig is the default Webhook middleware config.
+	DefaultWebhookConfig = WebhookConfig{
+		Skipper:           DefaultSkipper,
+		Hash:              sha256.New,
+		SignatureHeader:   "X-Webhook-Signature",
+		SignatureEncoding: WebhookEncodingHex,
+		Tolerance:         5 * time.Minute,
+		MaxBodySize:       1 << 20,
+	}
+
+	// ErrWebhookSignatureInvalid is error for missing or invalid webhook signature.
+	ErrWebhookSignatureInvalid = errors.New("invalid webhook signature")
+	// ErrWebhookTimestampInvalid is error for missing, malformed or out of tolerance webhook timestamp.
+	ErrWebhookTimestampInvalid = errors.New("invalid webhook timestamp")
+)
+
+// Webhook returns a middleware that verifies HMAC-SHA256 signature of request body sent in "X-Webhook-Signature"
+// header as hex. Requests with missing or invalid signature are responded with 401 Unauthorized before handler is
+// run. Body is buffered for verification and is readable by handler as usual.
+//
+// Example:
+//
+//	e.POST("/hooks/payments", onPayment, middleware.Webhook(secret))
+func Webhook(secret []byte) echo.MiddlewareFunc {
+	c := DefaultWebhookConfig
+	c.Secret = secret
+	return WebhookWithConfig(c)
+}
+
+// WebhookWithConfig returns a Webhook middleware with config.
+// See: `Webhook()`.
+//
+// Example for GitHub webhooks:
+//
+//	middleware.WebhookWithConfig(middleware.WebhookConfig{
+//		Secret:          secret,
+//		SignatureHeader: "X-Hub-Signature-256",
+//		SignaturePrefix: "sha256=",
+//	})
+func WebhookWithConfig(config WebhookConfig) echo.MiddlewareFunc {
+	// Defaults
+	if len(config.Secret) == 0 {
+		panic("echo: webhook middleware requires secret")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultWebhookConfig.Skipper
+	}
+	if config.Hash == nil {
+		config.Hash = DefaultWebhookConfig.Hash
+	}
+	if config.SignatureHeader == "" {
+		config.SignatureHeader = DefaultWebhookConfig.SignatureHeader
+	}
+	if config.SignatureEncoding == "" {
+		config.SignatureEncoding = DefaultWebhookConfig.SignatureEncoding
+	}
+	if config.Tolerance == 0 {
+		config.Tolerance = DefaultWebhookConfig.Tolerance
+	}
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = DefaultWebhookConfig.MaxBodySize
+	}
+
+	var decode func(string) ([]byte, error)
+	switch config.SignatureEncoding {
+	case WebhookEncodingHex:
+		decode = hex.DecodeString
+	case WebhookEncodingBase64:
+		decode = base64.StdEncoding.DecodeString
+	default:
+		panic("echo: webhook middleware has unknown signature encoding: " + config.SignatureEncoding)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			req := c.Request()
+
+			header := req.Header.Get(config.SignatureHeader)
+			if !strings.HasPrefix(header, config.SignaturePrefix) {
+				return webhookError(ErrWebhookSignatureInvalid)
+			}
+			signature, err := decode(header[len(config.SignaturePrefix):])
+			if err != nil || len(signature) == 0 {
+				return webhookError(ErrWebhookSignatureInvalid)
+			}
+
+			mac := hmac.New(config.Hash, config.Secret)
+			if config.TimestampHeader != "" {
+				timestamp := req.Header.Get(config.TimestampHeader)
+				ts, err := strconv.ParseInt(timestamp, 10, 64)
+				if err != nil {
+					return webhookError(ErrWebhookTimestampInvalid)
+				}
+				if d := time.Since(time.Unix(ts, 0)); d > config.Tolerance || d < -config.Tolerance {
+					return webhookError(ErrWebhookTimestampInvalid)
+				}
+				mac.Write([]byte(timestamp))
+				mac.Write([]byte{'.'})
+			}
+
+			body, err := ioutil.ReadAll(io.LimitReader(req.Body, config.MaxBodySize+1))
+			if err != nil {
+				return err
+			}
+			if int64(len(body)) > config.MaxBodySize {
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body)) // Reset
+
+			mac.Write(body)
+			if !hmac.Equal(signature, mac.Sum(nil)) {
+				return webhookError(ErrWebhookSignatureInvalid)
+			}
+			return next(c)
+		}
+	}
+}
+
+func webhookError(err error) *echo.HTTPError {
+	return &echo.HTTPError{
+		Code:     http.StatusUnauthorized,
+		Message:  err.Error(),
+		Internal: err,
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func webhookSignature(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func TestWebhook(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	body := `{"event":"paid"}`
+
+	var testCases = []struct {
+		name          string
+		givenConfig   WebhookConfig
+		whenHeaders   map[string]string
+		whenBody      string
+		expectError   string
+		expectHandler bool
+	}{
+		{
+			name:          "ok, hex signature",
+			givenConfig:   WebhookConfig{Secret: []byte("secret")},
+			whenHeaders:   map[string]string{"X-Webhook-Signature": hex.EncodeToString(webhookSignature("secret", body))},
+			expectHandler: true,
+		},
+		{
+			name: "ok, prefixed base64 signature with timestamp",
+			givenConfig: WebhookConfig{
+				Secret:            []byte("secret"),
+				SignatureHeader:   "X-Signature",
+				SignaturePrefix:   "v1=",
+				SignatureEncoding: WebhookEncodingBase64,
+				TimestampHeader:   "X-Timestamp",
+			},
+			whenHeaders: map[string]string{
+				"X-Signature": "v1=" + base64.StdEncoding.EncodeToString(webhookSignature("secret", now+"."+body)),
+				"X-Timestamp": now,
+			},
+			expectHandler: true,
+		},
+		{
+			name:        "nok, missing signature",
+			givenConfig: WebhookConfig{Secret: []byte("secret")},
+			expectError: "code=401, message=invalid webhook signature, internal=invalid webhook signature",
+		},
+		{
+			name:        "nok, wrong secret",
+			givenConfig: WebhookConfig{Secret: []byte("secret")},
+			whenHeaders: map[string]string{"X-Webhook-Signature": hex.EncodeToString(webhookSignature("other", body))},
+			expectError: "code=401, message=invalid webhook signature, internal=invalid webhook signature",
+		},
+		{
+			name:        "nok, missing prefix",
+			givenConfig: WebhookConfig{Secret: []byte("secret"), SignaturePrefix: "sha256="},
+			whenHeaders: map[string]string{"X-Webhook-Signature": hex.EncodeToString(webhookSignature("secret", body))},
+			expectError: "code=401, message=invalid webhook signature, internal=invalid webhook signature",
+		},
+		{
+			name:        "nok, stale timestamp",
+			givenConfig: WebhookConfig{Secret: []byte("secret"), TimestampHeader: "X-Timestamp"},
+			whenHeaders: map[string]string{
+				"X-Webhook-Signature": hex.EncodeToString(webhookSignature("secret", stale+"."+body)),
+				"X-Timestamp":         stale,
+			},
+			expectError: "code=401, message=invalid webhook timestamp, internal=invalid webhook timestamp",
+		},
+		{
+			name:        "nok, body too large",
+			givenConfig: WebhookConfig{Secret: []byte("secret"), MaxBodySize: 4},
+			whenHeaders: map[string]string{"X-Webhook-Signature": hex.EncodeToString(webhookSignature("secret", body))},
+			expectError: "code=413, message=Request Entity Too Large",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			handlerCalled := false
+			h := WebhookWithConfig(tc.givenConfig)(func(c echo.Context) error {
+				handlerCalled = true
+				b, err := ioutil.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+				assert.Equal(t, body, string(b))
+				return c.NoContent(http.StatusNoContent)
+			})
+
+			err := h(c)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectHandler, handlerCalled)
+		})
+	}
+}
+
+func TestWebhookWithConfig_panics(t *testing.T) {
+	assert.Panics(t, func() {
+		WebhookWithConfig(WebhookConfig{})
+	})
+	assert.Panics(t, func() {
+		WebhookWithConfig(WebhookConfig{Secret: []byte("secret"), SignatureEncoding: "base32"})
+	})
+}
@@ -0,0 +1,126 @@
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// RouteMiddlewareChain describes middlewares run for a route in execution order, grouped by the level they were added
+// at. Middleware names are function names (i.e. "github.com/labstack/echo/v4/middleware.LoggerWithConfig.func1").
+type RouteMiddlewareChain struct {
+	Host   string `json:"host"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+	// Pre are middlewares added with `Echo#Pre`. They run before router for every request.
+	Pre []string `json:"pre"`
+	// Global are middlewares added with `Echo#Use`, excluding middlewares skipped by the route.
+	Global []string `json:"global"`
+	// Group are middlewares added with `Echo#Group`, `Echo#Host` or `Group#Use` (including parent groups).
+	Group []string `json:"group"`
+	// Route are middlewares given when route was added.
+	Route []string `json:"route"`
+	// Skipped are global middlewares skipped by the route (see `Echo#SkipMiddleware`).
+	Skipped []string `json:"skipped"`
+	// Warnings describe suspicious chains (i.e. same middleware run more than once).
+	Warnings []string `json:"warnings"`
+}
+
+// MiddlewareChains returns effective middleware chains of registered routes in stable order (sorted by host, path and
+// method). Use it to see actual execution order of middlewares when debugging misordered `Echo#Pre`, `Echo#Use`
+// and `Group#Use` calls. See `Echo#MiddlewareChainsHandler` for debug endpoint.
+func (e *Echo) MiddlewareChains() []RouteMiddlewareChain {
+	latest := map[*Route]routeRegistration{}
+	for _, reg := range e.registrations {
+		latest[reg.route] = reg
+	}
+
+	pre := funcNames(e.premiddleware)
+	result := make([]RouteMiddlewareChain, 0, len(latest))
+	for host, router := range e.allRouters() {
+		for _, r := range router.routes {
+			reg, ok := latest[r]
+			if !ok {
+				continue
+			}
+			chain := RouteMiddlewareChain{
+				Host:     host,
+				Method:   r.Method,
+				Path:     r.Path,
+				Name:     r.Name,
+				Pre:      pre,
+				Global:   []string{},
+				Group:    funcNames(reg.middleware[:reg.groupMiddleware]),
+				Route:    funcNames(reg.middleware[reg.groupMiddleware:]),
+				Skipped:  []string{},
+				Warnings: []string{},
+			}
+			skip, _ := e.RouteMeta(r)[RouteMetaSkipMiddleware].([]string)
+			for i, m := range e.middleware {
+				if containsString(skip, e.middlewareNames[i]) {
+					chain.Skipped = append(chain.Skipped, e.middlewareNames[i])
+					continue
+				}
+				chain.Global = append(chain.Global, funcName(m))
+			}
+			chain.Warnings = chainWarnings(chain)
+			result = append(result, chain)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return result
+}
+
+// MiddlewareChainsHandler returns handler responding with middleware chains of all routes as JSON
+// (see `Echo#MiddlewareChains`). It is meant for development and internal debug endpoints as it exposes application
+// internals.
+//
+// Example: `e.GET("/debug/middlewares", e.MiddlewareChainsHandler())`
+func (e *Echo) MiddlewareChainsHandler() HandlerFunc {
+	return func(c Context) error {
+		return c.JSON(http.StatusOK, e.MiddlewareChains())
+	}
+}
+
+// chainWarnings reports middlewares that appear more than once in chain for the route (i.e. added both globally and to group).
+func chainWarnings(chain RouteMiddlewareChain) []string {
+	warnings := []string{}
+	levels := map[string][]string{}
+	var order []string
+	add := func(level string, names []string) {
+		for _, n := range names {
+			if _, ok := levels[n]; !ok {
+				order = append(order, n)
+			}
+			levels[n] = append(levels[n], level)
+		}
+	}
+	add("pre", chain.Pre)
+	add("global", chain.Global)
+	add("group", chain.Group)
+	add("route", chain.Route)
+	for _, n := range order {
+		if l := levels[n]; len(l) > 1 {
+			warnings = append(warnings, fmt.Sprintf("middleware %s appears %d times in chain (levels: %v)", n, len(l), l))
+		}
+	}
+	return warnings
+}
+
+func funcNames(middleware []MiddlewareFunc) []string {
+	names := make([]string, 0, len(middleware))
+	for _, m := range middleware {
+		names = append(names, funcName(m))
+	}
+	return names
+}
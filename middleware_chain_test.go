@@ -0,0 +1,82 @@
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func chainTestPreMiddleware(next HandlerFunc) HandlerFunc {
+	return next
+}
+
+func chainTestGroupMiddleware(next HandlerFunc) HandlerFunc {
+	return next
+}
+
+func TestEcho_MiddlewareChains(t *testing.T) {
+	var trace []string
+	e := New()
+	e.Pre(chainTestPreMiddleware)
+	e.Use(tracingMiddleware(&trace))
+	e.UseNamed("audit", routeTestMiddleware)
+
+	g := e.Group("/api", chainTestGroupMiddleware)
+	g.GET("/users", handlerFunc, routeTestMiddleware).Name = "users"
+	e.SkipMiddleware(e.GET("/health", handlerFunc), "audit").Name = "health"
+
+	const (
+		pre    = "github.com/labstack/echo/v4.chainTestPreMiddleware"
+		group  = "github.com/labstack/echo/v4.chainTestGroupMiddleware"
+		route  = "github.com/labstack/echo/v4.routeTestMiddleware"
+		global = "github.com/labstack/echo/v4.tracingMiddlewareWithConfig.func1"
+	)
+	chains := e.MiddlewareChains()
+
+	assert.Len(t, chains, len(methods)*2+2) // group Use registers "/api" and "/api/*" for all methods
+	byName := map[string]RouteMiddlewareChain{}
+	for _, c := range chains {
+		byName[c.Name] = c
+	}
+
+	assert.Equal(t, RouteMiddlewareChain{
+		Method:   http.MethodGet,
+		Path:     "/api/users",
+		Name:     "users",
+		Pre:      []string{pre},
+		Global:   []string{global, route},
+		Group:    []string{group},
+		Route:    []string{route},
+		Skipped:  []string{},
+		Warnings: []string{"middleware " + route + " appears 2 times in chain (levels: [global route])"},
+	}, byName["users"])
+
+	assert.Equal(t, RouteMiddlewareChain{
+		Method:   http.MethodGet,
+		Path:     "/health",
+		Name:     "health",
+		Pre:      []string{pre},
+		Global:   []string{global},
+		Group:    []string{},
+		Route:    []string{},
+		Skipped:  []string{"audit"},
+		Warnings: []string{},
+	}, byName["health"])
+}
+
+func TestEcho_MiddlewareChainsHandler(t *testing.T) {
+	e := New()
+	e.GET("/debug/middlewares", e.MiddlewareChainsHandler()).Name = "debug"
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/middlewares", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var chains []RouteMiddlewareChain
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &chains))
+	assert.Len(t, chains, 1)
+	assert.Equal(t, "debug", chains[0].Name)
+}
@@ -0,0 +1,93 @@
+package echo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteMetaSkipMiddleware is route metadata key for names (`[]string`) of global middlewares (added with `Echo#Use`)
+// that are not run for the route. Set it with `Echo#SkipMiddleware`.
+const RouteMetaSkipMiddleware = "echo.skip_middleware"
+
+// UseNamed adds middleware with explicit name to the chain which is run after router. Name can be used to skip
+// middleware for specific routes with `Echo#SkipMiddleware`. See `Echo#Use`.
+func (e *Echo) UseNamed(name string, middleware MiddlewareFunc) {
+	e.checkNotFrozen()
+	e.middleware = append(e.middleware, middleware)
+	e.middlewareNames = append(e.middlewareNames, name)
+}
+
+// SkipMiddleware marks route to skip global middlewares (added with `Echo#Use` or `Echo#UseNamed`) with given names.
+// Useful for exceptional routes like health checks instead of adding Skipper function to every middleware.
+// Middleware added with `Echo#Use` is named after function that created it, without package and `WithConfig`
+// suffix (i.e. `middleware.RateLimiter(store)` and `middleware.RateLimiterWithConfig(config)` are both named
+// `RateLimiter`). Pre middlewares and group middlewares can not be skipped. Unknown names are reported by
+// `Echo#VerifyRoutes`.
+//
+// Example: `e.SkipMiddleware(e.GET("/health", health), "Logger", "RateLimiter")`
+func (e *Echo) SkipMiddleware(r *Route, names ...string) *Route {
+	e.checkNotFrozen()
+	meta, ok := e.routeMeta[r]
+	if !ok {
+		return r
+	}
+	skip, _ := meta[RouteMetaSkipMiddleware].([]string)
+	meta[RouteMetaSkipMiddleware] = append(append([]string(nil), skip...), names...)
+	e.skipsMiddleware = true
+	return r
+}
+
+// middlewareName returns name of middleware derived from its function name
+// (i.e. "github.com/labstack/echo/v4/middleware.RateLimiterWithConfig.func1" is "RateLimiter").
+func middlewareName(m MiddlewareFunc) string {
+	name := funcName(m)
+	name = name[strings.LastIndex(name, "/")+1:]
+	if i := strings.IndexByte(name, '.'); i != -1 {
+		name = name[i+1:] // package
+	}
+	if i := strings.IndexByte(name, '.'); i != -1 {
+		name = name[:i] // closure
+	}
+	return strings.TrimSuffix(name, "WithConfig")
+}
+
+// applyGlobalMiddleware wraps handler with global middlewares except those skipped by route request was routed to.
+func (e *Echo) applyGlobalMiddleware(h HandlerFunc, c *context) HandlerFunc {
+	if !e.skipsMiddleware || len(e.middleware) == 0 {
+		return applyMiddleware(h, e.middleware...)
+	}
+	route := e.findRouter(c.request.Host).routes[c.request.Method+c.path]
+	skip, _ := e.routeMeta[route][RouteMetaSkipMiddleware].([]string)
+	if len(skip) == 0 {
+		return applyMiddleware(h, e.middleware...)
+	}
+	for i := len(e.middleware) - 1; i >= 0; i-- {
+		if containsString(skip, e.middlewareNames[i]) {
+			continue
+		}
+		h = e.middleware[i](h)
+	}
+	return h
+}
+
+func (e *Echo) verifySkippedMiddleware() []string {
+	var problems []string
+	for _, r := range e.sortedRoutes() {
+		skip, _ := e.RouteMeta(r)[RouteMetaSkipMiddleware].([]string)
+		for _, name := range skip {
+			if !containsString(e.middlewareNames, name) {
+				problems = append(problems, fmt.Sprintf("route %s %s skips unknown middleware %q", r.Method, r.Path, name))
+			}
+		}
+	}
+	return problems
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
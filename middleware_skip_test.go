@@ -0,0 +1,74 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tracingMiddlewareWithConfig(name string, trace *[]string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			*trace = append(*trace, name)
+			return next(c)
+		}
+	}
+}
+
+func tracingMiddleware(trace *[]string) MiddlewareFunc {
+	return tracingMiddlewareWithConfig("tracing", trace)
+}
+
+func TestMiddlewareName(t *testing.T) {
+	var trace []string
+	assert.Equal(t, "tracingMiddleware", middlewareName(tracingMiddleware(&trace)))
+	assert.Equal(t, "tracingMiddleware", middlewareName(tracingMiddlewareWithConfig("x", &trace)))
+	assert.Equal(t, "routeTestMiddleware", middlewareName(routeTestMiddleware))
+}
+
+func TestEcho_SkipMiddleware(t *testing.T) {
+	var trace []string
+	e := New()
+	e.Use(tracingMiddleware(&trace))
+	e.UseNamed("audit", tracingMiddlewareWithConfig("audit", &trace))
+
+	ok := func(c Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/", ok)
+	e.SkipMiddleware(e.GET("/health", ok), "tracingMiddleware")
+	e.SkipMiddleware(e.GET("/internal", ok), "tracingMiddleware", "audit")
+
+	var testCases = []struct {
+		whenURL     string
+		expectTrace []string
+	}{
+		{whenURL: "/", expectTrace: []string{"tracing", "audit"}},
+		{whenURL: "/health", expectTrace: []string{"audit"}},
+		{whenURL: "/internal", expectTrace: nil},
+		{whenURL: "/not-found", expectTrace: []string{"tracing", "audit"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenURL, func(t *testing.T) {
+			trace = nil
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+			assert.Equal(t, tc.expectTrace, trace)
+		})
+	}
+
+	trace = nil
+	e.Clone().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, []string{"audit"}, trace)
+}
+
+func TestEcho_SkipMiddlewareVerifyRoutes(t *testing.T) {
+	var trace []string
+	e := New()
+	e.Use(tracingMiddleware(&trace))
+	r := e.SkipMiddleware(e.GET("/health", handlerFunc), "tracingMiddleware", "Logger")
+
+	assert.Equal(t, []string{"tracingMiddleware", "Logger"}, e.RouteMeta(r)[RouteMetaSkipMiddleware])
+	assert.EqualError(t, e.VerifyRoutes(), `echo: routes skip unknown middlewares: route GET /health skips unknown middleware "Logger"`)
+}
@@ -0,0 +1,102 @@
+package echo
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// ErrMultipartPartTooLarge is returned when reading multipart part that exceeds size limit given to `Context#Parts`.
+var ErrMultipartPartTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge, "multipart part too large")
+
+// Parts iterates over parts of streamed multipart request body. Unlike `Context#MultipartForm` parts are not
+// buffered in memory or on disk so large uploads can be processed as they arrive.
+//
+// Example:
+//
+//	parts := c.Parts(10 << 20)
+//	for parts.Next() {
+//		part := parts.Part()
+//		if _, err := io.Copy(dst, part); err != nil {
+//			return err
+//		}
+//	}
+//	if err := parts.Err(); err != nil {
+//		return err
+//	}
+type Parts struct {
+	reader      *multipart.Reader
+	maxPartSize int64
+	part        *Part
+	err         error
+}
+
+// Part is single part of multipart request body. Reading part returns `ErrMultipartPartTooLarge` when part content
+// exceeds size limit.
+type Part struct {
+	*multipart.Part
+	remaining int64
+	limited   bool
+}
+
+func (c *context) MultipartReader() (*multipart.Reader, error) {
+	return c.request.MultipartReader()
+}
+
+func (c *context) Parts(maxPartSize int64) *Parts {
+	r, err := c.request.MultipartReader()
+	return &Parts{reader: r, maxPartSize: maxPartSize, err: err}
+}
+
+// Next advances to the next part. Previous part is closed and its unread content discarded. Next returns false
+// when there are no more parts or an error occurred. Use `Parts#Err` to distinguish between these cases.
+func (p *Parts) Next() bool {
+	if p.err != nil {
+		return false
+	}
+	if p.part != nil {
+		p.part.Close()
+		p.part = nil
+	}
+	part, err := p.reader.NextPart()
+	if err != nil {
+		if err != io.EOF {
+			p.err = err
+		}
+		return false
+	}
+	p.part = &Part{Part: part, remaining: p.maxPartSize, limited: p.maxPartSize > 0}
+	return true
+}
+
+// Part returns current part. Part is valid until next call to `Parts#Next`.
+func (p *Parts) Part() *Part {
+	return p.part
+}
+
+// Err returns the first error encountered during iteration.
+func (p *Parts) Err() error {
+	return p.err
+}
+
+// Read reads part content and returns `ErrMultipartPartTooLarge` when content exceeds size limit.
+func (p *Part) Read(b []byte) (int, error) {
+	if !p.limited {
+		return p.Part.Read(b)
+	}
+	if p.remaining <= 0 {
+		// probe for single byte to determine if part ends exactly at the limit
+		var probe [1]byte
+		n, err := p.Part.Read(probe[:])
+		if n > 0 {
+			return 0, ErrMultipartPartTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(b)) > p.remaining {
+		b = b[:p.remaining]
+	}
+	n, err := p.Part.Read(b)
+	p.remaining -= int64(n)
+	return n, err
+}
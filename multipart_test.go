@@ -0,0 +1,125 @@
+package echo
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMultipartRequest(t *testing.T, fields map[string]string, order ...string) *http.Request {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for _, name := range order {
+		fw, err := mw.CreateFormFile(name, name+".txt")
+		assert.NoError(t, err)
+		_, err = fw.Write([]byte(fields[name]))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	return req
+}
+
+func TestContext_MultipartReader(t *testing.T) {
+	e := New()
+	req := newMultipartRequest(t, map[string]string{"file": "content"}, "file")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	r, err := c.MultipartReader()
+	assert.NoError(t, err)
+
+	part, err := r.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "file", part.FormName())
+	b, err := ioutil.ReadAll(part)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(b))
+}
+
+func TestContext_Parts(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		maxPartSize   int64
+		expectParts   map[string]string
+		expectReadErr string
+	}{
+		{
+			name:        "ok, no limit",
+			maxPartSize: 0,
+			expectParts: map[string]string{"a": "first", "b": "second part"},
+		},
+		{
+			name:        "ok, parts exactly at limit",
+			maxPartSize: 11,
+			expectParts: map[string]string{"a": "first", "b": "second part"},
+		},
+		{
+			name:          "nok, part over limit",
+			maxPartSize:   6,
+			expectParts:   map[string]string{"a": "first", "b": "second"},
+			expectReadErr: "code=413, message=multipart part too large",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := newMultipartRequest(t, map[string]string{"a": "first", "b": "second part"}, "a", "b")
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := map[string]string{}
+			var readErr error
+			parts := c.Parts(tc.maxPartSize)
+			for parts.Next() {
+				part := parts.Part()
+				b, err := ioutil.ReadAll(part)
+				result[part.FormName()] = string(b)
+				if err != nil {
+					readErr = err
+					assert.True(t, errors.Is(err, ErrMultipartPartTooLarge))
+				}
+			}
+			assert.NoError(t, parts.Err())
+			assert.Equal(t, tc.expectParts, result)
+			if tc.expectReadErr != "" {
+				assert.EqualError(t, readErr, tc.expectReadErr)
+			} else {
+				assert.NoError(t, readErr)
+			}
+		})
+	}
+}
+
+func TestContext_PartsSkipsUnreadParts(t *testing.T) {
+	e := New()
+	req := newMultipartRequest(t, map[string]string{"a": "first", "b": "second"}, "a", "b")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	var names []string
+	parts := c.Parts(0)
+	for parts.Next() {
+		names = append(names, parts.Part().FormName())
+	}
+	assert.NoError(t, parts.Err())
+	assert.Equal(t, []string{"a", "b"}, names)
+}
+
+func TestContext_PartsNotMultipart(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a=1"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	parts := c.Parts(0)
+	assert.False(t, parts.Next())
+	assert.EqualError(t, parts.Err(), "request Content-Type isn't multipart/form-data")
+}
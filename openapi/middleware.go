@@ -0,0 +1,317 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/validation"
+)
+
+type (
+	// RequestValidatorConfig defines the config for RequestValidator middleware.
+	RequestValidatorConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper func(c echo.Context) bool
+
+		// Document is the OpenAPI document requests are validated against.
+		// Required.
+		Document *Document
+
+		// BasePath is prefix of Echo routes that is not part of paths in document (i.e. "/api/v1" when document
+		// server url is "https://example.com/api/v1").
+		// Optional.
+		BasePath string
+
+		// RejectUnknown responds 404 Not Found to requests of routes that are not described in document.
+		// Optional. Default value false (requests are not validated).
+		RejectUnknown bool
+
+		// ValidateResponses validates JSON responses against response schemas of operation and logs mismatches
+		// with `Echo#Logger`. Response is buffered and already sent to client so it is meant for development.
+		// Optional. Default value false.
+		ValidateResponses bool
+
+		// Message is the general message of validation error response.
+		// Optional. Default value "request validation failed".
+		Message string
+	}
+
+	responseCapture struct {
+		http.ResponseWriter
+		body *bytes.Buffer
+	}
+)
+
+var (
+	// DefaultRequestValidatorConfig is the default RequestValidator middleware config.
+	DefaultRequestValidatorConfig = RequestValidatorConfig{
+		Skipper: func(c echo.Context) bool { return false },
+		Message: "request validation failed",
+	}
+)
+
+// RequestValidator returns a middleware that validates requests against operation of OpenAPI document matching
+// the route of request.
+func RequestValidator(doc *Document) echo.MiddlewareFunc {
+	c := DefaultRequestValidatorConfig
+	c.Document = doc
+	return RequestValidatorWithConfig(c)
+}
+
+// RequestValidatorWithConfig returns a RequestValidator middleware with config.
+// See: `RequestValidator()`.
+func RequestValidatorWithConfig(config RequestValidatorConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Document == nil {
+		panic("echo: openapi request validator requires document")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestValidatorConfig.Skipper
+	}
+	if config.Message == "" {
+		config.Message = DefaultRequestValidatorConfig.Message
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			routePath := c.Path()
+			if config.BasePath != "" {
+				if !strings.HasPrefix(routePath, config.BasePath) {
+					return next(c)
+				}
+				routePath = routePath[len(config.BasePath):]
+			}
+			op := config.Document.Operation(c.Request().Method, routePath)
+			if op == nil {
+				if config.RejectUnknown {
+					return echo.ErrNotFound
+				}
+				return next(c)
+			}
+
+			violations, err := validateRequest(c, op)
+			if err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				return &echo.HTTPError{
+					Code:    http.StatusBadRequest,
+					Message: validation.Errors{Message: config.Message, Errors: violations},
+				}
+			}
+
+			if !config.ValidateResponses {
+				return next(c)
+			}
+			res := c.Response()
+			capture := &responseCapture{ResponseWriter: res.Writer, body: c.Echo().AcquireBuffer()}
+			defer c.Echo().ReleaseBuffer(capture.body)
+			res.Writer = capture
+			defer func() { res.Writer = capture.ResponseWriter }()
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if violations := validateResponse(op, res.Status, res.Header().Get(echo.HeaderContentType), capture.body.Bytes()); len(violations) > 0 {
+				c.Logger().Errorf("openapi: response of %s %s does not match document: %v", c.Request().Method, c.Path(), violations)
+			}
+			return nil
+		}
+	}
+}
+
+func validateRequest(c echo.Context, op *Operation) ([]validation.Violation, error) {
+	var violations []validation.Violation
+	req := c.Request()
+	for _, p := range op.Parameters {
+		values, ok := parameterValues(c, p)
+		field := p.In + "." + p.Name
+		if !ok {
+			if p.Required {
+				violations = append(violations, validation.Violation{Field: field, Rule: "required", Message: field + " is required"})
+			}
+			continue
+		}
+		if p.Schema == nil {
+			continue
+		}
+		value, violation := coerceParameter(p.Schema, values, field)
+		if violation != nil {
+			violations = append(violations, *violation)
+			continue
+		}
+		violations = append(violations, p.Schema.Validate(value, field)...)
+	}
+
+	if op.RequestBody == nil {
+		return violations, nil
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			violations = append(violations, validation.Violation{Field: "body", Rule: "required", Message: "body is required"})
+		}
+		return violations, nil
+	}
+	mt, ok := mediaType(op.RequestBody.Content, req.Header.Get(echo.HeaderContentType))
+	if !ok {
+		return append(violations, validation.Violation{
+			Field:   "body",
+			Rule:    "contentType",
+			Param:   req.Header.Get(echo.HeaderContentType),
+			Message: "body content type is not supported",
+		}), nil
+	}
+	return append(violations, validateJSON(mt, body, "body")...), nil
+}
+
+func validateResponse(op *Operation, status int, contentType string, body []byte) []validation.Violation {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		if resp, ok = op.Responses["default"]; !ok {
+			return []validation.Violation{{Field: "status", Rule: "status", Param: strconv.Itoa(status), Message: "response status is not documented"}}
+		}
+	}
+	if len(resp.Content) == 0 || len(body) == 0 {
+		return nil
+	}
+	mt, ok := mediaType(resp.Content, contentType)
+	if !ok {
+		return []validation.Violation{{Field: "body", Rule: "contentType", Param: contentType, Message: "response content type is not documented"}}
+	}
+	return validateJSON(mt, body, "response")
+}
+
+// validateJSON validates JSON body against schema of media type. Non JSON bodies are not validated.
+func validateJSON(mt *MediaType, body []byte, field string) []validation.Violation {
+	if mt == nil || mt.Schema == nil {
+		return nil
+	}
+	var value interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&value); err != nil {
+		return []validation.Violation{{Field: field, Rule: "json", Message: field + " is not valid JSON"}}
+	}
+	return mt.Schema.Validate(value, field)
+}
+
+// mediaType returns media type of content matching content type. Returns nil media type for matching non JSON content.
+func mediaType(content map[string]*MediaType, contentType string) (*MediaType, bool) {
+	ct, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	mt, ok := content[ct]
+	if !ok {
+		if mt, ok = content[ct[:strings.IndexByte(ct, '/')]+"/*"]; !ok {
+			if mt, ok = content["*/*"]; !ok {
+				return nil, false
+			}
+		}
+	}
+	if ct != echo.MIMEApplicationJSON && !strings.HasSuffix(ct, "+json") {
+		return nil, true
+	}
+	return mt, true
+}
+
+func parameterValues(c echo.Context, p *Parameter) ([]string, bool) {
+	req := c.Request()
+	switch p.In {
+	case InPath:
+		for _, name := range c.ParamNames() {
+			if name == p.Name {
+				return []string{c.Param(name)}, true
+			}
+		}
+	case InQuery:
+		v, ok := c.QueryParams()[p.Name]
+		return v, ok
+	case InHeader:
+		v, ok := req.Header[http.CanonicalHeaderKey(p.Name)]
+		return v, ok
+	case InCookie:
+		if cookie, err := req.Cookie(p.Name); err == nil {
+			return []string{cookie.Value}, true
+		}
+	}
+	return nil, false
+}
+
+// coerceParameter converts string values of parameter to value of schema type.
+func coerceParameter(s *Schema, values []string, field string) (interface{}, *validation.Violation) {
+	for s.target != nil {
+		s = s.target
+	}
+	if s.Type == "array" {
+		if len(values) == 1 && strings.Contains(values[0], ",") {
+			values = strings.Split(values[0], ",") // form style with explode=false
+		}
+		items := make([]interface{}, len(values))
+		for i, v := range values {
+			item := v
+			if s.Items != nil {
+				coerced, violation := coerceParameter(s.Items, []string{v}, field+"["+strconv.Itoa(i)+"]")
+				if violation != nil {
+					return nil, violation
+				}
+				items[i] = coerced
+				continue
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+
+	v := values[0]
+	switch s.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, &validation.Violation{Field: field, Rule: "type", Param: s.Type, Message: field + " must be integer"}
+		}
+		return json.Number(v), nil
+	case "number":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return nil, &validation.Violation{Field: field, Rule: "type", Param: s.Type, Message: field + " must be number"}
+		}
+		return json.Number(v), nil
+	case "boolean":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, &validation.Violation{Field: field, Rule: "type", Param: s.Type, Message: field + " must be boolean"}
+		}
+		return b, nil
+	}
+	return v, nil
+}
+
+// readBody reads request body and replaces it so it can be read again by handler.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (w *responseCapture) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
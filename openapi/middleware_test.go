@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestValidator(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenMethod   string
+		whenURL      string
+		whenHeaders  map[string]string
+		whenBody     string
+		expectStatus int
+		expectErrors []validation.Violation
+	}{
+		{
+			name:         "ok, path query and header parameters",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/users/1?fields=name&fields=email",
+			whenHeaders:  map[string]string{"X-Tenant": "5b8a2e4c-0a4f-4b8e-9d0c-3c2a7b1e6f10"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "nok, invalid parameters",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/users/0?fields=name,password",
+			whenHeaders:  map[string]string{"X-Tenant": "acme"},
+			expectStatus: http.StatusBadRequest,
+			expectErrors: []validation.Violation{
+				{Field: "query.fields[1]", Rule: "enum", Param: "name,email", Message: "query.fields[1] must be one of name,email"},
+				{Field: "header.X-Tenant", Rule: "format", Param: "uuid", Message: "header.X-Tenant must be valid uuid"},
+				{Field: "path.id", Rule: "minimum", Param: "1", Message: "path.id must be greater than or equal to 1"},
+			},
+		},
+		{
+			name:         "nok, missing required parameter and invalid type",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/users/abc",
+			expectStatus: http.StatusBadRequest,
+			expectErrors: []validation.Violation{
+				{Field: "header.X-Tenant", Rule: "required", Message: "header.X-Tenant is required"},
+				{Field: "path.id", Rule: "type", Param: "integer", Message: "path.id must be integer"},
+			},
+		},
+		{
+			name:         "ok, body",
+			whenMethod:   http.MethodPut,
+			whenURL:      "/users/1",
+			whenHeaders:  map[string]string{echo.HeaderContentType: "application/json; charset=utf-8"},
+			whenBody:     `{"name": "jon", "email": "jon@example.com"}`,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "nok, invalid body",
+			whenMethod:   http.MethodPut,
+			whenURL:      "/users/1",
+			whenHeaders:  map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON},
+			whenBody:     `{"name": "jon"}`,
+			expectStatus: http.StatusBadRequest,
+			expectErrors: []validation.Violation{
+				{Field: "body.email", Rule: "required", Message: "body.email is required"},
+			},
+		},
+		{
+			name:         "nok, missing required body",
+			whenMethod:   http.MethodPut,
+			whenURL:      "/users/1",
+			expectStatus: http.StatusBadRequest,
+			expectErrors: []validation.Violation{
+				{Field: "body", Rule: "required", Message: "body is required"},
+			},
+		},
+		{
+			name:         "nok, unsupported content type",
+			whenMethod:   http.MethodPut,
+			whenURL:      "/users/1",
+			whenHeaders:  map[string]string{echo.HeaderContentType: echo.MIMEApplicationXML},
+			whenBody:     `<user/>`,
+			expectStatus: http.StatusBadRequest,
+			expectErrors: []validation.Violation{
+				{Field: "body", Rule: "contentType", Param: echo.MIMEApplicationXML, Message: "body content type is not supported"},
+			},
+		},
+		{
+			name:         "nok, malformed json body",
+			whenMethod:   http.MethodPut,
+			whenURL:      "/users/1",
+			whenHeaders:  map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON},
+			whenBody:     `{"name":`,
+			expectStatus: http.StatusBadRequest,
+			expectErrors: []validation.Violation{
+				{Field: "body", Rule: "json", Message: "body is not valid JSON"},
+			},
+		},
+		{
+			name:         "ok, route not in document is not validated",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/health",
+			expectStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RequestValidator(mustLoad(t, testDocument)))
+			handler := func(c echo.Context) error {
+				// body must be readable by handler after validation
+				b, err := ioutil.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+				return c.String(http.StatusOK, string(b))
+			}
+			e.GET("/users/:id", handler)
+			e.PUT("/users/:id", handler)
+			e.GET("/health", handler)
+
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, strings.NewReader(tc.whenBody))
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			if tc.expectErrors == nil {
+				assert.Equal(t, tc.whenBody, rec.Body.String())
+				return
+			}
+			var body validation.Errors
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, "request validation failed", body.Message)
+			assert.Equal(t, tc.expectErrors, body.Errors)
+		})
+	}
+}
+
+func TestRequestValidatorWithConfig(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenConfig   RequestValidatorConfig
+		whenURL      string
+		expectStatus int
+	}{
+		{
+			name:         "ok, base path is stripped from route path",
+			whenConfig:   RequestValidatorConfig{BasePath: "/api"},
+			whenURL:      "/api/users?limit=1000",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ok, skipper",
+			whenConfig: RequestValidatorConfig{
+				BasePath: "/api",
+				Skipper:  func(c echo.Context) bool { return true },
+			},
+			whenURL:      "/api/users?limit=1000",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "nok, unknown route is rejected",
+			whenConfig:   RequestValidatorConfig{RejectUnknown: true},
+			whenURL:      "/api/users?limit=1",
+			expectStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			tc.whenConfig.Document = mustLoad(t, testDocument)
+			e.Use(RequestValidatorWithConfig(tc.whenConfig))
+			e.GET("/api/users", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, []interface{}{})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequestValidatorWithConfig_panicsWithoutDocument(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: openapi request validator requires document", func() {
+		RequestValidatorWithConfig(RequestValidatorConfig{})
+	})
+}
+
+func TestRequestValidatorWithConfig_validateResponses(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenStatus   int
+		whenResponse interface{}
+		expectLog    string
+	}{
+		{
+			name:         "ok, response matches document",
+			whenStatus:   http.StatusOK,
+			whenResponse: map[string]interface{}{"id": 1, "name": "jon", "email": "jon@example.com"},
+		},
+		{
+			name:         "nok, response does not match schema",
+			whenStatus:   http.StatusOK,
+			whenResponse: map[string]interface{}{"id": 1, "name": "jon"},
+			expectLog:    "openapi: response of GET /users/:id does not match document: [{response.email required  response.email is required}]",
+		},
+		{
+			name:         "nok, undocumented status",
+			whenStatus:   http.StatusAccepted,
+			whenResponse: map[string]interface{}{},
+			expectLog:    "openapi: response of GET /users/:id does not match document: [{status status 202 response status is not documented}]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			logs := new(bytes.Buffer)
+			e.Logger.SetOutput(logs)
+			e.Use(RequestValidatorWithConfig(RequestValidatorConfig{
+				Document:          mustLoad(t, testDocument),
+				ValidateResponses: true,
+			}))
+			e.GET("/users/:id", func(c echo.Context) error {
+				return c.JSON(tc.whenStatus, tc.whenResponse)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			req.Header.Set("X-Tenant", "5b8a2e4c-0a4f-4b8e-9d0c-3c2a7b1e6f10")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.whenStatus, rec.Code)
+			if tc.expectLog == "" {
+				assert.Empty(t, logs.String())
+				return
+			}
+			assert.Contains(t, logs.String(), tc.expectLog)
+		})
+	}
+}
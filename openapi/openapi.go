@@ -0,0 +1,355 @@
+/*
+Package openapi validates requests against OpenAPI 3 document.
+
+Operations of the document are matched to Echo routes by method and path (`/users/{id}` in document is Echo route
+`/users/:id`). Path, query, header and cookie parameters and JSON request bodies are validated against their schemas.
+Failed validation is responded with 400 and structured message in the same format as package validation uses.
+
+Example:
+
+	doc, err := openapi.Load(specJSON)
+	if err != nil {
+		log.Fatal(err)
+	}
+	e.Use(openapi.RequestValidator(doc))
+
+Only JSON documents are supported (convert YAML documents to JSON before loading). Supported subset of JSON Schema
+covers `type`, `format` (date, date-time, uuid, email), `enum`, `nullable`, `properties`, `required`,
+`additionalProperties`, `items`, numeric and length limits, `pattern` and `allOf`/`anyOf`/`oneOf`. References are
+supported to `#/components/...` of the same document.
+*/
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Document is OpenAPI 3 document.
+	Document struct {
+		OpenAPI    string               `json:"openapi"`
+		Paths      map[string]*PathItem `json:"paths"`
+		Components Components           `json:"components"`
+
+		// operations maps method and Echo route path to operation.
+		operations map[string]*Operation
+	}
+
+	// Components holds reusable objects of the document.
+	Components struct {
+		Schemas       map[string]*Schema      `json:"schemas"`
+		Parameters    map[string]*Parameter   `json:"parameters"`
+		RequestBodies map[string]*RequestBody `json:"requestBodies"`
+		Responses     map[string]*Response    `json:"responses"`
+	}
+
+	// PathItem describes operations available on single path.
+	PathItem struct {
+		Parameters []*Parameter `json:"parameters"`
+		Get        *Operation   `json:"get"`
+		Put        *Operation   `json:"put"`
+		Post       *Operation   `json:"post"`
+		Delete     *Operation   `json:"delete"`
+		Options    *Operation   `json:"options"`
+		Head       *Operation   `json:"head"`
+		Patch      *Operation   `json:"patch"`
+		Trace      *Operation   `json:"trace"`
+	}
+
+	// Operation describes single API operation on a path.
+	Operation struct {
+		OperationID string               `json:"operationId"`
+		Parameters  []*Parameter         `json:"parameters"`
+		RequestBody *RequestBody         `json:"requestBody"`
+		Responses   map[string]*Response `json:"responses"`
+	}
+
+	// Parameter describes single operation parameter.
+	Parameter struct {
+		Ref      string  `json:"$ref"`
+		Name     string  `json:"name"`
+		In       string  `json:"in"`
+		Required bool    `json:"required"`
+		Schema   *Schema `json:"schema"`
+	}
+
+	// RequestBody describes request body of operation.
+	RequestBody struct {
+		Ref      string                `json:"$ref"`
+		Required bool                  `json:"required"`
+		Content  map[string]*MediaType `json:"content"`
+	}
+
+	// Response describes single response of operation.
+	Response struct {
+		Ref     string                `json:"$ref"`
+		Content map[string]*MediaType `json:"content"`
+	}
+
+	// MediaType describes content of request or response body.
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+)
+
+// Parameter locations.
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+)
+
+// Load parses OpenAPI 3 document in JSON format and resolves references in it.
+func Load(data []byte) (*Document, error) {
+	doc := new(Document)
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("openapi: invalid document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, errors.New("openapi: unsupported document version: " + doc.OpenAPI)
+	}
+	r := &resolver{doc: doc, schemas: map[*Schema]bool{}}
+	if err := r.resolveDocument(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// LoadSchema parses standalone schema in JSON format. Schema must not contain references.
+func LoadSchema(data []byte) (*Schema, error) {
+	s := new(Schema)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("openapi: invalid schema: %w", err)
+	}
+	r := &resolver{doc: new(Document), schemas: map[*Schema]bool{}}
+	if err := r.resolveSchemaRef(&s); err != nil {
+		return nil, fmt.Errorf("openapi: schema: %w", err)
+	}
+	return s, nil
+}
+
+// Operation returns operation for method and Echo route path (i.e. `/users/:id`) or nil when document does not
+// describe it.
+func (d *Document) Operation(method, routePath string) *Operation {
+	return d.operations[method+" "+routePath]
+}
+
+// operations returns operations of path item by HTTP method.
+func (p *PathItem) operations() map[string]*Operation {
+	result := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		http.MethodGet:     p.Get,
+		http.MethodPut:     p.Put,
+		http.MethodPost:    p.Post,
+		http.MethodDelete:  p.Delete,
+		http.MethodOptions: p.Options,
+		http.MethodHead:    p.Head,
+		http.MethodPatch:   p.Patch,
+		http.MethodTrace:   p.Trace,
+	} {
+		if op != nil {
+			result[method] = op
+		}
+	}
+	return result
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// routePath converts OpenAPI path template (`/users/{id}`) to Echo route path (`/users/:id`).
+func routePath(p string) string {
+	return pathParamRegexp.ReplaceAllString(p, ":$1")
+}
+
+type resolver struct {
+	doc     *Document
+	schemas map[*Schema]bool
+}
+
+func (r *resolver) resolveDocument() error {
+	for name, s := range r.doc.Components.Schemas {
+		if err := r.resolveSchema(s); err != nil {
+			return fmt.Errorf("openapi: schema %s: %w", name, err)
+		}
+	}
+
+	r.doc.operations = map[string]*Operation{}
+	for p, item := range r.doc.Paths {
+		common, err := r.resolveParameters(item.Parameters)
+		if err != nil {
+			return fmt.Errorf("openapi: path %s: %w", p, err)
+		}
+		for method, op := range item.operations() {
+			if err := r.resolveOperation(op, common); err != nil {
+				return fmt.Errorf("openapi: operation %s %s: %w", method, p, err)
+			}
+			r.doc.operations[method+" "+routePath(p)] = op
+		}
+	}
+	return nil
+}
+
+func (r *resolver) resolveOperation(op *Operation, common []*Parameter) error {
+	own, err := r.resolveParameters(op.Parameters)
+	if err != nil {
+		return err
+	}
+	// operation level parameters override path level parameters with same name and location
+	params := append([]*Parameter(nil), own...)
+	for _, c := range common {
+		overridden := false
+		for _, o := range own {
+			if o.Name == c.Name && o.In == c.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			params = append(params, c)
+		}
+	}
+	op.Parameters = params
+
+	if op.RequestBody != nil {
+		if op.RequestBody.Ref != "" {
+			body, ok := r.doc.Components.RequestBodies[refName(op.RequestBody.Ref, "requestBodies")]
+			if !ok {
+				return errors.New("unresolved reference: " + op.RequestBody.Ref)
+			}
+			op.RequestBody = body
+		}
+		if err := r.resolveContent(op.RequestBody.Content); err != nil {
+			return err
+		}
+	}
+	for code, resp := range op.Responses {
+		if resp.Ref != "" {
+			ref, ok := r.doc.Components.Responses[refName(resp.Ref, "responses")]
+			if !ok {
+				return errors.New("unresolved reference: " + resp.Ref)
+			}
+			op.Responses[code] = ref
+			resp = ref
+		}
+		if err := r.resolveContent(resp.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *resolver) resolveParameters(params []*Parameter) ([]*Parameter, error) {
+	result := make([]*Parameter, 0, len(params))
+	for _, p := range params {
+		if p.Ref != "" {
+			ref, ok := r.doc.Components.Parameters[refName(p.Ref, "parameters")]
+			if !ok {
+				return nil, errors.New("unresolved reference: " + p.Ref)
+			}
+			p = ref
+		}
+		if p.Schema != nil {
+			if err := r.resolveSchemaRef(&p.Schema); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func (r *resolver) resolveContent(content map[string]*MediaType) error {
+	for _, mt := range content {
+		if mt.Schema == nil {
+			continue
+		}
+		if err := r.resolveSchemaRef(&mt.Schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSchemaRef replaces reference schema with referenced component schema and resolves it.
+func (r *resolver) resolveSchemaRef(s **Schema) error {
+	if (*s).Ref != "" {
+		ref, ok := r.doc.Components.Schemas[refName((*s).Ref, "schemas")]
+		if !ok {
+			return errors.New("unresolved reference: " + (*s).Ref)
+		}
+		*s = ref
+	}
+	return r.resolveSchema(*s)
+}
+
+func (r *resolver) resolveSchema(s *Schema) error {
+	if r.schemas[s] {
+		return nil // already resolved or recursive schema
+	}
+	r.schemas[s] = true
+
+	if s.Ref != "" {
+		ref, ok := r.doc.Components.Schemas[refName(s.Ref, "schemas")]
+		if !ok {
+			return errors.New("unresolved reference: " + s.Ref)
+		}
+		s.target = ref
+		return r.resolveSchema(ref)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		s.pattern = re
+	}
+	if len(s.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil {
+			s.noAdditional = !allowed
+		} else {
+			s.additional = new(Schema)
+			if err := json.Unmarshal(s.AdditionalProperties, s.additional); err != nil {
+				return fmt.Errorf("invalid additionalProperties: %w", err)
+			}
+			if err := r.resolveSchemaRef(&s.additional); err != nil {
+				return err
+			}
+		}
+	}
+	if s.Items != nil {
+		if err := r.resolveSchemaRef(&s.Items); err != nil {
+			return err
+		}
+	}
+	for name := range s.Properties {
+		p := s.Properties[name]
+		if err := r.resolveSchemaRef(&p); err != nil {
+			return err
+		}
+		s.Properties[name] = p
+	}
+	for _, list := range [][]*Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range list {
+			if err := r.resolveSchemaRef(&list[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refName returns component name of local reference (i.e. "#/components/schemas/User" is "User").
+func refName(ref string, kind string) string {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return ""
+	}
+	return ref[len(prefix):]
+}
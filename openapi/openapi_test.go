@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDocument = `{
+  "openapi": "3.0.3",
+  "info": {"title": "test", "version": "1"},
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}],
+      "get": {
+        "parameters": [
+          {"name": "fields", "in": "query", "schema": {"type": "array", "items": {"type": "string", "enum": ["name", "email"]}}},
+          {"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "string", "format": "uuid"}}
+        ],
+        "responses": {
+          "200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+        }
+      },
+      "put": {
+        "requestBody": {"$ref": "#/components/requestBodies/User"},
+        "responses": {"204": {"description": "updated"}}
+      }
+    },
+    "/users": {
+      "get": {
+        "parameters": [{"$ref": "#/components/parameters/Limit"}],
+        "responses": {"default": {"$ref": "#/components/responses/Users"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["name", "email"],
+        "additionalProperties": false,
+        "properties": {
+          "id": {"type": "integer"},
+          "name": {"type": "string", "minLength": 1, "maxLength": 10},
+          "email": {"type": "string", "format": "email"},
+          "tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}}
+        }
+      }
+    },
+    "parameters": {
+      "Limit": {"name": "limit", "in": "query", "required": true, "schema": {"type": "integer", "maximum": 100}}
+    },
+    "requestBodies": {
+      "User": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+    },
+    "responses": {
+      "Users": {"description": "users", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}}}}
+    }
+  }
+}`
+
+func mustLoad(t *testing.T, data string) *Document {
+	doc, err := Load([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestLoad(t *testing.T) {
+	doc := mustLoad(t, testDocument)
+
+	get := doc.Operation("GET", "/users/:id")
+	if assert.NotNil(t, get) {
+		assert.Len(t, get.Parameters, 3)
+		assert.Equal(t, "id", get.Parameters[2].Name)
+		assert.Equal(t, "object", get.Responses["200"].Content["application/json"].Schema.Type)
+	}
+	put := doc.Operation("PUT", "/users/:id")
+	if assert.NotNil(t, put) && assert.NotNil(t, put.RequestBody) {
+		assert.True(t, put.RequestBody.Required)
+		assert.Len(t, put.Parameters, 1)
+	}
+	list := doc.Operation("GET", "/users")
+	if assert.NotNil(t, list) {
+		assert.Equal(t, "limit", list.Parameters[0].Name)
+		assert.NotNil(t, list.Responses["default"].Content["application/json"])
+	}
+	assert.Nil(t, doc.Operation("POST", "/users"))
+}
+
+func TestLoad_errors(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenData  string
+		expectErr string
+	}{
+		{
+			name:      "nok, invalid json",
+			whenData:  `{`,
+			expectErr: "openapi: invalid document: unexpected end of JSON input",
+		},
+		{
+			name:      "nok, unsupported version",
+			whenData:  `{"swagger": "2.0"}`,
+			expectErr: "openapi: unsupported document version: ",
+		},
+		{
+			name:      "nok, unknown schema reference",
+			whenData:  `{"openapi": "3.0.0", "paths": {"/": {"get": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Nope"}}}}}}}}`,
+			expectErr: "openapi: operation GET /: unresolved reference: #/components/schemas/Nope",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := Load([]byte(tc.whenData))
+			assert.Nil(t, doc)
+			assert.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	doc := mustLoad(t, testDocument)
+	user := doc.Components.Schemas["User"]
+
+	var testCases = []struct {
+		name      string
+		whenJSON  string
+		expectErr []validation.Violation
+	}{
+		{
+			name:     "ok",
+			whenJSON: `{"id": 1, "name": "jon", "email": "jon@example.com", "tags": ["a"]}`,
+		},
+		{
+			name:     "nok, not an object",
+			whenJSON: `[]`,
+			expectErr: []validation.Violation{
+				{Field: "body", Rule: "type", Param: "object", Message: "body must be object"},
+			},
+		},
+		{
+			name:     "nok, multiple violations",
+			whenJSON: `{"id": 1.5, "name": "", "tags": ["a", "b", "c"], "admin": true}`,
+			expectErr: []validation.Violation{
+				{Field: "body.email", Rule: "required", Message: "body.email is required"},
+				{Field: "body.admin", Rule: "additionalProperties", Message: "body.admin is not allowed"},
+				{Field: "body.id", Rule: "type", Param: "integer", Message: "body.id must be integer"},
+				{Field: "body.name", Rule: "minLength", Param: "1", Message: "body.name must be at least 1 characters long"},
+				{Field: "body.tags", Rule: "maxItems", Param: "2", Message: "body.tags must have at most 2 items"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var value interface{}
+			d := json.NewDecoder(strings.NewReader(tc.whenJSON))
+			d.UseNumber()
+			assert.NoError(t, d.Decode(&value))
+
+			assert.Equal(t, tc.expectErr, user.Validate(value, "body"))
+		})
+	}
+}
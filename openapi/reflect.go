@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaOf returns schema describing JSON encoding of Go value `v` the way `encoding/json` marshals it. Struct fields
+// without `omitempty` are required and structs do not allow additional properties. Types implementing
+// `json.Marshaler` are not described (any value is allowed) and `encoding.TextMarshaler` types are strings.
+func SchemaOf(v interface{}) *Schema {
+	return schemaOf(reflect.TypeOf(v), map[reflect.Type]*Schema{})
+}
+
+func schemaOf(t reflect.Type, seen map[reflect.Type]*Schema) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+	if s, ok := seen[t]; ok {
+		return &Schema{Nullable: nullable, target: s} // recursive type
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case t == rawMessageType, t.Implements(jsonMarshalerType), reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &Schema{}
+	case t.Implements(textMarshalerType), reflect.PtrTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string", Nullable: nullable}
+	}
+
+	s := &Schema{Nullable: nullable}
+	switch t.Kind() {
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		s.Type = "number"
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			s.Type = "string" // []byte is encoded as base64 string
+			s.Nullable = true
+			break
+		}
+		s.Type = "array"
+		s.Nullable = s.Nullable || t.Kind() == reflect.Slice // nil slice is encoded as null
+		s.Items = schemaOf(t.Elem(), seen)
+	case reflect.Map:
+		s.Type = "object"
+		s.Nullable = true // nil map is encoded as null
+		s.additional = schemaOf(t.Elem(), seen)
+	case reflect.Struct:
+		s.Type = "object"
+		s.Properties = map[string]*Schema{}
+		s.noAdditional = true
+		seen[t] = s
+		addStructFields(s, t, seen)
+		delete(seen, t)
+	}
+	return s
+}
+
+func addStructFields(s *Schema, t reflect.Type, seen map[reflect.Type]*Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i != -1 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructFields(s, ft, seen) // fields of embedded struct are promoted
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = f.Name
+		}
+		p := schemaOf(f.Type, seen)
+		if containsOption(opts, "string") && (p.Type == "integer" || p.Type == "number" || p.Type == "boolean") {
+			p = &Schema{Type: "string", Nullable: p.Nullable}
+		}
+		s.Properties[name] = p
+		if !containsOption(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func containsOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testNode struct {
+	Name     string      `json:"name"`
+	Children []*testNode `json:"children,omitempty"`
+}
+
+type testUser struct {
+	testAddress
+	ID       int64           `json:"id,string"`
+	Name     string          `json:"name"`
+	Email    *string         `json:"email"`
+	Tags     []string        `json:"tags,omitempty"`
+	Labels   map[string]int  `json:"labels,omitempty"`
+	Created  time.Time       `json:"created"`
+	Raw      json.RawMessage `json:"raw,omitempty"`
+	Tree     *testNode       `json:"tree,omitempty"`
+	Password string          `json:"-"`
+	internal string
+}
+
+func TestSchemaOf(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenJSON  string
+		expectErr []validation.Violation
+	}{
+		{
+			name:     "ok",
+			whenJSON: `{"city": "Tallinn", "id": "1", "name": "jon", "email": null, "tags": ["a"], "labels": {"x": 1}, "created": "2021-01-01T00:00:00Z", "raw": [1], "tree": {"name": "root", "children": [{"name": "leaf"}, null]}}`,
+		},
+		{
+			name:     "nok, violations",
+			whenJSON: `{"id": 1, "name": null, "email": "jon", "tags": [1], "labels": {"x": "1"}, "created": "yesterday", "tree": {"children": []}, "Password": "secret"}`,
+			expectErr: []validation.Violation{
+				{Field: "response.city", Rule: "required", Message: "response.city is required"},
+				{Field: "response.Password", Rule: "additionalProperties", Message: "response.Password is not allowed"},
+				{Field: "response.created", Rule: "format", Param: "date-time", Message: "response.created must be valid date-time"},
+				{Field: "response.id", Rule: "type", Param: "string", Message: "response.id must be string"},
+				{Field: "response.labels.x", Rule: "type", Param: "integer", Message: "response.labels.x must be integer"},
+				{Field: "response.name", Rule: "nullable", Message: "response.name must not be null"},
+				{Field: "response.tags[0]", Rule: "type", Param: "string", Message: "response.tags[0] must be string"},
+				{Field: "response.tree.name", Rule: "required", Message: "response.tree.name is required"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var value interface{}
+			d := json.NewDecoder(strings.NewReader(tc.whenJSON))
+			d.UseNumber()
+			assert.NoError(t, d.Decode(&value))
+
+			assert.Equal(t, tc.expectErr, SchemaOf(testUser{}).Validate(value, "response"))
+		})
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	s, err := LoadSchema([]byte(`{"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []validation.Violation{
+		{Field: "body[1]", Rule: "pattern", Param: "^[a-z]+$", Message: "body[1] must match pattern ^[a-z]+$"},
+	}, s.Validate([]interface{}{"abc", "ABC"}, "body"))
+
+	s, err = LoadSchema([]byte(`{"$ref": "#/components/schemas/User"}`))
+	assert.Nil(t, s)
+	assert.EqualError(t, err, "openapi: schema: unresolved reference: #/components/schemas/User")
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/validation"
+)
+
+type (
+	// ResponseAssertionConfig defines the config for ResponseAssertion middleware.
+	ResponseAssertionConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper func(c echo.Context) bool
+
+		// Contracts are expected JSON responses of routes.
+		// Required.
+		Contracts []ResponseContract
+
+		// FailOnMismatch replaces response that does not match its contract with 500 Internal Server Error
+		// containing the violations. When false mismatches are only logged with `Echo#Logger`.
+		// Optional. Default value false.
+		FailOnMismatch bool
+	}
+
+	// ResponseContract describes expected JSON response of route.
+	ResponseContract struct {
+		// Method is HTTP method of route.
+		Method string
+		// Path is Echo route path (i.e. `/users/:id`).
+		Path string
+		// Status is response status the contract applies to. Zero value applies to all 2xx statuses.
+		Status int
+		// Schema is expected schema of response body. Takes precedence over Type.
+		Schema *Schema
+		// Type is Go value which JSON encoding response body must match (i.e. `User{}` or `[]User{}`). See `SchemaOf`.
+		Type interface{}
+	}
+
+	bufferedResponseWriter struct {
+		http.ResponseWriter
+		status int
+		body   *bytes.Buffer
+	}
+)
+
+var (
+	// DefaultResponseAssertionConfig is the default ResponseAssertion middleware config.
+	DefaultResponseAssertionConfig = ResponseAssertionConfig{
+		Skipper: func(c echo.Context) bool { return false },
+	}
+)
+
+// ResponseAssertion returns a middleware that checks JSON responses of routes against their contracts and logs
+// mismatches. Response is buffered until handler returns, so the middleware is meant for development and tests
+// to catch drift between handlers and documented API before clients do.
+//
+// For responses described in OpenAPI document see `RequestValidatorConfig.ValidateResponses`.
+func ResponseAssertion(contracts ...ResponseContract) echo.MiddlewareFunc {
+	c := DefaultResponseAssertionConfig
+	c.Contracts = contracts
+	return ResponseAssertionWithConfig(c)
+}
+
+// ResponseAssertionWithConfig returns a ResponseAssertion middleware with config.
+// See: `ResponseAssertion()`.
+func ResponseAssertionWithConfig(config ResponseAssertionConfig) echo.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultResponseAssertionConfig.Skipper
+	}
+	contracts := map[string][]ResponseContract{}
+	for _, rc := range config.Contracts {
+		if rc.Schema == nil {
+			if rc.Type == nil {
+				panic("echo: response contract of " + rc.Method + " " + rc.Path + " requires schema or type")
+			}
+			rc.Schema = SchemaOf(rc.Type)
+		}
+		key := rc.Method + " " + rc.Path
+		contracts[key] = append(contracts[key], rc)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			routeContracts, ok := contracts[c.Request().Method+" "+c.Path()]
+			if !ok {
+				return next(c)
+			}
+
+			res := c.Response()
+			buf := &bufferedResponseWriter{ResponseWriter: res.Writer, body: c.Echo().AcquireBuffer()}
+			defer c.Echo().ReleaseBuffer(buf.body)
+			res.Writer = buf
+			err := next(c)
+			res.Writer = buf.ResponseWriter
+			if err != nil || !res.Committed {
+				buf.flush()
+				return err
+			}
+
+			schema := contractSchema(routeContracts, res.Status)
+			ct := res.Header().Get(echo.HeaderContentType)
+			if schema == nil || !strings.HasPrefix(ct, echo.MIMEApplicationJSON) {
+				buf.flush()
+				return nil
+			}
+			violations := validateJSON(&MediaType{Schema: schema}, buf.body.Bytes(), "response")
+			if len(violations) == 0 {
+				buf.flush()
+				return nil
+			}
+			c.Logger().Errorf("openapi: response of %s %s does not match contract: %v", c.Request().Method, c.Path(), violations)
+			if !config.FailOnMismatch {
+				buf.flush()
+				return nil
+			}
+			// discard buffered response so error handler is able to send its own
+			res.Committed = false
+			res.Size = 0
+			res.Header().Del(echo.HeaderContentLength)
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: validation.Errors{Message: "response does not match contract", Errors: violations},
+			}
+		}
+	}
+}
+
+// contractSchema returns schema of contract for status preferring contracts with exact status.
+func contractSchema(contracts []ResponseContract, status int) *Schema {
+	var schema *Schema
+	for _, rc := range contracts {
+		if rc.Status == status {
+			return rc.Schema
+		}
+		if rc.Status == 0 && status >= 200 && status < 300 {
+			schema = rc.Schema
+		}
+	}
+	return schema
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush does nothing as response is sent after handler returns.
+func (w *bufferedResponseWriter) Flush() {}
+
+// flush sends buffered response to underlying writer.
+func (w *bufferedResponseWriter) flush() {
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseAssertion(t *testing.T) {
+	userSchema, err := LoadSchema([]byte(`{"type": "object", "required": ["city"]}`))
+	assert.NoError(t, err)
+
+	var testCases = []struct {
+		name          string
+		whenContracts []ResponseContract
+		whenFail      bool
+		whenStatus    int
+		whenResponse  interface{}
+		expectStatus  int
+		expectBody    string
+		expectLog     string
+	}{
+		{
+			name:          "ok, response matches type",
+			whenContracts: []ResponseContract{{Method: http.MethodGet, Path: "/users/:id", Type: testAddress{}}},
+			whenStatus:    http.StatusOK,
+			whenResponse:  testAddress{City: "Tallinn"},
+			expectStatus:  http.StatusOK,
+			expectBody:    `{"city":"Tallinn"}` + "\n",
+		},
+		{
+			name:          "ok, mismatch is logged",
+			whenContracts: []ResponseContract{{Method: http.MethodGet, Path: "/users/:id", Type: testAddress{}}},
+			whenStatus:    http.StatusOK,
+			whenResponse:  map[string]string{"town": "Tallinn"},
+			expectStatus:  http.StatusOK,
+			expectBody:    `{"town":"Tallinn"}` + "\n",
+			expectLog:     "openapi: response of GET /users/:id does not match contract: [{response.city required  response.city is required} {response.town additionalProperties  response.town is not allowed}]",
+		},
+		{
+			name:          "nok, mismatch fails response",
+			whenContracts: []ResponseContract{{Method: http.MethodGet, Path: "/users/:id", Schema: userSchema}},
+			whenFail:      true,
+			whenStatus:    http.StatusOK,
+			whenResponse:  map[string]string{"town": "Tallinn"},
+			expectStatus:  http.StatusInternalServerError,
+			expectBody:    `{"message":"response does not match contract","errors":[{"field":"response.city","rule":"required","message":"response.city is required"}]}` + "\n",
+			expectLog:     "openapi: response of GET /users/:id does not match contract",
+		},
+		{
+			name: "ok, contract with exact status is preferred",
+			whenContracts: []ResponseContract{
+				{Method: http.MethodGet, Path: "/users/:id", Type: testAddress{}},
+				{Method: http.MethodGet, Path: "/users/:id", Status: http.StatusAccepted, Type: map[string]string{}},
+			},
+			whenFail:     true,
+			whenStatus:   http.StatusAccepted,
+			whenResponse: map[string]string{"status": "queued"},
+			expectStatus: http.StatusAccepted,
+			expectBody:   `{"status":"queued"}` + "\n",
+		},
+		{
+			name:          "ok, error statuses are not checked by default",
+			whenContracts: []ResponseContract{{Method: http.MethodGet, Path: "/users/:id", Type: testAddress{}}},
+			whenFail:      true,
+			whenStatus:    http.StatusNotFound,
+			whenResponse:  map[string]string{"message": "not found"},
+			expectStatus:  http.StatusNotFound,
+			expectBody:    `{"message":"not found"}` + "\n",
+		},
+		{
+			name:          "ok, route without contract",
+			whenContracts: []ResponseContract{{Method: http.MethodPost, Path: "/users/:id", Type: testAddress{}}},
+			whenFail:      true,
+			whenStatus:    http.StatusOK,
+			whenResponse:  map[string]string{"town": "Tallinn"},
+			expectStatus:  http.StatusOK,
+			expectBody:    `{"town":"Tallinn"}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			logs := new(bytes.Buffer)
+			e.Logger.SetOutput(logs)
+			e.Use(ResponseAssertionWithConfig(ResponseAssertionConfig{
+				Contracts:      tc.whenContracts,
+				FailOnMismatch: tc.whenFail,
+			}))
+			e.GET("/users/:id", func(c echo.Context) error {
+				return c.JSON(tc.whenStatus, tc.whenResponse)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			if tc.expectLog == "" {
+				assert.Empty(t, logs.String())
+				return
+			}
+			assert.Contains(t, logs.String(), tc.expectLog)
+		})
+	}
+}
+
+func TestResponseAssertion_handlerError(t *testing.T) {
+	e := echo.New()
+	e.Use(ResponseAssertion(ResponseContract{Method: http.MethodGet, Path: "/", Type: testAddress{}}))
+	e.GET("/", func(c echo.Context) error {
+		return echo.ErrForbidden
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestResponseAssertionWithConfig_panicsWithoutSchema(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: response contract of GET /users requires schema or type", func() {
+		ResponseAssertion(ResponseContract{Method: http.MethodGet, Path: "/users"})
+	})
+}
@@ -0,0 +1,268 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4/validation"
+)
+
+// Schema is subset of OpenAPI 3 schema object (JSON Schema).
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	AllOf                []*Schema          `json:"allOf"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+
+	target       *Schema
+	pattern      *regexp.Regexp
+	additional   *Schema
+	noAdditional bool
+}
+
+var (
+	uuidRegexp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// Validate validates value decoded from JSON (with `json.Decoder.UseNumber`) against schema and returns violations.
+// Field is the path of the value used in violations (i.e. "body.address.city").
+func (s *Schema) Validate(value interface{}, field string) []validation.Violation {
+	var violations []validation.Violation
+	s.validate(value, field, &violations)
+	return violations
+}
+
+func (s *Schema) validate(value interface{}, field string, violations *[]validation.Violation) {
+	nullable := s.Nullable // nullable reference to recursive Go type, see `SchemaOf`
+	for s.target != nil {
+		s = s.target
+	}
+	add := func(rule, param, message string) {
+		*violations = append(*violations, validation.Violation{
+			Field:   field,
+			Rule:    rule,
+			Param:   param,
+			Message: field + " " + message,
+		})
+	}
+
+	if value == nil {
+		if !nullable && !s.Nullable && s.Type != "" {
+			add("nullable", "", "must not be null")
+		}
+		return
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(value, field, violations)
+	}
+	if len(s.AnyOf) > 0 && countValid(s.AnyOf, value, field) == 0 {
+		add("anyOf", "", "must match at least one schema")
+	}
+	if len(s.OneOf) > 0 && countValid(s.OneOf, value, field) != 1 {
+		add("oneOf", "", "must match exactly one schema")
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		add("enum", enumParam(s.Enum), "must be one of "+enumParam(s.Enum))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.Type != "" && s.Type != "object" {
+			add("type", s.Type, "must be "+s.Type)
+			return
+		}
+		s.validateObject(v, field, violations, add)
+	case []interface{}:
+		if s.Type != "" && s.Type != "array" {
+			add("type", s.Type, "must be "+s.Type)
+			return
+		}
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			add("minItems", strconv.Itoa(*s.MinItems), fmt.Sprintf("must have at least %d items", *s.MinItems))
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			add("maxItems", strconv.Itoa(*s.MaxItems), fmt.Sprintf("must have at most %d items", *s.MaxItems))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, field+"["+strconv.Itoa(i)+"]", violations)
+			}
+		}
+	case string:
+		if s.Type != "" && s.Type != "string" {
+			add("type", s.Type, "must be "+s.Type)
+			return
+		}
+		s.validateString(v, add)
+	case bool:
+		if s.Type != "" && s.Type != "boolean" {
+			add("type", s.Type, "must be "+s.Type)
+		}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil || (s.Type != "" && s.Type != "number" && s.Type != "integer") {
+			add("type", s.Type, "must be "+s.Type)
+			return
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			add("type", s.Type, "must be integer")
+			return
+		}
+		s.validateNumber(f, add)
+	default:
+		add("type", s.Type, "must be "+s.Type)
+	}
+}
+
+func (s *Schema) validateObject(v map[string]interface{}, field string, violations *[]validation.Violation, add func(rule, param, message string)) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			*violations = append(*violations, validation.Violation{
+				Field:   joinField(field, name),
+				Rule:    "required",
+				Message: joinField(field, name) + " is required",
+			})
+		}
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names) // stable order of violations
+	for _, name := range names {
+		if p, ok := s.Properties[name]; ok {
+			p.validate(v[name], joinField(field, name), violations)
+			continue
+		}
+		if s.noAdditional {
+			*violations = append(*violations, validation.Violation{
+				Field:   joinField(field, name),
+				Rule:    "additionalProperties",
+				Message: joinField(field, name) + " is not allowed",
+			})
+		} else if s.additional != nil {
+			s.additional.validate(v[name], joinField(field, name), violations)
+		}
+	}
+}
+
+func (s *Schema) validateString(v string, add func(rule, param, message string)) {
+	length := utf8.RuneCountInString(v)
+	if s.MinLength != nil && length < *s.MinLength {
+		add("minLength", strconv.Itoa(*s.MinLength), fmt.Sprintf("must be at least %d characters long", *s.MinLength))
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		add("maxLength", strconv.Itoa(*s.MaxLength), fmt.Sprintf("must be at most %d characters long", *s.MaxLength))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		add("pattern", s.Pattern, "must match pattern "+s.Pattern)
+	}
+	valid := true
+	switch s.Format {
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		valid = err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		valid = err == nil
+	case "uuid":
+		valid = uuidRegexp.MatchString(v)
+	case "email":
+		valid = emailRegexp.MatchString(v)
+	}
+	if !valid {
+		add("format", s.Format, "must be valid "+s.Format)
+	}
+}
+
+func (s *Schema) validateNumber(f float64, add func(rule, param, message string)) {
+	if s.Minimum != nil {
+		min := *s.Minimum
+		if f < min || (s.ExclusiveMinimum && f == min) {
+			add("minimum", formatFloat(min), "must be greater than "+orEqual(!s.ExclusiveMinimum)+formatFloat(min))
+		}
+	}
+	if s.Maximum != nil {
+		max := *s.Maximum
+		if f > max || (s.ExclusiveMaximum && f == max) {
+			add("maximum", formatFloat(max), "must be less than "+orEqual(!s.ExclusiveMaximum)+formatFloat(max))
+		}
+	}
+}
+
+func countValid(schemas []*Schema, value interface{}, field string) int {
+	count := 0
+	for _, sub := range schemas {
+		if len(sub.Validate(value, field)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	if n, ok := value.(json.Number); ok {
+		f, _ := n.Float64()
+		value = f
+	}
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumParam(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ",")
+}
+
+func joinField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func orEqual(inclusive bool) string {
+	if inclusive {
+		return "or equal to "
+	}
+	return ""
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
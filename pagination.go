@@ -0,0 +1,167 @@
+package echo
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	// PaginationConfig defines query parameter names and limits of list requests parsed with `Context#Pagination`.
+	PaginationConfig struct {
+		// LimitParam is the name of query parameter with maximum number of items to return.
+		// Optional. Default value "limit".
+		LimitParam string
+		// OffsetParam is the name of query parameter with number of items to skip.
+		// Optional. Default value "offset".
+		OffsetParam string
+		// CursorParam is the name of query parameter with opaque cursor of cursor based pagination.
+		// Optional. Default value "cursor".
+		CursorParam string
+		// DefaultLimit is the limit of requests without limit parameter.
+		// Optional. Default value 20.
+		DefaultLimit int
+		// MaxLimit caps limit requested by client.
+		// Optional. Default value 100.
+		MaxLimit int
+	}
+
+	// Pagination is pagination of list request parsed with `Context#Pagination`.
+	Pagination struct {
+		// Limit is the maximum number of items to return.
+		Limit int
+		// Offset is the number of items to skip.
+		Offset int
+		// Cursor is the cursor sent by client for cursor based pagination.
+		Cursor string
+
+		// NextCursor is the cursor of next page. Set by handler for cursor based pagination so
+		// `Context#SetPaginationHeaders` can link to next page.
+		NextCursor string
+		// PrevCursor is the cursor of previous page. Set by handler for cursor based pagination so
+		// `Context#SetPaginationHeaders` can link to previous page.
+		PrevCursor string
+
+		config PaginationConfig
+	}
+)
+
+// DefaultPaginationConfig is the default config of `Context#Pagination`.
+var DefaultPaginationConfig = PaginationConfig{
+	LimitParam:   "limit",
+	OffsetParam:  "offset",
+	CursorParam:  "cursor",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+func (c *context) Pagination(config PaginationConfig) (Pagination, error) {
+	if config.LimitParam == "" {
+		config.LimitParam = DefaultPaginationConfig.LimitParam
+	}
+	if config.OffsetParam == "" {
+		config.OffsetParam = DefaultPaginationConfig.OffsetParam
+	}
+	if config.CursorParam == "" {
+		config.CursorParam = DefaultPaginationConfig.CursorParam
+	}
+	if config.MaxLimit == 0 {
+		config.MaxLimit = DefaultPaginationConfig.MaxLimit
+	}
+	if config.DefaultLimit == 0 {
+		config.DefaultLimit = DefaultPaginationConfig.DefaultLimit
+	}
+	if config.DefaultLimit > config.MaxLimit {
+		config.DefaultLimit = config.MaxLimit
+	}
+
+	p := Pagination{Limit: config.DefaultLimit, config: config}
+	if v := c.QueryParam(config.LimitParam); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return p, NewHTTPError(http.StatusBadRequest, config.LimitParam+" must be positive integer")
+		}
+		if limit > config.MaxLimit {
+			limit = config.MaxLimit
+		}
+		p.Limit = limit
+	}
+	if p.Cursor = c.QueryParam(config.CursorParam); p.Cursor != "" {
+		return p, nil // offset is meaningless with cursor
+	}
+	if v := c.QueryParam(config.OffsetParam); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return p, NewHTTPError(http.StatusBadRequest, config.OffsetParam+" must be non-negative integer")
+		}
+		p.Offset = offset
+	}
+	return p, nil
+}
+
+func (c *context) SetPaginationHeaders(p Pagination, total int64) {
+	h := c.response.Header()
+	if total >= 0 {
+		h.Set(HeaderXTotalCount, strconv.FormatInt(total, 10))
+	}
+	if p.config.LimitParam == "" {
+		p.config = DefaultPaginationConfig
+	}
+	if p.Limit < 1 {
+		p.Limit = p.config.DefaultLimit
+	}
+
+	var links []string
+	link := func(rel string, set func(q url.Values)) {
+		u := *c.request.URL
+		q := u.Query()
+		set(q)
+		u.RawQuery = q.Encode()
+		links = append(links, "<"+c.Scheme()+"://"+c.request.Host+u.RequestURI()+`>; rel="`+rel+`"`)
+	}
+	if p.NextCursor != "" || p.PrevCursor != "" {
+		cursorLink := func(rel, cursor string) {
+			link(rel, func(q url.Values) {
+				q[p.config.CursorParam] = []string{cursor}
+				q[p.config.LimitParam] = []string{strconv.Itoa(p.Limit)}
+				delete(q, p.config.OffsetParam)
+			})
+		}
+		if p.NextCursor != "" {
+			cursorLink("next", p.NextCursor)
+		}
+		if p.PrevCursor != "" {
+			cursorLink("prev", p.PrevCursor)
+		}
+	} else {
+		offsetLink := func(rel string, offset int64) {
+			link(rel, func(q url.Values) {
+				q[p.config.OffsetParam] = []string{strconv.FormatInt(offset, 10)}
+				q[p.config.LimitParam] = []string{strconv.Itoa(p.Limit)}
+			})
+		}
+		limit, offset := int64(p.Limit), int64(p.Offset)
+		if total >= 0 && offset+limit < total {
+			offsetLink("next", offset+limit)
+		}
+		if offset > 0 {
+			prev := offset - limit
+			if prev < 0 {
+				prev = 0
+			}
+			offsetLink("prev", prev)
+		}
+		if total >= 0 {
+			offsetLink("first", 0)
+			last := int64(0)
+			if total > 0 {
+				last = (total - 1) / limit * limit
+			}
+			offsetLink("last", last)
+		}
+	}
+	if len(links) > 0 {
+		h.Set(HeaderLink, strings.Join(links, ", "))
+	}
+}
@@ -0,0 +1,144 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_Pagination(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenConfig PaginationConfig
+		whenURL    string
+		expect     Pagination
+		expectErr  string
+	}{
+		{
+			name:    "ok, defaults",
+			whenURL: "/users",
+			expect:  Pagination{Limit: 20},
+		},
+		{
+			name:    "ok, limit and offset",
+			whenURL: "/users?limit=50&offset=100",
+			expect:  Pagination{Limit: 50, Offset: 100},
+		},
+		{
+			name:    "ok, limit is capped",
+			whenURL: "/users?limit=1000",
+			expect:  Pagination{Limit: 100},
+		},
+		{
+			name:    "ok, cursor ignores offset",
+			whenURL: "/users?cursor=abc&offset=10&limit=5",
+			expect:  Pagination{Limit: 5, Cursor: "abc"},
+		},
+		{
+			name:       "ok, custom config",
+			whenConfig: PaginationConfig{LimitParam: "per_page", OffsetParam: "skip", DefaultLimit: 50, MaxLimit: 10},
+			whenURL:    "/users?skip=3",
+			expect:     Pagination{Limit: 10, Offset: 3},
+		},
+		{
+			name:      "nok, invalid limit",
+			whenURL:   "/users?limit=0",
+			expectErr: "code=400, message=limit must be positive integer",
+		},
+		{
+			name:      "nok, invalid offset",
+			whenURL:   "/users?offset=-1",
+			expectErr: "code=400, message=offset must be non-negative integer",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			p, err := c.Pagination(tc.whenConfig)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect.Limit, p.Limit)
+			assert.Equal(t, tc.expect.Offset, p.Offset)
+			assert.Equal(t, tc.expect.Cursor, p.Cursor)
+		})
+	}
+}
+
+func TestContext_SetPaginationHeaders(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		whenURL          string
+		whenNextCursor   string
+		whenPrevCursor   string
+		whenTotal        int64
+		expectLink       string
+		expectTotalCount string
+	}{
+		{
+			name:      "ok, first page",
+			whenURL:   "/users?limit=10&sort=name",
+			whenTotal: 25,
+			expectLink: `<http://example.com/users?limit=10&offset=10&sort=name>; rel="next", ` +
+				`<http://example.com/users?limit=10&offset=0&sort=name>; rel="first", ` +
+				`<http://example.com/users?limit=10&offset=20&sort=name>; rel="last"`,
+			expectTotalCount: "25",
+		},
+		{
+			name:      "ok, last page",
+			whenURL:   "/users?limit=10&offset=20",
+			whenTotal: 25,
+			expectLink: `<http://example.com/users?limit=10&offset=10>; rel="prev", ` +
+				`<http://example.com/users?limit=10&offset=0>; rel="first", ` +
+				`<http://example.com/users?limit=10&offset=20>; rel="last"`,
+			expectTotalCount: "25",
+		},
+		{
+			name:             "ok, unknown total",
+			whenURL:          "/users?limit=10&offset=5",
+			whenTotal:        -1,
+			expectLink:       `<http://example.com/users?limit=10&offset=0>; rel="prev"`,
+			expectTotalCount: "",
+		},
+		{
+			name:             "ok, empty list",
+			whenURL:          "/users",
+			whenTotal:        0,
+			expectLink:       `<http://example.com/users?limit=20&offset=0>; rel="first", <http://example.com/users?limit=20&offset=0>; rel="last"`,
+			expectTotalCount: "0",
+		},
+		{
+			name:           "ok, cursors",
+			whenURL:        "/users?cursor=b&limit=5&offset=3",
+			whenNextCursor: "c",
+			whenPrevCursor: "a",
+			whenTotal:      -1,
+			expectLink: `<http://example.com/users?cursor=c&limit=5>; rel="next", ` +
+				`<http://example.com/users?cursor=a&limit=5>; rel="prev"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			p, err := c.Pagination(PaginationConfig{})
+			assert.NoError(t, err)
+			p.NextCursor = tc.whenNextCursor
+			p.PrevCursor = tc.whenPrevCursor
+			c.SetPaginationHeaders(p, tc.whenTotal)
+
+			assert.Equal(t, tc.expectLink, rec.Header().Get(HeaderLink))
+			assert.Equal(t, tc.expectTotalCount, rec.Header().Get(HeaderXTotalCount))
+		})
+	}
+}
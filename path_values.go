@@ -0,0 +1,9 @@
+//go:build !go1.22
+// +build !go1.22
+
+package echo
+
+import "net/http"
+
+// setPathValues is no-op as `http.Request#PathValue` is available since Go 1.22.
+func setPathValues(r *http.Request, names, values []string) {}
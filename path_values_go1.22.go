@@ -0,0 +1,16 @@
+//go:build go1.22
+// +build go1.22
+
+package echo
+
+import "net/http"
+
+// setPathValues makes path parameters (including named wildcards i.e. `filepath` of `/files/*filepath`) available
+// to wrapped `http.Handler` with `http.Request#PathValue`.
+func setPathValues(r *http.Request, names, values []string) {
+	for i, name := range names {
+		if i < len(values) {
+			r.SetPathValue(name, values[i])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+//go:build go1.22
+// +build go1.22
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapHandler_pathValues(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenRoute  string
+		whenURL     string
+		expectValue map[string]string
+	}{
+		{
+			name:        "ok, named wildcard",
+			givenRoute:  "/files/*filepath",
+			whenURL:     "/files/a/b.txt",
+			expectValue: map[string]string{"filepath": "a/b.txt"},
+		},
+		{
+			name:        "ok, named wildcard followed by static suffix",
+			givenRoute:  "/users/:id/docs/*doc/raw",
+			whenURL:     "/users/1/docs/a/b/raw",
+			expectValue: map[string]string{"id": "1", "doc": "a/b"},
+		},
+		{
+			name:        "ok, unnamed wildcard",
+			givenRoute:  "/static/*",
+			whenURL:     "/static/css/app.css",
+			expectValue: map[string]string{"*": "css/app.css"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			values := map[string]string{}
+			e.GET(tc.givenRoute, WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name := range tc.expectValue {
+					values[name] = r.PathValue(name)
+				}
+			})))
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectValue, values)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package echo
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (c *context) EvaluatePreconditions(etag string, lastModified time.Time) (int, bool) {
+	etag = normalizeETag(etag)
+	h := c.request.Header
+	method := c.request.Method
+	isRead := method == http.MethodGet || method == http.MethodHead
+	exists := etag != "" || !lastModified.IsZero()
+
+	// RFC 9110, section 13.2.2: If-Match takes precedence over If-Unmodified-Since
+	if im := h.Get(HeaderIfMatch); im != "" {
+		if !matchETags(im, etag, exists, true) {
+			return http.StatusPreconditionFailed, false
+		}
+	} else if ius, err := http.ParseTime(h.Get(HeaderIfUnmodifiedSince)); err == nil && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(ius) {
+			return http.StatusPreconditionFailed, false
+		}
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since
+	if inm := h.Get(HeaderIfNoneMatch); inm != "" {
+		if matchETags(inm, etag, exists, false) {
+			if isRead {
+				return http.StatusNotModified, false
+			}
+			return http.StatusPreconditionFailed, false
+		}
+	} else if isRead && !lastModified.IsZero() {
+		if ims, err := http.ParseTime(h.Get(HeaderIfModifiedSince)); err == nil && !lastModified.Truncate(time.Second).After(ims) {
+			return http.StatusNotModified, false
+		}
+	}
+	return 0, true
+}
+
+// matchETags checks if entity tag matches any of tags in `If-Match` or `If-None-Match` header value using strong
+// or weak comparison. Wildcard "*" matches any existing representation.
+func matchETags(header, etag string, exists bool, strong bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return exists
+		}
+		if etag == "" {
+			continue
+		}
+		if strong {
+			if !strings.HasPrefix(tag, "W/") && !strings.HasPrefix(etag, "W/") && tag == etag {
+				return true
+			}
+			continue
+		}
+		if strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeETag quotes entity tag given without quotes (i.e. `v1` becomes `"v1"`).
+func normalizeETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
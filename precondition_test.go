@@ -0,0 +1,160 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_EvaluatePreconditions(t *testing.T) {
+	modified := time.Date(2021, 6, 1, 12, 0, 0, 500, time.UTC)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+	same := modified.Format(http.TimeFormat)
+
+	var testCases = []struct {
+		name             string
+		whenMethod       string
+		whenHeaders      map[string]string
+		whenETag         string
+		whenLastModified time.Time
+		expectStatus     int
+		expectOK         bool
+	}{
+		{
+			name:       "ok, no preconditions",
+			whenMethod: http.MethodGet,
+			whenETag:   `"v1"`,
+			expectOK:   true,
+		},
+		{
+			name:        "ok, if-match matches",
+			whenMethod:  http.MethodPut,
+			whenHeaders: map[string]string{HeaderIfMatch: `"v0", "v1"`},
+			whenETag:    `"v1"`,
+			expectOK:    true,
+		},
+		{
+			name:         "nok, if-match does not match",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfMatch: `"v0"`},
+			whenETag:     `"v1"`,
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:         "nok, if-match uses strong comparison",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfMatch: `W/"v1"`},
+			whenETag:     `W/"v1"`,
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:        "ok, if-match wildcard with existing resource",
+			whenMethod:  http.MethodPut,
+			whenHeaders: map[string]string{HeaderIfMatch: "*"},
+			whenETag:    "v1",
+			expectOK:    true,
+		},
+		{
+			name:         "nok, if-match wildcard without resource",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfMatch: "*"},
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:             "ok, if-match has precedence over if-unmodified-since",
+			whenMethod:       http.MethodPut,
+			whenHeaders:      map[string]string{HeaderIfMatch: `"v1"`, HeaderIfUnmodifiedSince: before},
+			whenETag:         `"v1"`,
+			whenLastModified: modified,
+			expectOK:         true,
+		},
+		{
+			name:             "nok, modified since if-unmodified-since",
+			whenMethod:       http.MethodDelete,
+			whenHeaders:      map[string]string{HeaderIfUnmodifiedSince: before},
+			whenLastModified: modified,
+			expectStatus:     http.StatusPreconditionFailed,
+		},
+		{
+			name:             "ok, not modified since if-unmodified-since",
+			whenMethod:       http.MethodDelete,
+			whenHeaders:      map[string]string{HeaderIfUnmodifiedSince: same},
+			whenLastModified: modified,
+			expectOK:         true,
+		},
+		{
+			name:         "nok, if-none-match matches weakly for read",
+			whenMethod:   http.MethodGet,
+			whenHeaders:  map[string]string{HeaderIfNoneMatch: `W/"v1"`},
+			whenETag:     `"v1"`,
+			expectStatus: http.StatusNotModified,
+		},
+		{
+			name:         "nok, if-none-match matches for write",
+			whenMethod:   http.MethodPost,
+			whenHeaders:  map[string]string{HeaderIfNoneMatch: `"v1"`},
+			whenETag:     `"v1"`,
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:        "ok, if-none-match wildcard without resource (create only if absent)",
+			whenMethod:  http.MethodPut,
+			whenHeaders: map[string]string{HeaderIfNoneMatch: "*"},
+			expectOK:    true,
+		},
+		{
+			name:             "ok, if-none-match has precedence over if-modified-since",
+			whenMethod:       http.MethodGet,
+			whenHeaders:      map[string]string{HeaderIfNoneMatch: `"v0"`, HeaderIfModifiedSince: after},
+			whenETag:         `"v1"`,
+			whenLastModified: modified,
+			expectOK:         true,
+		},
+		{
+			name:             "nok, not modified since if-modified-since",
+			whenMethod:       http.MethodHead,
+			whenHeaders:      map[string]string{HeaderIfModifiedSince: same},
+			whenLastModified: modified,
+			expectStatus:     http.StatusNotModified,
+		},
+		{
+			name:             "ok, modified since if-modified-since",
+			whenMethod:       http.MethodGet,
+			whenHeaders:      map[string]string{HeaderIfModifiedSince: before},
+			whenLastModified: modified,
+			expectOK:         true,
+		},
+		{
+			name:             "ok, if-modified-since is ignored for writes",
+			whenMethod:       http.MethodPut,
+			whenHeaders:      map[string]string{HeaderIfModifiedSince: after},
+			whenLastModified: modified,
+			expectOK:         true,
+		},
+		{
+			name:             "ok, invalid date is ignored",
+			whenMethod:       http.MethodGet,
+			whenHeaders:      map[string]string{HeaderIfModifiedSince: "yesterday"},
+			whenLastModified: modified,
+			expectOK:         true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(tc.whenMethod, "/", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			status, ok := c.EvaluatePreconditions(tc.whenETag, tc.whenLastModified)
+			assert.Equal(t, tc.expectStatus, status)
+			assert.Equal(t, tc.expectOK, ok)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package echo
+
+// Principal is the authenticated identity (user or service) of a request. It is set by authentication
+// middleware with `Context#SetUser` and read by handlers and authorization middleware with `Context#User`.
+type Principal struct {
+	// Subject uniquely identifies the principal, i.e. OpenID Connect `sub` claim or user ID.
+	Subject string `json:"sub"`
+
+	// Name is the display name of the principal.
+	Name string `json:"name,omitempty"`
+
+	// Email is the email address of the principal.
+	Email string `json:"email,omitempty"`
+
+	// Scopes lists scopes or permissions granted to the principal.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Claims holds all attributes provided by the authentication method, i.e. ID token claims.
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+// HasScope checks if principal was granted the scope.
+func (p *Principal) HasScope(scope string) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package echo
+
+import (
+	"net/url"
+	"strings"
+)
+
+type (
+	// JSONAPIQuery is query string parsed according to JSON:API conventions (https://jsonapi.org/format/#fetching).
+	//
+	// Example: `?include=author,comments.author&fields[articles]=title,body&sort=-created,title&filter[tag]=go&page[number]=2`
+	JSONAPIQuery struct {
+		// Include contains relationship paths from `include` parameter.
+		Include []string
+		// Fields contains sparse fieldsets from `fields[type]` parameters keyed by type.
+		Fields map[string][]string
+		// Sort contains sort fields from `sort` parameter in given order.
+		Sort []SortField
+		// Filter contains `filter[...]` parameters.
+		Filter url.Values
+		// Page contains `page[...]` parameters.
+		Page url.Values
+	}
+
+	// SortField is single field of `sort` query parameter.
+	SortField struct {
+		Field string
+		// Desc is true when field was prefixed with `-`.
+		Desc bool
+	}
+)
+
+// QueryParamsWithPrefix returns values named `prefix[key]` as `url.Values` keyed by `key`. Nested keys keep their
+// remaining brackets so result can be split again i.e. `filter[author][name]` results in `author[name]` for prefix
+// "filter".
+func QueryParamsWithPrefix(values url.Values, prefix string) url.Values {
+	result := url.Values{}
+	start := prefix + "["
+	for name, v := range values {
+		if !strings.HasPrefix(name, start) {
+			continue
+		}
+		rest := name[len(start):]
+		end := strings.IndexByte(rest, ']')
+		if end <= 0 {
+			continue
+		}
+		key := rest[:end] + rest[end+1:]
+		result[key] = append(result[key], v...)
+	}
+	return result
+}
+
+// ParseJSONAPIQuery parses query parameters according to JSON:API conventions. Comma separated values of
+// `include`, `fields[type]` and `sort` are split and empty items are skipped.
+func ParseJSONAPIQuery(values url.Values) JSONAPIQuery {
+	q := JSONAPIQuery{
+		Include: splitCommaValues(values["include"]),
+		Fields:  map[string][]string{},
+		Filter:  QueryParamsWithPrefix(values, "filter"),
+		Page:    QueryParamsWithPrefix(values, "page"),
+	}
+	for typ, v := range QueryParamsWithPrefix(values, "fields") {
+		q.Fields[typ] = splitCommaValues(v)
+	}
+	for _, field := range splitCommaValues(values["sort"]) {
+		sf := SortField{Field: field}
+		if strings.HasPrefix(field, "-") {
+			sf = SortField{Field: field[1:], Desc: true}
+		}
+		if sf.Field != "" {
+			q.Sort = append(q.Sort, sf)
+		}
+	}
+	return q
+}
+
+func splitCommaValues(values []string) []string {
+	var result []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
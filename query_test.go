@@ -0,0 +1,79 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryParamsWithPrefix(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenQuery  string
+		whenPrefix string
+		expect     url.Values
+	}{
+		{
+			name:       "ok",
+			whenQuery:  "filter[name]=jon&filter[age]=20&filter[age]=30&sort=name",
+			whenPrefix: "filter",
+			expect:     url.Values{"name": {"jon"}, "age": {"20", "30"}},
+		},
+		{
+			name:       "ok, nested keys keep remaining brackets",
+			whenQuery:  "filter[author][name]=jon",
+			whenPrefix: "filter",
+			expect:     url.Values{"author[name]": {"jon"}},
+		},
+		{
+			name:       "ok, empty or unclosed keys are skipped",
+			whenQuery:  "filter[]=1&filter[x=2&filter=3&filters[a]=4",
+			whenPrefix: "filter",
+			expect:     url.Values{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.whenQuery)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expect, QueryParamsWithPrefix(values, tc.whenPrefix))
+		})
+	}
+}
+
+func TestContext_QueryParamsWithPrefix(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?page[number]=2&page[size]=10", nil)
+	c := e.NewContext(req, nil)
+
+	assert.Equal(t, url.Values{"number": {"2"}, "size": {"10"}}, c.QueryParamsWithPrefix("page"))
+}
+
+func TestParseJSONAPIQuery(t *testing.T) {
+	values, err := url.ParseQuery(
+		"include=author,comments.author&fields[articles]=title,body&fields[people]=name" +
+			"&sort=-created,title,,-&filter[tag]=go&page[number]=2&unknown=1",
+	)
+	assert.NoError(t, err)
+
+	q := ParseJSONAPIQuery(values)
+
+	assert.Equal(t, JSONAPIQuery{
+		Include: []string{"author", "comments.author"},
+		Fields: map[string][]string{
+			"articles": {"title", "body"},
+			"people":   {"name"},
+		},
+		Sort: []SortField{
+			{Field: "created", Desc: true},
+			{Field: "title"},
+		},
+		Filter: url.Values{"tag": {"go"}},
+		Page:   url.Values{"number": {"2"}},
+	}, q)
+}
@@ -0,0 +1,78 @@
+package echo
+
+import (
+	"html/template"
+	"io"
+	"sync"
+)
+
+type (
+	// TemplateRendererConfig defines the config for TemplateRenderer.
+	TemplateRendererConfig struct {
+		// Patterns are glob patterns of template files (i.e. "views/*.html"). See `template.ParseGlob`.
+		// Required.
+		Patterns []string
+
+		// Funcs are functions available in templates.
+		// Optional.
+		Funcs template.FuncMap
+	}
+
+	// TemplateRenderer is `Renderer` executing `html/template` templates parsed from files. When `Echo#Debug` is
+	// enabled templates are parsed again for every render so changes to template files are visible without restart.
+	TemplateRenderer struct {
+		config    TemplateRendererConfig
+		mu        sync.RWMutex
+		templates *template.Template
+	}
+)
+
+// NewTemplateRenderer creates TemplateRenderer and parses templates matching patterns.
+func NewTemplateRenderer(patterns ...string) (*TemplateRenderer, error) {
+	return NewTemplateRendererWithConfig(TemplateRendererConfig{Patterns: patterns})
+}
+
+// NewTemplateRendererWithConfig creates TemplateRenderer with config and parses templates.
+func NewTemplateRendererWithConfig(config TemplateRendererConfig) (*TemplateRenderer, error) {
+	r := &TemplateRenderer{config: config}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload parses templates again.
+func (r *TemplateRenderer) Reload() error {
+	t, err := r.parse()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.templates = t
+	r.mu.Unlock()
+	return nil
+}
+
+// Render renders template by name.
+func (r *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c Context) error {
+	if c != nil && c.Echo().Debug {
+		if err := r.Reload(); err != nil {
+			return err
+		}
+	}
+	r.mu.RLock()
+	t := r.templates
+	r.mu.RUnlock()
+	return t.ExecuteTemplate(w, name, data)
+}
+
+func (r *TemplateRenderer) parse() (*template.Template, error) {
+	t := template.New("").Funcs(r.config.Funcs)
+	for _, pattern := range r.config.Patterns {
+		var err error
+		if t, err = t.ParseGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
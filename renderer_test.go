@@ -0,0 +1,72 @@
+package echo
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "renderer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "hello.html")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{{define "hello"}}Hello, {{upper .}}!{{end}}`), 0600))
+
+	r, err := NewTemplateRendererWithConfig(TemplateRendererConfig{
+		Patterns: []string{filepath.Join(dir, "*.html")},
+		Funcs:    template.FuncMap{"upper": strings.ToUpper},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	e := New()
+	e.Renderer = r
+	render := func() string {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		assert.NoError(t, c.Render(http.StatusOK, "hello", "<jon>"))
+		return rec.Body.String()
+	}
+	assert.Equal(t, "Hello, &lt;JON&gt;!", render())
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{{define "hello"}}Hi, {{.}}!{{end}}`), 0600))
+	assert.Equal(t, "Hello, &lt;JON&gt;!", render(), "templates are cached without debug mode")
+
+	e.Debug = true
+	assert.Equal(t, "Hi, &lt;jon&gt;!", render(), "templates are reloaded in debug mode")
+}
+
+func TestTemplateRenderer_errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "renderer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "broken.html")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{{define "broken"}}{{.Name}`), 0600))
+
+	r, err := NewTemplateRenderer(filepath.Join(dir, "*.html"))
+	assert.Nil(t, r)
+	assert.Error(t, err)
+
+	_, err = NewTemplateRenderer(filepath.Join(dir, "*.tmpl"))
+	assert.EqualError(t, err, "html/template: pattern matches no files: `"+filepath.Join(dir, "*.tmpl")+"`")
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{{define "ok"}}ok{{end}}`), 0600))
+	r, err = NewTemplateRenderer(filepath.Join(dir, "*.html"))
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{{define "ok"}}{{.Name}`), 0600))
+
+	e := New()
+	e.Debug = true
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Error(t, r.Render(new(bytes.Buffer), "ok", nil, c))
+	assert.NoError(t, r.Render(new(bytes.Buffer), "ok", nil, nil), "last successfully parsed templates are kept")
+}
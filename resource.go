@@ -0,0 +1,130 @@
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type (
+	// ResourceIndexer is implemented by resource controllers listing resources. Served as `GET /path`.
+	ResourceIndexer interface {
+		Index(c Context) error
+	}
+
+	// ResourceCreator is implemented by resource controllers creating resources. Served as `POST /path`.
+	ResourceCreator interface {
+		Create(c Context) error
+	}
+
+	// ResourceShower is implemented by resource controllers returning single resource. Served as `GET /path/:id`.
+	ResourceShower interface {
+		Show(c Context) error
+	}
+
+	// ResourceUpdater is implemented by resource controllers replacing resources. Served as `PUT /path/:id`.
+	ResourceUpdater interface {
+		Update(c Context) error
+	}
+
+	// ResourcePatcher is implemented by resource controllers partially updating resources. Served as
+	// `PATCH /path/:id`.
+	ResourcePatcher interface {
+		Patch(c Context) error
+	}
+
+	// ResourceDeleter is implemented by resource controllers deleting resources. Served as `DELETE /path/:id`.
+	ResourceDeleter interface {
+		Delete(c Context) error
+	}
+
+	// ResourceMiddlewarer is implemented by resource controllers that need action specific middleware. Action is
+	// one of `ResourceActionIndex`, `ResourceActionCreate`, `ResourceActionShow`, `ResourceActionUpdate`,
+	// `ResourceActionPatch` or `ResourceActionDelete`.
+	ResourceMiddlewarer interface {
+		ActionMiddleware(action string) []MiddlewareFunc
+	}
+)
+
+// Resource controller actions
+const (
+	ResourceActionIndex  = "index"
+	ResourceActionCreate = "create"
+	ResourceActionShow   = "show"
+	ResourceActionUpdate = "update"
+	ResourceActionPatch  = "patch"
+	ResourceActionDelete = "delete"
+)
+
+// Resource registers conventional REST routes for controller actions. Controller implements any of
+// `ResourceIndexer`, `ResourceCreator`, `ResourceShower`, `ResourceUpdater`, `ResourcePatcher` and
+// `ResourceDeleter` interfaces.
+// Resource identifier is available as path parameter "id". Routes are named `<name>.<action>` where name is
+// derived from path, i.e. "users.show" for path "/users" and "orgs.users.show" for path "/orgs/:org/users".
+//
+// Example:
+//
+//	e.Resource("/users", &UserController{}, middleware.KeyAuth(validator))
+//
+// registers
+//
+//	GET    /users     -> Index  (users.index)
+//	POST   /users     -> Create (users.create)
+//	GET    /users/:id -> Show   (users.show)
+//	PUT    /users/:id -> Update (users.update)
+//	PATCH  /users/:id -> Patch  (users.patch)
+//	DELETE /users/:id -> Delete (users.delete)
+func (e *Echo) Resource(path string, controller interface{}, middleware ...MiddlewareFunc) []*Route {
+	return e.resource(path, controller, e.Add, middleware...)
+}
+
+func (common) resource(path string, controller interface{}, add func(string, string, HandlerFunc, ...MiddlewareFunc) *Route, middleware ...MiddlewareFunc) []*Route {
+	path = strings.TrimSuffix(path, "/")
+	name := resourceName(path)
+	mc, _ := controller.(ResourceMiddlewarer)
+
+	var routes []*Route
+	register := func(action string, h HandlerFunc, method string, path string) {
+		m := middleware
+		if mc != nil {
+			m = append(append([]MiddlewareFunc(nil), middleware...), mc.ActionMiddleware(action)...)
+		}
+		r := add(method, path, h, m...)
+		r.Name = name + "." + action
+		routes = append(routes, r)
+	}
+	if c, ok := controller.(ResourceIndexer); ok {
+		register(ResourceActionIndex, c.Index, http.MethodGet, path)
+	}
+	if c, ok := controller.(ResourceCreator); ok {
+		register(ResourceActionCreate, c.Create, http.MethodPost, path)
+	}
+	if c, ok := controller.(ResourceShower); ok {
+		register(ResourceActionShow, c.Show, http.MethodGet, path+"/:id")
+	}
+	if c, ok := controller.(ResourceUpdater); ok {
+		register(ResourceActionUpdate, c.Update, http.MethodPut, path+"/:id")
+	}
+	if c, ok := controller.(ResourcePatcher); ok {
+		register(ResourceActionPatch, c.Patch, http.MethodPatch, path+"/:id")
+	}
+	if c, ok := controller.(ResourceDeleter); ok {
+		register(ResourceActionDelete, c.Delete, http.MethodDelete, path+"/:id")
+	}
+	if len(routes) == 0 {
+		panic(fmt.Sprintf("echo: resource controller %T for path %q implements no actions", controller, path))
+	}
+	return routes
+}
+
+// resourceName returns route name prefix for resource path, i.e. "orgs.users" for "/orgs/:org/users".
+func resourceName(path string) string {
+	var parts []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment[0] == ':' || segment[0] == '*' {
+			continue
+		}
+		parts = append(parts, segment)
+	}
+	return strings.Join(parts, ".")
+}
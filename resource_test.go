@@ -0,0 +1,101 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFullController struct{}
+
+func (testFullController) Index(c Context) error  { return c.String(http.StatusOK, "index") }
+func (testFullController) Create(c Context) error { return c.String(http.StatusCreated, "create") }
+func (testFullController) Show(c Context) error {
+	return c.String(http.StatusOK, "show:"+c.Param("id"))
+}
+func (testFullController) Update(c Context) error {
+	return c.String(http.StatusOK, "update:"+c.Param("id"))
+}
+func (testFullController) Patch(c Context) error {
+	return c.String(http.StatusOK, "patch:"+c.Param("id"))
+}
+func (testFullController) Delete(c Context) error { return c.NoContent(http.StatusNoContent) }
+
+func (testFullController) ActionMiddleware(action string) []MiddlewareFunc {
+	if action != ResourceActionDelete {
+		return nil
+	}
+	return []MiddlewareFunc{func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return ErrForbidden
+		}
+	}}
+}
+
+type testShowController struct{}
+
+func (testShowController) Show(c Context) error {
+	return c.String(http.StatusOK, "show:"+c.Param("id"))
+}
+
+func TestEcho_Resource(t *testing.T) {
+	e := New()
+	routes := e.Resource("/users/", testFullController{})
+
+	names := map[string]string{}
+	for _, r := range routes {
+		names[r.Method+" "+r.Path] = r.Name
+	}
+	assert.Equal(t, map[string]string{
+		"GET /users":        "users.index",
+		"POST /users":       "users.create",
+		"GET /users/:id":    "users.show",
+		"PUT /users/:id":    "users.update",
+		"PATCH /users/:id":  "users.patch",
+		"DELETE /users/:id": "users.delete",
+	}, names)
+
+	var testCases = []struct {
+		whenMethod string
+		whenURL    string
+		expectCode int
+		expectBody string
+	}{
+		{whenMethod: http.MethodGet, whenURL: "/users", expectCode: http.StatusOK, expectBody: "index"},
+		{whenMethod: http.MethodPost, whenURL: "/users", expectCode: http.StatusCreated, expectBody: "create"},
+		{whenMethod: http.MethodGet, whenURL: "/users/1", expectCode: http.StatusOK, expectBody: "show:1"},
+		{whenMethod: http.MethodPut, whenURL: "/users/1", expectCode: http.StatusOK, expectBody: "update:1"},
+		{whenMethod: http.MethodPatch, whenURL: "/users/1", expectCode: http.StatusOK, expectBody: "patch:1"},
+		{whenMethod: http.MethodDelete, whenURL: "/users/1", expectCode: http.StatusForbidden, expectBody: "{\"message\":\"Forbidden\"}\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenMethod+" "+tc.whenURL, func(t *testing.T) {
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestGroup_Resource(t *testing.T) {
+	e := New()
+	routes := e.Group("/api").Resource("/orgs/:org/users", testShowController{})
+
+	if assert.Len(t, routes, 1) {
+		assert.Equal(t, "/api/orgs/:org/users/:id", routes[0].Path)
+		assert.Equal(t, "orgs.users.show", routes[0].Name)
+	}
+	assert.Equal(t, "/api/orgs/acme/users/1", e.Reverse("orgs.users.show", "acme", 1))
+}
+
+func TestEcho_ResourceWithoutActions(t *testing.T) {
+	e := New()
+	assert.PanicsWithValue(t, `echo: resource controller struct {} for path "/users" implements no actions`, func() {
+		e.Resource("/users", struct{}{})
+	})
+}
@@ -18,6 +18,13 @@ type (
 		Status      int
 		Size        int64
 		Committed   bool
+
+		// ContentEncoding is the content encoding (i.e. "gzip") applied to the body by middleware. Empty when
+		// body was sent as is.
+		ContentEncoding string
+		// EncodedSize is the number of bytes sent to the client after content encoding was applied. `Size` is the
+		// number of bytes written before encoding.
+		EncodedSize int64
 	}
 )
 
@@ -60,6 +67,11 @@ func (r *Response) WriteHeader(code int) {
 	for _, fn := range r.beforeFuncs {
 		fn()
 	}
+	if r.echo != nil && r.echo.Debug && r.Header().Get(HeaderCacheControl) == "" {
+		// in debug mode disable caching so changes are visible on reload, unless handler, middleware or route
+		// cache policy set caching headers
+		r.Header().Set(HeaderCacheControl, "no-store")
+	}
 	r.Writer.WriteHeader(r.Status)
 	r.Committed = true
 }
@@ -80,6 +92,15 @@ func (r *Response) Write(b []byte) (n int, err error) {
 	return
 }
 
+// BytesSent returns the number of body bytes sent to the client. For encoded responses it is `EncodedSize` and
+// `Size` otherwise.
+func (r *Response) BytesSent() int64 {
+	if r.ContentEncoding != "" {
+		return r.EncodedSize
+	}
+	return r.Size
+}
+
 // Flush implements the http.Flusher interface to allow an HTTP handler to flush
 // buffered data to the client.
 // See [http.Flusher](https://golang.org/pkg/net/http/#Flusher)
@@ -88,10 +109,27 @@ func (r *Response) Flush() {
 }
 
 // Hijack implements the http.Hijacker interface to allow an HTTP handler to
-// take over the connection.
+// take over the connection. Writers wrapped by middlewares are unwrapped (see `Response#Unwrap`) until writer
+// supporting hijacking is found. Returns `http.ErrNotSupported` when there is no such writer (i.e. HTTP/2 request).
 // See [http.Hijacker](https://golang.org/pkg/net/http/#Hijacker)
 func (r *Response) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	return r.Writer.(http.Hijacker).Hijack()
+	w := r.Writer
+	for {
+		switch t := w.(type) {
+		case http.Hijacker:
+			return t.Hijack()
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return nil, nil, http.ErrNotSupported
+		}
+	}
+}
+
+// Unwrap returns the original http.ResponseWriter. `http.ResponseController` uses it to access optional interfaces
+// of the wrapped writer.
+func (r *Response) Unwrap() http.ResponseWriter {
+	return r.Writer
 }
 
 func (r *Response) reset(w http.ResponseWriter) {
@@ -99,6 +137,8 @@ func (r *Response) reset(w http.ResponseWriter) {
 	r.afterFuncs = nil
 	r.Writer = w
 	r.Size = 0
+	r.ContentEncoding = ""
+	r.EncodedSize = 0
 	r.Status = http.StatusOK
 	r.Committed = false
 }
@@ -1,6 +1,8 @@
 package echo
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -72,3 +74,46 @@ func TestResponse_ChangeStatusCodeBeforeWrite(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestResponse_BytesSent(t *testing.T) {
+	e := New()
+	res := &Response{echo: e, Writer: httptest.NewRecorder()}
+	res.Write([]byte("test"))
+	assert.Equal(t, int64(4), res.BytesSent())
+
+	res.ContentEncoding = "gzip"
+	res.EncodedSize = 2
+	assert.Equal(t, int64(2), res.BytesSent())
+}
+
+type unwrappingWriter struct {
+	http.ResponseWriter
+}
+
+func (w *unwrappingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type hijackableWriter struct {
+	http.ResponseWriter
+	hijacked bool
+}
+
+func (w *hijackableWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func TestResponse_Hijack(t *testing.T) {
+	e := New()
+	hw := &hijackableWriter{ResponseWriter: httptest.NewRecorder()}
+	res := &Response{echo: e, Writer: &unwrappingWriter{ResponseWriter: hw}}
+
+	_, _, err := res.Hijack()
+	assert.NoError(t, err)
+	assert.True(t, hw.hijacked)
+
+	res = &Response{echo: e, Writer: &unwrappingWriter{ResponseWriter: httptest.NewRecorder()}}
+	_, _, err = res.Hijack()
+	assert.Equal(t, http.ErrNotSupported, err)
+}
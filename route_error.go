@@ -0,0 +1,199 @@
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Route registration errors
+var (
+	ErrRouteFrozen        = errors.New("echo instance is frozen")
+	ErrRouteInvalidMethod = errors.New("unsupported method")
+	ErrRouteNilHandler    = errors.New("handler is nil")
+	ErrRouteInvalidPath   = errors.New("named wildcard can only be followed by static path segments")
+	ErrRouteVersionExists = errors.New("handler for version is already registered")
+	ErrRouteDuplicateName = errors.New("route name is already used")
+	ErrRouteInvalidParam  = errors.New("optional params can only be followed by optional params")
+)
+
+// RouteError describes route that could not be registered. Wrapped `Err` is one of `ErrRoute*` errors.
+type RouteError struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+// Error returns error message with route method and path.
+func (e *RouteError) Error() string {
+	return fmt.Sprintf("echo: can not add route %s %s: %v", e.Method, e.Path, e.Err)
+}
+
+// Unwrap returns wrapped error.
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// TryAdd registers a new route like `Echo#Add` but returns error instead of panicking (or silently registering
+// unreachable route) when route can not be registered, i.e. instance is frozen, method is not supported by router,
+// handler is nil or path is not valid.
+func (e *Echo) TryAdd(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) (*Route, error) {
+	return e.tryAdd("", 0, method, path, handler, middleware...)
+}
+
+// TryAny registers a new route for all supported HTTP methods like `Echo#Any` but returns error instead of
+// panicking. See `Echo#TryMatch`.
+func (e *Echo) TryAny(path string, handler HandlerFunc, middleware ...MiddlewareFunc) ([]*Route, error) {
+	return e.TryMatch(methods[:], path, handler, middleware...)
+}
+
+// TryMatch registers a new route for multiple HTTP methods like `Echo#Match` but returns error instead of
+// panicking. Routes are checked before registration so none of them is added when one of them is not valid.
+func (e *Echo) TryMatch(methods []string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) ([]*Route, error) {
+	return e.tryMatch(methods, path, handler, func(method string) (*Route, error) {
+		return e.TryAdd(method, path, handler, middleware...)
+	})
+}
+
+// RouteErrors returns errors of route registrations collected when `RouterConfig.CollectRouteErrors` is enabled.
+func (e *Echo) RouteErrors() []*RouteError {
+	return append([]*RouteError(nil), e.routeErrors...)
+}
+
+func (e *Echo) tryAdd(host string, groupMiddleware int, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) (*Route, error) {
+	if err := e.checkRoute(method, path, handler); err != nil {
+		return nil, err
+	}
+	if e.RouterConfig.DuplicateRouteNames == DuplicateRouteNamesReject {
+		if err := e.checkRouteName(method, path, e.routeName(method, path, handler)); err != nil {
+			return nil, err
+		}
+	}
+	return e.add(host, groupMiddleware, method, path, handler, middleware...), nil
+}
+
+// tryMatch checks routes for all methods before adding any of them with add.
+func (e *Echo) tryMatch(methods []string, path string, handler HandlerFunc, add func(method string) (*Route, error)) ([]*Route, error) {
+	for _, m := range methods {
+		if err := e.checkRoute(m, path, handler); err != nil {
+			return nil, err
+		}
+	}
+	routes := make([]*Route, 0, len(methods))
+	for _, m := range methods {
+		r, err := add(m)
+		if err != nil {
+			return routes, err
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+func (e *Echo) checkRoute(method, path string, handler HandlerFunc) error {
+	var err error
+	switch {
+	case e.frozen:
+		err = ErrRouteFrozen
+	case !isSupportedMethod(method):
+		err = ErrRouteInvalidMethod
+	case handler == nil:
+		err = ErrRouteNilHandler
+	case !isValidWildcardPath(path):
+		err = ErrRouteInvalidPath
+	case !isValidOptionalParams(path):
+		err = ErrRouteInvalidParam
+	default:
+		return nil
+	}
+	return &RouteError{Method: method, Path: path, Err: err}
+}
+
+// checkRouteName returns error when route name is already used by route with different path. Routes with the same
+// path and different methods can share name as it reverses to the same URL.
+func (e *Echo) checkRouteName(method, path, name string) error {
+	if e.RouterConfig.DuplicateRouteNames == DuplicateRouteNamesAllow {
+		return nil
+	}
+	if e.lastRoute != nil {
+		e.indexRouteName(e.lastRoute) // catches name assigned to previous route i.e. `e.GET(...).Name = "x"`
+	}
+	r := e.routeNames[name]
+	if r != nil && r.Name != name {
+		e.reindexRouteNames()
+		r = e.routeNames[name]
+	}
+	if r == nil || normalizePath(r.Path) == normalizePath(path) {
+		return nil
+	}
+	return &RouteError{
+		Method: method,
+		Path:   path,
+		Err:    fmt.Errorf("%w: %q by %s %s", ErrRouteDuplicateName, name, r.Method, r.Path),
+	}
+}
+
+// verifyDuplicateRouteNames checks that routes (sorted by path and method) sharing name have the same path. Names
+// assigned after registration (`Route.Name`) are not known when route is registered so they are checked here.
+func verifyDuplicateRouteNames(routes []*Route) error {
+	byName := map[string][]*Route{}
+	names := make([]string, 0)
+	for _, r := range routes {
+		if _, ok := byName[r.Name]; !ok {
+			names = append(names, r.Name)
+		}
+		byName[r.Name] = append(byName[r.Name], r)
+	}
+	problems := make([]string, 0)
+	for _, name := range names {
+		named := byName[name]
+		for _, r := range named[1:] {
+			if normalizePath(r.Path) == normalizePath(named[0].Path) {
+				continue
+			}
+			used := make([]string, 0, len(named))
+			for _, r := range named {
+				used = append(used, r.Method+" "+r.Path)
+			}
+			problems = append(problems, fmt.Sprintf("route name %q is used by routes with different paths: %s", name, strings.Join(used, ", ")))
+			break
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("echo: invalid route names: %s", strings.Join(problems, "; "))
+}
+
+func (e *Echo) verifyRouteErrors() error {
+	if len(e.routeErrors) == 0 {
+		return nil
+	}
+	problems := make([]string, 0, len(e.routeErrors))
+	for _, err := range e.routeErrors {
+		problems = append(problems, fmt.Sprintf("%s %s: %v", err.Method, err.Path, err.Err))
+	}
+	return errors.New("echo: can not add routes: " + strings.Join(problems, "; "))
+}
+
+func isValidWildcardPath(path string) bool {
+	for i := 0; i < len(path); i++ {
+		if path[i] != '*' {
+			continue
+		}
+		j := i + 1
+		for ; j < len(path) && path[j] != '/'; j++ {
+		}
+		return j == i+1 || isStaticPath(path[j:]) // unnamed wildcard `*` can be followed by anything
+	}
+	return true
+}
+
+func isSupportedMethod(method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_TryAdd(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenMethod  string
+		whenHandler HandlerFunc
+		whenFrozen  bool
+		expectErr   error
+	}{
+		{
+			name:        "ok",
+			whenMethod:  http.MethodGet,
+			whenHandler: handlerFunc,
+		},
+		{
+			name:        "nok, unsupported method",
+			whenMethod:  "FETCH",
+			whenHandler: handlerFunc,
+			expectErr:   ErrRouteInvalidMethod,
+		},
+		{
+			name:       "nok, nil handler",
+			whenMethod: http.MethodGet,
+			expectErr:  ErrRouteNilHandler,
+		},
+		{
+			name:        "nok, frozen",
+			whenMethod:  http.MethodGet,
+			whenHandler: handlerFunc,
+			whenFrozen:  true,
+			expectErr:   ErrRouteFrozen,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			if tc.whenFrozen {
+				assert.NoError(t, e.Freeze())
+			}
+
+			r, err := e.Group("/api").TryAdd(tc.whenMethod, "/users", tc.whenHandler)
+			if tc.expectErr != nil {
+				assert.Nil(t, r)
+				assert.True(t, errors.Is(err, tc.expectErr))
+				var rErr *RouteError
+				assert.True(t, errors.As(err, &rErr))
+				assert.Equal(t, "/api/users", rErr.Path)
+				assert.Len(t, e.Routes(), 0)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "/api/users", r.Path)
+				assert.Len(t, e.Routes(), 1)
+			}
+		})
+	}
+}
+
+func TestEcho_CollectRouteErrors(t *testing.T) {
+	e := New()
+	e.RouterConfig.CollectRouteErrors = true
+
+	e.GET("/ok", handlerFunc)
+	e.POST("/nil", nil).Name = "nil"
+	e.Add("FETCH", "/fetch", handlerFunc)
+
+	errs := e.RouteErrors()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, &RouteError{Method: http.MethodPost, Path: "/nil", Err: ErrRouteNilHandler}, errs[0])
+	assert.Equal(t, "echo: can not add route FETCH /fetch: unsupported method", errs[1].Error())
+	assert.Len(t, e.Routes(), 1)
+
+	assert.EqualError(t, e.VerifyRoutes(), "echo: can not add routes: POST /nil: handler is nil; FETCH /fetch: unsupported method")
+	assert.Error(t, e.Freeze())
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGroup_TryMatch(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenMethods  []string
+		whenHandler  HandlerFunc
+		expectRoutes int
+		expectErr    error
+	}{
+		{
+			name:         "ok",
+			whenMethods:  []string{http.MethodGet, http.MethodPost},
+			whenHandler:  handlerFunc,
+			expectRoutes: 2,
+		},
+		{
+			name:        "nok, unsupported method adds no routes",
+			whenMethods: []string{http.MethodGet, "FETCH"},
+			whenHandler: handlerFunc,
+			expectErr:   ErrRouteInvalidMethod,
+		},
+		{
+			name:        "nok, nil handler",
+			whenMethods: []string{http.MethodGet},
+			expectErr:   ErrRouteNilHandler,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+
+			routes, err := e.Group("/api").TryMatch(tc.whenMethods, "/users", tc.whenHandler)
+			if tc.expectErr != nil {
+				assert.Nil(t, routes)
+				assert.True(t, errors.Is(err, tc.expectErr))
+				var rErr *RouteError
+				assert.True(t, errors.As(err, &rErr))
+				assert.Equal(t, "/api/users", rErr.Path)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, routes, tc.expectRoutes)
+				assert.Equal(t, "/api/users", routes[0].Path)
+			}
+			assert.Len(t, e.Routes(), tc.expectRoutes)
+		})
+	}
+}
+
+func TestEcho_TryAny(t *testing.T) {
+	e := New()
+
+	routes, err := e.TryAny("/users", handlerFunc)
+	assert.NoError(t, err)
+	assert.Len(t, routes, len(methods))
+
+	routes, err = e.Group("/api").TryAny("/users", nil)
+	assert.Nil(t, routes)
+	assert.True(t, errors.Is(err, ErrRouteNilHandler))
+
+	assert.NoError(t, e.Freeze())
+	_, err = e.TryMatch([]string{http.MethodGet}, "/late", handlerFunc)
+	assert.True(t, errors.Is(err, ErrRouteFrozen))
+	assert.Len(t, e.Routes(), len(methods))
+}
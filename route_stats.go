@@ -0,0 +1,144 @@
+package echo
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// RouteStats contains metrics of single route collected when `RouterConfig.CollectRouteStats` is enabled.
+	RouteStats struct {
+		Host   string `json:"host"`
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Name   string `json:"name"`
+		// Requests is the number of requests served.
+		Requests uint64 `json:"requests"`
+		// Errors is the number of requests where handler returned an error or responded with 5xx status.
+		Errors uint64 `json:"errors"`
+		// BytesIn is the number of request body bytes read.
+		BytesIn int64 `json:"bytes_in"`
+		// BytesOut is the number of response body bytes written.
+		BytesOut int64 `json:"bytes_out"`
+		// Duration is total time spent serving requests (including middlewares).
+		Duration time.Duration `json:"duration"`
+		// P50, P90 and P99 are estimated latency percentiles.
+		P50 time.Duration `json:"p50"`
+		P90 time.Duration `json:"p90"`
+		P99 time.Duration `json:"p99"`
+	}
+
+	// routeStatsCollector holds metrics of routes of single router.
+	routeStatsCollector struct {
+		mu     sync.RWMutex
+		routes map[string]*routeStats // key is method + path
+	}
+
+	routeStats struct {
+		mu       sync.Mutex
+		requests uint64
+		errors   uint64
+		bytesIn  int64
+		bytesOut int64
+		duration time.Duration
+		latency  *tdigest
+	}
+)
+
+// routeStatsCompression is t-digest compression for route latencies. Higher values are more accurate and use more memory.
+const routeStatsCompression = 100
+
+// Stats returns metrics of all registered routes sorted by host, path and method. Metrics are collected only when
+// `RouterConfig.CollectRouteStats` is enabled. It is meant for lightweight introspection (i.e. debug endpoint)
+// where full metrics stack is not available.
+func (e *Echo) Stats() []RouteStats {
+	result := make([]RouteStats, 0)
+	for host, router := range e.allRouters() {
+		for key, r := range router.routes {
+			s := RouteStats{Host: host, Method: r.Method, Path: r.Path, Name: r.Name}
+			if rs := router.stats.get(key); rs != nil {
+				rs.snapshot(&s)
+			}
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return result
+}
+
+// recordRouteStats records metrics of request served by matched route.
+func (e *Echo) recordRouteStats(c *context, start time.Time, err error) {
+	r := c.request
+	router := e.findRouter(r.Host)
+	key := r.Method + c.path
+	if _, ok := router.routes[key]; !ok {
+		return
+	}
+	router.stats.getOrCreate(key).record(
+		time.Since(start),
+		err != nil || c.response.Status >= http.StatusInternalServerError,
+		c.RequestBodyBytesRead(),
+		c.response.Size,
+	)
+}
+
+func newRouteStatsCollector() *routeStatsCollector {
+	return &routeStatsCollector{routes: map[string]*routeStats{}}
+}
+
+func (sc *routeStatsCollector) get(key string) *routeStats {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.routes[key]
+}
+
+func (sc *routeStatsCollector) getOrCreate(key string) *routeStats {
+	if rs := sc.get(key); rs != nil {
+		return rs
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	rs, ok := sc.routes[key]
+	if !ok {
+		rs = &routeStats{latency: newTDigest(routeStatsCompression)}
+		sc.routes[key] = rs
+	}
+	return rs
+}
+
+func (rs *routeStats) record(d time.Duration, isError bool, bytesIn int64, bytesOut int64) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.requests++
+	if isError {
+		rs.errors++
+	}
+	rs.bytesIn += bytesIn
+	rs.bytesOut += bytesOut
+	rs.duration += d
+	rs.latency.add(float64(d))
+}
+
+func (rs *routeStats) snapshot(s *RouteStats) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	s.Requests = rs.requests
+	s.Errors = rs.errors
+	s.BytesIn = rs.bytesIn
+	s.BytesOut = rs.bytesOut
+	s.Duration = rs.duration
+	s.P50 = time.Duration(rs.latency.quantile(0.5))
+	s.P90 = time.Duration(rs.latency.quantile(0.9))
+	s.P99 = time.Duration(rs.latency.quantile(0.99))
+}
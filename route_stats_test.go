@@ -0,0 +1,120 @@
+package echo
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_Stats(t *testing.T) {
+	e := New()
+	e.RouterConfig.CollectRouteStats = true
+	e.GET("/users/:id", func(c Context) error {
+		return c.String(http.StatusOK, "user")
+	}).Name = "user"
+	e.POST("/users", func(c Context) error {
+		return errors.New("failed")
+	})
+	e.GET("/health", func(c Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	}
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("payload")))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/not-found", nil))
+
+	stats := e.Stats()
+	assert.Len(t, stats, 3)
+
+	assert.Equal(t, "/health", stats[0].Path)
+	assert.Equal(t, uint64(0), stats[0].Requests)
+
+	users := stats[1]
+	assert.Equal(t, http.MethodPost, users.Method)
+	assert.Equal(t, "/users", users.Path)
+	assert.Equal(t, uint64(1), users.Requests)
+	assert.Equal(t, uint64(1), users.Errors)
+
+	user := stats[2]
+	assert.Equal(t, "user", user.Name)
+	assert.Equal(t, uint64(3), user.Requests)
+	assert.Equal(t, uint64(0), user.Errors)
+	assert.Equal(t, int64(12), user.BytesOut)
+	assert.True(t, user.Duration > 0)
+	assert.True(t, user.P50 > 0)
+	assert.True(t, user.P50 <= user.P99)
+}
+
+func TestEcho_StatsBytesIn(t *testing.T) {
+	e := New()
+	e.RouterConfig.CollectRouteStats = true
+	e.POST("/upload", func(c Context) error {
+		b := make([]byte, 1024)
+		for {
+			if _, err := c.Request().Body.Read(b); err != nil {
+				break
+			}
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("0123456789")))
+
+	stats := e.Stats()
+	assert.Equal(t, int64(10), stats[0].BytesIn)
+}
+
+func TestEcho_StatsDisabled(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	stats := e.Stats()
+	assert.Len(t, stats, 1)
+	assert.Equal(t, uint64(0), stats[0].Requests)
+}
+
+func TestTDigest_Quantile(t *testing.T) {
+	td := newTDigest(routeStatsCompression)
+	assert.Equal(t, float64(0), td.quantile(0.5))
+
+	values := rand.New(rand.NewSource(1)).Perm(10000)
+	for _, v := range values {
+		td.add(float64(v))
+	}
+
+	var testCases = []struct {
+		q      float64
+		expect float64
+	}{
+		{q: 0, expect: 0},
+		{q: 0.5, expect: 5000},
+		{q: 0.9, expect: 9000},
+		{q: 0.99, expect: 9900},
+		{q: 1, expect: 9999},
+	}
+	for _, tc := range testCases {
+		assert.InDelta(t, tc.expect, td.quantile(tc.q), 100, "q=%v", tc.q)
+	}
+	assert.True(t, len(td.centroids) < 10*routeStatsCompression)
+}
+
+func TestTDigest_SingleValue(t *testing.T) {
+	td := newTDigest(routeStatsCompression)
+	td.add(42)
+
+	assert.Equal(t, float64(42), td.quantile(0.5))
+	assert.Equal(t, float64(42), td.quantile(0.99))
+	assert.False(t, math.IsNaN(td.quantile(0.1)))
+}
@@ -1,7 +1,10 @@
 package echo
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 type (
@@ -10,8 +13,57 @@ type (
 	Router struct {
 		tree   *node
 		routes map[string]*Route
-		echo   *Echo
+		// static maps paths of routes without params and wildcards to their handlers for `RouterConfig.StaticRouteFastPath`.
+		// Handlers are referenced by methodHandler as it is moved along with route when tree nodes are split.
+		static map[string]*methodHandler
+		// stats holds route metrics collected when `RouterConfig.CollectRouteStats` is enabled.
+		stats *routeStatsCollector
+		echo  *Echo
 	}
+	// RouterConfig defines configuration for route registration and naming.
+	RouterConfig struct {
+		// UniqueRouteNames requires all registered routes to have non-empty and unique names. Violations are reported
+		// as error by `Echo#VerifyRoutes` and server start methods refuse to start when routes are not valid.
+		UniqueRouteNames bool
+
+		// RouteNamer generates name for route when it is registered. Route name can still be changed with `Route.Name`.
+		// Optional. Default behaviour is to use handler function name. See `DefaultRouteNamer`.
+		RouteNamer func(method, path string) string
+
+		// DuplicateRouteNames defines what happens when route is registered with name (generated by `RouteNamer` or
+		// derived from handler function) that is already used by another route with different path. Names assigned
+		// after registration (`Route.Name`) are checked when next route is registered and by `Echo#VerifyRoutes`
+		// with `DuplicateRouteNamesReject`. Note that without `RouteNamer` handler used for multiple paths (i.e.
+		// `Echo#Static`) has the same name for all of them.
+		// Optional. Default value DuplicateRouteNamesAllow.
+		DuplicateRouteNames DuplicateRouteNames
+
+		// CollectRouteErrors makes route registration methods (`Echo#Add`, `Echo#GET`, `Group#POST` etc.) collect
+		// registration errors instead of panicking, so all of them can be reported at once. Collected errors are
+		// available with `Echo#RouteErrors` and are reported by `Echo#VerifyRoutes` so server start methods refuse
+		// to start. Routes that failed to register are not added to router.
+		CollectRouteErrors bool
+
+		// RawPathParams makes router always match routes against escaped request path so path parameter values are
+		// received without percent-decoding (i.e. `a%2Fb` stays `a%2Fb` and `a%20b` stays `a%20b`). Useful for proxy
+		// style routes that forward values upstream. By default escaped path is used only when it differs from
+		// default encoding of decoded path (i.e. contains `%2F`) so some values are decoded and some are not.
+		// Static route segments must be registered in escaped form for routes to match in this mode.
+		RawPathParams bool
+
+		// StaticRouteFastPath makes router look up routes without params and wildcards (i.e. `/api/health`) with single
+		// map lookup before walking the routing tree. Lookups are faster for APIs with many static routes and long
+		// paths and slightly slower for requests to param and wildcard routes. Routing results do not change.
+		StaticRouteFastPath bool
+
+		// CollectRouteStats makes Echo collect per route metrics (requests, errors, bytes, latency percentiles)
+		// available with `Echo#Stats`. Collecting adds small overhead to every request.
+		CollectRouteStats bool
+	}
+
+	// DuplicateRouteNames is policy for routes registered with already used name.
+	DuplicateRouteNames uint8
+
 	node struct {
 		kind           kind
 		label          byte
@@ -23,6 +75,9 @@ type (
 		methodHandler  *methodHandler
 		paramChild     *node
 		anyChild       *node
+		// suffixChildren are routes of named wildcards in the middle of path (i.e. `/files/*filepath/edit`) attached
+		// to any node. Node prefix is static path after wildcard. Children are ordered by prefix length, longest first.
+		suffixChildren children
 		// isLeaf indicates that node does not have child routes
 		isLeaf bool
 		// isHandler indicates that node has at least one handler registered to it
@@ -31,20 +86,36 @@ type (
 	kind          uint8
 	children      []*node
 	methodHandler struct {
-		connect  HandlerFunc
-		delete   HandlerFunc
-		get      HandlerFunc
-		head     HandlerFunc
-		options  HandlerFunc
-		patch    HandlerFunc
-		post     HandlerFunc
-		propfind HandlerFunc
-		put      HandlerFunc
-		trace    HandlerFunc
-		report   HandlerFunc
+		connect   HandlerFunc
+		delete    HandlerFunc
+		get       HandlerFunc
+		head      HandlerFunc
+		options   HandlerFunc
+		patch     HandlerFunc
+		post      HandlerFunc
+		propfind  HandlerFunc
+		put       HandlerFunc
+		trace     HandlerFunc
+		report    HandlerFunc
+		proppatch HandlerFunc
+		mkcol     HandlerFunc
+		copy      HandlerFunc
+		move      HandlerFunc
+		lock      HandlerFunc
+		unlock    HandlerFunc
 	}
 )
 
+const (
+	// DuplicateRouteNamesAllow registers routes with duplicate names silently.
+	DuplicateRouteNamesAllow DuplicateRouteNames = iota
+	// DuplicateRouteNamesWarn registers routes with duplicate names and logs warning.
+	DuplicateRouteNamesWarn
+	// DuplicateRouteNamesReject refuses to register routes with duplicate names. Route registration methods panic
+	// (or collect error with `RouterConfig.CollectRouteErrors`) and `Echo#TryAdd` returns error.
+	DuplicateRouteNamesReject
+)
+
 const (
 	staticKind kind = iota
 	paramKind
@@ -65,7 +136,18 @@ func (m *methodHandler) isHandler() bool {
 		m.propfind != nil ||
 		m.put != nil ||
 		m.trace != nil ||
-		m.report != nil
+		m.report != nil ||
+		m.proppatch != nil ||
+		m.mkcol != nil ||
+		m.copy != nil ||
+		m.move != nil ||
+		m.lock != nil ||
+		m.unlock != nil
+}
+
+// DefaultRouteNamer generates route name from method and path i.e. `GET /users/:id`.
+func DefaultRouteNamer(method, path string) string {
+	return method + " " + normalizePath(path)
 }
 
 // NewRouter returns a new Router instance.
@@ -75,19 +157,69 @@ func NewRouter(e *Echo) *Router {
 			methodHandler: new(methodHandler),
 		},
 		routes: map[string]*Route{},
+		static: map[string]*methodHandler{},
+		stats:  newRouteStatsCollector(),
 		echo:   e,
 	}
 }
 
-// Add registers a new route for method and path with matching handler.
-func (r *Router) Add(method, path string, h HandlerFunc) {
-	// Validate path
+// normalizePath makes sure that path starts with slash
+func normalizePath(path string) string {
 	if path == "" {
-		path = "/"
+		return "/"
 	}
 	if path[0] != '/' {
-		path = "/" + path
+		return "/" + path
 	}
+	return path
+}
+
+// expandOptionalParams returns paths matched by route with optional trailing params (`/archive/:year?/:month?`)
+// from longest to shortest (`/archive/:year/:month`, `/archive/:year`, `/archive`). Returns nil when path has no
+// optional params or they are not at the end of path.
+func expandOptionalParams(path string) []string {
+	if !strings.Contains(path, "?") {
+		return nil
+	}
+	segments := strings.Split(path, "/")
+	start := len(segments)
+	for ; start > 0 && isOptionalParam(segments[start-1]); start-- {
+		segments[start-1] = strings.TrimSuffix(segments[start-1], "?")
+	}
+	if start == len(segments) || strings.Contains(strings.Join(segments[:start], "/"), "?") {
+		return nil
+	}
+	paths := make([]string, 0, len(segments)-start+1)
+	for end := len(segments); end >= start; end-- {
+		paths = append(paths, normalizePath(strings.Join(segments[:end], "/")))
+	}
+	return paths
+}
+
+func isOptionalParam(segment string) bool {
+	return len(segment) > 2 && segment[0] == paramLabel && segment[len(segment)-1] == '?'
+}
+
+// isValidOptionalParams checks that optional params (`:year?`) are only followed by other optional params.
+func isValidOptionalParams(path string) bool {
+	return !strings.Contains(path, "?") || expandOptionalParams(path) != nil
+}
+
+// Add registers a new route for method and path with matching handler. Path with optional trailing params
+// (`/archive/:year?/:month?`) registers handler for all paths matched by it.
+func (r *Router) Add(method, path string, h HandlerFunc) {
+	r.echo.checkNotFrozen()
+	if paths := expandOptionalParams(path); paths != nil {
+		for _, p := range paths {
+			r.Add(method, p, h)
+		}
+		return
+	}
+	if !isValidOptionalParams(path) {
+		panic("echo: optional params can only be followed by optional params: " + path)
+	}
+	// Validate path
+	path = normalizePath(path)
 	pnames := []string{} // Param names
 	ppath := path        // Pristine path
 
@@ -116,12 +248,90 @@ func (r *Router) Add(method, path string, h HandlerFunc) {
 			}
 		} else if path[i] == '*' {
 			r.insert(method, path[:i], nil, staticKind, "", nil)
-			pnames = append(pnames, "*")
-			r.insert(method, path[:i+1], h, anyKind, ppath, pnames)
+			j := i + 1
+			for ; j < lcpIndex && path[j] != '/'; j++ {
+			}
+			if j == i+1 {
+				pnames = append(pnames, "*")
+				r.insert(method, path[:i+1], h, anyKind, ppath, pnames)
+				continue
+			}
+			// named wildcard i.e. `/files/*filepath` or `/files/*filepath/edit`
+			pnames = append(pnames, path[i+1:j])
+			suffix := path[j:]
+			if suffix == "" {
+				r.insert(method, path[:i+1], h, anyKind, ppath, pnames)
+				return
+			}
+			if !isStaticPath(suffix) {
+				panic("echo: named wildcard can only be followed by static path segments: " + ppath)
+			}
+			if *r.echo.maxParam < len(pnames) {
+				*r.echo.maxParam = len(pnames)
+			}
+			r.insert(method, path[:i+1], nil, anyKind, "", nil)
+			r.findNode(path[:i+1]).addSuffixChild(method, suffix, h, ppath, pnames)
+			return
 		}
 	}
 
 	r.insert(method, path, h, staticKind, ppath, pnames)
+	if len(pnames) == 0 {
+		r.static[path] = r.findNode(path).methodHandler
+	}
+}
+
+// isStaticPath checks that path does not contain param or wildcard segments.
+func isStaticPath(path string) bool {
+	return !strings.ContainsAny(path, ":*")
+}
+
+// findNode returns node for path that is already inserted to the tree.
+func (r *Router) findNode(path string) *node {
+	currentNode := r.tree
+	search := path
+	for {
+		search = search[len(currentNode.prefix):]
+		if search == "" {
+			return currentNode
+		}
+		currentNode = currentNode.findChildWithLabel(search[0])
+	}
+}
+
+// verifyRouteNames checks that all routes have non-empty and unique names.
+func verifyRouteNames(routes []*Route) error {
+	sorted := make([]*Route, len(routes))
+	copy(sorted, routes)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path == sorted[j].Path {
+			return sorted[i].Method < sorted[j].Method
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	problems := make([]string, 0)
+	byName := map[string][]string{}
+	names := make([]string, 0)
+	for _, r := range sorted {
+		if r.Name == "" {
+			problems = append(problems, fmt.Sprintf("route %s %s has no name", r.Method, r.Path))
+			continue
+		}
+		if _, ok := byName[r.Name]; !ok {
+			names = append(names, r.Name)
+		}
+		byName[r.Name] = append(byName[r.Name], r.Method+" "+r.Path)
+	}
+	for _, name := range names {
+		if routes := byName[name]; len(routes) > 1 {
+			problems = append(problems, fmt.Sprintf("route name %q is used by multiple routes: %s", name, strings.Join(routes, ", ")))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("echo: invalid route names: %s", strings.Join(problems, "; "))
 }
 
 func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string, pnames []string) {
@@ -270,6 +480,25 @@ func (n *node) addStaticChild(c *node) {
 	n.staticChildren = append(n.staticChildren, c)
 }
 
+func (n *node) addSuffixChild(method, suffix string, h HandlerFunc, ppath string, pnames []string) {
+	for _, c := range n.suffixChildren {
+		if c.prefix == suffix {
+			c.addHandler(method, h)
+			c.ppath = ppath
+			c.pnames = pnames
+			return
+		}
+	}
+	c := newNode(anyKind, suffix, n, nil, new(methodHandler), ppath, pnames, nil, nil)
+	c.addHandler(method, h)
+	i := 0
+	for ; i < len(n.suffixChildren) && len(n.suffixChildren[i].prefix) >= len(suffix); i++ {
+	}
+	n.suffixChildren = append(n.suffixChildren, nil)
+	copy(n.suffixChildren[i+1:], n.suffixChildren[i:])
+	n.suffixChildren[i] = c
+}
+
 func (n *node) findStaticChild(l byte) *node {
 	for _, c := range n.staticChildren {
 		if c.label == l {
@@ -318,6 +547,18 @@ func (n *node) addHandler(method string, h HandlerFunc) {
 		n.methodHandler.trace = h
 	case REPORT:
 		n.methodHandler.report = h
+	case PROPPATCH:
+		n.methodHandler.proppatch = h
+	case MKCOL:
+		n.methodHandler.mkcol = h
+	case COPY:
+		n.methodHandler.copy = h
+	case MOVE:
+		n.methodHandler.move = h
+	case LOCK:
+		n.methodHandler.lock = h
+	case UNLOCK:
+		n.methodHandler.unlock = h
 	}
 
 	if h != nil {
@@ -328,29 +569,45 @@ func (n *node) addHandler(method string, h HandlerFunc) {
 }
 
 func (n *node) findHandler(method string) HandlerFunc {
+	return n.methodHandler.find(method)
+}
+
+func (m *methodHandler) find(method string) HandlerFunc {
 	switch method {
 	case http.MethodConnect:
-		return n.methodHandler.connect
+		return m.connect
 	case http.MethodDelete:
-		return n.methodHandler.delete
+		return m.delete
 	case http.MethodGet:
-		return n.methodHandler.get
+		return m.get
 	case http.MethodHead:
-		return n.methodHandler.head
+		return m.head
 	case http.MethodOptions:
-		return n.methodHandler.options
+		return m.options
 	case http.MethodPatch:
-		return n.methodHandler.patch
+		return m.patch
 	case http.MethodPost:
-		return n.methodHandler.post
+		return m.post
 	case PROPFIND:
-		return n.methodHandler.propfind
+		return m.propfind
 	case http.MethodPut:
-		return n.methodHandler.put
+		return m.put
 	case http.MethodTrace:
-		return n.methodHandler.trace
+		return m.trace
 	case REPORT:
-		return n.methodHandler.report
+		return m.report
+	case PROPPATCH:
+		return m.proppatch
+	case MKCOL:
+		return m.mkcol
+	case COPY:
+		return m.copy
+	case MOVE:
+		return m.move
+	case LOCK:
+		return m.lock
+	case UNLOCK:
+		return m.unlock
 	default:
 		return nil
 	}
@@ -375,6 +632,16 @@ func (n *node) checkMethodNotAllowed() HandlerFunc {
 // - Return it `Echo#ReleaseContext()`.
 func (r *Router) Find(method, path string, c Context) {
 	ctx := c.(*context)
+	if r.echo.RouterConfig.StaticRouteFastPath {
+		if mh, ok := r.static[path]; ok {
+			if h := mh.find(method); h != nil {
+				ctx.handler = h
+				ctx.path = path
+				ctx.pnames = nil
+				return
+			}
+		}
+	}
 	ctx.path = path
 	currentNode := r.tree // Current node as root
 
@@ -514,9 +781,28 @@ func (r *Router) Find(method, path string, c Context) {
 	Any:
 		// Any node
 		if child := currentNode.anyChild; child != nil {
+			// Named wildcards in the middle of path match when remaining path ends with static suffix after wildcard
+			for _, sc := range child.suffixChildren {
+				if !strings.HasSuffix(search, sc.prefix) {
+					continue
+				}
+				if previousBestMatchNode == nil {
+					previousBestMatchNode = sc
+				}
+				if h := sc.findHandler(method); h != nil {
+					paramValues[paramIndex] = search[:len(search)-len(sc.prefix)]
+					currentNode = sc
+					matchedHandler = h
+					break
+				}
+			}
+			if matchedHandler != nil {
+				break
+			}
+
 			// If any node is found, use remaining path for paramValues
 			currentNode = child
-			paramValues[len(currentNode.pnames)-1] = search
+			paramValues[paramIndex] = search
 			// update indexes/search in case we need to backtrack when no handler match is found
 			paramIndex++
 			searchIndex += +len(search)
@@ -524,7 +810,7 @@ func (r *Router) Find(method, path string, c Context) {
 
 			// check if current node has handler registered for http method we are looking for. we store currentNode as
 			// best matching in case we do no find no more routes matching this path+method
-			if previousBestMatchNode == nil {
+			if previousBestMatchNode == nil && currentNode.isHandler {
 				previousBestMatchNode = currentNode
 			}
 			if h := currentNode.findHandler(method); h != nil {
@@ -547,7 +833,7 @@ func (r *Router) Find(method, path string, c Context) {
 		}
 	}
 
-	if currentNode == nil && previousBestMatchNode == nil {
+	if previousBestMatchNode == nil {
 		return // nothing matched at all
 	}
 
@@ -1,6 +1,7 @@
 package echo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -1986,6 +1987,38 @@ func testRouterAPI(t *testing.T, api []*Route) {
 	}
 }
 
+func TestRouterStaticRouteFastPath(t *testing.T) {
+	for name, api := range map[string][]*Route{"static": staticRoutes, "github": gitHubAPI, "parse": parseAPI, "gplus": googlePlusAPI} {
+		t.Run(name, func(t *testing.T) {
+			e := New()
+			fast := New()
+			fast.RouterConfig.StaticRouteFastPath = true
+			for i, route := range api {
+				h := handlerHelper("route", i)
+				e.router.Add(route.Method, route.Path, h)
+				fast.router.Add(route.Method, route.Path, h)
+			}
+
+			for _, route := range append(append([]*Route(nil), api...), missesAPI...) {
+				for _, method := range []string{route.Method, http.MethodOptions} {
+					c := e.NewContext(nil, nil).(*context)
+					e.router.Find(method, route.Path, c)
+					fc := fast.NewContext(nil, nil).(*context)
+					fast.router.Find(method, route.Path, fc)
+
+					assert.Equal(t, c.Path(), fc.Path(), route.Path)
+					for _, n := range c.ParamNames() {
+						assert.Equal(t, c.Param(n), fc.Param(n), route.Path)
+					}
+					c.handler(c)
+					fc.handler(fc)
+					assert.Equal(t, c.Get("route"), fc.Get("route"), route.Path)
+				}
+			}
+		})
+	}
+}
+
 func TestRouterGitHubAPI(t *testing.T) {
 	testRouterAPI(t, gitHubAPI)
 }
@@ -2249,10 +2282,40 @@ func benchmarkRouterRoutes(b *testing.B, routes []*Route, routesToFind []*Route)
 	}
 }
 
+func benchmarkRouterRoutesFastPath(b *testing.B, routes []*Route, routesToFind []*Route) {
+	e := New()
+	e.RouterConfig.StaticRouteFastPath = true
+	r := e.router
+	b.ReportAllocs()
+
+	for _, route := range routes {
+		r.Add(route.Method, route.Path, func(c Context) error {
+			return nil
+		})
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, route := range routesToFind {
+			c := e.pool.Get().(*context)
+			r.Find(route.Method, route.Path, c)
+			e.pool.Put(c)
+		}
+	}
+}
+
 func BenchmarkRouterStaticRoutes(b *testing.B) {
 	benchmarkRouterRoutes(b, staticRoutes, staticRoutes)
 }
 
+func BenchmarkRouterStaticRoutesFastPath(b *testing.B) {
+	benchmarkRouterRoutesFastPath(b, staticRoutes, staticRoutes)
+}
+
+func BenchmarkRouterGitHubAPIFastPath(b *testing.B) {
+	benchmarkRouterRoutesFastPath(b, gitHubAPI, gitHubAPI)
+}
+
 func BenchmarkRouterStaticRoutesMisses(b *testing.B) {
 	benchmarkRouterRoutes(b, staticRoutes, missesAPI)
 }
@@ -2314,3 +2377,210 @@ func prefix(tail bool, p, on, off string) string {
 	}
 	return fmt.Sprintf("%s%s", p, off)
 }
+
+func TestRouterNamedWildcard(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenMethod   string
+		whenURL      string
+		expectRoute  interface{}
+		expectParam  map[string]string
+		expectStatus int
+	}{
+		{
+			name:        "route /files/a/b.txt to /files/*filepath",
+			whenURL:     "/files/a/b.txt",
+			expectRoute: "/files/*filepath",
+			expectParam: map[string]string{"filepath": "a/b.txt"},
+		},
+		{
+			name:        "route /files/a/b.txt/edit to /files/*filepath/edit",
+			whenURL:     "/files/a/b.txt/edit",
+			expectRoute: "/files/*filepath/edit",
+			expectParam: map[string]string{"filepath": "a/b.txt"},
+		},
+		{
+			name:        "route /files/a/b.txt/edit/history to longest suffix /files/*filepath/edit/history",
+			whenURL:     "/files/a/b.txt/edit/history",
+			expectRoute: "/files/*filepath/edit/history",
+			expectParam: map[string]string{"filepath": "a/b.txt"},
+		},
+		{
+			name:        "route POST /files/a/edit to /files/*filepath as suffix route has no POST handler",
+			whenMethod:  http.MethodPost,
+			whenURL:     "/files/a/edit",
+			expectRoute: "/files/*filepath",
+			expectParam: map[string]string{"filepath": "a/edit"},
+		},
+		{
+			name:        "route /users/1/docs/a/b/raw to /users/:id/docs/*doc/raw",
+			whenURL:     "/users/1/docs/a/b/raw",
+			expectRoute: "/users/:id/docs/*doc/raw",
+			expectParam: map[string]string{"id": "1", "doc": "a/b"},
+		},
+		{
+			name:         "route PUT /users/1/docs/a/raw is method not allowed",
+			whenMethod:   http.MethodPut,
+			whenURL:      "/users/1/docs/a/raw",
+			expectRoute:  nil,
+			expectParam:  map[string]string{"id": "1"},
+			expectStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "route /users/1/docs/a/other is not found",
+			whenURL:      "/users/1/docs/a/other",
+			expectRoute:  nil,
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			r := e.router
+
+			r.Add(http.MethodGet, "/files/*filepath", handlerHelper("case", 1))
+			r.Add(http.MethodPost, "/files/*filepath", handlerHelper("case", 1))
+			r.Add(http.MethodGet, "/files/*filepath/edit", handlerHelper("case", 2))
+			r.Add(http.MethodGet, "/files/*filepath/edit/history", handlerHelper("case", 3))
+			r.Add(http.MethodGet, "/users/:id/docs/*doc/raw", handlerHelper("case", 4))
+
+			method := tc.whenMethod
+			if method == "" {
+				method = http.MethodGet
+			}
+			c := e.NewContext(nil, nil).(*context)
+			r.Find(method, tc.whenURL, c)
+
+			err := c.handler(c)
+			if tc.expectStatus != 0 {
+				assert.Equal(t, tc.expectStatus, err.(*HTTPError).Code)
+			}
+			assert.Equal(t, tc.expectRoute, c.Get("path"))
+			for param, expectedValue := range tc.expectParam {
+				assert.Equal(t, expectedValue, c.Param(param))
+			}
+			checkUnusedParamValues(t, c, tc.expectParam)
+		})
+	}
+}
+
+func TestRouterNamedWildcard_invalidPath(t *testing.T) {
+	e := New()
+	assert.PanicsWithValue(t, "echo: named wildcard can only be followed by static path segments: /files/*filepath/:action", func() {
+		e.router.Add(http.MethodGet, "/files/*filepath/:action", handlerFunc)
+	})
+
+	_, err := e.TryAdd(http.MethodGet, "/files/*filepath/:action", handlerFunc)
+	assert.True(t, errors.Is(err, ErrRouteInvalidPath))
+}
+
+func TestEcho_ReverseNamedWildcard(t *testing.T) {
+	e := New()
+	e.GET("/files/*filepath/edit", handlerFunc).Name = "edit"
+
+	assert.Equal(t, "/files/a/b.txt/edit", e.Reverse("edit", "a/b.txt"))
+}
+
+func TestRouterOptionalParams(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenURL     string
+		expectRoute interface{}
+		expectParam map[string]string
+	}{
+		{
+			name:        "route /archive/2021/05 to /archive/:year/:month",
+			whenURL:     "/archive/2021/05",
+			expectRoute: "/archive/:year/:month",
+			expectParam: map[string]string{"year": "2021", "month": "05"},
+		},
+		{
+			name:        "route /archive/2021 to /archive/:year",
+			whenURL:     "/archive/2021",
+			expectRoute: "/archive/:year",
+			expectParam: map[string]string{"year": "2021"},
+		},
+		{
+			name:        "route /archive to /archive",
+			whenURL:     "/archive",
+			expectRoute: "/archive",
+		},
+		{
+			name:        "route /:lang to /:lang",
+			whenURL:     "/en",
+			expectRoute: "/:lang",
+			expectParam: map[string]string{"lang": "en"},
+		},
+		{
+			name:        "route / to /",
+			whenURL:     "/",
+			expectRoute: "/",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			r := e.router
+
+			r.Add(http.MethodGet, "/archive/:year?/:month?", handlerHelper("case", 1))
+			r.Add(http.MethodGet, "/:lang?", handlerHelper("case", 2))
+
+			c := e.NewContext(nil, nil).(*context)
+			r.Find(http.MethodGet, tc.whenURL, c)
+
+			err := c.handler(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectRoute, c.Get("path"))
+			for param, expectedValue := range tc.expectParam {
+				assert.Equal(t, expectedValue, c.Param(param))
+			}
+			checkUnusedParamValues(t, c, tc.expectParam)
+		})
+	}
+}
+
+func TestRouterOptionalParams_invalidPath(t *testing.T) {
+	e := New()
+	assert.PanicsWithValue(t, "echo: optional params can only be followed by optional params: /archive/:year?/all", func() {
+		e.router.Add(http.MethodGet, "/archive/:year?/all", handlerFunc)
+	})
+
+	_, err := e.TryAdd(http.MethodGet, "/archive/:year?/:month", handlerFunc)
+	assert.True(t, errors.Is(err, ErrRouteInvalidParam))
+	assert.Len(t, e.Routes(), 0)
+}
+
+func TestEcho_AddOptionalParams(t *testing.T) {
+	e := New()
+	g := e.Group("/api")
+	r := g.GET("/archive/:year?/:month?", func(c Context) error {
+		return c.String(http.StatusOK, c.Path()+" "+c.Param("year")+" "+c.Param("month"))
+	})
+	e.RouteMeta(r)["scope"] = "archive"
+
+	assert.Equal(t, "/api/archive/:year/:month", r.Path)
+	assert.Len(t, e.Routes(), 3)
+	for _, route := range e.Routes() {
+		assert.Equal(t, "archive", e.RouteMeta(route)["scope"])
+	}
+
+	status, body := request(http.MethodGet, "/api/archive/2021", e)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/api/archive/:year 2021 ", body)
+
+	status, body = request(http.MethodGet, "/api/archive", e)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/api/archive  ", body)
+}
+
+func TestEcho_AddOptionalParams_routeError(t *testing.T) {
+	e := New()
+	e.RouterConfig.CollectRouteErrors = true
+
+	e.Add("FETCH", "/archive/:year?", handlerFunc)
+
+	assert.Len(t, e.RouteErrors(), 1)
+	assert.Len(t, e.Routes(), 0)
+}
@@ -0,0 +1,91 @@
+package echo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Secure cookie errors
+var (
+	ErrSecureCookieNoKeys  = errors.New("echo: secure cookies require Echo#SecureCookieKeys")
+	ErrSecureCookieInvalid = errors.New("invalid secure cookie")
+	ErrSecureCookieExpired = errors.New("secure cookie has expired")
+)
+
+// secureCookieIssuedAtSize is size of issue time (unix seconds) sealed before cookie value.
+const secureCookieIssuedAtSize = 8
+
+// EncryptCookieValue encrypts and authenticates cookie value with the first key of `Echo#SecureCookieKeys` using
+// AES-GCM. Cookie name is authenticated too, so value can not be moved to cookie with another name. Use it to set
+// secure cookies with custom attributes, see `Context#SetSecureCookie`. Time of encryption is sealed along with value
+// so values older than `Echo#SecureCookieMaxAge` are rejected by `Echo#DecryptCookieValue`.
+func (e *Echo) EncryptCookieValue(name, value string) (string, error) {
+	return e.encryptCookieValue(name, value, time.Now())
+}
+
+func (e *Echo) encryptCookieValue(name, value string, issuedAt time.Time) (string, error) {
+	if len(e.SecureCookieKeys) == 0 {
+		return "", ErrSecureCookieNoKeys
+	}
+	aead, err := secureCookieAEAD(e.SecureCookieKeys[0])
+	if err != nil {
+		return "", err
+	}
+	plain := make([]byte, secureCookieIssuedAtSize+len(value))
+	binary.BigEndian.PutUint64(plain, uint64(issuedAt.Unix()))
+	copy(plain[secureCookieIssuedAtSize:], value)
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plain, []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptCookieValue decrypts cookie value encrypted with `Echo#EncryptCookieValue` trying all keys of
+// `Echo#SecureCookieKeys`. Returns `ErrSecureCookieExpired` when value is older than `Echo#SecureCookieMaxAge`.
+func (e *Echo) DecryptCookieValue(name, value string) (string, error) {
+	if len(e.SecureCookieKeys) == 0 {
+		return "", ErrSecureCookieNoKeys
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", ErrSecureCookieInvalid
+	}
+	for _, key := range e.SecureCookieKeys {
+		aead, err := secureCookieAEAD(key)
+		if err != nil {
+			return "", err
+		}
+		if len(sealed) < aead.NonceSize() {
+			return "", ErrSecureCookieInvalid
+		}
+		plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(name))
+		if err != nil {
+			continue
+		}
+		if len(plain) < secureCookieIssuedAtSize {
+			return "", ErrSecureCookieInvalid
+		}
+		issuedAt := time.Unix(int64(binary.BigEndian.Uint64(plain)), 0)
+		if e.SecureCookieMaxAge > 0 && time.Since(issuedAt) > e.SecureCookieMaxAge {
+			return "", ErrSecureCookieExpired
+		}
+		return string(plain[secureCookieIssuedAtSize:]), nil
+	}
+	return "", ErrSecureCookieInvalid
+}
+
+func secureCookieAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,143 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_SecureCookie(t *testing.T) {
+	oldKey := []byte("0123456789abcdef")
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+
+	e := New()
+	e.SecureCookieKeys = [][]byte{oldKey}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	assert.NoError(t, c.SetSecureCookie("session", "user=42"))
+
+	cookie := rec.Result().Cookies()[0]
+	assert.Equal(t, "session", cookie.Name)
+	assert.NotContains(t, cookie.Value, "user=42")
+	assert.True(t, cookie.HttpOnly)
+	assert.False(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+
+	read := func(cookie *http.Cookie) (string, error) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		return e.NewContext(req, httptest.NewRecorder()).SecureCookie(cookie.Name)
+	}
+
+	v, err := read(cookie)
+	assert.NoError(t, err)
+	assert.Equal(t, "user=42", v)
+
+	// rotated keys still decrypt cookies encrypted with old key
+	e.SecureCookieKeys = [][]byte{newKey, oldKey}
+	v, err = read(cookie)
+	assert.NoError(t, err)
+	assert.Equal(t, "user=42", v)
+
+	// value moved to cookie with another name is rejected
+	_, err = read(&http.Cookie{Name: "other", Value: cookie.Value})
+	assert.Equal(t, ErrSecureCookieInvalid, err)
+
+	// tampered value is rejected
+	tampered := []byte(cookie.Value)
+	if tampered[10] == 'A' {
+		tampered[10] = 'B'
+	} else {
+		tampered[10] = 'A'
+	}
+	_, err = read(&http.Cookie{Name: "session", Value: string(tampered)})
+	assert.Equal(t, ErrSecureCookieInvalid, err)
+
+	// removed key no longer decrypts
+	e.SecureCookieKeys = [][]byte{newKey}
+	_, err = read(cookie)
+	assert.Equal(t, ErrSecureCookieInvalid, err)
+
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	_, err = c.SecureCookie("session")
+	assert.Equal(t, http.ErrNoCookie, err)
+}
+
+func TestContext_SetSecureCookie_behindTLSProxy(t *testing.T) {
+	e := New()
+	e.SecureCookieKeys = [][]byte{[]byte("0123456789abcdef")}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderXForwardedProto, "https")
+	rec := httptest.NewRecorder()
+	assert.NoError(t, e.NewContext(req, rec).SetSecureCookie("session", "user=42"))
+
+	assert.True(t, rec.Result().Cookies()[0].Secure)
+}
+
+func TestEcho_DecryptCookieValue_maxAge(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenMaxAge time.Duration
+		whenIssued  time.Duration
+		expectErr   error
+	}{
+		{
+			name:       "ok, age is not limited by default",
+			whenIssued: -365 * 24 * time.Hour,
+		},
+		{
+			name:        "ok, within max age",
+			givenMaxAge: time.Hour,
+			whenIssued:  -59 * time.Minute,
+		},
+		{
+			name:        "nok, older than max age",
+			givenMaxAge: time.Hour,
+			whenIssued:  -61 * time.Minute,
+			expectErr:   ErrSecureCookieExpired,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.SecureCookieKeys = [][]byte{[]byte("0123456789abcdef")}
+			e.SecureCookieMaxAge = tc.givenMaxAge
+			v, err := e.encryptCookieValue("session", "user=42", time.Now().Add(tc.whenIssued))
+			assert.NoError(t, err)
+
+			plain, err := e.DecryptCookieValue("session", v)
+			if tc.expectErr != nil {
+				assert.Equal(t, tc.expectErr, err)
+				assert.Empty(t, plain)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user=42", plain)
+			}
+		})
+	}
+}
+
+func TestContext_SetSecureCookie_maxAge(t *testing.T) {
+	e := New()
+	e.SecureCookieKeys = [][]byte{[]byte("0123456789abcdef")}
+	e.SecureCookieMaxAge = time.Hour
+	rec := httptest.NewRecorder()
+	assert.NoError(t, e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec).SetSecureCookie("session", "user=42"))
+
+	assert.Equal(t, 3600, rec.Result().Cookies()[0].MaxAge)
+}
+
+func TestEcho_EncryptCookieValue_errors(t *testing.T) {
+	e := New()
+	_, err := e.EncryptCookieValue("a", "b")
+	assert.Equal(t, ErrSecureCookieNoKeys, err)
+	_, err = e.DecryptCookieValue("a", "b")
+	assert.Equal(t, ErrSecureCookieNoKeys, err)
+
+	e.SecureCookieKeys = [][]byte{[]byte("short")}
+	_, err = e.EncryptCookieValue("a", "b")
+	assert.EqualError(t, err, "crypto/aes: invalid key size 5")
+}
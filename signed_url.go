@@ -0,0 +1,112 @@
+package echo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignedURLClaims are restrictions of signed URL that are protected by signature.
+type SignedURLClaims struct {
+	// Expires is the time after which URL is no longer valid.
+	// Required.
+	Expires time.Time
+	// Method restricts URL to be used only with given HTTP method.
+	// Optional.
+	Method string
+	// IP restricts URL to be used only by client with given IP address.
+	// Optional.
+	IP string
+}
+
+// Signed URL query parameters
+const (
+	SignedURLParamExpires   = "expires"
+	SignedURLParamMethod    = "method"
+	SignedURLParamIP        = "ip"
+	SignedURLParamSignature = "signature"
+)
+
+// Signed URL errors
+var (
+	ErrSignedURLInvalid = errors.New("invalid url signature")
+	ErrSignedURLExpired = errors.New("signed url has expired")
+	ErrSignedURLClaims  = errors.New("signed url claims do not match request")
+)
+
+// SignURL signs URL with HMAC-SHA256 key. Claims are added to URL as query parameters and are protected by the
+// signature together with URL path and query. Signed URLs are verified with `VerifySignedURL` or
+// `middleware.SignedURL`.
+func SignURL(rawURL string, key []byte, claims SignedURLClaims) (string, error) {
+	if claims.Expires.IsZero() {
+		return "", errors.New("signed url requires expiry")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for _, p := range []string{SignedURLParamExpires, SignedURLParamMethod, SignedURLParamIP, SignedURLParamSignature} {
+		q.Del(p)
+	}
+	q.Set(SignedURLParamExpires, strconv.FormatInt(claims.Expires.Unix(), 10))
+	if claims.Method != "" {
+		q.Set(SignedURLParamMethod, strings.ToUpper(claims.Method))
+	}
+	if claims.IP != "" {
+		q.Set(SignedURLParamIP, claims.IP)
+	}
+	u.RawQuery = q.Encode()
+	q.Set(SignedURLParamSignature, urlSignature(u.EscapedPath(), u.RawQuery, key))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// SignedReverse generates URL from route name and parameters (see `Echo#Reverse`) and signs it (see `SignURL`).
+// Example: `e.SignedReverse(key, echo.SignedURLClaims{Expires: time.Now().Add(time.Hour)}, "download", fileID)`
+func (e *Echo) SignedReverse(key []byte, claims SignedURLClaims, name string, params ...interface{}) (string, error) {
+	return SignURL(e.Reverse(name, params...), key, claims)
+}
+
+// VerifySignedURL verifies signature and claims of URL signed with `SignURL`. Method and IP of request are checked
+// against method and IP claims when URL has them.
+func VerifySignedURL(u *url.URL, key []byte, method, ip string) error {
+	q := u.Query()
+	signature := q.Get(SignedURLParamSignature)
+	if signature == "" {
+		return ErrSignedURLInvalid
+	}
+	q.Del(SignedURLParamSignature)
+	expected := urlSignature(u.EscapedPath(), q.Encode(), key)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrSignedURLInvalid
+	}
+
+	expires, err := strconv.ParseInt(q.Get(SignedURLParamExpires), 10, 64)
+	if err != nil {
+		return ErrSignedURLInvalid
+	}
+	if time.Now().Unix() > expires {
+		return ErrSignedURLExpired
+	}
+	if m := q.Get(SignedURLParamMethod); m != "" && !strings.EqualFold(m, method) {
+		return ErrSignedURLClaims
+	}
+	if i := q.Get(SignedURLParamIP); i != "" && i != ip {
+		return ErrSignedURLClaims
+	}
+	return nil
+}
+
+func urlSignature(path, query string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(query))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
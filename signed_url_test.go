@@ -0,0 +1,105 @@
+package echo
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignURL(t *testing.T) {
+	key := []byte("secret")
+	expires := time.Now().Add(time.Hour)
+
+	var testCases = []struct {
+		name        string
+		givenClaims SignedURLClaims
+		whenURL     func(signed string) string
+		whenMethod  string
+		whenIP      string
+		expectErr   error
+	}{
+		{
+			name:        "ok",
+			givenClaims: SignedURLClaims{Expires: expires},
+			whenMethod:  http.MethodGet,
+		},
+		{
+			name:        "ok, method and ip claims",
+			givenClaims: SignedURLClaims{Expires: expires, Method: "get", IP: "192.168.0.1"},
+			whenMethod:  http.MethodGet,
+			whenIP:      "192.168.0.1",
+		},
+		{
+			name:        "nok, expired",
+			givenClaims: SignedURLClaims{Expires: time.Now().Add(-time.Minute)},
+			whenMethod:  http.MethodGet,
+			expectErr:   ErrSignedURLExpired,
+		},
+		{
+			name:        "nok, method claim mismatch",
+			givenClaims: SignedURLClaims{Expires: expires, Method: http.MethodGet},
+			whenMethod:  http.MethodPost,
+			expectErr:   ErrSignedURLClaims,
+		},
+		{
+			name:        "nok, ip claim mismatch",
+			givenClaims: SignedURLClaims{Expires: expires, IP: "192.168.0.1"},
+			whenIP:      "10.0.0.1",
+			expectErr:   ErrSignedURLClaims,
+		},
+		{
+			name:        "nok, tampered path",
+			givenClaims: SignedURLClaims{Expires: expires},
+			whenURL: func(signed string) string {
+				return "/files/2" + signed[len("/files/1"):]
+			},
+			expectErr: ErrSignedURLInvalid,
+		},
+		{
+			name:        "nok, missing signature",
+			givenClaims: SignedURLClaims{Expires: expires},
+			whenURL: func(signed string) string {
+				return "/files/1?id=x"
+			},
+			expectErr: ErrSignedURLInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			signed, err := SignURL("/files/1?id=x", key, tc.givenClaims)
+			assert.NoError(t, err)
+
+			if tc.whenURL != nil {
+				signed = tc.whenURL(signed)
+			}
+			u, err := url.Parse(signed)
+			assert.NoError(t, err)
+
+			err = VerifySignedURL(u, key, tc.whenMethod, tc.whenIP)
+			assert.Equal(t, tc.expectErr, err)
+		})
+	}
+}
+
+func TestSignURL_requiresExpiry(t *testing.T) {
+	_, err := SignURL("/files/1", []byte("secret"), SignedURLClaims{})
+	assert.EqualError(t, err, "signed url requires expiry")
+}
+
+func TestEcho_SignedReverse(t *testing.T) {
+	e := New()
+	e.GET("/files/:id", handlerFunc).Name = "download"
+
+	signed, err := e.SignedReverse([]byte("secret"), SignedURLClaims{Expires: time.Now().Add(time.Hour)}, "download", "123")
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "/files/123", u.Path)
+	assert.NoError(t, VerifySignedURL(u, []byte("secret"), http.MethodGet, ""))
+	assert.Error(t, VerifySignedURL(u, []byte("other"), http.MethodGet, ""))
+}
@@ -0,0 +1,113 @@
+package echo
+
+import (
+	"math"
+	"sort"
+)
+
+// tdigest is merging t-digest (https://github.com/tdunning/t-digest) used to estimate quantiles of route latencies
+// with bounded memory. It is not safe for concurrent use.
+type tdigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []float64
+	count       float64
+	min         float64
+	max         float64
+}
+
+type centroid struct {
+	mean  float64
+	count float64
+}
+
+func newTDigest(compression float64) *tdigest {
+	return &tdigest{
+		compression: compression,
+		buffer:      make([]float64, 0, int(compression)*2),
+	}
+}
+
+func (t *tdigest) add(x float64) {
+	t.buffer = append(t.buffer, x)
+	if len(t.buffer) == cap(t.buffer) {
+		t.merge()
+	}
+}
+
+// scale is k1 scale function. Centroids near the tails are kept small so extreme quantiles are accurate.
+func (t *tdigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (t *tdigest) merge() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	points := make([]centroid, 0, len(t.centroids)+len(t.buffer))
+	points = append(points, t.centroids...)
+	for _, x := range t.buffer {
+		if t.count == 0 || x < t.min {
+			t.min = x
+		}
+		if t.count == 0 || x > t.max {
+			t.max = x
+		}
+		t.count++
+		points = append(points, centroid{mean: x, count: 1})
+	}
+	t.buffer = t.buffer[:0]
+	sort.Slice(points, func(i, j int) bool { return points[i].mean < points[j].mean })
+
+	merged := make([]centroid, 0, len(t.centroids)+1)
+	current := points[0]
+	weightSoFar := 0.0
+	for _, p := range points[1:] {
+		q0 := weightSoFar / t.count
+		q2 := (weightSoFar + current.count + p.count) / t.count
+		if t.scale(q2)-t.scale(q0) <= 1 {
+			current.count += p.count
+			current.mean += (p.mean - current.mean) * p.count / current.count
+			continue
+		}
+		weightSoFar += current.count
+		merged = append(merged, current)
+		current = p
+	}
+	t.centroids = append(merged, current)
+}
+
+// quantile returns estimated value at quantile q (0-1).
+func (t *tdigest) quantile(q float64) float64 {
+	t.merge()
+	n := len(t.centroids)
+	switch {
+	case n == 0:
+		return 0
+	case q <= 0:
+		return t.min
+	case q >= 1:
+		return t.max
+	case n == 1:
+		return t.centroids[0].mean
+	}
+
+	target := q * t.count
+	first := t.centroids[0]
+	if target < first.count/2 {
+		return t.min + (first.mean-t.min)*target/(first.count/2)
+	}
+	cumulative := 0.0
+	for i := 0; i < n-1; i++ {
+		c, next := t.centroids[i], t.centroids[i+1]
+		mid := cumulative + c.count/2
+		nextMid := cumulative + c.count + next.count/2
+		if target <= nextMid {
+			return c.mean + (next.mean-c.mean)*(target-mid)/(nextMid-mid)
+		}
+		cumulative += c.count
+	}
+	last := t.centroids[n-1]
+	lastMid := t.count - last.count/2
+	return last.mean + (t.max-last.mean)*(target-lastMid)/(t.count-lastMid)
+}
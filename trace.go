@@ -0,0 +1,57 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httputil"
+)
+
+// traceSensitiveHeaders are request headers `TraceHandler` does not reflect back to client.
+var traceSensitiveHeaders = []string{
+	HeaderAuthorization,
+	"Proxy-Authorization",
+	HeaderCookie,
+	HeaderXCSRFToken,
+	"X-Api-Key",
+}
+
+// DisableTRACE makes Echo respond to all TRACE requests with 405 Method Not Allowed, including requests to routes
+// registered for TRACE. Middlewares are still run. TRACE reflects request headers back to the client and is
+// commonly disabled for security hardening.
+func (e *Echo) DisableTRACE() {
+	e.checkNotFrozen()
+	e.traceDisabled = true
+}
+
+// routeHandler returns handler of route found for the request.
+func (e *Echo) routeHandler(c *context) HandlerFunc {
+	if e.traceDisabled && c.request.Method == http.MethodTrace {
+		return MethodNotAllowedHandler
+	}
+	return c.Handler()
+}
+
+// TraceHandler responds to TRACE request by reflecting received request (request line and headers) with
+// `message/http` content type as described in RFC 9110. Sensitive headers (Authorization, Proxy-Authorization,
+// Cookie, X-CSRF-Token, X-Api-Key) are stripped from response.
+//
+// Example: `e.TRACE("/*", echo.TraceHandler)`
+func TraceHandler(c Context) error {
+	req := c.Request()
+	r := &http.Request{
+		Method:     req.Method,
+		URL:        req.URL,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     req.Header.Clone(),
+		Host:       req.Host,
+	}
+	for _, h := range traceSensitiveHeaders {
+		r.Header.Del(h)
+	}
+	b, err := httputil.DumpRequest(r, false)
+	if err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, "message/http", b)
+}
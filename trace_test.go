@@ -0,0 +1,71 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceHandler(t *testing.T) {
+	e := New()
+	e.TRACE("/*", TraceHandler)
+
+	req := httptest.NewRequest(http.MethodTrace, "/api/users?id=1", nil)
+	req.Header.Set(HeaderAuthorization, "Bearer secret")
+	req.Header.Set(HeaderCookie, "session=secret")
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("X-Trace", "yes")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "message/http", rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "TRACE /api/users?id=1 HTTP/1.1\r\nHost: example.com\r\nX-Trace: yes\r\n\r\n", rec.Body.String())
+	assert.Equal(t, "Bearer secret", req.Header.Get(HeaderAuthorization)) // request headers are not modified
+}
+
+func TestEcho_DisableTRACE(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenDisable  bool
+		whenMethod   string
+		expectStatus int
+	}{
+		{
+			name:         "ok, TRACE is served by default",
+			whenMethod:   http.MethodTrace,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "nok, TRACE route is rejected when disabled",
+			whenDisable:  true,
+			whenMethod:   http.MethodTrace,
+			expectStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "ok, other methods are served when TRACE is disabled",
+			whenDisable:  true,
+			whenMethod:   http.MethodGet,
+			expectStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			if tc.whenDisable {
+				e.DisableTRACE()
+			}
+			e.Pre(func(next HandlerFunc) HandlerFunc { return next }) // routing behind pre middleware is covered too
+			e.TRACE("/", TraceHandler)
+			e.GET("/", handlerFunc)
+
+			req := httptest.NewRequest(tc.whenMethod, "/", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+		})
+	}
+}
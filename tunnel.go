@@ -0,0 +1,120 @@
+package echo
+
+import (
+	stdContext "context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TunnelConfig defines the config for CONNECT tunnels created with `Tunnel` and `TunnelHandler`.
+type TunnelConfig struct {
+	// Dial opens connection to tunnel target.
+	// Optional. Default dials TCP connection with `DialTimeout`.
+	Dial func(ctx stdContext.Context, network, address string) (net.Conn, error)
+
+	// DialTimeout is timeout of default Dial.
+	// Optional. Default value 10 seconds.
+	DialTimeout time.Duration
+
+	// IdleTimeout closes tunnel when no data is sent in either direction for given duration.
+	// Optional. Default value 0 (no timeout).
+	IdleTimeout time.Duration
+}
+
+// DefaultTunnelConfig is the default config for CONNECT tunnels.
+var DefaultTunnelConfig = TunnelConfig{
+	DialTimeout: 10 * time.Second,
+}
+
+// TunnelHandler returns handler for forward proxy style CONNECT requests tunneling client connection to request
+// target (`CONNECT example.com:443`). Handler does not restrict targets, use middleware to allow only known ones.
+//
+// Example:
+//
+//	e.CONNECT("/", echo.TunnelHandler(echo.TunnelConfig{IdleTimeout: 5 * time.Minute}))
+func TunnelHandler(config TunnelConfig) HandlerFunc {
+	return func(c Context) error {
+		return Tunnel(c, c.Request().Host, config)
+	}
+}
+
+// Tunnel dials target address, hijacks client connection, responds with 200 and copies data between connections
+// until one of them is closed or `TunnelConfig.IdleTimeout` passes. Returns 502 error when target can not be
+// dialed. Errors after connection is hijacked are not returned as response can not be sent anymore. Tunnels are
+// supported for HTTP/1.x requests only.
+func Tunnel(c Context, address string, config TunnelConfig) error {
+	dial := config.Dial
+	if dial == nil {
+		d := net.Dialer{Timeout: config.DialTimeout}
+		if d.Timeout == 0 {
+			d.Timeout = DefaultTunnelConfig.DialTimeout
+		}
+		dial = d.DialContext
+	}
+
+	out, err := dial(c.Request().Context(), "tcp", address)
+	if err != nil {
+		return NewHTTPError(http.StatusBadGateway).SetInternal(err)
+	}
+	defer out.Close()
+
+	res := c.Response()
+	in, rw, err := res.Hijack()
+	if err != nil {
+		return NewHTTPError(http.StatusInternalServerError, "connection can not be hijacked").SetInternal(err)
+	}
+	defer in.Close()
+	res.Status = http.StatusOK
+	res.Committed = true
+
+	if _, err := in.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return nil
+	}
+	// client may have sent data after request headers that server has already buffered
+	if n := rw.Reader.Buffered(); n > 0 {
+		data, _ := rw.Reader.Peek(n)
+		if _, err := out.Write(data); err != nil {
+			return nil
+		}
+	}
+
+	if config.IdleTimeout > 0 {
+		in = &idleTimeoutConn{Conn: in, timeout: config.IdleTimeout, peer: out}
+		out = &idleTimeoutConn{Conn: out, timeout: config.IdleTimeout, peer: in}
+	}
+	done := make(chan struct{}, 2)
+	cp := func(dst io.Writer, src io.Reader) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go cp(out, in)
+	go cp(in, out)
+	<-done
+	return nil
+}
+
+// idleTimeoutConn extends deadline of itself and its peer on every read and write so tunnel is closed only when
+// neither direction has activity.
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+	peer    net.Conn
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	c.extend()
+	return c.Conn.Read(b)
+}
+
+func (c *idleTimeoutConn) Write(b []byte) (int, error) {
+	c.extend()
+	return c.Conn.Write(b)
+}
+
+func (c *idleTimeoutConn) extend() {
+	deadline := time.Now().Add(c.timeout)
+	_ = c.Conn.SetDeadline(deadline)
+	_ = c.peer.SetDeadline(deadline)
+}
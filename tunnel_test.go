@@ -0,0 +1,125 @@
+package echo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startEchoTCPServer starts TCP server writing back everything it reads.
+func startEchoTCPServer(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return l
+}
+
+func connectTo(t *testing.T, server *httptest.Server, target string) (net.Conn, *bufio.Reader, *http.Response) {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", target, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, br, res
+}
+
+func TestTunnelHandler(t *testing.T) {
+	target := startEchoTCPServer(t)
+	defer target.Close()
+
+	e := New()
+	e.CONNECT("/", TunnelHandler(TunnelConfig{}))
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	conn, br, res := connectTo(t, server, target.Addr().String())
+	defer conn.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	_, err := conn.Write([]byte("ping"))
+	assert.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(br, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestTunnelHandler_idleTimeout(t *testing.T) {
+	target := startEchoTCPServer(t)
+	defer target.Close()
+
+	e := New()
+	e.CONNECT("/", TunnelHandler(TunnelConfig{IdleTimeout: 50 * time.Millisecond}))
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	conn, br, res := connectTo(t, server, target.Addr().String())
+	defer conn.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err := br.ReadByte()
+	assert.Equal(t, io.EOF, err) // closed by idle timeout, not by read deadline of client
+}
+
+func TestTunnelHandler_dialError(t *testing.T) {
+	e := New()
+	e.CONNECT("/", TunnelHandler(TunnelConfig{}))
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	conn, _, res := connectTo(t, server, addr)
+	defer conn.Close()
+	assert.Equal(t, http.StatusBadGateway, res.StatusCode)
+}
+
+func TestTunnel_notHijackable(t *testing.T) {
+	target := startEchoTCPServer(t)
+	defer target.Close()
+
+	e := New()
+	req := httptest.NewRequest(http.MethodConnect, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := Tunnel(c, target.Addr().String(), TunnelConfig{})
+	he, ok := err.(*HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+	assert.Equal(t, http.ErrNotSupported, he.Internal)
+}
@@ -0,0 +1,314 @@
+/*
+Package validation provides `echo.Validator` implementation backed by github.com/go-playground/validator.
+
+Validation errors are translated to `*echo.HTTPError` with status 400 and structured message containing failed
+field path (Go field names without top level struct name, i.e. "Address.City"), rule and human readable
+message. Messages can be translated to request locale.
+
+Example:
+
+	e := echo.New()
+	e.Validator = validation.New(validator.New())
+
+	e.POST("/users", func(c echo.Context) error {
+		u := new(User)
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+		if err := c.Validate(u); err != nil {
+			return err // {"message":"validation failed","errors":[{"field":"Address.City","rule":"required","message":"Address.City is required"}]}
+		}
+		return c.JSON(http.StatusCreated, u)
+	})
+
+Validation scenarios (see `echo.Context#ValidateScenario` and `echo.RouteMetaValidationScenarios`) are supported
+with validators registered for scenario in `Config.Scenarios`, i.e. go-playground/validator instance reading rules
+from other struct tag:
+
+	create := validator.New()
+	create.SetTagName("create")
+	e.Validator = validation.NewWithConfig(validation.Config{
+		Validator: validator.New(),
+		Scenarios: map[string]validation.StructValidator{"create": create},
+	})
+
+Package does not import go-playground/validator itself. Any validator with `Struct(interface{}) error` method
+returning errors as slice of field errors (i.e. `validator.ValidationErrors`) is supported.
+*/
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// StructValidator validates structs. Implemented by `*validator.Validate` from go-playground/validator.
+	StructValidator interface {
+		Struct(s interface{}) error
+	}
+
+	// FieldError describes single failed field validation. Implemented by `validator.FieldError` from
+	// go-playground/validator.
+	FieldError interface {
+		// Tag returns validation rule that failed, i.e. "required".
+		Tag() string
+		// Namespace returns field path including top level struct name, i.e. "User.Address.City".
+		Namespace() string
+		// Field returns field name, i.e. "City".
+		Field() string
+		// Param returns rule parameter, i.e. "10" for "max=10".
+		Param() string
+	}
+
+	// Translator returns message for field error in given locale. `fieldPath` is path of the field without top
+	// level struct name. Returning empty string falls back to default (english) message.
+	Translator func(fe FieldError, fieldPath string, locale string) string
+
+	// LocaleExtractor returns locale for current request.
+	LocaleExtractor func(c echo.Context) string
+
+	// Config defines the config for Validator.
+	Config struct {
+		// Validator is the validator used to validate structs.
+		// Required.
+		Validator StructValidator
+
+		// Translator translates field errors to messages in request locale.
+		// Optional. Default value uses english messages.
+		Translator Translator
+
+		// LocaleExtractor extracts locale from request.
+		// Optional. Default value uses first language from `Accept-Language` header.
+		LocaleExtractor LocaleExtractor
+
+		// DefaultLocale is used when LocaleExtractor returns empty locale and when validating without context.
+		// Optional. Default value "en".
+		DefaultLocale string
+
+		// Message is the general message of validation error response.
+		// Optional. Default value "validation failed".
+		Message string
+
+		// Scenarios are validators of validation scenarios (i.e. "create", "update"). Structs validated with
+		// scenarios are validated by `Validator` and validators of all given scenarios.
+		// Optional.
+		Scenarios map[string]StructValidator
+	}
+
+	// Validator implements `echo.Validator`, `echo.ContextValidator` and `echo.ScenarioValidator`.
+	Validator struct {
+		config Config
+	}
+
+	// Violation is a single field validation failure.
+	Violation struct {
+		Field   string `json:"field"`
+		Rule    string `json:"rule"`
+		Param   string `json:"param,omitempty"`
+		Message string `json:"message"`
+	}
+
+	// Errors is the message of `*echo.HTTPError` returned for failed validation.
+	Errors struct {
+		Message string      `json:"message"`
+		Errors  []Violation `json:"errors"`
+	}
+)
+
+var (
+	// DefaultConfig is the default Validator config.
+	DefaultConfig = Config{
+		LocaleExtractor: AcceptLanguageLocale,
+		DefaultLocale:   "en",
+		Message:         "validation failed",
+	}
+)
+
+// New creates Validator backed by given struct validator.
+func New(v StructValidator) *Validator {
+	c := DefaultConfig
+	c.Validator = v
+	return NewWithConfig(c)
+}
+
+// NewWithConfig creates Validator with config.
+// See `New()`.
+func NewWithConfig(config Config) *Validator {
+	if config.Validator == nil {
+		panic("echo: validation requires a validator")
+	}
+	if config.LocaleExtractor == nil {
+		config.LocaleExtractor = DefaultConfig.LocaleExtractor
+	}
+	if config.DefaultLocale == "" {
+		config.DefaultLocale = DefaultConfig.DefaultLocale
+	}
+	if config.Message == "" {
+		config.Message = DefaultConfig.Message
+	}
+	return &Validator{config: config}
+}
+
+// ErrUnknownScenario is returned when struct is validated with scenario that has no validator in `Config.Scenarios`.
+var ErrUnknownScenario = errors.New("validation: unknown scenario")
+
+// Validate implements `echo.Validator`. Messages are in default locale.
+func (v *Validator) Validate(i interface{}) error {
+	return v.validate(i, v.config.DefaultLocale, v.config.Validator)
+}
+
+// ValidateScenarios implements `echo.ScenarioValidator`. Struct is validated by `Config.Validator` and validators
+// of given scenarios. Messages are in default locale.
+func (v *Validator) ValidateScenarios(i interface{}, scenarios ...string) error {
+	validators := []StructValidator{v.config.Validator}
+	for _, s := range scenarios {
+		sv, ok := v.config.Scenarios[s]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownScenario, s)
+		}
+		validators = append(validators, sv)
+	}
+	return v.validate(i, v.config.DefaultLocale, validators...)
+}
+
+// ValidateContext implements `echo.ContextValidator`. Messages are in request locale.
+func (v *Validator) ValidateContext(c echo.Context, i interface{}) error {
+	locale := v.config.LocaleExtractor(c)
+	if locale == "" {
+		locale = v.config.DefaultLocale
+	}
+	return v.validate(i, locale, v.config.Validator)
+}
+
+func (v *Validator) validate(i interface{}, locale string, validators ...StructValidator) error {
+	var fieldErrors []FieldError
+	var internal error
+	for _, sv := range validators {
+		err := sv.Struct(i)
+		if err == nil {
+			continue
+		}
+		fes, ok := toFieldErrors(err)
+		if !ok {
+			// i.e. `*validator.InvalidValidationError` when validating non-struct value
+			return err
+		}
+		if len(fes) == 0 {
+			continue // empty slice of field errors has no failed field
+		}
+		fieldErrors = append(fieldErrors, fes...)
+		if internal == nil {
+			internal = err
+		}
+	}
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	violations := make([]Violation, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		path := fieldPath(fe)
+		msg := ""
+		if v.config.Translator != nil {
+			msg = v.config.Translator(fe, path, locale)
+		}
+		if msg == "" {
+			msg = defaultMessage(fe, path)
+		}
+		violations = append(violations, Violation{
+			Field:   path,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: msg,
+		})
+	}
+	return &echo.HTTPError{
+		Code:     http.StatusBadRequest,
+		Message:  Errors{Message: v.config.Message, Errors: violations},
+		Internal: internal,
+	}
+}
+
+// toFieldErrors extracts field errors from slice type errors (i.e. `validator.ValidationErrors`). Returns false
+// when err is not slice of field errors.
+func toFieldErrors(err error) ([]FieldError, bool) {
+	rv := reflect.ValueOf(err)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	result := make([]FieldError, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		fe, ok := rv.Index(i).Interface().(FieldError)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, fe)
+	}
+	return result, true
+}
+
+// fieldPath returns field namespace without top level struct name, i.e. "User.Address.City" -> "Address.City"
+func fieldPath(fe FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.IndexByte(ns, '.'); i != -1 {
+		return ns[i+1:]
+	}
+	if ns == "" {
+		return fe.Field()
+	}
+	return ns
+}
+
+func defaultMessage(fe FieldError, path string) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", path)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", path)
+	case "url", "uri":
+		return fmt.Sprintf("%s must be a valid URL", path)
+	case "uuid", "uuid4":
+		return fmt.Sprintf("%s must be a valid UUID", path)
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s", path, fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s", path, fe.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", path, fe.Param())
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", path, fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must have length of %s", path, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", path, fe.Param())
+	}
+	if fe.Param() != "" {
+		return fmt.Sprintf("%s failed on '%s=%s' rule", path, fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("%s failed on '%s' rule", path, fe.Tag())
+}
+
+// AcceptLanguageLocale returns first language from `Accept-Language` header, i.e. "de" for "de-CH;q=0.9, en".
+func AcceptLanguageLocale(c echo.Context) string {
+	header := c.Request().Header.Get("Accept-Language")
+	if i := strings.IndexByte(header, ','); i != -1 {
+		header = header[:i]
+	}
+	if i := strings.IndexByte(header, ';'); i != -1 {
+		header = header[:i]
+	}
+	lang := strings.TrimSpace(header)
+	if i := strings.IndexByte(lang, '-'); i != -1 {
+		lang = lang[:i]
+	}
+	if lang == "*" {
+		return ""
+	}
+	return strings.ToLower(lang)
+}
@@ -0,0 +1,251 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldError struct {
+	tag       string
+	namespace string
+	field     string
+	param     string
+}
+
+func (fe fieldError) Tag() string       { return fe.tag }
+func (fe fieldError) Namespace() string { return fe.namespace }
+func (fe fieldError) Field() string     { return fe.field }
+func (fe fieldError) Param() string     { return fe.param }
+func (fe fieldError) Error() string     { return fe.namespace + " " + fe.tag }
+
+// fieldErrors mimics `validator.ValidationErrors` which is `[]FieldError`
+type fieldErrors []fieldError
+
+func (fe fieldErrors) Error() string { return "validation errors" }
+
+type structValidator struct {
+	err error
+}
+
+func (v structValidator) Struct(s interface{}) error {
+	return v.err
+}
+
+func TestValidator_Validate(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenErr      error
+		expectErr     error
+		expectMessage interface{}
+	}{
+		{
+			name:      "ok",
+			givenErr:  nil,
+			expectErr: nil,
+		},
+		{
+			name: "nok, field errors",
+			givenErr: fieldErrors{
+				{tag: "required", namespace: "User.Address.City", field: "City"},
+				{tag: "max", namespace: "User.Name", field: "Name", param: "10"},
+				{tag: "custom", namespace: "User.Code", field: "Code"},
+			},
+			expectMessage: Errors{
+				Message: "validation failed",
+				Errors: []Violation{
+					{Field: "Address.City", Rule: "required", Message: "Address.City is required"},
+					{Field: "Name", Rule: "max", Param: "10", Message: "Name must be at most 10"},
+					{Field: "Code", Rule: "custom", Message: "Code failed on 'custom' rule"},
+				},
+			},
+		},
+		{
+			name:      "ok, empty field errors",
+			givenErr:  fieldErrors{},
+			expectErr: nil,
+		},
+		{
+			name:      "nok, not field errors are returned as is",
+			givenErr:  errors.New("validator: (nil *main.User)"),
+			expectErr: errors.New("validator: (nil *main.User)"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := New(structValidator{err: tc.givenErr})
+
+			err := v.Validate(struct{}{})
+			if tc.expectMessage != nil {
+				he, ok := err.(*echo.HTTPError)
+				if assert.True(t, ok) {
+					assert.Equal(t, http.StatusBadRequest, he.Code)
+					assert.Equal(t, tc.expectMessage, he.Message)
+					assert.Equal(t, tc.givenErr, he.Internal)
+				}
+			} else {
+				assert.Equal(t, tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateScenarios(t *testing.T) {
+	v := NewWithConfig(Config{
+		Validator: structValidator{err: fieldErrors{
+			{tag: "required", namespace: "User.Name", field: "Name"},
+		}},
+		Scenarios: map[string]StructValidator{
+			"create": structValidator{err: fieldErrors{
+				{tag: "required", namespace: "User.Password", field: "Password"},
+			}},
+			"update": structValidator{},
+		},
+	})
+
+	var testCases = []struct {
+		name             string
+		whenScenarios    []string
+		expectViolations []Violation
+		expectErr        string
+	}{
+		{
+			name:          "nok, base and scenario rules",
+			whenScenarios: []string{"create"},
+			expectViolations: []Violation{
+				{Field: "Name", Rule: "required", Message: "Name is required"},
+				{Field: "Password", Rule: "required", Message: "Password is required"},
+			},
+		},
+		{
+			name:          "nok, base rules",
+			whenScenarios: []string{"update"},
+			expectViolations: []Violation{
+				{Field: "Name", Rule: "required", Message: "Name is required"},
+			},
+		},
+		{
+			name:          "nok, unknown scenario",
+			whenScenarios: []string{"delete"},
+			expectErr:     `validation: unknown scenario: "delete"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := v.ValidateScenarios(struct{}{}, tc.whenScenarios...)
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				assert.True(t, errors.Is(err, ErrUnknownScenario))
+				return
+			}
+			he, ok := err.(*echo.HTTPError)
+			if assert.True(t, ok) {
+				assert.Equal(t, tc.expectViolations, he.Message.(Errors).Errors)
+			}
+		})
+	}
+}
+
+func TestValidator_routeScenarios(t *testing.T) {
+	e := echo.New()
+	e.Validator = NewWithConfig(Config{
+		Validator: structValidator{},
+		Scenarios: map[string]StructValidator{
+			"create": structValidator{err: fieldErrors{
+				{tag: "required", namespace: "User.Password", field: "Password"},
+			}},
+		},
+	})
+	e.RouteMeta(e.POST("/users", func(c echo.Context) error {
+		return c.Validate(struct{}{})
+	}))[echo.RouteMetaValidationScenarios] = []string{"create"}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"Password"`)
+}
+
+func TestValidator_ValidateContextLocale(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenHeader       string
+		expectFieldLocale string
+	}{
+		{
+			name:              "ok, locale from Accept-Language",
+			givenHeader:       "de-CH;q=0.9, en;q=0.8",
+			expectFieldLocale: "de",
+		},
+		{
+			name:              "ok, default locale without header",
+			givenHeader:       "",
+			expectFieldLocale: "et",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = NewWithConfig(Config{
+				Validator: structValidator{err: fieldErrors{
+					{tag: "required", namespace: "User.Name", field: "Name"},
+				}},
+				DefaultLocale: "et",
+				Translator: func(fe FieldError, fieldPath string, locale string) string {
+					return locale + ":" + fieldPath
+				},
+			})
+			e.POST("/", func(c echo.Context) error {
+				return c.Validate(struct{}{})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.givenHeader != "" {
+				req.Header.Set("Accept-Language", tc.givenHeader)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			result := Errors{}
+			assert.NoError(t, json.NewDecoder(strings.NewReader(rec.Body.String())).Decode(&result))
+			assert.Equal(t, []Violation{
+				{Field: "Name", Rule: "required", Message: tc.expectFieldLocale + ":Name"},
+			}, result.Errors)
+		})
+	}
+}
+
+func TestAcceptLanguageLocale(t *testing.T) {
+	var testCases = []struct {
+		whenHeader string
+		expect     string
+	}{
+		{whenHeader: "", expect: ""},
+		{whenHeader: "*", expect: ""},
+		{whenHeader: "fr", expect: "fr"},
+		{whenHeader: "EN-us, en;q=0.5", expect: "en"},
+		{whenHeader: "de;q=0.9", expect: "de"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.whenHeader, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tc.whenHeader)
+			c := e.NewContext(req, nil)
+
+			assert.Equal(t, tc.expect, AcceptLanguageLocale(c))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package echo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type (
+	// VersioningConfig defines how API version of request is resolved for routes registered with `Echo#Version`.
+	VersioningConfig struct {
+		// PathPrefix registers routes of version under path prefix of version (i.e. `e.Version("v2").GET("/users", h)`
+		// registers "/v2/users"). When false routes of all versions share the same path and version of request is
+		// resolved with Extractors. Must be set before versions are registered.
+		// Optional. Default value false.
+		PathPrefix bool
+
+		// Extractors resolve version of request. Extractors are tried in order and first non-empty version is used.
+		// Optional. Default value is `VersionFromAccept()` followed by `VersionFromHeader(HeaderXAPIVersion)`.
+		Extractors []VersionExtractor
+
+		// Default is the version used for requests that do not specify version. When empty the latest version
+		// registered for the route is used (versions are compared by numbers in them, i.e. "v10" > "v9").
+		// Optional.
+		Default string
+
+		// Deprecated maps deprecated versions to their deprecation info. Responses of deprecated versions have
+		// `Deprecation`, `Sunset` and `Link` headers.
+		// Optional.
+		Deprecated map[string]Deprecation
+	}
+
+	// VersionExtractor returns API version requested by client or empty string when request does not specify it.
+	VersionExtractor func(c Context) string
+
+	// versionedRoute holds handlers of route registered for multiple versions with `Echo#Version`.
+	versionedRoute struct {
+		route    *Route
+		handlers map[string]HandlerFunc
+		latest   string
+	}
+)
+
+const (
+	apiVersionKey = "echo.api_version"
+)
+
+var (
+	defaultVersionExtractors = []VersionExtractor{VersionFromAccept(), VersionFromHeader(HeaderXAPIVersion)}
+	versionNumberRegexp      = regexp.MustCompile(`\d+`)
+)
+
+// Version creates a router group for API version with optional version-level middleware. Routes of the group
+// serve requests of that version (see `Echo#Versioning`) and version serving the request is available with
+// `RequestVersion`. Requests for versions not registered for the route are responded with 404 Not Found.
+//
+// Example:
+//
+//	e.Versioning.Deprecated = map[string]echo.Deprecation{"v1": {Sunset: sunset}}
+//	e.Version("v1").GET("/users", listUsersV1)
+//	e.Version("v2").GET("/users", listUsersV2) // GET /users with `Accept: application/vnd.example.v2+json`
+func (e *Echo) Version(version string, m ...MiddlewareFunc) (g *Group) {
+	if version == "" {
+		panic("echo: version must not be empty")
+	}
+	if e.Versioning.PathPrefix {
+		g = &Group{prefix: "/" + version, echo: e, middleware: []MiddlewareFunc{versionMiddleware(version)}}
+		if len(m) > 0 {
+			g.Use(m...)
+		}
+		return
+	}
+	g = &Group{version: version, echo: e}
+	g.Use(m...)
+	return
+}
+
+// RequestVersion returns API version serving the request or empty string when request is not served by route
+// registered with `Echo#Version`.
+func RequestVersion(c Context) string {
+	v, _ := c.Get(apiVersionKey).(string)
+	return v
+}
+
+// VersionFromHeader returns extractor resolving version from request header (i.e. "X-API-Version: v2").
+func VersionFromHeader(name string) VersionExtractor {
+	return func(c Context) string {
+		return strings.TrimSpace(c.Request().Header.Get(name))
+	}
+}
+
+// VersionFromAccept returns extractor resolving version from vendor media type of `Accept` header. Version is the
+// last dot separated part of vendor subtype (i.e. "v2" in "application/vnd.example.v2+json") or value of "version"
+// parameter (i.e. "v2" in "application/vnd.example+json; version=v2").
+func VersionFromAccept() VersionExtractor {
+	return func(c Context) string {
+		for _, m := range ParseAccept(c.Request().Header[HeaderAccept]...) {
+			if !strings.HasPrefix(m.Subtype, "vnd.") {
+				continue
+			}
+			if v := m.Params["version"]; v != "" {
+				return v
+			}
+			subtype := m.Subtype
+			if i := strings.IndexByte(subtype, '+'); i != -1 {
+				subtype = subtype[:i]
+			}
+			parts := strings.Split(subtype, ".")
+			if len(parts) > 2 { // "vnd.example" has no version
+				return parts[len(parts)-1]
+			}
+		}
+		return ""
+	}
+}
+
+// addVersioned registers handler for version of route. All versions of route share one registered route which
+// dispatches requests to handler of requested version. Handler replaces previously registered handler of version.
+func (e *Echo) addVersioned(host, version string, groupMiddleware int, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	key := host + " " + method + " " + path
+	vr, ok := e.versionedRoutes[key]
+	if !ok {
+		vr = &versionedRoute{handlers: map[string]HandlerFunc{}}
+		vr.route = e.add(host, groupMiddleware, method, path, func(c Context) error {
+			return c.Echo().serveVersioned(c, key)
+		})
+		e.versionedRoutes[key] = vr
+	}
+	vr.handlers[version] = applyMiddleware(handler, middleware...)
+	if vr.latest == "" || compareVersions(version, vr.latest) > 0 {
+		vr.latest = version
+	}
+	return vr.route
+}
+
+func (e *Echo) tryAddVersioned(host, version string, groupMiddleware int, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) (*Route, error) {
+	if err := e.checkRoute(method, path, handler); err != nil {
+		return nil, err
+	}
+	if vr, ok := e.versionedRoutes[host+" "+method+" "+path]; ok && vr.handlers[version] != nil {
+		return nil, &RouteError{Method: method, Path: path, Err: ErrRouteVersionExists}
+	}
+	return e.addVersioned(host, version, groupMiddleware, method, path, handler, middleware...), nil
+}
+
+func (e *Echo) serveVersioned(c Context, key string) error {
+	vr := e.versionedRoutes[key]
+	extractors := e.Versioning.Extractors
+	if len(extractors) == 0 {
+		extractors = defaultVersionExtractors
+	}
+	version := ""
+	for _, extract := range extractors {
+		if version = extract(c); version != "" {
+			break
+		}
+	}
+	if version == "" {
+		version = e.Versioning.Default
+	}
+	if version == "" {
+		version = vr.latest
+	}
+	h, ok := vr.handlers[version]
+	if !ok {
+		return ErrNotFound
+	}
+	serveVersion(c, version)
+	return h(c)
+}
+
+func versionMiddleware(version string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			serveVersion(c, version)
+			return next(c)
+		}
+	}
+}
+
+// serveVersion stores version of request in context and sets deprecation headers of deprecated version.
+func serveVersion(c Context, version string) {
+	c.Set(apiVersionKey, version)
+	d, ok := c.Echo().Versioning.Deprecated[version]
+	if !ok {
+		return
+	}
+	d.WriteHeaders(c.Response().Header())
+}
+
+// compareVersions compares versions by numbers in them (i.e. "v1.10" > "v1.9") and falls back to comparing strings.
+func compareVersions(a, b string) int {
+	an := versionNumberRegexp.FindAllString(a, -1)
+	bn := versionNumberRegexp.FindAllString(b, -1)
+	for i := 0; i < len(an) && i < len(bn); i++ {
+		x, _ := strconv.Atoi(an[i])
+		y, _ := strconv.Atoi(bn[i])
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	if len(an) != len(bn) {
+		if len(an) > len(bn) {
+			return 1
+		}
+		return -1
+	}
+	return strings.Compare(a, b)
+}
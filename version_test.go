@@ -0,0 +1,239 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func versionHandler(c Context) error {
+	return c.String(http.StatusOK, RequestVersion(c)+" "+c.Path())
+}
+
+func TestEcho_Version(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenConfig   VersioningConfig
+		whenHeaders  map[string]string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "ok, latest version without version in request",
+			expectStatus: http.StatusOK,
+			expectBody:   "v10 /users/:id",
+		},
+		{
+			name:         "ok, default version without version in request",
+			whenConfig:   VersioningConfig{Default: "v1"},
+			expectStatus: http.StatusOK,
+			expectBody:   "v1 /users/:id",
+		},
+		{
+			name:         "ok, vendor media type",
+			whenHeaders:  map[string]string{HeaderAccept: "text/html, application/vnd.example.v2+json"},
+			expectStatus: http.StatusOK,
+			expectBody:   "v2 /users/:id",
+		},
+		{
+			name:         "ok, version parameter of vendor media type",
+			whenHeaders:  map[string]string{HeaderAccept: "application/vnd.example+json; version=v1"},
+			expectStatus: http.StatusOK,
+			expectBody:   "v1 /users/:id",
+		},
+		{
+			name:         "ok, header",
+			whenHeaders:  map[string]string{HeaderXAPIVersion: "v2"},
+			expectStatus: http.StatusOK,
+			expectBody:   "v2 /users/:id",
+		},
+		{
+			name:         "ok, accept has precedence over header",
+			whenHeaders:  map[string]string{HeaderAccept: "application/vnd.example.v1+json", HeaderXAPIVersion: "v2"},
+			expectStatus: http.StatusOK,
+			expectBody:   "v1 /users/:id",
+		},
+		{
+			name:         "ok, custom extractor",
+			whenConfig:   VersioningConfig{Extractors: []VersionExtractor{VersionFromHeader("Api-Version")}},
+			whenHeaders:  map[string]string{"Api-Version": "v1", HeaderXAPIVersion: "v2"},
+			expectStatus: http.StatusOK,
+			expectBody:   "v1 /users/:id",
+		},
+		{
+			name:         "nok, unknown version",
+			whenHeaders:  map[string]string{HeaderXAPIVersion: "v3"},
+			expectStatus: http.StatusNotFound,
+			expectBody:   "{\"message\":\"Not Found\"}\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Versioning = tc.whenConfig
+			e.Version("v1").GET("/users/:id", versionHandler)
+			e.Version("v10").GET("/users/:id", versionHandler)
+			r := e.Version("v2").GET("/users/:id", versionHandler)
+
+			assert.Equal(t, "/users/:id", r.Path)
+			assert.Len(t, e.Routes(), 1)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEcho_Version_pathPrefix(t *testing.T) {
+	e := New()
+	e.Versioning.PathPrefix = true
+	e.Version("v1").GET("/users", versionHandler)
+	e.Version("v2").Group("/admin").GET("/users", versionHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "v1 /v1/users", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/v2/admin/users", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "v2 /v2/admin/users", rec.Body.String())
+}
+
+func TestEcho_Version_deprecation(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2021, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	var testCases = []struct {
+		name              string
+		whenPathPrefix    bool
+		whenURL           string
+		whenVersion       string
+		expectDeprecation string
+		expectSunset      string
+	}{
+		{
+			name:              "ok, deprecated since",
+			whenVersion:       "v1",
+			expectDeprecation: "@1609459200",
+			expectSunset:      "Fri, 31 Dec 2021 23:59:59 GMT",
+		},
+		{
+			name:              "ok, deprecated without dates",
+			whenVersion:       "v2",
+			expectDeprecation: "true",
+		},
+		{
+			name:        "ok, not deprecated",
+			whenVersion: "v3",
+		},
+		{
+			name:              "ok, path prefix",
+			whenPathPrefix:    true,
+			whenURL:           "/v1/users",
+			expectDeprecation: "@1609459200",
+			expectSunset:      "Fri, 31 Dec 2021 23:59:59 GMT",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Versioning = VersioningConfig{
+				PathPrefix: tc.whenPathPrefix,
+				Deprecated: map[string]Deprecation{
+					"v1": {Since: since, Sunset: sunset},
+					"v2": {},
+				},
+			}
+			for _, v := range []string{"v1", "v2", "v3"} {
+				e.Version(v).GET("/users", versionHandler)
+			}
+
+			url := tc.whenURL
+			if url == "" {
+				url = "/users"
+			}
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req.Header.Set(HeaderXAPIVersion, tc.whenVersion)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectDeprecation, rec.Header().Get(HeaderDeprecation))
+			assert.Equal(t, tc.expectSunset, rec.Header().Get(HeaderSunset))
+		})
+	}
+}
+
+func TestEcho_Version_middleware(t *testing.T) {
+	e := New()
+	v1 := e.Version("v1", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Group", "v1")
+			return next(c)
+		}
+	})
+	v1.GET("/users", versionHandler)
+	e.Version("v2").GET("/users", versionHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(HeaderXAPIVersion, "v1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "v1", rec.Header().Get("X-Group"))
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "v2 /users", rec.Body.String())
+	assert.Empty(t, rec.Header().Get("X-Group"))
+}
+
+func TestGroup_TryAdd_versionExists(t *testing.T) {
+	e := New()
+	_, err := e.Version("v1").TryAdd(http.MethodGet, "/users", versionHandler)
+	assert.NoError(t, err)
+	_, err = e.Version("v2").TryAdd(http.MethodGet, "/users", versionHandler)
+	assert.NoError(t, err)
+
+	_, err = e.Version("v1").TryAdd(http.MethodGet, "/users", versionHandler)
+	assert.True(t, errors.Is(err, ErrRouteVersionExists))
+	assert.EqualError(t, err, "echo: can not add route GET /users: handler for version is already registered")
+}
+
+func TestEcho_Version_clone(t *testing.T) {
+	e := New()
+	e.Version("v1").GET("/users", versionHandler)
+
+	c := e.Clone()
+	c.Version("v2").GET("/users", versionHandler)
+
+	serve := func(e *Echo) string {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+	assert.Equal(t, "v1 /users", serve(e))
+	assert.Equal(t, "v2 /users", serve(c))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 1, compareVersions("v10", "v9"))
+	assert.Equal(t, -1, compareVersions("v1.9", "v1.10"))
+	assert.Equal(t, 1, compareVersions("v1.1", "v1"))
+	assert.Equal(t, 0, compareVersions("v2", "v2"))
+	assert.Equal(t, -1, compareVersions("alpha", "beta"))
+}
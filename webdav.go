@@ -0,0 +1,119 @@
+package echo
+
+import (
+	"strings"
+
+	"golang.org/x/net/webdav"
+)
+
+// PROPFIND registers a new PROPFIND route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) PROPFIND(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(PROPFIND, path, h, m...)
+}
+
+// PROPPATCH registers a new PROPPATCH route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) PROPPATCH(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(PROPPATCH, path, h, m...)
+}
+
+// MKCOL registers a new MKCOL route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) MKCOL(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(MKCOL, path, h, m...)
+}
+
+// COPY registers a new COPY route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) COPY(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(COPY, path, h, m...)
+}
+
+// MOVE registers a new MOVE route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) MOVE(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(MOVE, path, h, m...)
+}
+
+// LOCK registers a new LOCK route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) LOCK(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(LOCK, path, h, m...)
+}
+
+// UNLOCK registers a new UNLOCK route for a path with matching handler in the
+// router with optional route-level middleware.
+func (e *Echo) UNLOCK(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return e.Add(UNLOCK, path, h, m...)
+}
+
+// WebDAV registers WebDAV handler for all requests with path prefix. Unlike `Echo#Mount` request path is not
+// stripped as WebDAV responses (i.e. PROPFIND hrefs) and `Destination` header of COPY and MOVE requests refer to
+// full paths. When `webdav.Handler.Prefix` is empty handler is served with copy of it having prefix set to route
+// prefix. Prefix can not contain path parameters.
+//
+// Example:
+//
+//	e.WebDAV("/dav", &webdav.Handler{
+//		FileSystem: webdav.Dir("/srv/dav"),
+//		LockSystem: webdav.NewMemLS(),
+//	})
+func (e *Echo) WebDAV(prefix string, h *webdav.Handler, middleware ...MiddlewareFunc) []*Route {
+	return e.webDAV(prefix, prefix, h, e.Any, middleware...)
+}
+
+// PROPFIND implements `Echo#PROPFIND()` for sub-routes within the Group.
+func (g *Group) PROPFIND(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(PROPFIND, path, h, m...)
+}
+
+// PROPPATCH implements `Echo#PROPPATCH()` for sub-routes within the Group.
+func (g *Group) PROPPATCH(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(PROPPATCH, path, h, m...)
+}
+
+// MKCOL implements `Echo#MKCOL()` for sub-routes within the Group.
+func (g *Group) MKCOL(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(MKCOL, path, h, m...)
+}
+
+// COPY implements `Echo#COPY()` for sub-routes within the Group.
+func (g *Group) COPY(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(COPY, path, h, m...)
+}
+
+// MOVE implements `Echo#MOVE()` for sub-routes within the Group.
+func (g *Group) MOVE(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(MOVE, path, h, m...)
+}
+
+// LOCK implements `Echo#LOCK()` for sub-routes within the Group.
+func (g *Group) LOCK(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(LOCK, path, h, m...)
+}
+
+// UNLOCK implements `Echo#UNLOCK()` for sub-routes within the Group.
+func (g *Group) UNLOCK(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
+	return g.Add(UNLOCK, path, h, m...)
+}
+
+// WebDAV implements `Echo#WebDAV()` for sub-routes within the Group. Handler prefix includes group prefix.
+func (g *Group) WebDAV(prefix string, h *webdav.Handler, middleware ...MiddlewareFunc) []*Route {
+	return g.webDAV(g.prefix+prefix, prefix, h, g.Any, middleware...)
+}
+
+func (common) webDAV(fullPrefix, prefix string, h *webdav.Handler, anyFn func(string, HandlerFunc, ...MiddlewareFunc) []*Route, m ...MiddlewareFunc) []*Route {
+	if h.Prefix == "" {
+		dav := *h
+		dav.Prefix = strings.TrimSuffix(fullPrefix, "/")
+		h = &dav
+	}
+	handler := WrapHandler(h)
+	prefix = strings.TrimSuffix(prefix, "/")
+	routes := anyFn(prefix+"/*", handler, m...)
+	if prefix != "" {
+		routes = append(routes, anyFn(prefix, handler, m...)...)
+	}
+	return routes
+}
@@ -0,0 +1,84 @@
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/webdav"
+)
+
+func TestEcho_WebDAVMethods(t *testing.T) {
+	e := New()
+	g := e.Group("/g")
+	register := map[string]func(string, HandlerFunc, ...MiddlewareFunc) *Route{
+		PROPFIND:  e.PROPFIND,
+		PROPPATCH: g.PROPPATCH,
+		MKCOL:     e.MKCOL,
+		COPY:      g.COPY,
+		MOVE:      e.MOVE,
+		LOCK:      g.LOCK,
+		UNLOCK:    e.UNLOCK,
+	}
+	for _, add := range register {
+		add("/res", func(c Context) error {
+			return c.String(http.StatusOK, c.Request().Method)
+		})
+	}
+
+	for method := range register {
+		t.Run(method, func(t *testing.T) {
+			path := "/res"
+			if method == PROPPATCH || method == COPY || method == LOCK {
+				path = "/g/res"
+			}
+			status, body := request(method, path, e)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, method, body)
+
+			status, _ = request(http.MethodGet, path, e)
+			assert.Equal(t, http.StatusMethodNotAllowed, status)
+		})
+	}
+}
+
+func TestGroup_WebDAV(t *testing.T) {
+	e := New()
+	h := &webdav.Handler{
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	}
+	e.Group("/files").WebDAV("/dav", h)
+
+	send := func(method, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(MKCOL, "/files/dav/docs", "", nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = send(http.MethodPut, "/files/dav/docs/a.txt", "hello", nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = send(COPY, "/files/dav/docs/a.txt", "", map[string]string{"Destination": "http://example.com/files/dav/docs/b.txt"})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = send(PROPFIND, "/files/dav/docs", "", map[string]string{"Depth": "1"})
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<D:href>/files/dav/docs/a.txt</D:href>")
+	assert.Contains(t, rec.Body.String(), "<D:href>/files/dav/docs/b.txt</D:href>")
+
+	rec = send(http.MethodGet, "/files/dav/docs/b.txt", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hello", rec.Body.String())
+
+	assert.Empty(t, h.Prefix) // handler given by caller is not modified
+}